@@ -0,0 +1,5 @@
+// Package ability implements helpers for composing the ability data of the UpdateAbilities packet. Ability
+// layers are composed using a LayerBuilder, which keeps the Abilities and Values bitsets of a layer
+// consistent, and the abilities and permission levels matching a Role, such as an operator, may be obtained
+// using Role.Layer and Update.
+package ability
@@ -0,0 +1,77 @@
+package ability
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// All is a bitset of all abilities, combining all protocol.Ability constants.
+const All = protocol.AbilityCount - 1
+
+// LayerBuilder composes a protocol.AbilityLayer. An ability of a layer may be unset, in which case the layer
+// does not change it, or set to enabled or disabled. Abilities are set by setting their bit in the Abilities
+// bitset of the layer and, if enabled, in the Values bitset. The methods of a LayerBuilder return the
+// LayerBuilder so that calls may be chained.
+type LayerBuilder struct {
+	l protocol.AbilityLayer
+}
+
+// NewLayer returns a LayerBuilder for a layer of the type passed, which is one of the protocol.AbilityLayerType
+// constants, with no abilities set. The base layer, protocol.AbilityLayerTypeBase, should set all abilities,
+// as done by Role.Layer.
+func NewLayer(layerType uint16) *LayerBuilder {
+	return &LayerBuilder{l: protocol.AbilityLayer{Type: layerType}}
+}
+
+// Enable sets the abilities passed, which are protocol.Ability constants combined using a bitwise OR, to
+// enabled.
+func (b *LayerBuilder) Enable(abilities uint32) *LayerBuilder {
+	b.l.Abilities |= abilities
+	b.l.Values |= abilities
+	return b
+}
+
+// Disable sets the abilities passed, which are protocol.Ability constants combined using a bitwise OR, to
+// disabled.
+func (b *LayerBuilder) Disable(abilities uint32) *LayerBuilder {
+	b.l.Abilities |= abilities
+	b.l.Values &^= abilities
+	return b
+}
+
+// Set sets the abilities passed to enabled if enabled is true and to disabled otherwise.
+func (b *LayerBuilder) Set(abilities uint32, enabled bool) *LayerBuilder {
+	if enabled {
+		return b.Enable(abilities)
+	}
+	return b.Disable(abilities)
+}
+
+// Unset unsets the abilities passed, so that the layer no longer changes them.
+func (b *LayerBuilder) Unset(abilities uint32) *LayerBuilder {
+	b.l.Abilities &^= abilities
+	b.l.Values &^= abilities
+	return b
+}
+
+// FlySpeed sets the fly speed of the layer. protocol.AbilityBaseFlySpeed is the default fly speed.
+func (b *LayerBuilder) FlySpeed(speed float32) *LayerBuilder {
+	b.l.FlySpeed = speed
+	return b.Enable(protocol.AbilityFlySpeed)
+}
+
+// WalkSpeed sets the walk speed of the layer. protocol.AbilityBaseWalkSpeed is the default walk speed.
+func (b *LayerBuilder) WalkSpeed(speed float32) *LayerBuilder {
+	b.l.WalkSpeed = speed
+	return b.Enable(protocol.AbilityWalkSpeed)
+}
+
+// Build returns the protocol.AbilityLayer composed.
+func (b *LayerBuilder) Build() protocol.AbilityLayer {
+	return b.l
+}
+
+// Enabled checks if the ability passed, one of the protocol.Ability constants, is enabled in the layer
+// passed. The second return value is false if the layer does not set the ability.
+func Enabled(l protocol.AbilityLayer, ability uint32) (enabled, set bool) {
+	return l.Values&ability != 0, l.Abilities&ability != 0
+}
@@ -0,0 +1,91 @@
+package ability
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Role is a simple permission model of a player, matching the permission levels found in the player list:
+// Visitors may only look around, members may interact with the world and operators may additionally run
+// operator commands and teleport.
+type Role uint8
+
+const (
+	RoleVisitor Role = iota
+	RoleMember
+	RoleOperator
+)
+
+// memberAbilities holds the abilities enabled for members.
+const memberAbilities = protocol.AbilityBuild | protocol.AbilityMine | protocol.AbilityDoorsAndSwitches |
+	protocol.AbilityOpenContainers | protocol.AbilityAttackPlayers | protocol.AbilityAttackMobs
+
+// operatorAbilities holds the abilities enabled for operators.
+const operatorAbilities = memberAbilities | protocol.AbilityOperatorCommands | protocol.AbilityTeleport
+
+// PermissionLevel returns the player permission level of the Role, which is one of the
+// packet.PermissionLevel constants.
+func (r Role) PermissionLevel() byte {
+	switch r {
+	case RoleOperator:
+		return packet.PermissionLevelOperator
+	case RoleMember:
+		return packet.PermissionLevelMember
+	default:
+		return packet.PermissionLevelVisitor
+	}
+}
+
+// CommandPermissionLevel returns the command permission level of the Role, which is one of the
+// packet.CommandPermissionLevel constants.
+func (r Role) CommandPermissionLevel() byte {
+	if r == RoleOperator {
+		return packet.CommandPermissionLevelGameDirectors
+	}
+	return packet.CommandPermissionLevelNormal
+}
+
+// Layer returns a LayerBuilder for a base layer setting all abilities, with those of the Role enabled and
+// the default fly and walk speeds. Abilities depending on the game mode of the player, such as
+// protocol.AbilityMayFly, may be enabled on the LayerBuilder returned.
+func (r Role) Layer() *LayerBuilder {
+	enabled := uint32(0)
+	switch r {
+	case RoleOperator:
+		enabled = operatorAbilities
+	case RoleMember:
+		enabled = memberAbilities
+	}
+	return NewLayer(protocol.AbilityLayerTypeBase).
+		Disable(All).
+		Enable(enabled).
+		FlySpeed(protocol.AbilityBaseFlySpeed).
+		WalkSpeed(protocol.AbilityBaseWalkSpeed)
+}
+
+// RoleOf returns the Role matching the player permission level passed, which is one of the
+// packet.PermissionLevel constants. The custom permission level is treated as a member.
+func RoleOf(permissionLevel byte) Role {
+	switch permissionLevel {
+	case packet.PermissionLevelVisitor:
+		return RoleVisitor
+	case packet.PermissionLevelOperator:
+		return RoleOperator
+	default:
+		return RoleMember
+	}
+}
+
+// Update returns an UpdateAbilities packet for the player with the entity unique ID passed, using the
+// permission levels of the Role passed. If no layers are passed, the base layer of the Role is used.
+func Update(entityUniqueID int64, r Role, layers ...protocol.AbilityLayer) *packet.UpdateAbilities {
+	if len(layers) == 0 {
+		layers = []protocol.AbilityLayer{r.Layer().Build()}
+	}
+	return &packet.UpdateAbilities{AbilityData: protocol.AbilityData{
+		EntityUniqueID:     entityUniqueID,
+		PlayerPermissions:  r.PermissionLevel(),
+		CommandPermissions: r.CommandPermissionLevel(),
+		Layers:             layers,
+	}}
+}
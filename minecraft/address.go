@@ -0,0 +1,58 @@
+package minecraft
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// interfaceAddress replaces the host of the address passed with an IP address of the network interface with
+// the name passed. An IPv6 address of the interface is used if the host of the address is an IPv6 address,
+// and an IPv4 address otherwise, falling back to an address of the other family if the interface has none.
+func interfaceAddress(name, address string) (string, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return "", fmt.Errorf("find interface: %w", err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return "", fmt.Errorf("list addresses of interface %v: %w", name, err)
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", err
+	}
+	preferV6 := isIPv6(host)
+
+	var fallback net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		if (ipNet.IP.To4() == nil) == preferV6 {
+			return net.JoinHostPort(ipNet.IP.String(), port), nil
+		}
+		if fallback == nil {
+			fallback = ipNet.IP
+		}
+	}
+	if fallback == nil {
+		return "", fmt.Errorf("interface %v has no usable addresses", name)
+	}
+	return net.JoinHostPort(fallback.String(), port), nil
+}
+
+// isIPv6 checks if the host passed, which may be surrounded by brackets, is an IPv6 address.
+func isIPv6(host string) bool {
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.To4() == nil
+}
+
+// joinHostPort joins the host and port passed into an address, like net.JoinHostPort. Unlike
+// net.JoinHostPort, it accepts IPv6 hosts that are already surrounded by brackets, as sent by some servers
+// in Transfer packets.
+func joinHostPort(host string, port int) string {
+	return net.JoinHostPort(strings.Trim(host, "[]"), strconv.Itoa(port))
+}
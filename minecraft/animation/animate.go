@@ -0,0 +1,92 @@
+package animation
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/molang"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// SwingArm returns an Animate packet that makes the entity passed swing its arm.
+func SwingArm(entityRuntimeID uint64) *packet.Animate {
+	return &packet.Animate{ActionType: packet.AnimateActionSwingArm, EntityRuntimeID: entityRuntimeID}
+}
+
+// StopSleep returns an Animate packet that makes the entity passed stop its sleeping animation.
+func StopSleep(entityRuntimeID uint64) *packet.Animate {
+	return &packet.Animate{ActionType: packet.AnimateActionStopSleep, EntityRuntimeID: entityRuntimeID}
+}
+
+// CriticalHit returns an Animate packet that shows critical hit particles around the entity passed. If
+// magic is true, the particles of a magic critical hit are shown instead, as for enchanted weapons.
+func CriticalHit(entityRuntimeID uint64, magic bool) *packet.Animate {
+	if magic {
+		return &packet.Animate{ActionType: packet.AnimateActionMagicCriticalHit, EntityRuntimeID: entityRuntimeID}
+	}
+	return &packet.Animate{ActionType: packet.AnimateActionCriticalHit, EntityRuntimeID: entityRuntimeID}
+}
+
+// Row returns an Animate packet that moves the left or right paddle of the boat that the entity passed is
+// rowing. The rowing time is the time in seconds that the paddle has been rowing for, which determines the
+// position of the paddle.
+func Row(entityRuntimeID uint64, left bool, rowingTime float32) *packet.Animate {
+	pk := &packet.Animate{ActionType: packet.AnimateActionRowRight, EntityRuntimeID: entityRuntimeID, BoatRowingTime: rowingTime}
+	if left {
+		pk.ActionType = packet.AnimateActionRowLeft
+	}
+	return pk
+}
+
+// EntityBuilder builds an AnimateEntity packet. An EntityBuilder is created using Entity. By default, the
+// animation stops once it finished playing.
+type EntityBuilder struct {
+	pk packet.AnimateEntity
+}
+
+// Entity returns an EntityBuilder that plays the animation passed, such as
+// 'animation.player.attack.rotations', on the entities passed.
+func Entity(animation string, entityRuntimeIDs ...uint64) *EntityBuilder {
+	return &EntityBuilder{pk: packet.AnimateEntity{
+		Animation:            animation,
+		StopCondition:        molang.Query("any_animation_finished"),
+		StopConditionVersion: molang.Version,
+		EntityRuntimeIDs:     entityRuntimeIDs,
+	}}
+}
+
+// Controller sets the animation controller used to play the animation and the state of the controller to
+// start in.
+func (b *EntityBuilder) Controller(controller, nextState string) *EntityBuilder {
+	b.pk.Controller, b.pk.NextState = controller, nextState
+	return b
+}
+
+// Until sets the MoLang expression that stops the animation once it evaluates to true, such as
+// 'query.is_moving'. The expression may be constructed using the molang package.
+func (b *EntityBuilder) Until(stopCondition string) *EntityBuilder {
+	b.pk.StopCondition = stopCondition
+	return b
+}
+
+// Loop makes the animation loop until a new animation is played on the entity, instead of stopping once it
+// finished playing.
+func (b *EntityBuilder) Loop() *EntityBuilder {
+	return b.Until(molang.Bool(false))
+}
+
+// BlendOut sets the time in seconds over which the animation is blended out once it stops.
+func (b *EntityBuilder) BlendOut(seconds float32) *EntityBuilder {
+	b.pk.BlendOutTime = seconds
+	return b
+}
+
+// Add adds entities to those that the animation is played on.
+func (b *EntityBuilder) Add(entityRuntimeIDs ...uint64) *EntityBuilder {
+	b.pk.EntityRuntimeIDs = append(b.pk.EntityRuntimeIDs, entityRuntimeIDs...)
+	return b
+}
+
+// Packet returns the AnimateEntity packet built.
+func (b *EntityBuilder) Packet() *packet.AnimateEntity {
+	pk := b.pk
+	pk.EntityRuntimeIDs = append([]uint64(nil), b.pk.EntityRuntimeIDs...)
+	return &pk
+}
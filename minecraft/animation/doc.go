@@ -0,0 +1,5 @@
+// Package animation implements helpers for constructing the Emote, Animate and AnimateEntity packets. It
+// holds the IDs of the default emotes available to every player, constructors for the emotes and arm
+// animations a player may perform, and an EntityBuilder for the AnimateEntity packet that takes care of the
+// MoLang stop condition and its version.
+package animation
@@ -0,0 +1,55 @@
+package animation
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// IDs of the emotes that every player has available by default, which may be used as the emote ID in the
+// Emote packet.
+const (
+	EmoteWave         = "4c8ae710-df2e-47cd-814d-cc7bf21a3d67"
+	EmoteSimpleClap   = "9a469a61-c83b-4ba9-b507-bdbe64430582"
+	EmoteOverThere    = "ce5c0300-7f03-455d-aaf1-352e4927b54d"
+	EmoteVictoryCheer = "d0c60245-538e-4ea2-bdd7-33477db5aa89"
+)
+
+// DefaultEmotes maps the names of the emotes that every player has available by default to their IDs.
+var DefaultEmotes = map[string]string{
+	"Wave":          EmoteWave,
+	"Simple Clap":   EmoteSimpleClap,
+	"Over There!":   EmoteOverThere,
+	"Victory Cheer": EmoteVictoryCheer,
+}
+
+// Emote returns an Emote packet to be sent by a client to perform the emote with the ID passed. The entity
+// runtime ID passed must be that of the player itself.
+func Emote(entityRuntimeID uint64, emoteID string) *packet.Emote {
+	return &packet.Emote{EntityRuntimeID: entityRuntimeID, EmoteID: emoteID}
+}
+
+// BroadcastEmote returns an Emote packet to be sent by a server to show the player with the entity runtime
+// ID passed performing the emote with the ID passed. The XUID and platform ID are those of the player
+// performing the emote. If muteChat is true, no chat message is shown for the emote.
+func BroadcastEmote(entityRuntimeID uint64, emoteID, xuid, platformID string, muteChat bool) *packet.Emote {
+	pk := &packet.Emote{
+		EntityRuntimeID: entityRuntimeID,
+		EmoteID:         emoteID,
+		XUID:            xuid,
+		PlatformID:      platformID,
+		Flags:           packet.EmoteFlagServerSide,
+	}
+	if muteChat {
+		pk.Flags |= packet.EmoteFlagMuteChat
+	}
+	return pk
+}
+
+// Equipped returns the IDs of the emotes held in the EmoteList packet passed, which a client sends with the
+// emotes it has equipped. These IDs may be used in the Emote packet in addition to the default emotes.
+func Equipped(pk *packet.EmoteList) []string {
+	ids := make([]string, len(pk.EmotePieces))
+	for i, id := range pk.EmotePieces {
+		ids[i] = id.String()
+	}
+	return ids
+}
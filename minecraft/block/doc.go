@@ -0,0 +1,5 @@
+// Package block implements a mapping between block runtime IDs, as sent in packets such as UpdateBlock and in
+// the chunks sent in LevelChunk and SubChunk packets, and the block states they represent. The block states
+// are typically loaded from the canonical block states of a Minecraft version, as found in
+// canonical_block_states.nbt, and may be mapped either to sequential runtime IDs or to hashed runtime IDs.
+package block
@@ -0,0 +1,206 @@
+package block
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"slices"
+	"sort"
+
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+// State is a block state: A block name combined with a value for each of the properties of the block.
+type State struct {
+	// Name is the name of the block, such as 'minecraft:stone'.
+	Name string
+	// Properties holds the values of the properties of the block, such as 'pillar_axis'. Values are of the
+	// type byte, int32 or string.
+	Properties map[string]any
+	// Version is the version of the block state format, as held in the canonical block states.
+	Version int32
+}
+
+// Registry maps block runtime IDs to block States and the other way around. A Registry is immutable once
+// created and is therefore safe for concurrent use.
+type Registry struct {
+	hashed bool
+	states []State
+	// indices maps the network hash of a State to its index in states.
+	indices map[uint32]int
+}
+
+// NewRegistry creates a Registry holding the States passed. If hashed is false, States are assigned
+// sequential runtime IDs the same way the client does: Sorted by the 64-bit FNV-1 hash of their names, with
+// the States of a single block in the order they were passed. If hashed is true, the runtime ID of a State is
+// its network hash, as returned by Hash. This is the case if the UseBlockNetworkIDHashes field of the
+// StartGame packet is true.
+func NewRegistry(states []State, hashed bool) *Registry {
+	states = slices.Clone(states)
+	if !hashed {
+		sort.SliceStable(states, func(i, j int) bool {
+			return states[i].Name != states[j].Name && nameHash(states[i].Name) < nameHash(states[j].Name)
+		})
+	}
+	r := &Registry{hashed: hashed, states: states, indices: make(map[uint32]int, len(states))}
+	for i, s := range states {
+		r.indices[Hash(s)] = i
+	}
+	return r
+}
+
+// LoadRegistry creates a Registry, as done by NewRegistry, from the canonical block states read from the
+// io.Reader passed. The canonical block states are a sequence of network NBT compounds holding the name,
+// states and version of each block state, such as the canonical_block_states.nbt file of a Minecraft
+// version.
+func LoadRegistry(r io.Reader, hashed bool) (*Registry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("load block states: %w", err)
+	}
+	var states []State
+	buf := bytes.NewBuffer(data)
+	for buf.Len() > 0 {
+		// A new decoder is used for every state, as network NBT decoders limit the total number of bytes read.
+		var m map[string]any
+		if err := nbt.NewDecoderWithEncoding(buf, nbt.NetworkLittleEndian).Decode(&m); err != nil {
+			return nil, fmt.Errorf("load block states: decode state %v: %w", len(states), err)
+		}
+		s, err := stateFromNBT(m)
+		if err != nil {
+			return nil, fmt.Errorf("load block states: state %v: %w", len(states), err)
+		}
+		states = append(states, s)
+	}
+	return NewRegistry(states, hashed), nil
+}
+
+// Hashed checks if the Registry uses hashed runtime IDs.
+func (r *Registry) Hashed() bool {
+	return r.hashed
+}
+
+// Len returns the number of States in the Registry.
+func (r *Registry) Len() int {
+	return len(r.states)
+}
+
+// State returns the State with the runtime ID passed. False is returned if no State with the runtime ID
+// exists.
+func (r *Registry) State(rid uint32) (State, bool) {
+	if r.hashed {
+		i, ok := r.indices[rid]
+		if !ok {
+			return State{}, false
+		}
+		return r.states[i], true
+	}
+	if int(rid) >= len(r.states) {
+		return State{}, false
+	}
+	return r.states[rid], true
+}
+
+// RuntimeID returns the runtime ID of the State passed. The Version of the State is ignored. False is
+// returned if the Registry does not hold the State.
+func (r *Registry) RuntimeID(s State) (uint32, bool) {
+	h := Hash(s)
+	i, ok := r.indices[h]
+	if !ok {
+		return 0, false
+	}
+	if r.hashed {
+		return h, true
+	}
+	return uint32(i), true
+}
+
+// Hash returns the network hash of the State passed: The 32-bit FNV-1a hash of the little endian NBT
+// encoding of a compound holding the name and properties of the State, with the properties sorted by name.
+// It is the runtime ID of the State if hashed runtime IDs are used.
+func Hash(s State) uint32 {
+	buf := bytes.NewBuffer(make([]byte, 0, 64))
+	buf.WriteByte(tagCompound)
+	writeString(buf, "")
+
+	buf.WriteByte(tagString)
+	writeString(buf, "name")
+	writeString(buf, s.Name)
+
+	buf.WriteByte(tagCompound)
+	writeString(buf, "states")
+	keys := make([]string, 0, len(s.Properties))
+	for k := range s.Properties {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		switch v := s.Properties[k].(type) {
+		case byte:
+			buf.WriteByte(tagByte)
+			writeString(buf, k)
+			buf.WriteByte(v)
+		case bool:
+			buf.WriteByte(tagByte)
+			writeString(buf, k)
+			if v {
+				buf.WriteByte(1)
+			} else {
+				buf.WriteByte(0)
+			}
+		case int32:
+			buf.WriteByte(tagInt)
+			writeString(buf, k)
+			_ = binary.Write(buf, binary.LittleEndian, v)
+		case string:
+			buf.WriteByte(tagString)
+			writeString(buf, k)
+			writeString(buf, v)
+		}
+	}
+	buf.WriteByte(tagEnd)
+	buf.WriteByte(tagEnd)
+
+	h := fnv.New32a()
+	_, _ = h.Write(buf.Bytes())
+	return h.Sum32()
+}
+
+// NBT tag types written by Hash.
+const (
+	tagEnd      = 0
+	tagByte     = 1
+	tagInt      = 3
+	tagString   = 8
+	tagCompound = 10
+)
+
+// writeString writes a string prefixed by its length as a little endian uint16, as done in little endian
+// NBT.
+func writeString(buf *bytes.Buffer, s string) {
+	_ = binary.Write(buf, binary.LittleEndian, uint16(len(s)))
+	buf.WriteString(s)
+}
+
+// nameHash returns the 64-bit FNV-1 hash of a block name, used to order the block states of a Registry.
+func nameHash(name string) uint64 {
+	h := fnv.New64()
+	_, _ = h.Write([]byte(name))
+	return h.Sum64()
+}
+
+// stateFromNBT converts a block state decoded from the canonical block states to a State.
+func stateFromNBT(m map[string]any) (State, error) {
+	name, ok := m["name"].(string)
+	if !ok {
+		return State{}, fmt.Errorf("expected string name, got %T", m["name"])
+	}
+	properties, _ := m["states"].(map[string]any)
+	if properties == nil {
+		properties = map[string]any{}
+	}
+	version, _ := m["version"].(int32)
+	return State{Name: name, Properties: properties, Version: version}, nil
+}
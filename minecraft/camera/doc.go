@@ -0,0 +1,4 @@
+// Package camera implements builders for the CameraPresets and CameraInstruction packets. Presets are
+// collected in a Presets list, which assigns each preset its index and validates the list, while the
+// instructions referring to those presets are created using Set, Fade and Clear.
+package camera
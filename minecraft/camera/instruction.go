@@ -0,0 +1,107 @@
+package camera
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// SetBuilder composes a CameraInstruction setting the camera to a preset. Its methods return the SetBuilder
+// so that calls may be chained.
+type SetBuilder struct {
+	set protocol.CameraInstructionSet
+}
+
+// Set returns a SetBuilder for an instruction setting the camera to the preset with the index passed, as
+// returned by Presets.Add.
+func Set(preset uint32) *SetBuilder {
+	return &SetBuilder{set: protocol.CameraInstructionSet{Preset: preset}}
+}
+
+// Ease makes the camera move to its new position and rotation using the easing type passed, which is one of
+// the protocol.EasingType constants, over the duration in seconds passed.
+func (b *SetBuilder) Ease(easingType uint8, duration float32) *SetBuilder {
+	b.set.Ease = protocol.Option(protocol.CameraEase{Type: easingType, Duration: duration})
+	return b
+}
+
+// Position sets the position of the camera.
+func (b *SetBuilder) Position(pos mgl32.Vec3) *SetBuilder {
+	b.set.Position = protocol.Option(pos)
+	return b
+}
+
+// Rotation sets the pitch and yaw of the camera.
+func (b *SetBuilder) Rotation(pitch, yaw float32) *SetBuilder {
+	b.set.Rotation = protocol.Option(mgl32.Vec2{pitch, yaw})
+	return b
+}
+
+// Facing makes the camera face the position passed for the duration of the instruction.
+func (b *SetBuilder) Facing(pos mgl32.Vec3) *SetBuilder {
+	b.set.Facing = protocol.Option(pos)
+	return b
+}
+
+// Default sets whether the camera is a default camera.
+func (b *SetBuilder) Default(def bool) *SetBuilder {
+	b.set.Default = protocol.Option(def)
+	return b
+}
+
+// Instruction returns the CameraInstruction packet composed.
+func (b *SetBuilder) Instruction() *packet.CameraInstruction {
+	return &packet.CameraInstruction{Set: protocol.Option(b.set)}
+}
+
+// Fade returns a CameraInstruction packet fading the screen to the colour passed over fadeIn seconds,
+// keeping it that colour for wait seconds and fading back over fadeOut seconds. Only the red, green and blue
+// components of the colour are used.
+func Fade(fadeIn, wait, fadeOut float32, colour color.RGBA) *packet.CameraInstruction {
+	return &packet.CameraInstruction{Fade: protocol.Option(protocol.CameraInstructionFade{
+		TimeData: protocol.Option(protocol.CameraFadeTimeData{FadeInDuration: fadeIn, WaitDuration: wait, FadeOutDuration: fadeOut}),
+		Colour:   protocol.Option(colour),
+	})}
+}
+
+// Clear returns a CameraInstruction packet clearing all camera instructions, returning the camera of the
+// player to normal.
+func Clear() *packet.CameraInstruction {
+	return &packet.CameraInstruction{Clear: protocol.Option(true)}
+}
+
+// Validate checks if the CameraInstruction packet passed is valid. It must hold at least one instruction,
+// any preset set must be an index into the Presets passed, easing must be of a known type with a
+// non-negative duration and fade durations must not be negative. If presets is nil, preset indices are not
+// checked.
+func Validate(pk *packet.CameraInstruction, presets *Presets) error {
+	set, hasSet := pk.Set.Value()
+	cleared, hasClear := pk.Clear.Value()
+	fade, hasFade := pk.Fade.Value()
+	if !hasSet && !hasFade && !(hasClear && cleared) {
+		return errors.New("camera instruction holds no instruction")
+	}
+	if hasSet {
+		if presets != nil && int(set.Preset) >= presets.Len() {
+			return fmt.Errorf("camera instruction set: preset index %v out of range for %v presets", set.Preset, presets.Len())
+		}
+		if ease, ok := set.Ease.Value(); ok {
+			if ease.Type > protocol.EasingTypeInOutElastic {
+				return fmt.Errorf("camera instruction set: unknown easing type %v", ease.Type)
+			}
+			if ease.Duration < 0 {
+				return fmt.Errorf("camera instruction set: negative easing duration %v", ease.Duration)
+			}
+		}
+	}
+	if hasFade {
+		if t, ok := fade.TimeData.Value(); ok && (t.FadeInDuration < 0 || t.WaitDuration < 0 || t.FadeOutDuration < 0) {
+			return fmt.Errorf("camera instruction fade: negative duration in %+v", t)
+		}
+	}
+	return nil
+}
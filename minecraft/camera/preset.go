@@ -0,0 +1,132 @@
+package camera
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+const (
+	PresetFree              = "minecraft:free"
+	PresetFirstPerson       = "minecraft:first_person"
+	PresetThirdPerson       = "minecraft:third_person"
+	PresetThirdPersonFront  = "minecraft:third_person_front"
+	PresetFollowOrbit       = "minecraft:follow_orbit"
+	PresetFixedBoom         = "minecraft:fixed_boom"
+	PresetControlSchemeView = "minecraft:control_scheme_view"
+)
+
+// builtin holds the names of the presets built into the client, which presets may use as parent without
+// them being present in the CameraPresets packet.
+var builtin = map[string]bool{
+	PresetFree: true, PresetFirstPerson: true, PresetThirdPerson: true, PresetThirdPersonFront: true,
+	PresetFollowOrbit: true, PresetFixedBoom: true, PresetControlSchemeView: true,
+}
+
+// PresetBuilder composes a protocol.CameraPreset. Its methods return the PresetBuilder so that calls may be
+// chained.
+type PresetBuilder struct {
+	p protocol.CameraPreset
+}
+
+// NewPreset returns a PresetBuilder for a preset with the name passed, such as 'example:cinematic', which
+// extends the parent preset passed, such as PresetFree. The parent may be empty.
+func NewPreset(name, parent string) *PresetBuilder {
+	return &PresetBuilder{p: protocol.CameraPreset{Name: name, Parent: parent}}
+}
+
+// Position sets the default position of the camera.
+func (b *PresetBuilder) Position(pos mgl32.Vec3) *PresetBuilder {
+	b.p.PosX, b.p.PosY, b.p.PosZ = protocol.Option(pos[0]), protocol.Option(pos[1]), protocol.Option(pos[2])
+	return b
+}
+
+// Rotation sets the default pitch and yaw of the camera.
+func (b *PresetBuilder) Rotation(pitch, yaw float32) *PresetBuilder {
+	b.p.RotX, b.p.RotY = protocol.Option(pitch), protocol.Option(yaw)
+	return b
+}
+
+// AudioListener sets where audio is heard from when the preset is used. It is protocol.AudioListenerCamera
+// or protocol.AudioListenerPlayer.
+func (b *PresetBuilder) AudioListener(listener byte) *PresetBuilder {
+	b.p.AudioListener = protocol.Option(listener)
+	return b
+}
+
+// PlayerEffects sets the PlayerEffects field of the preset.
+func (b *PresetBuilder) PlayerEffects(effects bool) *PresetBuilder {
+	b.p.PlayerEffects = protocol.Option(effects)
+	return b
+}
+
+// Build returns the protocol.CameraPreset composed.
+func (b *PresetBuilder) Build() protocol.CameraPreset {
+	return b.p
+}
+
+// Presets is a list of camera presets sent in a CameraPresets packet. Instructions refer to presets by their
+// index in the list, which is returned by Add and Index.
+type Presets struct {
+	presets []protocol.CameraPreset
+	indices map[string]uint32
+}
+
+// Add adds the preset passed to the Presets and returns its index. An error is returned if a preset with
+// the same name was already added, or if the preset is invalid.
+func (p *Presets) Add(preset protocol.CameraPreset) (uint32, error) {
+	if p.indices == nil {
+		p.indices = make(map[string]uint32)
+	}
+	if _, ok := p.indices[preset.Name]; ok {
+		return 0, fmt.Errorf("add camera preset: duplicate preset %q", preset.Name)
+	}
+	if err := p.validate(preset); err != nil {
+		return 0, fmt.Errorf("add camera preset %q: %w", preset.Name, err)
+	}
+	p.indices[preset.Name] = uint32(len(p.presets))
+	p.presets = append(p.presets, preset)
+	return p.indices[preset.Name], nil
+}
+
+// Index returns the index of the preset with the name passed. False is returned if no such preset was
+// added.
+func (p *Presets) Index(name string) (uint32, bool) {
+	i, ok := p.indices[name]
+	return i, ok
+}
+
+// Len returns the number of presets added.
+func (p *Presets) Len() int {
+	return len(p.presets)
+}
+
+// Packet returns a CameraPresets packet holding the presets added.
+func (p *Presets) Packet() *packet.CameraPresets {
+	return &packet.CameraPresets{Presets: append([]protocol.CameraPreset(nil), p.presets...)}
+}
+
+// validate checks if the preset passed is valid, given the presets already added.
+func (p *Presets) validate(preset protocol.CameraPreset) error {
+	if preset.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	if preset.Parent != "" && !builtin[preset.Parent] {
+		if _, ok := p.indices[preset.Parent]; !ok {
+			return fmt.Errorf("unknown parent preset %q", preset.Parent)
+		}
+	}
+	if listener, ok := preset.AudioListener.Value(); ok && listener != protocol.AudioListenerCamera && listener != protocol.AudioListenerPlayer {
+		return fmt.Errorf("unknown audio listener %v", listener)
+	}
+	_, x := preset.PosX.Value()
+	_, y := preset.PosY.Value()
+	_, z := preset.PosZ.Value()
+	if x != y || y != z {
+		return errors.New("position must have either all or none of its components set")
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package minecraft
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// captureMagic is written at the start of every capture to identify the format.
+var captureMagic = []byte("GTCAPTURE")
+
+// captureVersion is the version of the capture format written by a CaptureWriter.
+const captureVersion = 1
+
+// CapturedPacket is a single packet found in a capture, as written by a CaptureWriter.
+type CapturedPacket struct {
+	// Direction is the direction that the packet travelled in over the Conn that was captured.
+	Direction Direction
+	// Time is the time at which the packet was read or written.
+	Time time.Time
+	// Data holds the raw data of the packet, including the packet header.
+	Data []byte
+}
+
+// CaptureWriter writes packets read from and written to a Conn to an io.Writer in a simple binary format. A
+// capture starts with a header holding a magic string and version, followed by records that each consist of
+// a direction byte, a Unix timestamp in nanoseconds (int64, little endian), the length of the packet data
+// (uint32, little endian) and the packet data itself. CaptureWriter is safe for concurrent use.
+type CaptureWriter struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+	c  io.Writer
+}
+
+// NewCaptureWriter returns a CaptureWriter that writes a capture to the io.Writer passed. The header of the
+// capture is written immediately.
+func NewCaptureWriter(w io.Writer) (*CaptureWriter, error) {
+	cw := &CaptureWriter{w: bufio.NewWriter(w), c: w}
+	_, _ = cw.w.Write(captureMagic)
+	_ = cw.w.WriteByte(captureVersion)
+	if err := cw.w.Flush(); err != nil {
+		return nil, fmt.Errorf("write capture header: %w", err)
+	}
+	return cw, nil
+}
+
+// WritePacket writes a record of the packet data passed to the capture.
+func (w *CaptureWriter) WritePacket(dir Direction, t time.Time, data []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var hdr [13]byte
+	hdr[0] = byte(dir)
+	binary.LittleEndian.PutUint64(hdr[1:], uint64(t.UnixNano()))
+	binary.LittleEndian.PutUint32(hdr[9:], uint32(len(data)))
+	_, _ = w.w.Write(hdr[:])
+	if _, err := w.w.Write(data); err != nil {
+		return fmt.Errorf("write captured packet: %w", err)
+	}
+	return nil
+}
+
+// Flush flushes all records buffered to the underlying io.Writer.
+func (w *CaptureWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Flush()
+}
+
+// Close flushes all buffered records and closes the underlying io.Writer if it implements io.Closer.
+func (w *CaptureWriter) Close() error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if c, ok := w.c.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// CaptureReader reads packets from a capture written by a CaptureWriter.
+type CaptureReader struct {
+	r *bufio.Reader
+}
+
+// NewCaptureReader returns a CaptureReader that reads a capture from the io.Reader passed. An error is
+// returned if the io.Reader does not start with a valid capture header.
+func NewCaptureReader(r io.Reader) (*CaptureReader, error) {
+	cr := &CaptureReader{r: bufio.NewReader(r)}
+	hdr := make([]byte, len(captureMagic)+1)
+	if _, err := io.ReadFull(cr.r, hdr); err != nil {
+		return nil, fmt.Errorf("read capture header: %w", err)
+	}
+	if !bytes.Equal(hdr[:len(captureMagic)], captureMagic) {
+		return nil, fmt.Errorf("read capture header: invalid magic %q", hdr[:len(captureMagic)])
+	}
+	if v := hdr[len(captureMagic)]; v != captureVersion {
+		return nil, fmt.Errorf("read capture header: unsupported version %v", v)
+	}
+	return cr, nil
+}
+
+// ReadPacket reads the next packet from the capture. io.EOF is returned if the end of the capture was
+// reached.
+func (r *CaptureReader) ReadPacket() (CapturedPacket, error) {
+	var hdr [13]byte
+	if _, err := io.ReadFull(r.r, hdr[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return CapturedPacket{}, fmt.Errorf("read captured packet: %w", err)
+		}
+		return CapturedPacket{}, err
+	}
+	pk := CapturedPacket{
+		Direction: Direction(hdr[0]),
+		Time:      time.Unix(0, int64(binary.LittleEndian.Uint64(hdr[1:]))),
+		Data:      make([]byte, binary.LittleEndian.Uint32(hdr[9:])),
+	}
+	if _, err := io.ReadFull(r.r, pk.Data); err != nil {
+		return CapturedPacket{}, fmt.Errorf("read captured packet: %w", err)
+	}
+	return pk, nil
+}
+
+// capture records the packet data passed to the capture of the Conn, if any.
+func (conn *Conn) capture(dir Direction, data []byte) {
+	if conn.captureWriter == nil {
+		return
+	}
+	if err := conn.captureWriter.WritePacket(dir, time.Now(), data); err != nil {
+		conn.log.Printf("capture packet: %v\n", err)
+	}
+}
@@ -0,0 +1,86 @@
+package chunk
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// DecodeBiomes decodes the biome section of a chunk with the Range passed, in network encoding. This section
+// is part of the RawPayload of the LevelChunk packet, or is sent as the last blob of a chunk if the blob cache
+// is enabled. A PalettedStorage holding the biome ID of each position is returned for each sub-chunk in the
+// Range, from the bottom of the Range to the top.
+func DecodeBiomes(data []byte, r Range) ([]*PalettedStorage, error) {
+	return decodeBiomes(bytes.NewBuffer(data), r)
+}
+
+// EncodeBiomes encodes the biome storages passed, one for each sub-chunk, into a biome section in network
+// encoding. Storages that are equal to the storage below them are encoded as copies of that storage.
+func EncodeBiomes(biomes []*PalettedStorage) []byte {
+	buf := bytes.NewBuffer(nil)
+	encodeBiomes(buf, biomes)
+	return buf.Bytes()
+}
+
+// Biome returns the biome ID at the position passed. x and z must be in the range 0-15, while y is the Y
+// coordinate in the Range of the chunk. 0 is returned if the chunk has no biomes for the Y coordinate.
+func (c *Chunk) Biome(x byte, y int, z byte) uint32 {
+	i := (y - c.Range.Min()) >> 4
+	if i < 0 || i >= len(c.Biomes) || c.Biomes[i] == nil {
+		return 0
+	}
+	return c.Biomes[i].At(x, byte(y), z)
+}
+
+// SetBiome sets the biome ID at the position passed. x and z must be in the range 0-15, while y is the Y
+// coordinate in the Range of the chunk.
+func (c *Chunk) SetBiome(x byte, y int, z byte, biome uint32) {
+	i := (y - c.Range.Min()) >> 4
+	if i < 0 || i >= c.Range.SubChunks() {
+		return
+	}
+	if len(c.Biomes) != c.Range.SubChunks() {
+		c.Biomes = append(c.Biomes, make([]*PalettedStorage, c.Range.SubChunks()-len(c.Biomes))...)
+	}
+	if c.Biomes[i] == nil {
+		c.Biomes[i] = NewPalettedStorage(0)
+	}
+	c.Biomes[i].Set(x, byte(y), z, biome)
+}
+
+// decodeBiomes decodes a biome storage for each sub-chunk in the Range passed from the buffer. Storages that
+// are encoded as a copy of the storage below are returned as that same storage.
+func decodeBiomes(buf *bytes.Buffer, r Range) ([]*PalettedStorage, error) {
+	biomes := make([]*PalettedStorage, r.SubChunks())
+	for i := range biomes {
+		s, err := decodePalettedStorage(buf)
+		if err != nil {
+			return nil, fmt.Errorf("decode biomes of sub-chunk %v: %w", i, err)
+		}
+		if s == nil {
+			if i == 0 {
+				return nil, fmt.Errorf("decode biomes of sub-chunk 0: storage refers to previous storage")
+			}
+			s = biomes[i-1]
+		}
+		biomes[i] = s
+	}
+	return biomes, nil
+}
+
+// encodeBiomes writes the biome storages passed to the buffer. Storages that are nil are encoded as biome ID
+// 0, and storages that are the same as the storage below as a copy of it.
+func encodeBiomes(buf *bytes.Buffer, biomes []*PalettedStorage) {
+	var prev *PalettedStorage
+	for i, s := range biomes {
+		if s == nil {
+			s = NewPalettedStorage(0)
+		}
+		if i > 0 && s.equals(prev) {
+			// A storage header of 0x7f bits per index copies the storage of the sub-chunk below.
+			buf.WriteByte(0x7f<<1 | 1)
+			continue
+		}
+		s.encode(buf)
+		prev = s
+	}
+}
@@ -0,0 +1,190 @@
+package chunk
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Range is the range of Y coordinates of the blocks in a chunk, from the lowest to the highest Y coordinate.
+type Range [2]int
+
+var (
+	// OverworldRange is the Range of chunks in the overworld.
+	OverworldRange = Range{-64, 319}
+	// NetherRange is the Range of chunks in the nether.
+	NetherRange = Range{0, 127}
+	// EndRange is the Range of chunks in the end.
+	EndRange = Range{0, 255}
+)
+
+// DimensionRange returns the Range of chunks in the dimension passed, such as packet.DimensionNether.
+// OverworldRange is returned for unknown dimensions.
+func DimensionRange(dim int32) Range {
+	switch dim {
+	case packet.DimensionNether:
+		return NetherRange
+	case packet.DimensionEnd:
+		return EndRange
+	default:
+		return OverworldRange
+	}
+}
+
+// Min returns the lowest Y coordinate of the Range.
+func (r Range) Min() int {
+	return r[0]
+}
+
+// Max returns the highest Y coordinate of the Range.
+func (r Range) Max() int {
+	return r[1]
+}
+
+// SubChunks returns the number of sub-chunks in a chunk with the Range.
+func (r Range) SubChunks() int {
+	return (r[1] - r[0] + 1) >> 4
+}
+
+// Chunk is a 16 blocks wide column of sub-chunks, as sent in the LevelChunk packet.
+type Chunk struct {
+	// Range is the Range of the Y coordinates of the blocks in the chunk.
+	Range Range
+	// SubChunks holds the sub-chunks of the chunk, from the bottom of the Range to the top. Sub-chunks that
+	// were not sent, or that are sent separately using SubChunk packets, are nil.
+	SubChunks []*SubChunk
+	// Biomes holds a storage of the biome ID of each position for each sub-chunk, from the bottom of the
+	// Range to the top. It is empty if the biomes are not sent in the LevelChunk packet, which is the case if
+	// the blob cache is enabled: The biomes are then sent as the last blob, which may be decoded using
+	// DecodeBiomes. Sub-chunks with the same biomes may share a storage.
+	Biomes []*PalettedStorage
+	// BorderBlocks holds the border blocks of the chunk. It is generally empty.
+	BorderBlocks []byte
+	// BlockEntities holds the NBT data of the block entities in the chunk.
+	BlockEntities []map[string]any
+}
+
+// New returns an empty Chunk with the Range passed.
+func New(r Range) *Chunk {
+	return &Chunk{Range: r, SubChunks: make([]*SubChunk, r.SubChunks())}
+}
+
+// SubChunk returns the sub-chunk that holds the Y coordinate passed, or nil if the chunk does not have it.
+func (c *Chunk) SubChunk(y int) *SubChunk {
+	i := (y - c.Range.Min()) >> 4
+	if i < 0 || i >= len(c.SubChunks) {
+		return nil
+	}
+	return c.SubChunks[i]
+}
+
+// Block returns the runtime ID of the block at the position passed in the layer passed. x and z must be in
+// the range 0-15, while y is the Y coordinate in the Range of the chunk. If the chunk does not have the
+// sub-chunk or layer, airRID is returned.
+func (c *Chunk) Block(x byte, y int, z byte, layer int, airRID uint32) uint32 {
+	s := c.SubChunk(y)
+	if s == nil {
+		return airRID
+	}
+	return s.Block(x, byte(y), z, layer, airRID)
+}
+
+// SetBlock sets the runtime ID of the block at the position passed in the layer passed. x and z must be in
+// the range 0-15, while y is the Y coordinate in the Range of the chunk. Sub-chunks and layers that do not yet
+// exist are created, filled with air.
+func (c *Chunk) SetBlock(x byte, y int, z byte, layer int, rid, airRID uint32) {
+	i := (y - c.Range.Min()) >> 4
+	if i < 0 || i >= len(c.SubChunks) {
+		return
+	}
+	if c.SubChunks[i] == nil {
+		c.SubChunks[i] = &SubChunk{Index: int8((c.Range.Min() >> 4) + i)}
+	}
+	c.SubChunks[i].SetBlock(x, byte(y), z, layer, rid, airRID)
+}
+
+// DecodeLevelChunk decodes the RawPayload of the LevelChunk packet passed into a Chunk with the Range of the
+// dimension of the packet. If the packet enables the blob cache, the sub-chunks and biomes are sent as blobs
+// rather than in the payload, and if it requests sub-chunks using the SubChunkRequest packet, the sub-chunks
+// are sent in SubChunk packets. These sub-chunks may be decoded using DecodeSubChunk.
+func DecodeLevelChunk(pk *packet.LevelChunk) (*Chunk, error) {
+	return DecodeLevelChunkRange(pk, DimensionRange(pk.Dimension))
+}
+
+// DecodeLevelChunkRange decodes the RawPayload of the LevelChunk packet passed like DecodeLevelChunk, but
+// into a Chunk with the Range passed. It should be used for dimensions of which the Range was changed using
+// the DimensionData packet, which may be obtained using Ranges.
+func DecodeLevelChunkRange(pk *packet.LevelChunk, r Range) (*Chunk, error) {
+	c := New(r)
+	buf := bytes.NewBuffer(pk.RawPayload)
+	requested := pk.SubChunkCount == protocol.SubChunkRequestModeLimited || pk.SubChunkCount == protocol.SubChunkRequestModeLimitless
+
+	if !pk.CacheEnabled {
+		if !requested {
+			if int(pk.SubChunkCount) > len(c.SubChunks) {
+				return nil, fmt.Errorf("decode level chunk: %v sub-chunks exceeds %v sub-chunks in range", pk.SubChunkCount, len(c.SubChunks))
+			}
+			for i := range int(pk.SubChunkCount) {
+				s, err := decodeSubChunk(buf, int8((c.Range.Min()>>4)+i))
+				if err != nil {
+					return nil, fmt.Errorf("decode level chunk: sub-chunk %v: %w", i, err)
+				}
+				index := int(s.Index) - c.Range.Min()>>4
+				if index < 0 || index >= len(c.SubChunks) {
+					return nil, fmt.Errorf("decode level chunk: sub-chunk index %v out of range", s.Index)
+				}
+				c.SubChunks[index] = s
+			}
+		}
+		biomes, err := decodeBiomes(buf, c.Range)
+		if err != nil {
+			return nil, fmt.Errorf("decode level chunk: %w", err)
+		}
+		c.Biomes = biomes
+	}
+
+	n, err := buf.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("decode level chunk: read border block count: %w", err)
+	}
+	if c.BorderBlocks = buf.Next(int(n)); len(c.BorderBlocks) != int(n) {
+		return nil, fmt.Errorf("decode level chunk: expected %v border blocks, got %v", n, len(c.BorderBlocks))
+	}
+	if c.BlockEntities, err = decodeBlockEntities(buf); err != nil {
+		return nil, fmt.Errorf("decode level chunk: %w", err)
+	}
+	return c, nil
+}
+
+// LevelChunk encodes the Chunk into a LevelChunk packet at the position and in the dimension passed, with the
+// blob cache disabled. All sub-chunks up to the highest sub-chunk present are sent, with missing sub-chunks
+// sent as empty sub-chunks. Sub-chunks without biomes are sent with biome ID 0.
+func (c *Chunk) LevelChunk(pos protocol.ChunkPos, dim int32) (*packet.LevelChunk, error) {
+	count := len(c.SubChunks)
+	for count > 0 && c.SubChunks[count-1] == nil {
+		count--
+	}
+	buf := bytes.NewBuffer(nil)
+	for i, s := range c.SubChunks[:count] {
+		if s == nil {
+			s = &SubChunk{Index: int8((c.Range.Min() >> 4) + i)}
+		}
+		s.encode(buf)
+	}
+	biomes := make([]*PalettedStorage, c.Range.SubChunks())
+	copy(biomes, c.Biomes)
+	encodeBiomes(buf, biomes)
+	buf.WriteByte(byte(len(c.BorderBlocks)))
+	buf.Write(c.BorderBlocks)
+	if err := encodeBlockEntities(buf, c.BlockEntities); err != nil {
+		return nil, fmt.Errorf("encode level chunk: %w", err)
+	}
+	return &packet.LevelChunk{
+		Position:      pos,
+		Dimension:     dim,
+		SubChunkCount: uint32(count),
+		RawPayload:    buf.Bytes(),
+	}, nil
+}
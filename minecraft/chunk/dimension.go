@@ -0,0 +1,112 @@
+package chunk
+
+import (
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Names of the dimensions that may be defined in the DimensionData packet. Currently, the client only
+// applies definitions of the overworld.
+const (
+	DimensionNameOverworld = "minecraft:overworld"
+	DimensionNameNether    = "minecraft:nether"
+	DimensionNameEnd       = "minecraft:the_end"
+)
+
+// dimensionIDs maps the names of dimensions to their IDs, such as packet.DimensionNether.
+var dimensionIDs = map[string]int32{
+	DimensionNameOverworld: packet.DimensionOverworld,
+	DimensionNameNether:    packet.DimensionNether,
+	DimensionNameEnd:       packet.DimensionEnd,
+}
+
+// Validate checks if the Range may be used for a chunk. The lowest Y coordinate must be at the bottom of a
+// sub-chunk and the highest Y coordinate at the top of one, and the index of every sub-chunk in the Range
+// must fit in the signed byte that sub-chunk indices are encoded as.
+func (r Range) Validate() error {
+	switch {
+	case r.Max() < r.Min():
+		return fmt.Errorf("range %v: maximum %v is below minimum %v", r, r.Max(), r.Min())
+	case r.Min()&15 != 0:
+		return fmt.Errorf("range %v: minimum %v is not a multiple of 16", r, r.Min())
+	case (r.Max()+1)&15 != 0:
+		return fmt.Errorf("range %v: maximum %v is not one below a multiple of 16", r, r.Max())
+	case r.Min()>>4 < -128 || r.Max()>>4 > 127:
+		return fmt.Errorf("range %v: sub-chunk indices %v to %v do not fit in a signed byte", r, r.Min()>>4, r.Max()>>4)
+	}
+	return nil
+}
+
+// Definition returns a protocol.DimensionDefinition for the dimension with the name passed, such as
+// DimensionNameOverworld, with the Range and generator passed. The generator is one of the protocol.Generator
+// constants. Unlike the Range, the height range of the definition holds the Y coordinate above the highest
+// block. An error is returned if the Range is not valid.
+func Definition(name string, r Range, generator int32) (protocol.DimensionDefinition, error) {
+	if err := r.Validate(); err != nil {
+		return protocol.DimensionDefinition{}, fmt.Errorf("dimension %v: %w", name, err)
+	}
+	if generator < protocol.GeneratorLegacy || generator > protocol.GeneratorVoid {
+		return protocol.DimensionDefinition{}, fmt.Errorf("dimension %v: unknown generator %v", name, generator)
+	}
+	return protocol.DimensionDefinition{Name: name, Range: [2]int32{int32(r.Min()), int32(r.Max() + 1)}, Generator: generator}, nil
+}
+
+// DefinitionRange returns the Range of the protocol.DimensionDefinition passed. An error is returned if the
+// height range of the definition is not valid for a chunk.
+func DefinitionRange(def protocol.DimensionDefinition) (Range, error) {
+	r := Range{int(def.Range[0]), int(def.Range[1]) - 1}
+	if err := r.Validate(); err != nil {
+		return Range{}, fmt.Errorf("dimension %v: %w", def.Name, err)
+	}
+	return r, nil
+}
+
+// Ranges holds the Range of each dimension, indexed by dimension ID. It may be used to decode chunks of
+// dimensions of which the Range was changed using the DimensionData packet.
+type Ranges map[int32]Range
+
+// RangesOf returns the Ranges of the dimensions defined in the DimensionData packet passed. Definitions of
+// dimensions not present in the packet keep their default Range. An error is returned if the packet holds a
+// definition of an unknown dimension or a definition with an invalid height range.
+func RangesOf(pk *packet.DimensionData) (Ranges, error) {
+	ranges := make(Ranges, len(pk.Definitions))
+	for _, def := range pk.Definitions {
+		id, ok := dimensionIDs[def.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown dimension %v", def.Name)
+		}
+		r, err := DefinitionRange(def)
+		if err != nil {
+			return nil, err
+		}
+		ranges[id] = r
+	}
+	return ranges, nil
+}
+
+// Range returns the Range of the dimension passed, or the default Range of the dimension as returned by
+// DimensionRange if the Ranges do not hold it.
+func (r Ranges) Range(dim int32) Range {
+	if rng, ok := r[dim]; ok {
+		return rng
+	}
+	return DimensionRange(dim)
+}
+
+// DimensionData returns a DimensionData packet holding the definitions passed. An error is returned if any
+// definition has an invalid height range or if a dimension is defined twice.
+func DimensionData(definitions ...protocol.DimensionDefinition) (*packet.DimensionData, error) {
+	seen := make(map[string]struct{}, len(definitions))
+	for _, def := range definitions {
+		if _, ok := seen[def.Name]; ok {
+			return nil, fmt.Errorf("dimension %v defined twice", def.Name)
+		}
+		seen[def.Name] = struct{}{}
+		if _, err := DefinitionRange(def); err != nil {
+			return nil, err
+		}
+	}
+	return &packet.DimensionData{Definitions: definitions}, nil
+}
@@ -0,0 +1,5 @@
+// Package chunk implements decoding and encoding of the chunk data sent over network in the LevelChunk and
+// SubChunk packets. It decodes the sub-chunks, with their layers of paletted block storages holding block
+// runtime IDs, the border blocks and the block entities of a chunk into Go structures, and encodes these
+// structures back into the format expected by the client.
+package chunk
@@ -0,0 +1,199 @@
+package chunk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// paletteSizes holds all numbers of bits per index that a PalettedStorage may use, in ascending order.
+var paletteSizes = [...]byte{0, 1, 2, 3, 4, 5, 6, 8, 16}
+
+// PalettedStorage is a storage of 4096 values, one for each position in a 16x16x16 sub-chunk. Rather than
+// storing the values directly, a PalettedStorage holds a palette of the distinct values present and, for each
+// position, the index of its value in the palette, packed using as few bits as possible. For block storages,
+// the values are block runtime IDs.
+type PalettedStorage struct {
+	bitsPerIndex byte
+	indices      []uint32
+	palette      []uint32
+}
+
+// NewPalettedStorage returns a PalettedStorage with all 4096 positions set to the value passed.
+func NewPalettedStorage(v uint32) *PalettedStorage {
+	return &PalettedStorage{palette: []uint32{v}}
+}
+
+// At returns the value at the position passed. x, y and z must be in the range 0-15.
+func (s *PalettedStorage) At(x, y, z byte) uint32 {
+	return s.palette[s.index(position(x, y, z))]
+}
+
+// Set sets the value at the position passed. x, y and z must be in the range 0-15. The palette of the
+// PalettedStorage grows as needed.
+func (s *PalettedStorage) Set(x, y, z byte, v uint32) {
+	i := slices.Index(s.palette, v)
+	if i == -1 {
+		i = len(s.palette)
+		s.palette = append(s.palette, v)
+		if i >= 1<<s.bitsPerIndex {
+			s.resize(bitsFor(len(s.palette)))
+		}
+	}
+	s.setIndex(position(x, y, z), uint32(i))
+}
+
+// Palette returns the palette of the PalettedStorage: The distinct values that the positions may hold. The
+// slice returned must not be modified.
+func (s *PalettedStorage) Palette() []uint32 {
+	return s.palette
+}
+
+// Uniform checks if all positions in the PalettedStorage may hold only a single value, returning that value
+// if so.
+func (s *PalettedStorage) Uniform() (uint32, bool) {
+	if len(s.palette) == 1 {
+		return s.palette[0], true
+	}
+	return 0, false
+}
+
+// equals checks if the PalettedStorage holds the same value as the PalettedStorage passed at all positions.
+func (s *PalettedStorage) equals(o *PalettedStorage) bool {
+	if s == o {
+		return true
+	}
+	for pos := range uint16(4096) {
+		if s.palette[s.index(pos)] != o.palette[o.index(pos)] {
+			return false
+		}
+	}
+	return true
+}
+
+// position returns the index of the position passed in a PalettedStorage.
+func position(x, y, z byte) uint16 {
+	return uint16(x&15)<<8 | uint16(z&15)<<4 | uint16(y&15)
+}
+
+// index returns the palette index stored for the position passed.
+func (s *PalettedStorage) index(pos uint16) uint32 {
+	if s.bitsPerIndex == 0 {
+		return 0
+	}
+	perWord := uint16(32 / s.bitsPerIndex)
+	offset := (pos % perWord) * uint16(s.bitsPerIndex)
+	return (s.indices[pos/perWord] >> offset) & (1<<s.bitsPerIndex - 1)
+}
+
+// setIndex sets the palette index stored for the position passed.
+func (s *PalettedStorage) setIndex(pos uint16, i uint32) {
+	if s.bitsPerIndex == 0 {
+		return
+	}
+	perWord := uint16(32 / s.bitsPerIndex)
+	offset := (pos % perWord) * uint16(s.bitsPerIndex)
+	mask := uint32(1<<s.bitsPerIndex-1) << offset
+	s.indices[pos/perWord] = s.indices[pos/perWord]&^mask | i<<offset
+}
+
+// resize changes the number of bits used per index of the PalettedStorage, re-packing all indices.
+func (s *PalettedStorage) resize(bits byte) {
+	resized := &PalettedStorage{bitsPerIndex: bits, indices: make([]uint32, wordCount(bits)), palette: s.palette}
+	for pos := range uint16(4096) {
+		resized.setIndex(pos, s.index(pos))
+	}
+	*s = *resized
+}
+
+// bitsFor returns the lowest number of bits per index that can index a palette of n values.
+func bitsFor(n int) byte {
+	for _, size := range paletteSizes {
+		if n <= 1<<size {
+			return size
+		}
+	}
+	return paletteSizes[len(paletteSizes)-1]
+}
+
+// wordCount returns the number of uint32s needed to store 4096 indices of the number of bits passed.
+func wordCount(bits byte) int {
+	if bits == 0 {
+		return 0
+	}
+	perWord := 32 / int(bits)
+	return (4096 + perWord - 1) / perWord
+}
+
+// decodePalettedStorage decodes a PalettedStorage in network encoding from the buffer passed. If the header
+// of the storage indicates it is a copy of the previous storage, as is the case for biome storages, nil is
+// returned.
+func decodePalettedStorage(buf *bytes.Buffer) (*PalettedStorage, error) {
+	header, err := buf.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("read storage header: %w", err)
+	}
+	bits := header >> 1
+	if bits == 0x7f {
+		return nil, nil
+	}
+	if header&1 == 0 {
+		return nil, fmt.Errorf("storage uses persistent palette, expected runtime IDs")
+	}
+	if !slices.Contains(paletteSizes[:], bits) {
+		return nil, fmt.Errorf("invalid bits per index %v", bits)
+	}
+	s := &PalettedStorage{bitsPerIndex: bits, indices: make([]uint32, wordCount(bits))}
+	data := buf.Next(len(s.indices) * 4)
+	if len(data) != len(s.indices)*4 {
+		return nil, fmt.Errorf("read storage indices: expected %v bytes, got %v", len(s.indices)*4, len(data))
+	}
+	for i := range s.indices {
+		s.indices[i] = binary.LittleEndian.Uint32(data[i*4:])
+	}
+
+	paletteLen := int32(1)
+	if bits != 0 {
+		if err := protocol.Varint32(buf, &paletteLen); err != nil {
+			return nil, fmt.Errorf("read palette length: %w", err)
+		}
+		if paletteLen <= 0 || paletteLen > 4096 {
+			return nil, fmt.Errorf("invalid palette length %v", paletteLen)
+		}
+	}
+	s.palette = make([]uint32, paletteLen)
+	for i := range s.palette {
+		var v int32
+		if err := protocol.Varint32(buf, &v); err != nil {
+			return nil, fmt.Errorf("read palette value: %w", err)
+		}
+		s.palette[i] = uint32(v)
+	}
+	for pos := range uint16(4096) {
+		if int(s.index(pos)) >= len(s.palette) {
+			return nil, fmt.Errorf("palette index %v out of range for palette of length %v", s.index(pos), len(s.palette))
+		}
+	}
+	return s, nil
+}
+
+// encode writes the PalettedStorage in network encoding to the buffer passed. The smallest number of bits per
+// index able to index the palette is used.
+func (s *PalettedStorage) encode(buf *bytes.Buffer) {
+	if bits := bitsFor(len(s.palette)); bits != s.bitsPerIndex {
+		s.resize(bits)
+	}
+	buf.WriteByte(s.bitsPerIndex<<1 | 1)
+	for _, word := range s.indices {
+		_ = binary.Write(buf, binary.LittleEndian, word)
+	}
+	if s.bitsPerIndex != 0 {
+		_ = protocol.WriteVarint32(buf, int32(len(s.palette)))
+	}
+	for _, v := range s.palette {
+		_ = protocol.WriteVarint32(buf, int32(v))
+	}
+}
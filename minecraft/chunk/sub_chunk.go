@@ -0,0 +1,143 @@
+package chunk
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+const (
+	// SubChunkVersion is the version of the sub-chunk format written by EncodeSubChunk. This version writes
+	// the Y index of the sub-chunk along with its layers.
+	SubChunkVersion = 9
+)
+
+// SubChunk is a 16x16x16 section of a chunk. It holds one or more layers of blocks: The first layer holds the
+// regular blocks, while the second typically holds liquids that are in the same position as a block, such as
+// water in a waterlogged block.
+type SubChunk struct {
+	// Index is the Y index of the sub-chunk: The Y coordinate of its lowest blocks, right-shifted four bits.
+	// It is only encoded in sub-chunks of version 9, and is otherwise derived from the position of the
+	// sub-chunk in a chunk.
+	Index int8
+	// Layers holds the block storages of the sub-chunk, each holding the block runtime ID of each position.
+	Layers []*PalettedStorage
+}
+
+// Block returns the runtime ID of the block at the position passed in the layer passed. If the sub-chunk
+// does not have the layer, airRID is returned.
+func (s *SubChunk) Block(x, y, z byte, layer int, airRID uint32) uint32 {
+	if layer >= len(s.Layers) {
+		return airRID
+	}
+	return s.Layers[layer].At(x, y, z)
+}
+
+// SetBlock sets the runtime ID of the block at the position passed in the layer passed. Missing layers up to
+// the one passed are added, filled with air.
+func (s *SubChunk) SetBlock(x, y, z byte, layer int, rid, airRID uint32) {
+	for len(s.Layers) <= layer {
+		s.Layers = append(s.Layers, NewPalettedStorage(airRID))
+	}
+	s.Layers[layer].Set(x, y, z, rid)
+}
+
+// DecodeSubChunk decodes a sub-chunk in network encoding, such as the RawPayload of a SubChunkEntry in the
+// SubChunk packet, along with the block entities that follow it.
+func DecodeSubChunk(data []byte) (*SubChunk, []map[string]any, error) {
+	buf := bytes.NewBuffer(data)
+	s, err := decodeSubChunk(buf, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	blockEntities, err := decodeBlockEntities(buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, blockEntities, nil
+}
+
+// EncodeSubChunk encodes a sub-chunk in network encoding, along with the block entities passed, so that it
+// may be sent as the RawPayload of a SubChunkEntry in the SubChunk packet.
+func EncodeSubChunk(s *SubChunk, blockEntities []map[string]any) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	s.encode(buf)
+	if err := encodeBlockEntities(buf, blockEntities); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeSubChunk decodes a single sub-chunk from the buffer passed. index is used as the Index of the
+// sub-chunk if its version does not hold it.
+func decodeSubChunk(buf *bytes.Buffer, index int8) (*SubChunk, error) {
+	version, err := buf.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("read sub-chunk version: %w", err)
+	}
+	s := &SubChunk{Index: index}
+	layers := byte(1)
+	switch version {
+	case 1:
+	case 8, 9:
+		if layers, err = buf.ReadByte(); err != nil {
+			return nil, fmt.Errorf("read sub-chunk layer count: %w", err)
+		}
+		if version == 9 {
+			y, err := buf.ReadByte()
+			if err != nil {
+				return nil, fmt.Errorf("read sub-chunk index: %w", err)
+			}
+			s.Index = int8(y)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported sub-chunk version %v", version)
+	}
+	s.Layers = make([]*PalettedStorage, layers)
+	for i := range s.Layers {
+		if s.Layers[i], err = decodePalettedStorage(buf); err != nil {
+			return nil, fmt.Errorf("decode sub-chunk layer %v: %w", i, err)
+		}
+		if s.Layers[i] == nil {
+			return nil, fmt.Errorf("decode sub-chunk layer %v: layer refers to previous storage", i)
+		}
+	}
+	return s, nil
+}
+
+// encode writes the sub-chunk in network encoding to the buffer passed, using SubChunkVersion.
+func (s *SubChunk) encode(buf *bytes.Buffer) {
+	buf.WriteByte(SubChunkVersion)
+	buf.WriteByte(byte(len(s.Layers)))
+	buf.WriteByte(byte(s.Index))
+	for _, layer := range s.Layers {
+		layer.encode(buf)
+	}
+}
+
+// decodeBlockEntities decodes the block entities that make up the rest of the buffer passed. Each block
+// entity is an NBT compound in network encoding.
+func decodeBlockEntities(buf *bytes.Buffer) ([]map[string]any, error) {
+	var blockEntities []map[string]any
+	dec := nbt.NewDecoder(buf)
+	for buf.Len() != 0 {
+		var m map[string]any
+		if err := dec.Decode(&m); err != nil {
+			return nil, fmt.Errorf("decode block entity: %w", err)
+		}
+		blockEntities = append(blockEntities, m)
+	}
+	return blockEntities, nil
+}
+
+// encodeBlockEntities writes the block entities passed to the buffer as NBT compounds in network encoding.
+func encodeBlockEntities(buf *bytes.Buffer, blockEntities []map[string]any) error {
+	enc := nbt.NewEncoder(buf)
+	for _, m := range blockEntities {
+		if err := enc.Encode(m); err != nil {
+			return fmt.Errorf("encode block entity: %w", err)
+		}
+	}
+	return nil
+}
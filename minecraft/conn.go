@@ -5,12 +5,14 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"crypto/rand"
-	"crypto/sha256"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net"
+	"os"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -35,15 +37,6 @@ type exemptedResourcePack struct {
 	version string
 }
 
-// exemptedPacks is a list of all resource packs that do not need to be downloaded, but may always be applied
-// in the ResourcePackStack packet.
-var exemptedPacks = []exemptedResourcePack{
-	{
-		uuid:    "0fba4063-dba1-4281-9b89-ff9390653530",
-		version: "1.0.0",
-	},
-}
-
 type IConn interface {
 	Authenticated() bool
 	ChunkRadius() int
@@ -98,16 +91,46 @@ type Conn struct {
 	dec           *packet.Decoder
 	compression   packet.Compression
 	readerLimits  bool
+	// serverSide is true for Conns obtained using a Listener, and false for Conns obtained using a Dialer.
+	serverSide bool
+
+	// compressionThreshold is the minimum size of a batch for it to be compressed. For a Listener, it is
+	// sent in the NetworkSettings packet. For a Dialer, it overrides the threshold sent by the server if
+	// non-zero.
+	compressionThreshold int
+	// compressionFunc, if non-nil, selects the compression and threshold for the connection instead.
+	compressionFunc func(conn *Conn) (packet.Compression, int)
+	// acceptedCompressions holds the compression algorithms that a Conn obtained using Dial accepts from
+	// the server. If empty, all algorithms are accepted.
+	acceptedCompressions []packet.Compression
 
 	disconnectOnUnknownPacket bool
 	disconnectOnInvalidPacket bool
+	// readLimits holds the limits enforced when decoding packets read after logging in.
+	readLimits protocol.ReaderLimits
+	// strictDecoding specifies if the field paths of packets that fail to decode are included in errors.
+	strictDecoding bool
+	// preserveTrailing specifies if bytes left after decoding a packet are kept in a packet.Trailing rather
+	// than the packet being dropped.
+	preserveTrailing bool
 
 	identityData login.IdentityData
 	clientData   login.ClientData
 	onClientData func(*Conn)
+	// allowLogin is called on the server side once the login request of the client was verified. If it
+	// returns false, the client is disconnected with the message returned.
+	allowLogin func(conn *Conn) (message string, ok bool)
+	// chainCache, if non-nil, caches verified login chains. onLoginVerified is called after every login
+	// request was verified.
+	chainCache      *login.ChainCache
+	onLoginVerified func(conn *Conn, d time.Duration, cached bool)
 
 	gameData         GameData
 	gameDataReceived atomic.Bool
+	// gameDataFuncs holds functions that modify the GameData passed to StartGame before the client is spawned
+	// with it. They are added using UpdateGameData.
+	gameDataMu    sync.Mutex
+	gameDataFuncs []func(data *GameData)
 
 	// privateKey is the private key of this end of the connection. Each connection, regardless of which side
 	// the connection is on, server or client, has a unique private key generated.
@@ -115,10 +138,23 @@ type Conn struct {
 	// salt is a 16 byte long randomly generated byte slice which is only used if the Conn is a server sided
 	// connection. It is otherwise left unused.
 	salt []byte
+	// keyExchange is called on the server side to obtain the KeyExchange settings of the connection once the
+	// client logged in. deriveKey is used on the client side to derive the encryption key.
+	keyExchange func(conn *Conn) KeyExchange
+	deriveKey   func(salt, sharedSecret []byte) [32]byte
 
 	// packets is a channel of byte slices containing serialised packets that are coming in from the other
 	// side of the connection.
 	packets chan *packetData
+	// rawBatches is a channel of undecoded batches returned by ReadRawBatch. It is nil if the connection does
+	// not read raw batches.
+	rawBatches chan []byte
+	// decodePool, if non-nil, decodes packets passed on to ReadPacket before they are read.
+	decodePool *DecodePool
+	// pooledPackets specifies if packets passed on to ReadPacket are held in pooled buffers. lastRead is the
+	// packetData of the packet last returned by ReadPacket, of which the buffer is released on the next read.
+	pooledPackets bool
+	lastRead      *packetData
 
 	deferredPacketMu sync.Mutex
 	// deferredPackets is a list of packets that were pushed back during the login sequence because they
@@ -126,12 +162,28 @@ type Conn struct {
 	// ReadPacket after being connected.
 	deferredPackets []*packetData
 	readDeadline    <-chan time.Time
+	// readTimeout and writeTimeout are the timeouts in nanoseconds set using SetReadTimeout and
+	// SetWriteTimeout.
+	readTimeout, writeTimeout atomic.Int64
 
 	sendMu sync.Mutex
 	// bufferedSend is a slice of byte slices containing packets that are 'written'. They are buffered until
 	// they are sent each 20th of a second.
 	bufferedSend [][]byte
-	hdr          *packet.Header
+	bufferedSize int
+	// sendBuffers holds, for every packet in bufferedSend at the same index, the pooled buffer that the
+	// packet was encoded in, so that it may be returned to the pool once the packet is sent. It holds nil for
+	// packets whose data is not owned by the Conn, such as those passed to Write.
+	sendBuffers []*bytes.Buffer
+	hdr         *packet.Header
+	// flushSize is the total size of buffered packets in bytes at which they are flushed immediately, rather
+	// than at the next tick. If 0, packets are only flushed every tick.
+	flushSize int
+	// queue, if non-nil, holds packets waiting to be sent grouped by their priority.
+	queue *writeQueue
+	// flushIDs holds the IDs of packets that cause the buffered packets to be flushed immediately after they
+	// are written.
+	flushIDs []uint32
 
 	// readyToLogin is a bool indicating if the connection is ready to login. This is used to ensure that the client
 	// has received the relevant network settings before the login sequence starts.
@@ -143,10 +195,28 @@ type Conn struct {
 	// the world: It is completing a sequence that will result in the spawning.
 	spawn           chan struct{}
 	waitingForSpawn atomic.Bool
+	// loginTimeouts holds the timeouts of the phases of the login sequence. phaseTimer closes the connection
+	// if the current phase does not finish in time, or once loginDeadline, set from the Total timeout, passes.
+	loginTimeouts LoginTimeouts
+	phaseMu       sync.Mutex
+	phaseTimer    *time.Timer
+	loginDeadline time.Time
+	// keepAliveInterval is the interval at which keep alive packets are sent once spawned.
+	keepAliveInterval time.Duration
+	// ticks tracks the tick of the server for connections obtained using Dial. It is nil for connections
+	// obtained using a Listener.
+	ticks *tickTracker
+	// renegotiating is true while resource packs are negotiated again after the Conn spawned.
+	renegotiating atomic.Bool
+
+	// startGameReceived is closed once a client Conn receives the StartGame packet. spawnCfg holds the
+	// settings of the spawn sequence that follows.
+	startGameReceived chan struct{}
+	spawnCfg          SpawnConfig
 
 	// expectedIDs is a slice of packet identifiers that are next expected to arrive, until the connection is
 	// logged in.
-	expectedIDs atomic.Value
+	expectedIDs atomic.Pointer[[]uint32]
 
 	// biomes is a map of biome definitions that the listener may hold. Each client will be sent these biome
 	// definitions upon joining.
@@ -157,6 +227,62 @@ type Conn struct {
 	// downloadResourcePack is an optional function passed to a Dial() call. If set, each resource pack received
 	// from the server will call this function to see if it should be downloaded or not.
 	downloadResourcePack func(id uuid.UUID, version string, currentPack, totalPacks int) bool
+	// packDecision is an optional function passed to a Dial() call. If set, it is used instead of
+	// downloadResourcePack to decide what to do with each resource pack received from the server.
+	packDecision func(id uuid.UUID, version string, currentPack, totalPacks int) PackDecision
+	// skipPacks specifies if the resource pack sequence should be completed without downloading any resource
+	// packs or verifying the resource pack stack.
+	skipPacks bool
+	// chunkTimeout is the maximum duration to wait for a single chunk of a resource pack to arrive before
+	// requesting it again, up to chunkRetries times. If chunkTimeout is 0 or lower, chunks never time out.
+	chunkTimeout time.Duration
+	chunkRetries int
+	// packURLTimeout is the maximum duration of downloading a resource pack from a URL sent by the server. If
+	// 0 or lower, downloads from URLs never time out.
+	packURLTimeout time.Duration
+	// exemptedPacks holds the resource packs that the client ships with, which do not need to be downloaded
+	// but may always be applied in the ResourcePackStack packet.
+	exemptedPacks *ExemptedPacks
+	// packMemory is the amount of bytes that resource packs being downloaded may still hold in memory before
+	// they are spilled to temporary files. If nil, downloads are always held in memory.
+	packMemory *atomic.Int64
+	// packetLogger, if non-nil, logs packets read from and written to the connection.
+	packetLogger *PacketLogger
+	// captureWriter, if non-nil, records all packets read from and written to the connection.
+	captureWriter *CaptureWriter
+
+	// middlewareMu guards middleware, which holds the middleware that packets read from and written to the
+	// connection are passed through. It is added to using Conn.Use.
+	middlewareMu sync.RWMutex
+	middleware   []Middleware
+
+	// stats holds the network metrics of the connection.
+	stats connStats
+	// rtt holds the round trip times of the connection sampled recently.
+	rtt rttWindow
+
+	// parent is the Conn that a sub-client Conn shares its connection with, and subClientID the ID of the
+	// sub-client. parent is nil for Conns that are not sub-clients.
+	parent      *Conn
+	subClientID byte
+	// subClients holds the sub-clients that joined over the connection, indexed by their sub-client ID.
+	subClientsMu sync.Mutex
+	subClients   map[byte]*Conn
+	// onTransfer, if non-nil, is called when a Transfer packet is received over a client sided connection,
+	// instead of passing the packet on to the user.
+	onTransfer func(pk *packet.Transfer)
+	// onSubClient is called when a sub-client has logged in over a server sided connection. If nil,
+	// SubClientLogin packets are not handled and passed on to the user instead.
+	onSubClient func(sub *Conn)
+	// packStats holds metrics of the resource pack transfers of the connection.
+	packStats packStats
+	// packHooks holds functions called during the phases of resource pack negotiation.
+	packHooks ResourcePackHooks
+	// decryptPacks specifies if encrypted resource packs downloaded from the server should be decrypted.
+	decryptPacks bool
+	// checksumPolicy specifies what happens if a resource pack downloaded from the server does not match its
+	// checksum.
+	checksumPolicy ChecksumPolicy
 
 	cacheEnabled bool
 
@@ -164,7 +290,12 @@ type Conn struct {
 	// to this connection will call this function.
 	packetFunc func(header packet.Header, payload []byte, src, dst net.Addr)
 
-	disconnectMessage atomic.Pointer[string]
+	// disconnect holds the Disconnect packet that the other end of the connection closed the connection with,
+	// or nil if no such packet was received.
+	disconnect atomic.Pointer[packet.Disconnect]
+	// closeCause is the error that caused the Conn to be closed, if it was closed due to a failure on this
+	// end of the connection.
+	closeCause atomic.Pointer[error]
 
 	shieldID atomic.Int32
 
@@ -179,31 +310,32 @@ type Conn struct {
 // key is generated.
 func newConn(netConn net.Conn, key *ecdsa.PrivateKey, log *log.Logger, proto Protocol, flushRate time.Duration, limits bool) *Conn {
 	conn := &Conn{
-		enc:          packet.NewEncoder(netConn),
-		dec:          packet.NewDecoder(netConn),
-		salt:         make([]byte, 16),
-		packets:      make(chan *packetData, 8),
-		additional:   make(chan packet.Packet, 16),
-		close:        make(chan struct{}),
-		spawn:        make(chan struct{}),
-		conn:         netConn,
-		privateKey:   key,
-		log:          log,
-		hdr:          &packet.Header{},
-		proto:        proto,
-		readerLimits: limits,
-	}
-	var s string
-	conn.disconnectMessage.Store(&s)
+		salt:              make([]byte, 16),
+		packets:           make(chan *packetData, 8),
+		additional:        make(chan packet.Packet, 16),
+		close:             make(chan struct{}),
+		spawn:             make(chan struct{}),
+		startGameReceived: make(chan struct{}),
+		conn:              netConn,
+		privateKey:        key,
+		log:               log,
+		hdr:               &packet.Header{},
+		proto:             proto,
+		readerLimits:      limits,
+	}
+	counted := countConn(netConn, &conn.stats)
+	conn.enc, conn.dec = packet.NewEncoder(counted), packet.NewDecoder(counted)
 
 	conn.ResourcePackHandler = &defaultResourcepackHandler{c: conn}
+	conn.exemptedPacks = DefaultExemptedPacks()
 	if !limits {
 		// Disable the batch packet limit so that the server can send packets as often as it wants to.
 		conn.dec.DisableBatchPacketLimit()
 	}
 	_, _ = rand.Read(conn.salt)
 
-	conn.expectedIDs.Store([]uint32{packet.IDRequestNetworkSettings})
+	conn.expect(packet.IDRequestNetworkSettings)
+	go conn.sampleLatency()
 
 	if flushRate <= 0 {
 		return conn
@@ -290,6 +422,12 @@ func (conn *Conn) StartGameContext(ctx context.Context, data GameData) error {
 	if data.WorldName == "" {
 		data.WorldName = conn.gameData.WorldName
 	}
+	conn.gameDataMu.Lock()
+	for _, f := range conn.gameDataFuncs {
+		f(&data)
+	}
+	conn.gameDataFuncs = nil
+	conn.gameDataMu.Unlock()
 
 	conn.gameData = data
 	for _, item := range data.Items {
@@ -299,6 +437,7 @@ func (conn *Conn) StartGameContext(ctx context.Context, data GameData) error {
 	}
 	conn.waitingForSpawn.Store(true)
 	conn.startGame()
+	conn.enterPhase("spawn", conn.loginTimeouts.Spawn)
 
 	select {
 	case <-conn.close:
@@ -340,11 +479,19 @@ func (conn *Conn) DoSpawnTimeout(timeout time.Duration) error {
 // DoSpawnContext will start the spawning sequence using the game data found in conn.GameData(), which was
 // sent earlier by the server.
 func (conn *Conn) DoSpawnContext(ctx context.Context) error {
+	if err := conn.awaitSpawnStage(ctx, conn.spawnCfg.StartGameTimeout, conn.startGameReceived, "start game"); err != nil {
+		return err
+	}
+	if conn.spawnCfg.SpawnTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, conn.spawnCfg.SpawnTimeout)
+		defer cancel()
+	}
 	select {
 	case <-conn.close:
 		return conn.closeErr("do spawn")
 	case <-ctx.Done():
-		return conn.wrap(ctx.Err(), "do spawn")
+		return conn.wrap(LoginTimeoutError{Stage: conn.spawnStage(), Err: ctx.Err()}, "do spawn")
 	case <-conn.spawn:
 		// Conn was spawned successfully.
 		return nil
@@ -359,29 +506,146 @@ func (conn *Conn) WritePacket(pk packet.Packet) error {
 		return conn.closeErr("write packet")
 	default:
 	}
+	pk, ok := conn.applyMiddleware(pk, DirectionWrite)
+	if !ok {
+		// The packet was dropped by middleware, so we don't write it.
+		return nil
+	}
+	if conn.educationDisabled(pk) {
+		conn.log.Printf("not writing %T: education features are not enabled\n", pk)
+		return nil
+	}
+	conn.packetLogger.log(conn, DirectionWrite, pk)
+	if conn.bufferPacket(pk) || slices.Contains(conn.flushIDs, pk.ID()) {
+		return conn.Flush()
+	}
+	return nil
+}
+
+// WritePackets encodes the packets passed and writes them to the Conn in a single batch, together with any
+// packets buffered before. Unlike calling WritePacket for every packet, the packets are compressed and
+// encrypted in one pass and are guaranteed to be sent in order, without packets written concurrently on
+// other goroutines ending up between them. The batch is sent immediately rather than at the next flush.
+// Packets configured with a lower priority that are still queued from previous writes may be sent after
+// the batch.
+func (conn *Conn) WritePackets(pks []packet.Packet) error {
+	select {
+	case <-conn.close:
+		return conn.closeErr("write packets")
+	default:
+	}
+	written := make([]packet.Packet, 0, len(pks))
+	for _, pk := range pks {
+		pk, ok := conn.applyMiddleware(pk, DirectionWrite)
+		if !ok {
+			// The packet was dropped by middleware, so we don't write it.
+			continue
+		}
+		if conn.educationDisabled(pk) {
+			conn.log.Printf("not writing %T: education features are not enabled\n", pk)
+			continue
+		}
+		conn.packetLogger.log(conn, DirectionWrite, pk)
+		written = append(written, pk)
+	}
+	var batch []outgoingPacket
+	conn.sendMu.Lock()
+	for _, pk := range written {
+		batch = conn.marshalPacket(pk, batch)
+	}
+	conn.sendMu.Unlock()
+
+	if conn.parent != nil {
+		// Sub-clients share the connection of their parent, so their packets are sent in its batches.
+		return conn.parent.writeBatch(batch)
+	}
+	return conn.writeBatch(batch)
+}
+
+// writeBatch sends the serialised packets passed in a single batch, after the packets that were buffered
+// before.
+func (conn *Conn) writeBatch(batch []outgoingPacket) error {
 	conn.sendMu.Lock()
 	defer conn.sendMu.Unlock()
 
-	buf := internal.BufferPool.Get().(*bytes.Buffer)
-	defer func() {
-		// Reset the buffer, so we can return it to the buffer pool safely.
-		buf.Reset()
-		internal.BufferPool.Put(buf)
-	}()
+	conn.dequeue()
+	for _, pk := range batch {
+		conn.appendSend(pk)
+	}
+	return conn.sendBuffered("write packets")
+}
 
-	conn.hdr.PacketID = pk.ID()
-	_ = conn.hdr.Write(buf)
-	l := buf.Len()
+// bufferPacket encodes the packet passed and adds it to the packets buffered to be sent in the next batch. It
+// returns true if the size of the packets buffered reached the flush size of the Conn.
+func (conn *Conn) bufferPacket(pk packet.Packet) bool {
+	conn.sendMu.Lock()
+	defer conn.sendMu.Unlock()
 
+	for _, p := range conn.marshalPacket(pk, nil) {
+		if conn.parent != nil {
+			_ = conn.parent.writeOutgoing(p)
+			continue
+		}
+		conn.enqueue(p)
+	}
+	return conn.flushSize > 0 && conn.bufferedSize >= conn.flushSize
+}
+
+// outgoingPacket is a serialised packet waiting to be sent over a Conn.
+type outgoingPacket struct {
+	// data holds the header and payload of the packet.
+	data []byte
+	// id is the ID of the packet, which determines its Priority.
+	id uint32
+	// buf is the pooled buffer that data was encoded in, or nil if data is not owned by the Conn.
+	buf *bytes.Buffer
+}
+
+// marshalPacket encodes the packet passed, converted to the protocol of the Conn, and appends the serialised
+// packets to dst. The send mutex of the Conn must be held.
+func (conn *Conn) marshalPacket(pk packet.Packet, dst []outgoingPacket) []outgoingPacket {
 	for _, converted := range conn.proto.ConvertFromLatest(pk, conn) {
-		converted.Marshal(conn.proto.NewWriter(buf, conn.shieldID.Load()))
+		// The packet may have been converted to a packet with a different ID, so we write the header of every
+		// converted packet separately.
+		buf := internal.BufferPool.Get().(*bytes.Buffer)
+		buf.Reset()
+		conn.hdr.PacketID = converted.ID()
+		_ = conn.hdr.Write(buf)
+		l := buf.Len()
+
+		w, release := conn.newWriter(buf)
+		packet.Marshal(converted, w)
+		release()
 
 		if conn.packetFunc != nil {
 			conn.packetFunc(*conn.hdr, buf.Bytes()[l:], conn.LocalAddr(), conn.RemoteAddr())
 		}
-		conn.bufferedSend = append(conn.bufferedSend, append([]byte(nil), buf.Bytes()...))
+		conn.capture(DirectionWrite, buf.Bytes())
+		conn.sendStats().sent(conn.hdr.PacketID, buf.Len())
+		if conn.parent != nil {
+			// Packets of sub-clients are sent in batches of the parent, so we can't tell when the buffer may
+			// be re-used and copy the packet instead.
+			dst = append(dst, outgoingPacket{data: append([]byte(nil), buf.Bytes()...), id: conn.hdr.PacketID})
+			buf.Reset()
+			internal.BufferPool.Put(buf)
+			continue
+		}
+		dst = append(dst, outgoingPacket{data: buf.Bytes(), id: conn.hdr.PacketID, buf: buf})
 	}
-	return nil
+	return dst
+}
+
+// newWriter returns a protocol.IO that writes packets of the Protocol of the Conn to the buffer passed, and a
+// function that must be called once the packet is written. The built-in protocols use the default
+// protocol.Writer, so for those the Conn takes a Writer from the pool and returns it afterwards. Writers
+// returned by the NewWriter method of other Protocols are left to the Protocol.
+func (conn *Conn) newWriter(buf *bytes.Buffer) (protocol.IO, func()) {
+	switch conn.proto.(type) {
+	case proto, translatedProtocol:
+		w := protocol.GetWriter(buf, conn.shieldID.Load())
+		return w, func() { protocol.PutWriter(w) }
+	}
+	return conn.proto.NewWriter(buf, conn.shieldID.Load()), func() {}
 }
 
 // ReadPacket reads a packet from the Conn, depending on the packet ID that is found in front of the packet
@@ -396,6 +660,7 @@ func (conn *Conn) ReadPacket() (pk packet.Packet, err error) {
 	}
 	if data, ok := conn.takeDeferredPacket(); ok {
 		pk, err := data.decode(conn)
+		conn.read(data)
 		if err != nil {
 			conn.log.Println(err)
 			return conn.ReadPacket()
@@ -409,13 +674,18 @@ func (conn *Conn) ReadPacket() (pk packet.Packet, err error) {
 		return pk[0], nil
 	}
 
+	timeout, stop := conn.readTimer()
+	defer stop()
 	select {
 	case <-conn.close:
 		return nil, conn.closeErr("read packet")
 	case <-conn.readDeadline:
 		return nil, conn.wrap(context.DeadlineExceeded, "read packet")
+	case <-timeout:
+		return nil, conn.wrap(context.DeadlineExceeded, "read packet")
 	case data := <-conn.packets:
 		pk, err := data.decode(conn)
+		conn.read(data)
 		if err != nil {
 			conn.log.Println(err)
 			return conn.ReadPacket()
@@ -442,14 +712,51 @@ func (conn *Conn) ResourcePacks() []*resource.Pack {
 	return conn.ResourcePackHandler.ResourcePacks()
 }
 
+// AcknowledgedPacks returns the metadata of all resource packs sent by the server that were acknowledged
+// without being downloaded, as a result of Dialer.ResourcePackDecision returning PackDecisionAcknowledge.
+// AcknowledgedPacks returns nil if the Conn does not use the default ResourcePackHandler.
+func (conn *Conn) AcknowledgedPacks() []AcknowledgedPack {
+	if h, ok := conn.ResourcePackHandler.(*defaultResourcepackHandler); ok {
+		return h.AcknowledgedPacks()
+	}
+	return nil
+}
+
+// PackTransferStats returns metrics of the resource packs transferred over the Conn so far, such as the
+// amount of bytes sent and received and the duration of each transfer. These metrics are only recorded by
+// the default ResourcePackHandler.
+func (conn *Conn) PackTransferStats() PackTransferStats {
+	return conn.packStats.snapshot()
+}
+
 // Write writes a slice of serialised packet data to the Conn. The data is buffered until the next 20th of a
 // tick, after which it is flushed to the connection. Write returns the amount of bytes written n.
 func (conn *Conn) Write(b []byte) (n int, err error) {
+	var h packet.Header
+	_ = h.Read(bytes.NewBuffer(b))
+	conn.sendStats().sent(h.PacketID, len(b))
+	if err := conn.writeOutgoing(outgoingPacket{data: b, id: h.PacketID}); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// writeOutgoing adds the serialised packet passed to the packets to be sent in the next batch, flushing them
+// if they reached the flush size of the Conn.
+func (conn *Conn) writeOutgoing(pk outgoingPacket) error {
+	if conn.parent != nil {
+		// Sub-clients share the connection of their parent, so their packets are sent in its batches.
+		return conn.parent.writeOutgoing(pk)
+	}
 	conn.sendMu.Lock()
-	defer conn.sendMu.Unlock()
+	conn.enqueue(pk)
+	flush := conn.flushSize > 0 && conn.bufferedSize >= conn.flushSize
+	conn.sendMu.Unlock()
 
-	conn.bufferedSend = append(conn.bufferedSend, b)
-	return len(b), nil
+	if flush {
+		return conn.Flush()
+	}
+	return nil
 }
 
 // Read reads a packet from the connection into the byte slice passed, provided the byte slice is big enough
@@ -460,18 +767,26 @@ func (conn *Conn) Read(b []byte) (n int, err error) {
 		if len(b) < len(data.full) {
 			return 0, conn.wrap(errBufferTooSmall, "read")
 		}
-		return copy(b, data.full), nil
+		n = copy(b, data.full)
+		data.release()
+		return n, nil
 	}
+	timeout, stop := conn.readTimer()
+	defer stop()
 	select {
 	case <-conn.close:
 		return 0, conn.closeErr("read")
 	case <-conn.readDeadline:
 		return 0, conn.wrap(context.DeadlineExceeded, "read")
+	case <-timeout:
+		return 0, conn.wrap(context.DeadlineExceeded, "read")
 	case data := <-conn.packets:
 		if len(b) < len(data.full) {
 			return 0, conn.wrap(errBufferTooSmall, "read")
 		}
-		return copy(b, data.full), nil
+		n = copy(b, data.full)
+		data.release()
+		return n, nil
 	}
 }
 
@@ -483,38 +798,146 @@ func (conn *Conn) Flush() error {
 		return conn.closeErr("flush")
 	default:
 	}
+	if conn.parent != nil {
+		return conn.parent.Flush()
+	}
 	conn.sendMu.Lock()
 	defer conn.sendMu.Unlock()
 
-	if len(conn.bufferedSend) > 0 {
-		if err := conn.enc.Encode(conn.bufferedSend); err != nil && !errors.Is(err, net.ErrClosed) {
-			// Should never happen.
-			panic(fmt.Errorf("error encoding packet batch: %w", err))
-		}
-		// First manually clear out conn.bufferedSend so that re-using the slice after resetting its length to
-		// 0 doesn't result in an 'invisible' memory leak.
-		for i := range conn.bufferedSend {
-			conn.bufferedSend[i] = nil
+	conn.dequeue()
+	return conn.sendBuffered("flush")
+}
+
+// sendBuffered encodes the packets in conn.bufferedSend into a batch and sends it. The send mutex of the Conn
+// must be held. The operation passed is used to wrap errors returned.
+func (conn *Conn) sendBuffered(op string) error {
+	if len(conn.bufferedSend) == 0 {
+		return nil
+	}
+	conn.applyWriteTimeout()
+	err := conn.enc.Encode(conn.bufferedSend)
+	// The packets were either copied into the batch or lost with it, so the buffers they were encoded in may
+	// be re-used in both cases.
+	conn.resetSend()
+	if err != nil && !errors.Is(err, net.ErrClosed) {
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			// The write timeout of the connection expired. The batch is lost, so the connection can no
+			// longer be used reliably. Close flushes too, so it must be called without holding the lock.
+			go conn.closeWithErr(err)
+			return conn.wrap(err, op)
 		}
-		// Slice the conn.bufferedSend to a length of 0 so we don't have to re-allocate space in this slice
-		// every time.
-		conn.bufferedSend = conn.bufferedSend[:0]
+		// Should never happen.
+		panic(fmt.Errorf("error encoding packet batch: %w", err))
 	}
 	return nil
 }
 
+// resetSend returns the buffers of the packets in conn.bufferedSend to the buffer pool and clears the
+// packets buffered. The send mutex of the Conn must be held.
+func (conn *Conn) resetSend() {
+	for _, buf := range conn.sendBuffers {
+		if buf != nil {
+			buf.Reset()
+			internal.BufferPool.Put(buf)
+		}
+	}
+	// First manually clear out conn.bufferedSend so that re-using the slice after resetting its length to 0
+	// doesn't result in an 'invisible' memory leak.
+	clear(conn.bufferedSend)
+	clear(conn.sendBuffers)
+	// Slice the conn.bufferedSend to a length of 0 so we don't have to re-allocate space in this slice every
+	// time.
+	conn.bufferedSend, conn.sendBuffers = conn.bufferedSend[:0], conn.sendBuffers[:0]
+	conn.bufferedSize = 0
+	if conn.queue != nil {
+		// Packets of a lower priority may not have fit in the batch, in which case they are still queued.
+		conn.bufferedSize = conn.queue.size()
+	}
+}
+
+// enqueue adds the serialised packet passed to the packets to be sent in the next batch, or to the write
+// queue of the Conn if packet priorities are configured. The send mutex of the Conn must be held.
+func (conn *Conn) enqueue(pk outgoingPacket) {
+	conn.bufferedSize += len(pk.data)
+	if conn.queue != nil {
+		conn.queue.push(pk)
+		return
+	}
+	conn.appendSend(pk)
+}
+
+// dequeue moves the packets in the write queue of the Conn that should be sent in the next batch to
+// conn.bufferedSend. The send mutex of the Conn must be held.
+func (conn *Conn) dequeue() {
+	if conn.queue != nil {
+		conn.bufferedSend, conn.sendBuffers = conn.queue.next(conn.bufferedSend, conn.sendBuffers)
+	}
+}
+
+// appendSend adds the serialised packet passed to conn.bufferedSend, keeping its buffer in conn.sendBuffers
+// at the same index. The send mutex of the Conn must be held.
+func (conn *Conn) appendSend(pk outgoingPacket) {
+	conn.bufferedSend = append(conn.bufferedSend, pk.data)
+	conn.sendBuffers = append(conn.sendBuffers, pk.buf)
+}
+
+// Closed returns a channel that is closed once the Conn is closed, either by calling Close or because the
+// connection was lost.
+func (conn *Conn) Closed() <-chan struct{} {
+	return conn.close
+}
+
 // Close closes the Conn and its underlying connection. Before closing, it also calls Flush() so that any
 // packets currently pending are sent out.
 func (conn *Conn) Close() error {
+	if conn.parent != nil {
+		conn.closeSubClient()
+		return nil
+	}
 	var err error
 	conn.once.Do(func() {
+		conn.closeSubClients()
 		err = conn.Flush()
+		for err == nil && conn.queued() {
+			// Packets of a lower priority may not have fit in a single batch, so we keep flushing until all
+			// packets were sent.
+			err = conn.Flush()
+		}
 		close(conn.close)
 		_ = conn.conn.Close()
+		if conn.captureWriter != nil {
+			_ = conn.captureWriter.Close()
+		}
 	})
 	return err
 }
 
+// Disconnect disconnects the Conn by sending a Disconnect packet with the message passed, flushing it and
+// closing the connection after. The message may hold formatting codes and may be a translation key, such as
+// "disconnectionScreen.serverFull", which is translated by the client. If hideScreen is true, the client is
+// sent directly back to the menu instead of being shown a disconnection screen with the message.
+// Disconnect is typically used by servers to kick a player. Subsequent calls to ReadPacket return an error
+// wrapping net.ErrClosed.
+func (conn *Conn) Disconnect(message string, hideScreen bool) error {
+	select {
+	case <-conn.close:
+		return conn.closeErr("disconnect")
+	default:
+	}
+	if err := conn.WritePacket(&packet.Disconnect{HideDisconnectionScreen: hideScreen, Message: message}); err != nil {
+		return err
+	}
+	// Close flushes the Disconnect packet before closing the underlying connection.
+	return conn.Close()
+}
+
+// closeWithErr closes the Conn because of the error passed. The error is returned by subsequent calls to
+// methods such as ReadPacket, which would otherwise return net.ErrClosed.
+func (conn *Conn) closeWithErr(err error) {
+	conn.closeCause.CompareAndSwap(nil, &err)
+	_ = conn.Close()
+}
+
 // LocalAddr returns the local address of the underlying connection.
 func (conn *Conn) LocalAddr() net.Addr {
 	return conn.conn.LocalAddr()
@@ -525,6 +948,14 @@ func (conn *Conn) RemoteAddr() net.Addr {
 	return conn.conn.RemoteAddr()
 }
 
+// NetConn returns the underlying connection of the Conn, such as a *raknet.Conn, so that it may be used for
+// querying transport specific information like the MTU size. Packets must not be read from or written to
+// the net.Conn returned directly, as doing so corrupts the encrypted and compressed packet stream of the
+// Conn. For sub-clients, the connection of the main client is returned.
+func (conn *Conn) NetConn() net.Conn {
+	return conn.conn
+}
+
 // SetDeadline sets the read and write deadline of the connection. It is equivalent to calling SetReadDeadline
 // and SetWriteDeadline at the same time.
 func (conn *Conn) SetDeadline(t time.Time) error {
@@ -575,6 +1006,27 @@ func (conn *Conn) ChunkRadius() int {
 	return int(conn.gameData.ChunkRadius)
 }
 
+// SetCompressionThreshold sets the minimum size in bytes of a batch sent by the Conn for it to be compressed.
+// Smaller batches are sent uncompressed. It only affects batches sent after the call and does not change the
+// threshold advertised in the NetworkSettings packet. Calling it before compression is enabled has no effect,
+// as the threshold is then set when the NetworkSettings packet is sent or received. The threshold is clamped
+// to the range 0-65535 that may be advertised in the NetworkSettings packet.
+func (conn *Conn) SetCompressionThreshold(threshold int) {
+	if conn.parent != nil {
+		conn.parent.SetCompressionThreshold(threshold)
+		return
+	}
+	conn.sendMu.Lock()
+	defer conn.sendMu.Unlock()
+	conn.enc.SetCompressionThreshold(clampCompressionThreshold(threshold))
+}
+
+// clampCompressionThreshold clamps the compression threshold passed to the range that fits in the uint16
+// CompressionThreshold field of the NetworkSettings packet.
+func clampCompressionThreshold(threshold int) int {
+	return min(max(threshold, 0), math.MaxUint16)
+}
+
 // takeDeferredPacket locks the deferred packets lock and takes the next packet from the list of deferred
 // packets. If none was found, it returns false, and if one was found, the data and true is returned.
 func (conn *Conn) takeDeferredPacket() (*packetData, bool) {
@@ -603,21 +1055,62 @@ func (conn *Conn) deferPacket(pk *packetData) {
 // receive receives an incoming serialised packet from the underlying connection. If the connection is not yet
 // logged in, the packet is immediately handled.
 func (conn *Conn) receive(data []byte) error {
+	conn.capture(DirectionRead, data)
 	pkData, err := ParseData(data, conn.packetFunc, conn.RemoteAddr(), conn.LocalAddr())
 	if err != nil {
 		return err
 	}
+	conn.stats.received(pkData.h.PacketID, len(data))
+	if id := subClientID(pkData.h); id != 0 {
+		if handled, err := conn.receiveSubClient(id, pkData); handled {
+			return err
+		}
+	}
+	return conn.receivePacketData(pkData)
+}
+
+// receivePacketData handles the packetData passed, which was received over the connection. Depending on the
+// state of the connection, it is either handled immediately or passed on to ReadPacket.
+func (conn *Conn) receivePacketData(pkData *packetData) error {
 	if pkData.h.PacketID == packet.IDDisconnect {
 		// We always handle disconnect packets and close the connection if one comes in.
 		pks, err := pkData.decode(conn)
 		if err != nil {
 			return err
 		}
-		conn.disconnectMessage.Store(&pks[0].(*packet.Disconnect).Message)
+		if len(pks) != 0 {
+			if pk, ok := packet.Unwrap(pks[0]).(*packet.Disconnect); ok {
+				conn.disconnect.Store(pk)
+			}
+		}
 		_ = conn.Close()
 		return nil
 	}
+	if pkData.h.PacketID == packet.IDTransfer && conn.onTransfer != nil {
+		pks, err := pkData.decode(conn)
+		if err != nil {
+			return err
+		}
+		if len(pks) != 0 {
+			if pk, ok := packet.Unwrap(pks[0]).(*packet.Transfer); ok {
+				go conn.onTransfer(pk)
+				return nil
+			}
+		}
+	}
 	if conn.loggedIn && !conn.waitingForSpawn.Load() {
+		if conn.renegotiatingPacks(pkData) {
+			// The packet is part of a resource pack negotiation after spawning, which the Conn handles
+			// itself like it does during the login sequence.
+			return conn.handle(pkData)
+		}
+		if conn.pooledPackets {
+			pkData.pool()
+		}
+		if conn.decodePool != nil {
+			// Start decoding the packet right away, so that it is likely decoded by the time it is read.
+			conn.decodePool.submit(conn, pkData)
+		}
 		select {
 		case <-conn.close:
 		case previous := <-conn.packets:
@@ -637,7 +1130,7 @@ func (conn *Conn) receive(data []byte) error {
 
 // handle tries to handle the incoming packetData.
 func (conn *Conn) handle(pkData *packetData) error {
-	for _, id := range conn.expectedIDs.Load().([]uint32) {
+	for _, id := range *conn.expectedIDs.Load() {
 		if id == pkData.h.PacketID {
 			// If the packet was expected, so we handle it right now.
 			pks, err := pkData.decode(conn)
@@ -671,7 +1164,7 @@ func (conn *Conn) handlePacket(pk packet.Packet) error {
 	defer func() {
 		_ = conn.Flush()
 	}()
-	switch pk := pk.(type) {
+	switch pk := packet.Unwrap(pk).(type) {
 	// Internal packets destined for the server.
 	case *packet.RequestNetworkSettings:
 		return conn.handleRequestNetworkSettings(pk)
@@ -698,6 +1191,7 @@ func (conn *Conn) handlePacket(pk packet.Packet) error {
 	case *packet.PlayStatus:
 		return conn.handlePlayStatus(pk)
 	case *packet.ResourcePacksInfo:
+		conn.enterPhase("resource packs", conn.loginTimeouts.ResourcePacks)
 		return conn.ResourcePackHandler.OnResourcePacksInfo(pk)
 	case *packet.ResourcePackDataInfo:
 		return conn.ResourcePackHandler.OnResourcePackDataInfo(pk)
@@ -716,60 +1210,101 @@ func (conn *Conn) handlePacket(pk packet.Packet) error {
 // handleRequestNetworkSettings handles an incoming RequestNetworkSettings packet. It returns an error if the protocol
 // version is not supported, otherwise sending back a NetworkSettings packet.
 func (conn *Conn) handleRequestNetworkSettings(pk *packet.RequestNetworkSettings) error {
-	found := false
-	for _, pro := range conn.acceptedProto {
-		if pro.ID() == pk.ClientProtocol {
-			conn.proto = pro
-			conn.pool = pro.Packets(true)
-			found = true
-			break
-		}
-	}
-	if !found {
-		status := packet.PlayStatusLoginFailedClient
-		if pk.ClientProtocol > protocol.CurrentProtocol {
-			// The server is outdated in this case, so we have to change the status we send.
-			status = packet.PlayStatusLoginFailedServer
-		}
-		_ = conn.WritePacket(&packet.PlayStatus{Status: status})
-		return fmt.Errorf("incompatible protocol version: expected %v, got %v", protocol.CurrentProtocol, pk.ClientProtocol)
+	if err := conn.negotiateProtocol(pk.ClientProtocol); err != nil {
+		return err
 	}
 
+	compression, threshold := conn.compression, conn.compressionThreshold
+	if conn.compressionFunc != nil {
+		compression, threshold = conn.compressionFunc(conn)
+	}
+	threshold = clampCompressionThreshold(threshold)
 	conn.expect(packet.IDLogin)
 	if err := conn.WritePacket(&packet.NetworkSettings{
-		CompressionThreshold: 512,
-		CompressionAlgorithm: conn.compression.EncodeCompression(),
+		CompressionThreshold: uint16(threshold),
+		CompressionAlgorithm: compression.EncodeCompression(),
 	}); err != nil {
 		return fmt.Errorf("send NetworkSettings: %w", err)
 	}
 	_ = conn.Flush()
-	conn.enc.EnableCompression(conn.compression)
+	conn.enc.EnableCompression(compression)
+	conn.enc.SetCompressionThreshold(threshold)
 	conn.dec.EnableCompression()
+	conn.enterPhase("login", conn.loginTimeouts.Login)
 	return nil
 }
 
+// negotiateProtocol selects the Protocol with the ID passed from the accepted Protocols of the connection and
+// starts using it to read and write packets. If no such Protocol is accepted, the client is told that either
+// it or the server is outdated and an error is returned.
+func (conn *Conn) negotiateProtocol(id int32) error {
+	for _, pro := range conn.acceptedProto {
+		if pro.ID() == id {
+			conn.proto = pro
+			conn.pool = pro.Packets(true)
+			return nil
+		}
+	}
+	status := packet.PlayStatusLoginFailedClient
+	if id > protocol.CurrentProtocol {
+		// The server is outdated in this case, so we have to change the status we send.
+		status = packet.PlayStatusLoginFailedServer
+	}
+	_ = conn.WritePacket(&packet.PlayStatus{Status: status})
+
+	accepted := make([]int32, 0, len(conn.acceptedProto))
+	for _, pro := range conn.acceptedProto {
+		accepted = append(accepted, pro.ID())
+	}
+	return fmt.Errorf("incompatible protocol version: expected one of %v, got %v", accepted, id)
+}
+
+// Protocol returns the Protocol that is used to read and write packets over the Conn. For a Conn obtained
+// using a Listener, this is the Protocol negotiated with the client out of ListenConfig.AcceptedProtocols.
+func (conn *Conn) Protocol() Protocol {
+	return conn.proto
+}
+
 // handleNetworkSettings handles an incoming NetworkSettings packet, enabling compression for future packets.
 func (conn *Conn) handleNetworkSettings(pk *packet.NetworkSettings) error {
 	alg, ok := packet.CompressionByID(pk.CompressionAlgorithm)
 	if !ok {
 		return fmt.Errorf("unknown compression algorithm %v", pk.CompressionAlgorithm)
 	}
+	if len(conn.acceptedCompressions) != 0 && !slices.ContainsFunc(conn.acceptedCompressions, func(c packet.Compression) bool {
+		return c.EncodeCompression() == pk.CompressionAlgorithm
+	}) {
+		return fmt.Errorf("compression algorithm %v not accepted", pk.CompressionAlgorithm)
+	}
+	threshold := int(pk.CompressionThreshold)
+	if conn.compressionThreshold != 0 {
+		threshold = max(conn.compressionThreshold, 0)
+	}
 	conn.enc.EnableCompression(alg)
+	conn.enc.SetCompressionThreshold(threshold)
 	conn.dec.EnableCompression()
 	conn.readyToLogin = true
+	conn.enterPhase("login", conn.loginTimeouts.Login)
 	return nil
 }
 
 // handleLogin handles an incoming login packet. It verifies and decodes the login request found in the packet
 // and returns an error if it couldn't be done successfully.
 func (conn *Conn) handleLogin(pk *packet.Login) error {
+	if pk.ClientProtocol != conn.proto.ID() {
+		// The client sent a different protocol version than in its RequestNetworkSettings packet. We negotiate
+		// again, which fails if the new version is not accepted either.
+		if err := conn.negotiateProtocol(pk.ClientProtocol); err != nil {
+			return err
+		}
+	}
 	// The next expected packet is a response from the client to the handshake.
 	conn.expect(packet.IDClientToServerHandshake)
 	var (
 		err        error
 		authResult login.AuthResult
 	)
-	conn.identityData, conn.clientData, authResult, err = login.Parse(pk.ConnectionRequest)
+	conn.identityData, conn.clientData, authResult, err = conn.parseLogin(pk.ConnectionRequest)
 	if err != nil {
 		return fmt.Errorf("parse login request: %w", err)
 	}
@@ -782,12 +1317,49 @@ func (conn *Conn) handleLogin(pk *packet.Login) error {
 		_ = conn.WritePacket(&packet.Disconnect{Message: text.Colourf("<red>You must be logged in with XBOX Live to join.</red>")})
 		return fmt.Errorf("client was not authenticated to XBOX Live")
 	}
-	if err := conn.enableEncryption(authResult.PublicKey); err != nil {
+	if conn.allowLogin != nil {
+		if message, ok := conn.allowLogin(conn); !ok {
+			_ = conn.WritePacket(&packet.Disconnect{Message: message})
+			return fmt.Errorf("login of %v was not allowed: %v", conn.identityData.DisplayName, message)
+		}
+	}
+	var kex KeyExchange
+	if conn.keyExchange != nil {
+		kex = conn.keyExchange(conn)
+	}
+	if kex.Disabled {
+		// No handshake takes place without encryption, so we continue the login sequence right away as if
+		// the client responded to it.
+		return conn.handleClientToServerHandshake()
+	}
+	if err := conn.enableEncryption(authResult.PublicKey, kex); err != nil {
 		return fmt.Errorf("enable encryption: %w", err)
 	}
 	return nil
 }
 
+// parseLogin parses and verifies the login request passed, using the login chain cache of the Conn if it has
+// one.
+func (conn *Conn) parseLogin(request []byte) (login.IdentityData, login.ClientData, login.AuthResult, error) {
+	start := time.Now()
+	var (
+		identityData login.IdentityData
+		clientData   login.ClientData
+		authResult   login.AuthResult
+		cached       bool
+		err          error
+	)
+	if conn.chainCache != nil {
+		identityData, clientData, authResult, cached, err = conn.chainCache.Parse(request)
+	} else {
+		identityData, clientData, authResult, err = login.Parse(request)
+	}
+	if err == nil && conn.onLoginVerified != nil {
+		conn.onLoginVerified(conn, time.Since(start), cached)
+	}
+	return identityData, clientData, authResult, err
+}
+
 // handleClientToServerHandshake handles an incoming ClientToServerHandshake packet.
 func (conn *Conn) handleClientToServerHandshake() error {
 	// The next expected packet is a resource pack client response.
@@ -800,6 +1372,7 @@ func (conn *Conn) handleClientToServerHandshake() error {
 	if err := conn.WritePacket(pk); err != nil {
 		return fmt.Errorf("send ResourcePacksInfo: %w", err)
 	}
+	conn.enterPhase("resource packs", conn.loginTimeouts.ResourcePacks)
 	return nil
 }
 
@@ -855,15 +1428,8 @@ func (conn *Conn) handleServerToClientHandshake(pk *packet.ServerToClientHandsha
 		return fmt.Errorf("decode ServerToClientHandshake salt: %w", err)
 	}
 
-	x, _ := pub.Curve.ScalarMult(pub.X, pub.Y, conn.privateKey.D.Bytes())
-	// Make sure to pad the shared secret up to 96 bytes.
-	sharedSecret := append(bytes.Repeat([]byte{0}, 48-len(x.Bytes())), x.Bytes()...)
-
-	keyBytes := sha256.Sum256(append(salt, sharedSecret...))
-
-	// Finally we enable encryption for the enc and dec using the secret pubKey bytes we produced.
-	conn.enc.EnableEncryption(keyBytes)
-	conn.dec.EnableEncryption(keyBytes)
+	// Finally we enable encryption for the enc and dec using the key derived from the shared secret.
+	conn.enableEncryptionKey(conn.deriveKey, salt, sharedSecret(pub, conn.privateKey))
 
 	// We write a ClientToServerHandshake packet (which has no payload) as a response.
 	_ = conn.WritePacket(&packet.ClientToServerHandshake{})
@@ -902,7 +1468,10 @@ func (conn *Conn) startGame() {
 		Items:                        data.Items,
 		AchievementsDisabled:         true,
 		Generator:                    1,
-		EducationFeaturesEnabled:     true,
+		EducationFeaturesEnabled:     !data.EducationFeaturesDisabled,
+		EducationEditionOffer:        data.EducationEditionOffer,
+		EducationProductID:           data.EducationProductID,
+		EducationSharedResourceURI:   data.EducationSharedResourceURI,
 		MultiPlayerGame:              true,
 		MultiPlayerCorrelationID:     uuid.Must(uuid.NewRandom()).String(),
 		CommandsEnabled:              true,
@@ -963,6 +1532,10 @@ func (conn *Conn) handleStartGame(pk *packet.StartGame) error {
 		Experiments:                  pk.Experiments,
 		UseBlockNetworkIDHashes:      pk.UseBlockNetworkIDHashes,
 		ServerAuthoritativeSound:     pk.ServerAuthoritativeSound,
+		EducationFeaturesDisabled:    !pk.EducationFeaturesEnabled,
+		EducationEditionOffer:        pk.EducationEditionOffer,
+		EducationProductID:           pk.EducationProductID,
+		EducationSharedResourceURI:   pk.EducationSharedResourceURI,
 	}
 	for _, item := range pk.Items {
 		if item.Name == "minecraft:shield" {
@@ -970,8 +1543,7 @@ func (conn *Conn) handleStartGame(pk *packet.StartGame) error {
 		}
 	}
 
-	_ = conn.WritePacket(&packet.RequestChunkRadius{ChunkRadius: 16})
-	conn.expect(packet.IDChunkRadiusUpdated, packet.IDPlayStatus)
+	conn.startSpawn()
 	return nil
 }
 
@@ -1031,6 +1603,8 @@ func (conn *Conn) handleSetLocalPlayerAsInitialised(pk *packet.SetLocalPlayerAsI
 	}
 	if conn.waitingForSpawn.CompareAndSwap(true, false) {
 		close(conn.spawn)
+		conn.enterPhase("", 0)
+		go conn.keepAlive(true)
 	}
 	return nil
 }
@@ -1046,35 +1620,17 @@ func (conn *Conn) handlePlayStatus(pk *packet.PlayStatus) error {
 		// The next packet we expect is the ResourcePacksInfo packet.
 		conn.expect(packet.IDResourcePacksInfo)
 		return conn.Flush()
-	case packet.PlayStatusLoginFailedClient:
-		_ = conn.Close()
-		return fmt.Errorf("client outdated")
-	case packet.PlayStatusLoginFailedServer:
-		_ = conn.Close()
-		return fmt.Errorf("server outdated")
 	case packet.PlayStatusPlayerSpawn:
 		// We've spawned and can send the last packet in the spawn sequence.
 		conn.waitingForSpawn.Store(true)
 		conn.tryFinaliseClientConn()
 		return nil
-	case packet.PlayStatusLoginFailedInvalidTenant:
-		_ = conn.Close()
-		return fmt.Errorf("invalid edu edition game owner")
-	case packet.PlayStatusLoginFailedVanillaEdu:
-		_ = conn.Close()
-		return fmt.Errorf("cannot join an edu edition game on vanilla")
-	case packet.PlayStatusLoginFailedEduVanilla:
-		_ = conn.Close()
-		return fmt.Errorf("cannot join a vanilla game on edu edition")
-	case packet.PlayStatusLoginFailedServerFull:
-		_ = conn.Close()
-		return fmt.Errorf("server full")
-	case packet.PlayStatusLoginFailedEditorVanilla:
-		_ = conn.Close()
-		return fmt.Errorf("cannot join a vanilla game on editor")
-	case packet.PlayStatusLoginFailedVanillaEditor:
-		_ = conn.Close()
-		return fmt.Errorf("cannot join an editor game on vanilla")
+	case packet.PlayStatusLoginFailedClient, packet.PlayStatusLoginFailedServer, packet.PlayStatusLoginFailedInvalidTenant,
+		packet.PlayStatusLoginFailedVanillaEdu, packet.PlayStatusLoginFailedEduVanilla, packet.PlayStatusLoginFailedServerFull,
+		packet.PlayStatusLoginFailedEditorVanilla, packet.PlayStatusLoginFailedVanillaEditor:
+		err := PlayStatusError{Status: pk.Status}
+		conn.closeWithErr(err)
+		return err
 	default:
 		return fmt.Errorf("unknown play status %v", pk.Status)
 	}
@@ -1090,19 +1646,29 @@ func (conn *Conn) tryFinaliseClientConn() {
 
 		close(conn.spawn)
 		conn.loggedIn = true
+		conn.enterPhase("", 0)
 		_ = conn.WritePacket(&packet.SetLocalPlayerAsInitialised{EntityRuntimeID: conn.gameData.EntityRuntimeID})
+		go conn.keepAlive(false)
 	}
 }
 
 // enableEncryption enables encryption on the server side over the connection. It sends an unencrypted
-// handshake packet to the client and enables encryption after that.
-func (conn *Conn) enableEncryption(clientPublicKey *ecdsa.PublicKey) error {
-	signer, _ := jose.NewSigner(jose.SigningKey{Key: conn.privateKey, Algorithm: jose.ES384}, &jose.SignerOptions{
-		ExtraHeaders: map[jose.HeaderKey]any{"x5u": login.MarshalPublicKey(&conn.privateKey.PublicKey)},
+// handshake packet to the client and enables encryption after that, using the key material of the
+// KeyExchange passed where set.
+func (conn *Conn) enableEncryption(clientPublicKey *ecdsa.PublicKey, kex KeyExchange) error {
+	key, salt := conn.privateKey, conn.salt
+	if kex.PrivateKey != nil {
+		key = kex.PrivateKey
+	}
+	if len(kex.Salt) != 0 {
+		salt = kex.Salt
+	}
+	signer, _ := jose.NewSigner(jose.SigningKey{Key: key, Algorithm: jose.ES384}, &jose.SignerOptions{
+		ExtraHeaders: map[jose.HeaderKey]any{"x5u": login.MarshalPublicKey(&key.PublicKey)},
 	})
 	// We produce an encoded JWT using the header and payload above, then we send the JWT in a ServerToClient-
 	// Handshake packet so that the client can initialise encryption.
-	serverJWT, err := jwt.Signed(signer).Claims(saltClaims{Salt: base64.RawStdEncoding.EncodeToString(conn.salt)}).CompactSerialize()
+	serverJWT, err := jwt.Signed(signer).Claims(saltClaims{Salt: base64.RawStdEncoding.EncodeToString(salt)}).CompactSerialize()
 	if err != nil {
 		return fmt.Errorf("compact serialise server JWT: %w", err)
 	}
@@ -1112,39 +1678,72 @@ func (conn *Conn) enableEncryption(clientPublicKey *ecdsa.PublicKey) error {
 	// Flush immediately as we'll enable encryption after this.
 	_ = conn.Flush()
 
-	// We first compute the shared secret.
-	x, _ := clientPublicKey.Curve.ScalarMult(clientPublicKey.X, clientPublicKey.Y, conn.privateKey.D.Bytes())
-
-	sharedSecret := append(bytes.Repeat([]byte{0}, 48-len(x.Bytes())), x.Bytes()...)
-
-	keyBytes := sha256.Sum256(append(conn.salt, sharedSecret...))
-
-	// Finally we enable encryption for the encoder and decoder using the secret key bytes we produced.
-	conn.enc.EnableEncryption(keyBytes)
-	conn.dec.EnableEncryption(keyBytes)
-
+	// Finally we enable encryption for the encoder and decoder using the key derived from the shared secret.
+	conn.enableEncryptionKey(kex.DeriveKey, salt, sharedSecret(clientPublicKey, key))
 	return nil
 }
 
 // expect sets the packet IDs that are next expected to arrive.
 func (conn *Conn) expect(packetIDs ...uint32) {
-	conn.expectedIDs.Store(packetIDs)
+	conn.expectedIDs.Store(&packetIDs)
 }
 
-// Expect sets the packet IDs that are next expected to arrive.
+// Expect sets the packet IDs that are next expected to arrive during the login sequence, replacing the ones
+// expected before. Packets with one of these IDs are handled by the Conn, and by its ResourcePackHandler for
+// resource pack related packets, while other packets are deferred until the Conn is logged in. Expect may be
+// used by custom ResourcePackHandlers and login flows to declare which packets should be handled next.
 func (conn *Conn) Expect(packetIDs ...uint32) {
-	conn.expect(packetIDs...)
+	conn.expect(slices.Clone(packetIDs)...)
+}
+
+// ExpectAlso adds the packet IDs passed to the packet IDs that are next expected to arrive during the login
+// sequence, keeping the ones expected before.
+func (conn *Conn) ExpectAlso(packetIDs ...uint32) {
+	for {
+		old := conn.expectedIDs.Load()
+		expected := append(slices.Clone(*old), packetIDs...)
+		if conn.expectedIDs.CompareAndSwap(old, &expected) {
+			return
+		}
+	}
+}
+
+// Expected returns the packet IDs that are next expected to arrive during the login sequence.
+func (conn *Conn) Expected() []uint32 {
+	return slices.Clone(*conn.expectedIDs.Load())
+}
+
+// Expecting checks if a packet with the ID passed is expected to arrive next during the login sequence, and
+// would thus be handled by the Conn if it arrived.
+func (conn *Conn) Expecting(packetID uint32) bool {
+	return slices.Contains(*conn.expectedIDs.Load(), packetID)
 }
 
 // closeErr returns an adequate connection closed error for the op passed. If the connection was closed
 // through a Disconnect packet, the message is contained.
 func (conn *Conn) closeErr(op string) error {
-	if msg := *conn.disconnectMessage.Load(); msg != "" {
-		return conn.wrap(DisconnectError(msg), op)
+	if pk := conn.disconnect.Load(); pk != nil {
+		return conn.wrap(DisconnectError{Reason: pk.Reason, Message: pk.Message}, op)
+	}
+	if cause := conn.closeCause.Load(); cause != nil {
+		return conn.wrap(*cause, op)
 	}
 	return conn.wrap(net.ErrClosed, op)
 }
 
+// SetGameData sets the GameData of the Conn, as returned by GameData, without sending it to the other end of
+// the connection. To change the GameData that a client obtained using a Listener is spawned with, use
+// UpdateGameData.
 func (conn *Conn) SetGameData(data GameData) {
 	conn.gameData = data
 }
+
+// UpdateGameData adds a function that modifies the GameData that a client obtained using a Listener is spawned
+// with. The function is called with the GameData passed to StartGame, right before it is sent to the client,
+// so that the spawn position, game mode, dimension, world name or experiments may be changed per player.
+// Functions are called in the order they were added. UpdateGameData has no effect after StartGame was called.
+func (conn *Conn) UpdateGameData(f func(data *GameData)) {
+	conn.gameDataMu.Lock()
+	defer conn.gameDataMu.Unlock()
+	conn.gameDataFuncs = append(conn.gameDataFuncs, f)
+}
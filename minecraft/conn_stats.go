@@ -0,0 +1,228 @@
+package minecraft
+
+import (
+	"expvar"
+	"maps"
+	"net"
+	"strconv"
+	"sync"
+)
+
+// PacketCount holds the amount of packets of a single packet ID that were sent or received over a Conn and
+// the amount of bytes those packets took up, including their header but before compression.
+type PacketCount struct {
+	Packets, Bytes uint64
+}
+
+// ConnStats holds network metrics of a Conn. It may be obtained by calling Conn.Stats.
+type ConnStats struct {
+	// PacketsSent and PacketsReceived are the total amount of packets sent and received over the Conn.
+	PacketsSent, PacketsReceived uint64
+	// BytesSent and BytesReceived are the total amount of bytes of packet data sent and received over the
+	// Conn, before compression and encryption.
+	BytesSent, BytesReceived uint64
+	// Sent and Received hold the PacketCount of every packet ID sent and received over the Conn.
+	Sent, Received map[uint32]PacketCount
+	// BatchesSent and BatchesReceived are the amount of batches of packets sent and received over the
+	// underlying net.Conn.
+	BatchesSent, BatchesReceived uint64
+	// WireBytesSent and WireBytesReceived are the amount of bytes written to and read from the underlying
+	// net.Conn, after compression and encryption.
+	WireBytesSent, WireBytesReceived uint64
+	// Resends is the amount of datagrams that the underlying net.Conn had to send again because they were
+	// lost. It is only filled out if the net.Conn reports it through a Resends() uint64 method.
+	Resends uint64
+}
+
+// CompressionRatio returns the ratio between the size of the packet data sent and received over the Conn
+// and the size of that data on the wire. A ratio of 4 means that data was compressed to a quarter of its
+// original size. CompressionRatio returns 0 if no data was sent or received yet.
+func (stats ConnStats) CompressionRatio() float64 {
+	wire := stats.WireBytesSent + stats.WireBytesReceived
+	if wire == 0 {
+		return 0
+	}
+	return float64(stats.BytesSent+stats.BytesReceived) / float64(wire)
+}
+
+// Metric is a single named value of ConnStats, in a form that may be exported directly to monitoring systems
+// such as Prometheus.
+type Metric struct {
+	// Name is the name of the metric, such as "packets_sent_total".
+	Name string
+	// Labels holds additional labels of the metric, such as the packet ID that a per-packet metric is for.
+	// It is nil for metrics without labels.
+	Labels map[string]string
+	// Value is the value of the metric.
+	Value float64
+}
+
+// Metrics returns all values of the ConnStats as a flat list of Metrics. Totals use names ending with
+// "_total" and per-packet counts carry a "packet_id" label, following the conventions of Prometheus.
+func (stats ConnStats) Metrics() []Metric {
+	metrics := []Metric{
+		{Name: "packets_sent_total", Value: float64(stats.PacketsSent)},
+		{Name: "packets_received_total", Value: float64(stats.PacketsReceived)},
+		{Name: "bytes_sent_total", Value: float64(stats.BytesSent)},
+		{Name: "bytes_received_total", Value: float64(stats.BytesReceived)},
+		{Name: "batches_sent_total", Value: float64(stats.BatchesSent)},
+		{Name: "batches_received_total", Value: float64(stats.BatchesReceived)},
+		{Name: "wire_bytes_sent_total", Value: float64(stats.WireBytesSent)},
+		{Name: "wire_bytes_received_total", Value: float64(stats.WireBytesReceived)},
+		{Name: "resends_total", Value: float64(stats.Resends)},
+		{Name: "compression_ratio", Value: stats.CompressionRatio()},
+	}
+	perPacket := func(prefix string, counts map[uint32]PacketCount) {
+		for id, count := range counts {
+			labels := map[string]string{"packet_id": strconv.FormatUint(uint64(id), 10)}
+			metrics = append(metrics,
+				Metric{Name: prefix + "_packets_total", Labels: labels, Value: float64(count.Packets)},
+				Metric{Name: prefix + "_bytes_total", Labels: labels, Value: float64(count.Bytes)},
+			)
+		}
+	}
+	perPacket("sent", stats.Sent)
+	perPacket("received", stats.Received)
+	return metrics
+}
+
+// Stats returns a snapshot of the network metrics of the Conn, such as the amount of packets and bytes sent
+// and received per packet ID.
+func (conn *Conn) Stats() ConnStats {
+	stats := conn.stats.snapshot()
+	if r, ok := conn.conn.(interface{ Resends() uint64 }); ok {
+		stats.Resends = r.Resends()
+	}
+	return stats
+}
+
+// StatsVar returns an expvar.Var that reports the ConnStats of the Conn as JSON every time it is read. It
+// may be published using expvar.Publish, typically under a name unique to the connection.
+func (conn *Conn) StatsVar() expvar.Var {
+	return expvar.Func(func() any {
+		return conn.Stats()
+	})
+}
+
+// sendStats returns the connStats that packets sent by the Conn are recorded in. Sub-clients send their
+// packets over the connection of their parent, so like the packets they receive, they are recorded by the
+// parent.
+func (conn *Conn) sendStats() *connStats {
+	if conn.parent != nil {
+		return &conn.parent.stats
+	}
+	return &conn.stats
+}
+
+// connStats records the ConnStats of a Conn. It is safe for concurrent use.
+type connStats struct {
+	mu    sync.Mutex
+	stats ConnStats
+}
+
+// sent records a serialised packet with the ID passed being sent.
+func (s *connStats) sent(id uint32, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.PacketsSent++
+	s.stats.BytesSent += uint64(n)
+	if s.stats.Sent == nil {
+		s.stats.Sent = make(map[uint32]PacketCount)
+	}
+	count := s.stats.Sent[id]
+	count.Packets++
+	count.Bytes += uint64(n)
+	s.stats.Sent[id] = count
+}
+
+// received records a serialised packet with the ID passed being received.
+func (s *connStats) received(id uint32, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.PacketsReceived++
+	s.stats.BytesReceived += uint64(n)
+	if s.stats.Received == nil {
+		s.stats.Received = make(map[uint32]PacketCount)
+	}
+	count := s.stats.Received[id]
+	count.Packets++
+	count.Bytes += uint64(n)
+	s.stats.Received[id] = count
+}
+
+// batchSent records a batch of n bytes being written to the underlying net.Conn.
+func (s *connStats) batchSent(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.BatchesSent++
+	s.stats.WireBytesSent += uint64(n)
+}
+
+// batchReceived records a batch of n bytes being read from the underlying net.Conn.
+func (s *connStats) batchReceived(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.BatchesReceived++
+	s.stats.WireBytesReceived += uint64(n)
+}
+
+// snapshot returns a copy of the ConnStats recorded so far.
+func (s *connStats) snapshot() ConnStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats
+	stats.Sent, stats.Received = maps.Clone(s.stats.Sent), maps.Clone(s.stats.Received)
+	return stats
+}
+
+// countingConn is a net.Conn that records every batch written to and read from the net.Conn it wraps in a
+// connStats. The encoder and decoder of a Conn write and read exactly one batch per call.
+type countingConn struct {
+	net.Conn
+	stats *connStats
+}
+
+// countConn wraps the net.Conn passed in a countingConn that records its batches in the connStats passed.
+func countConn(conn net.Conn, stats *connStats) net.Conn {
+	c := &countingConn{Conn: conn, stats: stats}
+	if pr, ok := conn.(interface{ ReadPacket() ([]byte, error) }); ok {
+		// The decoder of a Conn reads full packets if the net.Conn supports it, so we need to make sure we
+		// keep supporting it.
+		return &countingPacketConn{countingConn: c, pr: pr}
+	}
+	return c
+}
+
+// Write writes b to the net.Conn and records it as a batch sent.
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.stats.batchSent(n)
+	}
+	return n, err
+}
+
+// Read reads a batch from the net.Conn into b and records it as a batch received.
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.stats.batchReceived(n)
+	}
+	return n, err
+}
+
+// countingPacketConn is a countingConn for net.Conns that support reading full packets at once, such as
+// RakNet connections.
+type countingPacketConn struct {
+	*countingConn
+	pr interface{ ReadPacket() ([]byte, error) }
+}
+
+// ReadPacket reads a full packet from the net.Conn and records it as a batch received.
+func (c *countingPacketConn) ReadPacket() ([]byte, error) {
+	b, err := c.pr.ReadPacket()
+	if len(b) > 0 {
+		c.stats.batchReceived(len(b))
+	}
+	return b, err
+}
@@ -0,0 +1,96 @@
+package minecraft
+
+import (
+	"sync"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// DecodePool is a pool of goroutines that decode packets received by Conns in parallel. By default, the
+// packets of a Conn are decoded one by one when they are read, so that a single large packet, such as a
+// LevelChunk or CraftingData packet, delays all packets behind it. If a DecodePool is set as the DecodePool of
+// a Dialer or ListenConfig, packets are decoded by the pool as soon as they arrive, while ReadPacket still
+// returns them in the order they were received. A DecodePool may be shared by any number of Conns.
+type DecodePool struct {
+	jobs chan *packetData
+
+	mu     sync.RWMutex
+	closed bool
+	once   sync.Once
+	close  chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewDecodePool creates a DecodePool that decodes packets on the number of goroutines passed. If workers is
+// 0 or lower, a single goroutine is used.
+func NewDecodePool(workers int) *DecodePool {
+	p := &DecodePool{jobs: make(chan *packetData, 256), close: make(chan struct{})}
+	for range max(workers, 1) {
+		p.wg.Add(1)
+		go p.work()
+	}
+	return p
+}
+
+// Close stops the goroutines of the DecodePool once they finish the packets they are decoding. Packets
+// received after the DecodePool is closed are decoded when they are read, like without a DecodePool.
+func (p *DecodePool) Close() error {
+	p.once.Do(func() {
+		// Packets may no longer be submitted once closed is set, so that the goroutines can finish all
+		// packets that were submitted before stopping.
+		p.mu.Lock()
+		p.closed = true
+		p.mu.Unlock()
+
+		close(p.close)
+		p.wg.Wait()
+	})
+	return nil
+}
+
+// submit starts decoding the packetData passed using the settings of the Conn passed. If the DecodePool was
+// closed, the packetData is left as is, so that it is decoded when it is read.
+func (p *DecodePool) submit(conn *Conn, pkData *packetData) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return
+	}
+	pkData.conn, pkData.done = conn, make(chan struct{})
+	p.jobs <- pkData
+}
+
+// work decodes packets submitted to the DecodePool until it is closed and no packets are left.
+func (p *DecodePool) work() {
+	defer p.wg.Done()
+	for {
+		select {
+		case pkData := <-p.jobs:
+			pkData.decodeAsync()
+		case <-p.close:
+			for {
+				select {
+				case pkData := <-p.jobs:
+					pkData.decodeAsync()
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// decodeAsync decodes the packetData using the settings of the Conn it was submitted with and marks it as
+// done, so that the result may be read by the Conn.
+func (p *packetData) decodeAsync() {
+	conn := p.conn
+	p.pks, p.err = p.Decode(conn.pool, conn.proto, conn.Close, conn.disconnectOnUnknownPacket, conn.disconnectOnInvalidPacket, conn.shieldID.Load(), conn.readLimits, conn.strictDecoding, conn.preserveTrailing)
+	close(p.done)
+}
+
+// result returns the packets decoded from an asynchronously decoded packetData, waiting for decoding to
+// complete first.
+func (p *packetData) result() ([]packet.Packet, error) {
+	<-p.done
+	return p.pks, p.err
+}
@@ -12,12 +12,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	rand "math/rand"
 	"net"
 	"os"
 	"strconv"
-	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-jose/go-jose/v3/jwt"
@@ -53,18 +54,95 @@ type Dialer struct {
 	// device auth to login.
 	// If TokenSource is nil, the connection will not use authentication.
 	TokenSource oauth2.TokenSource
+	// Identities, if non-nil, holds the accounts that the Dialer logs in with. Every dial uses the next
+	// Identity in the pool, overriding TokenSource, IdentityData and GetClientData. Dialer.WithIdentity may be
+	// used to dial with a specific Identity instead.
+	Identities *IdentityPool
 
 	// PacketFunc is called whenever a packet is read from or written to the connection returned when using
 	// Dialer.Dial(). It includes packets that are otherwise covered in the connection sequence, such as the
 	// Login packet. The function is called with the header of the packet and its raw payload, the address
 	// from which the packet originated, and the destination address.
 	PacketFunc func(header packet.Header, payload []byte, src, dst net.Addr)
+	// PacketLogger, if non-nil, logs packets read from and written to the Conn to a slog.Handler. Packets
+	// may be filtered by their ID and direction.
+	PacketLogger *PacketLogger
+
+	// UploadRateLimit and DownloadRateLimit limit the amount of bytes per second that may be sent to and
+	// received from the server. Limits are applied to every batch of packets, so that bursts of packets,
+	// for example during resource pack transfers, are spread out. If zero or lower, no limit is applied.
+	UploadRateLimit, DownloadRateLimit int
+	// RateLimitBurst is the maximum amount of bytes that may be sent or received at once without waiting
+	// for the UploadRateLimit or DownloadRateLimit. It is raised to the rate limit if lower.
+	RateLimitBurst int
+
+	// Capture, if non-nil, is an io.Writer that all packets read from and written to the Conn are recorded
+	// to, together with their direction and the time they were read or written. The capture may be read
+	// using NewCaptureReader. Capture is closed when the Conn is closed if it implements io.Closer. If the Conn
+	// follows a Transfer packet, the capture stops at the transfer: The new Conn is not recorded.
+	Capture io.Writer
 
 	// DownloadResourcePack is called individually for every texture and behaviour pack sent by the connection when
 	// using Dialer.Dial(), and can be used to stop the pack from being downloaded. The function is called with the UUID
 	// and version of the resource pack, the number of the current pack being downloaded, and the total amount of packs.
 	// The boolean returned determines if the pack will be downloaded or not.
 	DownloadResourcePack func(id uuid.UUID, version string, current, total int) bool
+	// ResourcePackDecision is called individually for every texture and behaviour pack sent by the connection
+	// when using Dialer.Dial(), with the same arguments as DownloadResourcePack. Unlike DownloadResourcePack, it
+	// allows acknowledging a pack without downloading it by returning PackDecisionAcknowledge, in which case
+	// the metadata of the pack is available through Conn.AcknowledgedPacks. If non-nil, ResourcePackDecision
+	// is used instead of DownloadResourcePack.
+	ResourcePackDecision func(id uuid.UUID, version string, current, total int) PackDecision
+	// SkipResourcePacks specifies if the resource pack sequence should be completed as quickly as possible,
+	// without downloading any of the resource packs sent by the server. This is useful for tools that only
+	// need the packets sent after spawning, as joining servers with many packs then takes much less time.
+	// If true, DownloadResourcePack and ResourcePackDecision are not called.
+	SkipResourcePacks bool
+	// Spawn holds settings for the spawn sequence that starts when the server sends the StartGame packet,
+	// such as packets that should not be waited for and timeouts for each stage of the sequence.
+	Spawn SpawnConfig
+	// LoginTimeouts holds timeouts for the separate phases of the login sequence, which apply in addition to
+	// the context or timeout passed to DialContext or DialTimeout.
+	LoginTimeouts LoginTimeouts
+	// KeepAliveInterval is the interval at which a TickSync packet is sent to the server once spawned, so that
+	// idle connections are not dropped by middleboxes. If zero or lower, no keep alive packets are sent.
+	KeepAliveInterval time.Duration
+
+	// ResourcePackChunkTimeout is the maximum duration to wait for a single chunk of a resource pack sent by
+	// the server. If a chunk does not arrive in time, it is requested again. If zero, a default of 10 seconds
+	// is used. If negative, chunks never time out.
+	ResourcePackChunkTimeout time.Duration
+	// ResourcePackChunkRetries is the maximum number of times a chunk that timed out is requested again. If a
+	// chunk still does not arrive after that, the connection is closed and the error is returned by Dial. If
+	// zero, a default of 3 retries is used. If negative, chunks are never requested again.
+	ResourcePackChunkRetries int
+	// ResourcePackURLTimeout is the maximum duration of downloading a resource pack from a URL sent by the
+	// server. If the download takes longer, it is cancelled and the pack is requested over the connection
+	// instead. If zero, a default of 1 minute is used. If negative, downloads from URLs never time out.
+	ResourcePackURLTimeout time.Duration
+
+	// ExemptedPacks holds the resource packs that the client ships with. The server may apply these packs in
+	// the ResourcePackStack packet without sending them first. If nil, DefaultExemptedPacks is used.
+	ExemptedPacks *ExemptedPacks
+
+	// ResourcePackMemoryLimit is the maximum amount of bytes that resource packs being downloaded from the
+	// server may hold in memory at the same time. Once exceeded, the data of a pack is moved to a temporary
+	// file until the download is complete. If zero or lower, downloads are always held in memory.
+	ResourcePackMemoryLimit int64
+
+	// ResourcePackHooks holds functions that are called during the phases of resource pack negotiation with
+	// the server, such as when a pack starts or finishes downloading.
+	ResourcePackHooks ResourcePackHooks
+
+	// DecryptResourcePacks specifies if encrypted resource packs downloaded from the server should be
+	// decrypted using the content key sent by the server. If set to true, Conn.ResourcePacks returns packs of
+	// which the files may be read directly. Packs that fail to decrypt are kept in their encrypted form.
+	DecryptResourcePacks bool
+
+	// ChecksumPolicy specifies what happens if a resource pack downloaded from the server does not match the
+	// SHA256 checksum that the server sent for it. By default, ChecksumPolicyWarn is used, which logs the
+	// mismatch to the ErrorLog and continues using the pack.
+	ChecksumPolicy ChecksumPolicy
 
 	// DisconnectOnUnknownPackets specifies if the connection should disconnect if packets received are not present
 	// in the packet pool. If true, such packets lead to the connection being closed immediately.
@@ -75,12 +153,37 @@ type Dialer struct {
 	// allowed. If true, such packets lead to the connection being closed immediately. If false,
 	// packets with too many bytes will be returned while packets with too few bytes will be skipped.
 	DisconnectOnInvalidPackets bool
+	// ReaderLimits holds the limits on the lengths of slices and strings in packets read from the server. By
+	// default, no limits are enforced, as packets such as CraftingData legitimately hold many elements.
+	// Length prefixes that exceed the remaining bytes of a packet are rejected regardless.
+	ReaderLimits protocol.ReaderLimits
+	// StrictDecoding specifies if errors returned for packets that could not be decoded, or that had bytes
+	// left after decoding, should include the path of the field being read, such as
+	// 'AvailableCommands.Enums[3].Options'. This is useful when debugging protocol changes, but makes
+	// decoding packets slower.
+	StrictDecoding bool
+	// PreserveTrailingBytes specifies if bytes left after decoding a packet should be preserved rather than
+	// the packet being treated as invalid. Such packets are returned by ReadPacket as a *packet.Trailing that
+	// holds the decoded packet and the bytes left, which are written again when the packet is written, so that
+	// a proxy forwards packets unchanged even if the game added new fields to them.
+	PreserveTrailingBytes bool
 
 	// Protocol is the Protocol version used to communicate with the target server. By default, this field is
 	// set to the current protocol as implemented in the minecraft/protocol package. Note that packets written
 	// to and read from the Conn are always any of those found in the protocol/packet package, as packets
 	// are converted from and to this Protocol.
 	Protocol Protocol
+	// RakNet, if non-nil, holds the settings of the RakNet layer used when dialing over the network "raknet".
+	RakNet *RakNet
+
+	// AcceptedCompressions holds the compression algorithms, such as packet.FlateCompression,
+	// packet.SnappyCompression and packet.NopCompression, that the Dialer accepts from the server. If the
+	// server selects an algorithm not in the slice, dialing fails. If empty, all algorithms are accepted.
+	AcceptedCompressions []packet.Compression
+	// CompressionThreshold, if non-zero, is the minimum size in bytes of a batch sent by the Dialer for it to
+	// be compressed, overriding the threshold sent by the server. Smaller batches, such as those holding only
+	// movement packets, are sent uncompressed. If negative, all batches are compressed.
+	CompressionThreshold int
 
 	// FlushRate is the rate at which packets sent are flushed. Packets are buffered for a duration up to
 	// FlushRate and are compressed/encrypted together to improve compression ratios. The lower this
@@ -89,6 +192,40 @@ type Dialer struct {
 	// will not be flushed automatically. In this case, calling `(*Conn).Flush()` is required after any
 	// calls to `(*Conn).Write()` or `(*Conn).WritePacket()` to send the packets over network.
 	FlushRate time.Duration
+	// FlushSize is the total size in bytes of the packets buffered at which they are flushed immediately,
+	// rather than after FlushRate. It may be used to send large amounts of data in batches of a maximum size.
+	// If zero or lower, packets are only flushed after FlushRate.
+	FlushSize int
+	// FlushPacketIDs holds the IDs of packets, such as packet.IDPlayerAuthInput or packet.IDMovePlayer, after
+	// which all buffered packets are flushed immediately. This reduces latency for packets that are sensitive
+	// to it, without flushing every packet written.
+	FlushPacketIDs []uint32
+	// PacketPriorities holds the Priority of packets sent over the Conn by their ID. If non-nil, packets
+	// with a high priority are always sent first, while normal and low priority packets share the space left
+	// in a batch of up to PriorityBatchSize bytes, so that bulk data does not delay packets such as
+	// PlayerAuthInput. Packets of different priorities are not sent in the order they were written, so
+	// packets that depend on each other must share a Priority. DefaultPacketPriorities may be used as a
+	// sensible default. If nil, packets are sent in the order they are written.
+	PacketPriorities map[uint32]Priority
+	// PriorityBatchSize is the maximum amount of bytes of normal and low priority packets sent in a single
+	// batch if PacketPriorities is set. Packets that do not fit are sent in the next batch. If zero or lower,
+	// all packets are sent in every batch, with packets of a higher priority first.
+	PriorityBatchSize int
+	// ReadRawBatches specifies if batches received after the Conn spawned should not be decoded, but be
+	// returned undecoded by Conn.ReadRawBatch instead, for example so that a proxy can forward them using
+	// Conn.WriteRawBatch. If true, ReadPacket no longer returns packets once the Conn spawned, and packets
+	// such as Disconnect are no longer handled by the Conn.
+	ReadRawBatches bool
+	// DecodePool, if non-nil, decodes packets received by the Conn in parallel as soon as they arrive, instead
+	// of one by one when they are read. Packets are still returned by ReadPacket in the order they were
+	// received. A single DecodePool may be shared by many Dialers and Listeners.
+	DecodePool *DecodePool
+	// PooledPackets specifies if packets read using Conn.ReadPacket after logging in should be decoded from
+	// pooled buffers, so that byte slices in the packets, such as the payload of a LevelChunk packet, alias
+	// the buffer instead of being allocated. This greatly reduces allocations when reading many packets, but
+	// such byte slices are only valid until the next call to ReadPacket or Conn.Release. Conn.ClonePacket may
+	// be used to obtain a copy of a packet that remains valid.
+	PooledPackets bool
 
 	// EnableClientCache, if set to true, enables the client blob cache for the client. This means that the
 	// server will send chunks as blobs, which may be saved by the client so that chunks don't have to be
@@ -101,6 +238,20 @@ type Dialer struct {
 	// For getting this to work with BDS, authentication should be disabled.
 	KeepXBLIdentityData bool
 
+	// TransferFunc, if non-nil, makes the Conn follow Transfer packets sent by the server. When the server
+	// transfers the client, the Conn is closed and the address in the Transfer packet is dialed using the same
+	// Dialer, so with the same identity and client data. TransferFunc is then called with the old Conn and
+	// either the new Conn or the error that dialing it returned. The new Conn still needs to be spawned using
+	// Conn.DoSpawn. Transfer packets are not returned by Conn.ReadPacket if TransferFunc is set.
+	TransferFunc func(old, new *Conn, err error)
+	// TransferTimeout is the maximum duration of dialing the address that the server transferred the client
+	// to if TransferFunc is set. It defaults to 30 seconds.
+	TransferTimeout time.Duration
+	// DeriveKey, if non-nil, derives the encryption key of the connection from the salt sent by the server and
+	// the shared secret produced by the key exchange. If nil, DeriveKey is used. The private key used in the
+	// key exchange is ChainKey. If the server does not start a key exchange, the connection stays unencrypted.
+	DeriveKey func(salt, sharedSecret []byte) [32]byte
+
 	ChainKey  *ecdsa.PrivateKey
 	ChainData string
 
@@ -165,9 +316,17 @@ func CreateChain(ctx context.Context, src oauth2.TokenSource) (key *ecdsa.Privat
 }
 
 // DialContext dials a Minecraft connection to the address passed over the network passed. The network is
-// typically "raknet". A Conn is returned which may be used to receive packets from and send packets to.
+// typically "raknet", or "tcp" for connections between trusted backends, such as proxies. A Conn is returned
+// which may be used to receive packets from and send packets to.
 // If a connection is not established before the context passed is cancelled, DialContext returns an error.
 func (d Dialer) DialContext(ctx context.Context, network, address string, initialTimeout time.Duration) (conn *Conn, err error) {
+	if d.Identities != nil {
+		id, ok := d.Identities.Next()
+		if !ok {
+			return nil, fmt.Errorf("dial: no identities in identity pool")
+		}
+		d = d.WithIdentity(id)
+	}
 	if d.ErrorLog == nil {
 		d.ErrorLog = log.New(os.Stderr, "", log.LstdFlags)
 	}
@@ -177,8 +336,17 @@ func (d Dialer) DialContext(ctx context.Context, network, address string, initia
 	if d.FlushRate == 0 {
 		d.FlushRate = time.Second / 20
 	}
+	if d.ResourcePackChunkTimeout == 0 {
+		d.ResourcePackChunkTimeout = time.Second * 10
+	}
+	if d.ResourcePackChunkRetries == 0 {
+		d.ResourcePackChunkRetries = 3
+	}
+	if d.ResourcePackURLTimeout == 0 {
+		d.ResourcePackURLTimeout = time.Minute
+	}
 
-	n, ok := networkByID(network)
+	n, ok := networkFor(network, d.RakNet)
 	if !ok {
 		return nil, fmt.Errorf("dial: no network under id %v", network)
 	}
@@ -209,14 +377,59 @@ func (d Dialer) DialContext(ctx context.Context, network, address string, initia
 		d.clientData = d.GetClientData()
 	}
 
+	netConn = throttle(netConn, d.UploadRateLimit, d.DownloadRateLimit, d.RateLimitBurst)
 	conn = newConn(netConn, d.ChainKey, d.ErrorLog, d.Protocol, d.FlushRate, false)
 	conn.pool = conn.proto.Packets(false)
 	conn.identityData = d.IdentityData
 	conn.clientData = d.clientData
 	conn.packetFunc = d.PacketFunc
+	conn.packetLogger = d.PacketLogger
+	conn.deriveKey = d.DeriveKey
+	conn.flushSize, conn.flushIDs = d.FlushSize, d.FlushPacketIDs
+	conn.decodePool, conn.pooledPackets = d.DecodePool, d.PooledPackets
+	conn.loginTimeouts, conn.keepAliveInterval = d.LoginTimeouts, d.KeepAliveInterval
+	conn.ticks = &tickTracker{}
+	if d.ReadRawBatches {
+		conn.rawBatches = make(chan []byte, 8)
+	}
+	if d.PacketPriorities != nil {
+		conn.queue = &writeQueue{priorities: d.PacketPriorities, batchSize: d.PriorityBatchSize}
+	}
+	if d.TransferFunc != nil {
+		conn.onTransfer = func(pk *packet.Transfer) {
+			d.followTransfer(conn, network, pk)
+		}
+	}
+	conn.acceptedCompressions = d.AcceptedCompressions
+	conn.compressionThreshold = d.CompressionThreshold
+	if d.Capture != nil {
+		if conn.captureWriter, err = NewCaptureWriter(d.Capture); err != nil {
+			_ = netConn.Close()
+			return nil, &net.OpError{Op: "dial", Net: "minecraft", Err: err}
+		}
+	}
 	conn.downloadResourcePack = d.DownloadResourcePack
+	conn.packDecision = d.ResourcePackDecision
+	conn.skipPacks = d.SkipResourcePacks
+	conn.spawnCfg = d.Spawn
+	conn.checksumPolicy = d.ChecksumPolicy
+	conn.decryptPacks = d.DecryptResourcePacks
+	conn.packHooks = d.ResourcePackHooks
+	if d.ResourcePackMemoryLimit > 0 {
+		conn.packMemory = new(atomic.Int64)
+		conn.packMemory.Store(d.ResourcePackMemoryLimit)
+	}
+	if d.ExemptedPacks != nil {
+		conn.exemptedPacks = d.ExemptedPacks
+	}
+	conn.chunkTimeout = d.ResourcePackChunkTimeout
+	conn.chunkRetries = d.ResourcePackChunkRetries
+	conn.packURLTimeout = d.ResourcePackURLTimeout
 	conn.cacheEnabled = d.EnableClientCache
 	conn.disconnectOnInvalidPacket = d.DisconnectOnInvalidPackets
+	conn.readLimits = d.ReaderLimits
+	conn.strictDecoding = d.StrictDecoding
+	conn.preserveTrailing = d.PreserveTrailingBytes
 	conn.disconnectOnUnknownPacket = d.DisconnectOnUnknownPackets
 
 	defaultIdentityData(&conn.identityData)
@@ -251,6 +464,7 @@ func (d Dialer) DialContext(ctx context.Context, network, address string, initia
 	go listenConn(conn, d.ErrorLog, l, c)
 
 	conn.expect(packet.IDNetworkSettings, packet.IDPlayStatus)
+	conn.startLogin()
 	if err := conn.WritePacket(&packet.RequestNetworkSettings{ClientProtocol: d.Protocol.ID()}); err != nil {
 		return conn, err
 	}
@@ -260,7 +474,7 @@ func (d Dialer) DialContext(ctx context.Context, network, address string, initia
 	case <-conn.close:
 		return conn, conn.closeErr("dial")
 	case <-ctx.Done():
-		return conn, conn.wrap(ctx.Err(), "dial")
+		return conn, conn.wrap(LoginTimeoutError{Stage: "network settings", Err: ctx.Err()}, "dial")
 	case <-l:
 		// We've received our network settings, so we can now send our login request.
 		conn.expect(packet.IDServerToClientHandshake, packet.IDPlayStatus, packet.IDResourcePacksInfo)
@@ -273,7 +487,7 @@ func (d Dialer) DialContext(ctx context.Context, network, address string, initia
 		case <-conn.close:
 			return conn, conn.closeErr("dial")
 		case <-ctx.Done():
-			return conn, conn.wrap(ctx.Err(), "dial")
+			return conn, conn.wrap(LoginTimeoutError{Stage: "login", Err: ctx.Err()}, "dial")
 		case <-c:
 			// We've connected successfully. We return the connection and no error.
 			return conn, nil
@@ -317,10 +531,11 @@ func listenConn(conn *Conn, logger *log.Logger, l, c chan struct{}) {
 	for {
 		// We finally arrived at the packet decoding loop. We constantly decode packets that arrive
 		// and push them to the Conn so that they may be processed.
-		packets, err := conn.dec.Decode()
+		packets, err := conn.decode()
 		if err != nil {
 			if !errors.Is(err, net.ErrClosed) {
 				logger.Printf("dialer conn: %v\n", err)
+				conn.closeWithErr(err)
 			}
 			return
 		}
@@ -328,6 +543,9 @@ func listenConn(conn *Conn, logger *log.Logger, l, c chan struct{}) {
 			loggedInBefore, readyToLoginBefore := conn.loggedIn, conn.readyToLogin
 			if err := conn.receive(data); err != nil {
 				logger.Printf("dialer conn: %v", err)
+				// Close the connection with the error, so that it is returned by Dial if the connection was not
+				// yet logged in.
+				conn.closeWithErr(err)
 				return
 			}
 			if !readyToLoginBefore && conn.readyToLogin {
@@ -479,18 +697,23 @@ func splitPong(s string) []string {
 // found if present, or the original address if not.
 func addressWithPongPort(pong []byte, address string) string {
 	frag := splitPong(string(pong))
-	if len(frag) > 10 {
-		portStr := frag[10]
-		port, err := strconv.Atoi(portStr)
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	// The pong holds the IPv4 port at index 10 and the IPv6 port at index 11.
+	index := 10
+	if isIPv6(host) {
+		index = 11
+	}
+	if len(frag) > index {
+		port, err := strconv.Atoi(frag[index])
 		// Vanilla (realms, in particular) will sometimes send port 19132 when you ping a port that isn't 19132 already,
 		// but we should ignore that.
-		if err != nil || port == 19132 {
+		if err != nil || port == 19132 || port <= 0 || port > 65535 {
 			return address
 		}
-		// Remove the port from the address.
-		addressParts := strings.Split(address, ":")
-		address = strings.Join(strings.Split(address, ":")[:len(addressParts)-1], ":")
-		return address + ":" + portStr
+		return joinHostPort(host, port)
 	}
 	return address
 }
@@ -0,0 +1,39 @@
+package minecraft
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// educationPackets holds the IDs of education edition packets, which are only functional if education
+// features are enabled in the game data.
+var educationPackets = map[uint32]bool{
+	packet.IDLabTable:             true,
+	packet.IDCodeBuilderSource:    true,
+	packet.IDCodeBuilder:          true,
+	packet.IDEducationSettings:    true,
+	packet.IDEducationResourceURI: true,
+}
+
+// educationDisabled checks if the packet passed is an education edition packet that should not be read or
+// written by the Conn, because it was obtained using a Listener of which the game data has education
+// features disabled.
+func (conn *Conn) educationDisabled(pk packet.Packet) bool {
+	return conn.serverSide && conn.gameData.EducationFeaturesDisabled && educationPackets[pk.ID()]
+}
+
+// filterEducationPackets removes the education edition packets from pks if they were sent by a client to a
+// Conn obtained using a Listener of which the game data has education features disabled.
+func (conn *Conn) filterEducationPackets(pks []packet.Packet) []packet.Packet {
+	if !conn.serverSide || !conn.gameData.EducationFeaturesDisabled {
+		return pks
+	}
+	filtered := pks[:0]
+	for _, pk := range pks {
+		if conn.educationDisabled(pk) {
+			conn.log.Printf("ignoring %T: education features are not enabled\n", pk)
+			continue
+		}
+		filtered = append(filtered, pk)
+	}
+	return filtered
+}
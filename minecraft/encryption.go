@@ -0,0 +1,53 @@
+package minecraft
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"slices"
+)
+
+// KeyExchange holds the settings used by a Listener for the key exchange with a client, which takes place
+// through the ServerToClientHandshake and ClientToServerHandshake packets after the client logs in. It is
+// returned by ListenConfig.KeyExchange.
+type KeyExchange struct {
+	// Disabled specifies if encryption should be disabled for the connection. If true, no handshake is sent
+	// and packets are sent unencrypted. This should only be used for trusted links, such as connections over
+	// localhost between a proxy and a server. Note that Minecraft clients may refuse to play on a connection
+	// that is not encrypted.
+	Disabled bool
+	// PrivateKey is the private key used in the key exchange. If nil, the key of the Listener, which is
+	// generated when it starts listening, is used.
+	PrivateKey *ecdsa.PrivateKey
+	// Salt is the salt sent to the client in the ServerToClientHandshake packet and used to derive the
+	// encryption key. If empty, a random salt of 16 bytes is used.
+	Salt []byte
+	// DeriveKey derives the encryption key from the salt and the shared secret produced by the key exchange.
+	// If nil, DeriveKey is used.
+	DeriveKey func(salt, sharedSecret []byte) [32]byte
+}
+
+// DeriveKey derives the key used to encrypt a connection from the salt sent by the server and the shared
+// secret produced by the key exchange, the way Minecraft does: The key is the SHA-256 hash of the salt
+// followed by the shared secret.
+func DeriveKey(salt, sharedSecret []byte) [32]byte {
+	return sha256.Sum256(append(slices.Clone(salt), sharedSecret...))
+}
+
+// sharedSecret computes the ECDH shared secret of the public key and private key passed, padded to 48 bytes.
+func sharedSecret(pub *ecdsa.PublicKey, priv *ecdsa.PrivateKey) []byte {
+	x, _ := pub.Curve.ScalarMult(pub.X, pub.Y, priv.D.Bytes())
+	// Make sure to pad the shared secret up to 48 bytes.
+	return append(bytes.Repeat([]byte{0}, 48-len(x.Bytes())), x.Bytes()...)
+}
+
+// enableEncryptionKey derives the encryption key from the salt and shared secret passed using the function
+// passed, or DeriveKey if nil, and enables encryption on the Conn using it.
+func (conn *Conn) enableEncryptionKey(derive func(salt, sharedSecret []byte) [32]byte, salt, secret []byte) {
+	if derive == nil {
+		derive = DeriveKey
+	}
+	key := derive(salt, secret)
+	conn.enc.EnableEncryption(key)
+	conn.dec.EnableEncryption(key)
+}
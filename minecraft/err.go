@@ -2,7 +2,10 @@ package minecraft
 
 import (
 	"errors"
+	"fmt"
 	"net"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
 
 var (
@@ -27,10 +30,77 @@ func (conn *Conn) wrap(err error, op string) error {
 
 // DisconnectError is an error returned by operations from Conn when the connection is closed by the other
 // end through a packet.Disconnect. It is wrapped in a net.OpError and may be obtained using
-// errors.Unwrap(net.OpError).
-type DisconnectError string
+// errors.Unwrap(net.OpError) or errors.As. It is also returned if the Disconnect packet held no message,
+// for example because the disconnection screen was hidden.
+type DisconnectError struct {
+	// Reason is the reason code sent in the packet.Disconnect.
+	Reason int32
+	// Message is the message held in the packet.Disconnect. It may be a translation key.
+	Message string
+}
 
-// Error returns the message held in the packet.Disconnect.
+// Error returns the message held in the packet.Disconnect, or a description of the reason if the packet held
+// no message.
 func (d DisconnectError) Error() string {
-	return string(d)
+	if d.Message == "" {
+		return fmt.Sprintf("disconnected (reason %v)", d.Reason)
+	}
+	return d.Message
+}
+
+// PlayStatusError is an error returned by Dial when the server refuses the login of the client by sending a
+// packet.PlayStatus with a failure status, for example because the client or server is outdated or because
+// the server is full.
+type PlayStatusError struct {
+	// Status is the status sent by the server, such as packet.PlayStatusLoginFailedClient.
+	Status int32
+}
+
+// Error returns a description of the status of the PlayStatusError.
+func (e PlayStatusError) Error() string {
+	switch e.Status {
+	case packet.PlayStatusLoginFailedClient:
+		return "client outdated"
+	case packet.PlayStatusLoginFailedServer:
+		return "server outdated"
+	case packet.PlayStatusLoginFailedInvalidTenant:
+		return "invalid edu edition game owner"
+	case packet.PlayStatusLoginFailedVanillaEdu:
+		return "cannot join an edu edition game on vanilla"
+	case packet.PlayStatusLoginFailedEduVanilla:
+		return "cannot join a vanilla game on edu edition"
+	case packet.PlayStatusLoginFailedServerFull:
+		return "server full"
+	case packet.PlayStatusLoginFailedEditorVanilla:
+		return "cannot join a vanilla game on editor"
+	case packet.PlayStatusLoginFailedVanillaEditor:
+		return "cannot join an editor game on vanilla"
+	}
+	return fmt.Sprintf("login failed with play status %v", e.Status)
+}
+
+// Outdated checks if the login failed because the protocol version of the client or the server is outdated.
+func (e PlayStatusError) Outdated() bool {
+	return e.Status == packet.PlayStatusLoginFailedClient || e.Status == packet.PlayStatusLoginFailedServer
+}
+
+// LoginTimeoutError is an error returned by Dial when the context passed expires before the login sequence
+// of the connection is complete. It unwraps to the error of the context, so errors.Is may still be used to
+// check for context.DeadlineExceeded.
+type LoginTimeoutError struct {
+	// Stage is the stage of the login sequence that had not completed, such as "network settings" or
+	// "login".
+	Stage string
+	// Err is the error of the context that expired.
+	Err error
+}
+
+// Error ...
+func (e LoginTimeoutError) Error() string {
+	return fmt.Sprintf("login timed out waiting for %v: %v", e.Stage, e.Err)
+}
+
+// Unwrap returns the error of the context that expired.
+func (e LoginTimeoutError) Unwrap() error {
+	return e.Err
 }
@@ -0,0 +1,69 @@
+package minecraft
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// ExemptedPacks is a set of resource packs that do not need to be downloaded, as the client ships with them,
+// but that may always be applied in the ResourcePackStack packet. New packs of this kind are occasionally
+// added in game updates, so the set may be updated at runtime, after which the changes apply to all
+// connections that use it. ExemptedPacks is safe for concurrent use.
+type ExemptedPacks struct {
+	mu    sync.RWMutex
+	packs []exemptedResourcePack
+}
+
+// NewExemptedPacks returns an ExemptedPacks set that holds the packs passed.
+func NewExemptedPacks(packs ...protocol.StackResourcePack) *ExemptedPacks {
+	e := &ExemptedPacks{}
+	for _, pack := range packs {
+		e.Add(pack.UUID, pack.Version)
+	}
+	return e
+}
+
+// DefaultExemptedPacks returns a new ExemptedPacks set that holds the packs that the client is known to ship
+// with, such as the chemistry pack. It is used by a Listener and Dialer if no ExemptedPacks are set.
+func DefaultExemptedPacks() *ExemptedPacks {
+	return NewExemptedPacks(protocol.StackResourcePack{UUID: "0fba4063-dba1-4281-9b89-ff9390653530", Version: "1.0.0"})
+}
+
+// Add adds the pack with the UUID and version passed to the set. Nothing happens if the set already holds
+// the pack.
+func (e *ExemptedPacks) Add(uuid, version string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !slices.Contains(e.packs, exemptedResourcePack{uuid: uuid, version: version}) {
+		e.packs = append(e.packs, exemptedResourcePack{uuid: uuid, version: version})
+	}
+}
+
+// Remove removes the pack with the UUID and version passed from the set.
+func (e *ExemptedPacks) Remove(uuid, version string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.packs = slices.DeleteFunc(e.packs, func(pack exemptedResourcePack) bool {
+		return pack.uuid == uuid && pack.version == version
+	})
+}
+
+// Contains checks if the set holds the pack with the UUID and version passed.
+func (e *ExemptedPacks) Contains(uuid, version string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return slices.Contains(e.packs, exemptedResourcePack{uuid: uuid, version: version})
+}
+
+// Packs returns all packs currently held by the set.
+func (e *ExemptedPacks) Packs() []protocol.StackResourcePack {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	packs := make([]protocol.StackResourcePack, 0, len(e.packs))
+	for _, pack := range e.packs {
+		packs = append(packs, protocol.StackResourcePack{UUID: pack.uuid, Version: pack.version})
+	}
+	return packs
+}
@@ -0,0 +1,238 @@
+package form
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Custom is a form with a title and a list of elements, such as inputs and toggles, built from the struct
+// that Elements points to. Each exported field of the struct that is an Element, such as Input or Toggle,
+// is shown in the order of the fields. When the response is parsed, the Value of each element is set to the
+// value submitted by the client.
+type Custom struct {
+	// Title is the title of the form, shown at the top.
+	Title string
+	// Elements is a pointer to a struct holding the elements of the form as fields.
+	Elements any
+}
+
+// Element is an element of a Custom form. It is one of Label, Input, Toggle, Slider, Dropdown or StepSlider.
+type Element interface {
+	element() map[string]any
+	parse(data json.RawMessage) error
+}
+
+// MarshalJSON ...
+func (c Custom) MarshalJSON() ([]byte, error) {
+	elements, err := c.elements()
+	if err != nil {
+		return nil, err
+	}
+	content := make([]map[string]any, len(elements))
+	for i, e := range elements {
+		content[i] = e.element()
+	}
+	return json.Marshal(map[string]any{
+		"type":    "custom_form",
+		"title":   c.Title,
+		"content": content,
+	})
+}
+
+// Parse parses the response to the Custom form, setting the Value of each element in the struct that Elements
+// points to. An error is returned if a value submitted is invalid for its element, such as a dropdown index
+// out of range or a slider value outside its bounds, in which case the struct may be partially filled out.
+// ErrClosed is returned if the client closed the form.
+func (c Custom) Parse(pk *packet.ModalFormResponse) error {
+	data, err := responseData(pk)
+	if err != nil {
+		return err
+	}
+	elements, err := c.elements()
+	if err != nil {
+		return err
+	}
+	var values []json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("parse custom form response: %w", err)
+	}
+	if len(values) != len(elements) {
+		return fmt.Errorf("parse custom form response: expected %v values, got %v", len(elements), len(values))
+	}
+	for i, e := range elements {
+		if err := e.parse(values[i]); err != nil {
+			return fmt.Errorf("parse custom form response: element %v (%T): %w", i, e, err)
+		}
+	}
+	return nil
+}
+
+// elements returns the elements held by the struct that Elements points to.
+func (c Custom) elements() ([]Element, error) {
+	v := reflect.ValueOf(c.Elements)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("custom form elements must be a pointer to a struct, got %T", c.Elements)
+	}
+	v = v.Elem()
+	var elements []Element
+	for i := range v.NumField() {
+		if !v.Type().Field(i).IsExported() {
+			continue
+		}
+		if e, ok := v.Field(i).Addr().Interface().(Element); ok {
+			elements = append(elements, e)
+		}
+	}
+	return elements, nil
+}
+
+// Label is an element showing text. It has no value.
+type Label struct {
+	// Text is the text shown.
+	Text string
+}
+
+func (l *Label) element() map[string]any {
+	return map[string]any{"type": "label", "text": l.Text}
+}
+
+func (l *Label) parse(json.RawMessage) error {
+	return nil
+}
+
+// Input is an element in which the client may enter text.
+type Input struct {
+	// Text is the text shown above the input.
+	Text string
+	// Default is the text entered by default.
+	Default string
+	// Placeholder is the text shown in the input when it is empty.
+	Placeholder string
+	// Value is the text entered by the client, set when the response is parsed.
+	Value string
+}
+
+func (in *Input) element() map[string]any {
+	return map[string]any{"type": "input", "text": in.Text, "default": in.Default, "placeholder": in.Placeholder}
+}
+
+func (in *Input) parse(data json.RawMessage) error {
+	return json.Unmarshal(data, &in.Value)
+}
+
+// Toggle is an element that the client may switch on or off.
+type Toggle struct {
+	// Text is the text shown next to the toggle.
+	Text string
+	// Default specifies if the toggle is on by default.
+	Default bool
+	// Value specifies if the toggle was on when the form was submitted, set when the response is parsed.
+	Value bool
+}
+
+func (t *Toggle) element() map[string]any {
+	return map[string]any{"type": "toggle", "text": t.Text, "default": t.Default}
+}
+
+func (t *Toggle) parse(data json.RawMessage) error {
+	return json.Unmarshal(data, &t.Value)
+}
+
+// Slider is an element with which the client may select a number in a range.
+type Slider struct {
+	// Text is the text shown above the slider.
+	Text string
+	// Min and Max are the lowest and highest numbers that may be selected.
+	Min, Max float64
+	// StepSize is the difference between two numbers that may be selected. If 0, a step size of 1 is used.
+	StepSize float64
+	// Default is the number selected by default.
+	Default float64
+	// Value is the number selected by the client, set when the response is parsed.
+	Value float64
+}
+
+func (s *Slider) element() map[string]any {
+	step := s.StepSize
+	if step == 0 {
+		step = 1
+	}
+	return map[string]any{"type": "slider", "text": s.Text, "min": s.Min, "max": s.Max, "step": step, "default": s.Default}
+}
+
+func (s *Slider) parse(data json.RawMessage) error {
+	var v float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if math.IsNaN(v) || v < s.Min || v > s.Max {
+		return fmt.Errorf("slider value %v out of range %v-%v", v, s.Min, s.Max)
+	}
+	s.Value = v
+	return nil
+}
+
+// Dropdown is an element with which the client may select one of a list of options.
+type Dropdown struct {
+	// Text is the text shown above the dropdown.
+	Text string
+	// Options holds the options that may be selected.
+	Options []string
+	// Default is the index of the option selected by default.
+	Default int
+	// Value is the index of the option selected by the client, set when the response is parsed.
+	Value int
+}
+
+func (d *Dropdown) element() map[string]any {
+	return map[string]any{"type": "dropdown", "text": d.Text, "options": nonNil(d.Options), "default": d.Default}
+}
+
+func (d *Dropdown) parse(data json.RawMessage) error {
+	return parseIndex(data, len(d.Options), &d.Value)
+}
+
+// StepSlider is an element with which the client may select one of a list of options using a slider.
+type StepSlider struct {
+	// Text is the text shown above the slider.
+	Text string
+	// Options holds the options that may be selected.
+	Options []string
+	// Default is the index of the option selected by default.
+	Default int
+	// Value is the index of the option selected by the client, set when the response is parsed.
+	Value int
+}
+
+func (s *StepSlider) element() map[string]any {
+	return map[string]any{"type": "step_slider", "text": s.Text, "steps": nonNil(s.Options), "default": s.Default}
+}
+
+func (s *StepSlider) parse(data json.RawMessage) error {
+	return parseIndex(data, len(s.Options), &s.Value)
+}
+
+// parseIndex parses an option index, checking if it is within the number of options passed.
+func parseIndex(data json.RawMessage, options int, index *int) error {
+	var v int
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v < 0 || v >= options {
+		return fmt.Errorf("option index %v out of range for %v options", v, options)
+	}
+	*index = v
+	return nil
+}
+
+// nonNil returns an empty slice if the slice passed is nil, so that it is encoded as an empty JSON array.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,5 @@
+// Package form implements the forms that a server may show to a client using the ModalFormRequest packet,
+// and the parsing of the ModalFormResponse packet the client sends back. Modal forms have two buttons, menu
+// forms have a list of buttons and custom forms are built from a struct holding elements such as inputs,
+// toggles, sliders and dropdowns, whose values are filled out when the response is parsed.
+package form
@@ -0,0 +1,136 @@
+package form
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// ErrClosed is returned when parsing the response to a form that the client closed without submitting it,
+// for example by clicking the X at the top right corner of the form.
+var ErrClosed = errors.New("form closed")
+
+// Form is a form that may be sent to a client in a ModalFormRequest packet. It is one of Modal, Menu or
+// Custom.
+type Form interface {
+	json.Marshaler
+}
+
+// Request returns a ModalFormRequest packet with the form ID passed that makes the client open the Form.
+func Request(formID uint32, f Form) (*packet.ModalFormRequest, error) {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, fmt.Errorf("encode form: %w", err)
+	}
+	return &packet.ModalFormRequest{FormID: formID, FormData: data}, nil
+}
+
+// responseData returns the response data of the ModalFormResponse passed, or ErrClosed if the client closed
+// the form.
+func responseData(pk *packet.ModalFormResponse) ([]byte, error) {
+	data, ok := pk.ResponseData.Value()
+	if !ok {
+		return nil, ErrClosed
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, ErrClosed
+	}
+	return data, nil
+}
+
+// Modal is a form with a title, content and two buttons.
+type Modal struct {
+	// Title is the title of the form, shown at the top.
+	Title string
+	// Content is the text shown in the body of the form.
+	Content string
+	// Button1 and Button2 are the texts of the two buttons of the form.
+	Button1, Button2 string
+}
+
+// MarshalJSON ...
+func (m Modal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"type":    "modal",
+		"title":   m.Title,
+		"content": m.Content,
+		"button1": m.Button1,
+		"button2": m.Button2,
+	})
+}
+
+// Parse parses the response to the Modal, returning true if Button1 was clicked and false if Button2 was
+// clicked. ErrClosed is returned if the client closed the form.
+func (m Modal) Parse(pk *packet.ModalFormResponse) (bool, error) {
+	data, err := responseData(pk)
+	if err != nil {
+		return false, err
+	}
+	var b bool
+	if err := json.Unmarshal(data, &b); err != nil {
+		return false, fmt.Errorf("parse modal form response: %w", err)
+	}
+	return b, nil
+}
+
+// Menu is a form with a title, content and a list of buttons, of which the client may click one.
+type Menu struct {
+	// Title is the title of the form, shown at the top.
+	Title string
+	// Content is the text shown above the buttons.
+	Content string
+	// Buttons holds the buttons of the form.
+	Buttons []Button
+}
+
+// Button is a button of a Menu.
+type Button struct {
+	// Text is the text shown on the button.
+	Text string
+	// Image is the image shown on the button. It is either a URL, starting with 'http://' or 'https://', or
+	// the path of a texture in a resource pack, such as 'textures/items/apple'. No image is shown if it is
+	// empty.
+	Image string
+}
+
+// MarshalJSON ...
+func (m Menu) MarshalJSON() ([]byte, error) {
+	buttons := make([]map[string]any, len(m.Buttons))
+	for i, b := range m.Buttons {
+		buttons[i] = map[string]any{"text": b.Text}
+		if b.Image != "" {
+			imageType := "path"
+			if bytes.HasPrefix([]byte(b.Image), []byte("http://")) || bytes.HasPrefix([]byte(b.Image), []byte("https://")) {
+				imageType = "url"
+			}
+			buttons[i]["image"] = map[string]any{"type": imageType, "data": b.Image}
+		}
+	}
+	return json.Marshal(map[string]any{
+		"type":    "form",
+		"title":   m.Title,
+		"content": m.Content,
+		"buttons": buttons,
+	})
+}
+
+// Parse parses the response to the Menu, returning the index of the button clicked. ErrClosed is returned if
+// the client closed the form.
+func (m Menu) Parse(pk *packet.ModalFormResponse) (int, error) {
+	data, err := responseData(pk)
+	if err != nil {
+		return 0, err
+	}
+	var index int
+	if err := json.Unmarshal(data, &index); err != nil {
+		return 0, fmt.Errorf("parse menu form response: %w", err)
+	}
+	if index < 0 || index >= len(m.Buttons) {
+		return 0, fmt.Errorf("parse menu form response: button index %v out of range for %v buttons", index, len(m.Buttons))
+	}
+	return index, nil
+}
@@ -111,4 +111,19 @@ type GameData struct {
 
 	// ServerAuthoritativeSound ...
 	ServerAuthoritativeSound bool
+
+	// EducationFeaturesDisabled is true if the world does not have education edition features enabled, such
+	// as the blocks and entities specific to education edition. Education features are enabled by default.
+	// If true, a Conn obtained using a Listener ignores education edition packets sent by the client, such as
+	// LabTable and CodeBuilderSource, and does not write education edition packets, such as
+	// EducationSettings, to it.
+	EducationFeaturesDisabled bool
+	// EducationEditionOffer specifies what 'region' an education edition world was from. It is one of the
+	// packet.EducationEditionOffer constants.
+	EducationEditionOffer int32
+	// EducationProductID is a UUID used to identify the education edition server instance.
+	EducationProductID string
+	// EducationSharedResourceURI holds the name and link of a button that education edition clients show to
+	// open a resource shared by the server.
+	EducationSharedResourceURI protocol.EducationSharedResourceURI
 }
@@ -0,0 +1,73 @@
+package minecraft
+
+import (
+	"sync"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"golang.org/x/oauth2"
+)
+
+// Identity is an account that a Dialer may log in to a server with. Identities are typically held by an
+// IdentityPool, so that a single Dialer may be used to dial with many accounts.
+type Identity struct {
+	// TokenSource is the source of Microsoft Live Connect tokens of the account. If nil, the account does not
+	// use authentication and IdentityData is used instead.
+	TokenSource oauth2.TokenSource
+	// IdentityData is the identity data logged in with if TokenSource is nil.
+	IdentityData login.IdentityData
+	// ClientData is the client data logged in with. Fields left empty are filled with defaults.
+	ClientData login.ClientData
+}
+
+// IdentityPool holds a pool of Identities that a Dialer rotates through. It may be set as Dialer.Identities,
+// in which case every dial uses the next Identity in the pool, so that multiple accounts can share the same
+// Dialer. An IdentityPool is safe for concurrent use.
+type IdentityPool struct {
+	mu         sync.Mutex
+	identities []Identity
+	next       int
+}
+
+// NewIdentityPool returns an IdentityPool holding the Identities passed.
+func NewIdentityPool(identities ...Identity) *IdentityPool {
+	return &IdentityPool{identities: identities}
+}
+
+// Add adds the Identities passed to the IdentityPool.
+func (p *IdentityPool) Add(identities ...Identity) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.identities = append(p.identities, identities...)
+}
+
+// Len returns the amount of Identities in the IdentityPool.
+func (p *IdentityPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.identities)
+}
+
+// Next returns the next Identity in the IdentityPool. Identities are returned in the order they were added,
+// starting from the first again after the last was returned. False is returned if the IdentityPool is empty.
+func (p *IdentityPool) Next() (Identity, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.identities) == 0 {
+		return Identity{}, false
+	}
+	id := p.identities[p.next%len(p.identities)]
+	p.next = (p.next + 1) % len(p.identities)
+	return id, true
+}
+
+// WithIdentity returns a copy of the Dialer that logs in with the Identity passed. The Identities of the
+// Dialer returned are cleared, so that the Identity passed is always used.
+func (d Dialer) WithIdentity(id Identity) Dialer {
+	d.Identities = nil
+	d.TokenSource = id.TokenSource
+	d.IdentityData = id.IdentityData
+	d.GetClientData = func() login.ClientData { return id.ClientData }
+	// The chain of the Dialer belongs to another account, so a new one must be created for the Identity.
+	d.ChainKey, d.ChainData = nil, ""
+	return d
+}
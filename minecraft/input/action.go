@@ -0,0 +1,112 @@
+package input
+
+import (
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Block faces as used in block actions and item interactions.
+const (
+	FaceDown = iota
+	FaceUp
+	FaceNorth
+	FaceSouth
+	FaceWest
+	FaceEast
+)
+
+// faceNames holds the names of the block faces, indexed by face.
+var faceNames = [...]string{"down", "up", "north", "south", "west", "east"}
+
+// FaceName returns the name of the block face passed, such as 'north'.
+func FaceName(face int32) string {
+	if face < 0 || int(face) >= len(faceNames) {
+		return fmt.Sprintf("face(%v)", face)
+	}
+	return faceNames[face]
+}
+
+// Side returns the position of the block adjacent to the position passed on the face passed. The position is
+// returned unchanged if the face is not valid.
+func Side(pos protocol.BlockPos, face int32) protocol.BlockPos {
+	switch face {
+	case FaceDown:
+		pos[1]--
+	case FaceUp:
+		pos[1]++
+	case FaceNorth:
+		pos[2]--
+	case FaceSouth:
+		pos[2]++
+	case FaceWest:
+		pos[0]--
+	case FaceEast:
+		pos[0]++
+	}
+	return pos
+}
+
+// actionNames holds the names of the protocol.PlayerAction constants, indexed by action.
+var actionNames = [...]string{
+	"StartBreak", "AbortBreak", "StopBreak", "GetUpdatedBlock", "DropItem", "StartSleeping", "StopSleeping",
+	"Respawn", "Jump", "StartSprint", "StopSprint", "StartSneak", "StopSneak", "CreativePlayerDestroyBlock",
+	"DimensionChangeDone", "StartGlide", "StopGlide", "BuildDenied", "CrackBreak", "ChangeSkin",
+	"SetEnchantmentSeed", "StartSwimming", "StopSwimming", "StartSpinAttack", "StopSpinAttack",
+	"StartBuildingBlock", "PredictDestroyBlock", "ContinueDestroyBlock", "StartItemUseOn", "StopItemUseOn",
+	"HandledTeleport", "MissedSwing", "StartCrawling", "StopCrawling", "StartFlying", "StopFlying",
+	"ClientAckServerData",
+}
+
+// ActionName returns the name of the protocol.PlayerAction constant passed without its prefix, such as
+// 'StartBreak'.
+func ActionName(action int32) string {
+	if action < 0 || int(action) >= len(actionNames) {
+		return fmt.Sprintf("action(%v)", action)
+	}
+	return actionNames[action]
+}
+
+// BlockAction is a block action performed by a player, as found in the BlockActions field of a
+// PlayerAuthInput packet.
+type BlockAction protocol.PlayerBlockAction
+
+// BlockActions returns the block actions of the PlayerAuthInput packet passed. Nil is returned if the packet
+// does not have the packet.InputFlagPerformBlockActions flag set.
+func BlockActions(pk *packet.PlayerAuthInput) []BlockAction {
+	if pk.InputData&packet.InputFlagPerformBlockActions == 0 {
+		return nil
+	}
+	actions := make([]BlockAction, len(pk.BlockActions))
+	for i, a := range pk.BlockActions {
+		actions[i] = BlockAction(a)
+	}
+	return actions
+}
+
+// Name returns the name of the action of the BlockAction, such as 'StartBreak'.
+func (a BlockAction) Name() string {
+	return ActionName(a.Action)
+}
+
+// Breaking checks if the BlockAction is part of breaking a block: Starting, continuing, aborting, stopping
+// or cracking a block being broken.
+func (a BlockAction) Breaking() bool {
+	switch a.Action {
+	case protocol.PlayerActionStartBreak, protocol.PlayerActionContinueDestroyBlock, protocol.PlayerActionAbortBreak,
+		protocol.PlayerActionStopBreak, protocol.PlayerActionCrackBreak:
+		return true
+	}
+	return false
+}
+
+// Destroys checks if the BlockAction reports that the player destroyed the block at its position.
+func (a BlockAction) Destroys() bool {
+	return a.Action == protocol.PlayerActionPredictDestroyBlock || a.Action == protocol.PlayerActionCreativePlayerDestroyBlock
+}
+
+// String returns a readable representation of the BlockAction, such as 'StartBreak 1 64 -3 (north)'.
+func (a BlockAction) String() string {
+	return fmt.Sprintf("%v %v %v %v (%v)", a.Name(), a.BlockPos[0], a.BlockPos[1], a.BlockPos[2], FaceName(a.Face))
+}
@@ -0,0 +1,61 @@
+package input
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Delta holds the changes between two successive PlayerAuthInput packets of a player.
+type Delta struct {
+	// Ticks is the number of ticks between the two inputs. It is usually 1, but may be larger if inputs were
+	// skipped or lost.
+	Ticks uint64
+	// Movement is the change in position of the player.
+	Movement mgl32.Vec3
+	// Pitch, Yaw and HeadYaw are the changes in rotation of the player, in degrees. Yaw and HeadYaw are
+	// normalised to the range [-180, 180), so that turning across the wrap-around point results in a small
+	// change.
+	Pitch, Yaw, HeadYaw float32
+	// Pressed holds the input flags that are set in the new input but not in the old one. Released holds
+	// those set in the old input but not in the new one.
+	Pressed, Released Flags
+}
+
+// DeltaOf returns the Delta between the PlayerAuthInput packet prev and the PlayerAuthInput packet cur that
+// followed it.
+func DeltaOf(prev, cur *packet.PlayerAuthInput) Delta {
+	var ticks uint64
+	if cur.Tick > prev.Tick {
+		ticks = cur.Tick - prev.Tick
+	}
+	return Delta{
+		Ticks:    ticks,
+		Movement: cur.Position.Sub(prev.Position),
+		Pitch:    cur.Pitch - prev.Pitch,
+		Yaw:      angleDelta(prev.Yaw, cur.Yaw),
+		HeadYaw:  angleDelta(prev.HeadYaw, cur.HeadYaw),
+		Pressed:  FlagsOf(cur.InputData &^ prev.InputData),
+		Released: FlagsOf(prev.InputData &^ cur.InputData),
+	}
+}
+
+// HorizontalSpeed returns the horizontal distance moved per tick.
+func (d Delta) HorizontalSpeed() float32 {
+	return mgl32.Vec2{d.Movement[0], d.Movement[2]}.Len() / float32(max(d.Ticks, 1))
+}
+
+// VerticalSpeed returns the vertical distance moved per tick. It is negative when the player moves down.
+func (d Delta) VerticalSpeed() float32 {
+	return d.Movement[1] / float32(max(d.Ticks, 1))
+}
+
+// angleDelta returns the difference between the angles a and b in degrees, normalised to [-180, 180).
+func angleDelta(a, b float32) float32 {
+	d := math.Mod(float64(b-a)+180, 360)
+	if d < 0 {
+		d += 360
+	}
+	return float32(d - 180)
+}
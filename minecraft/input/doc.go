@@ -0,0 +1,5 @@
+// Package input implements helpers for interpreting the PlayerAuthInput packet sent by clients every tick.
+// The input bitset of the packet may be expanded into named booleans using FlagsOf, block actions and item
+// interactions may be obtained in a friendlier form using BlockActions and ItemInteractionOf, and the
+// changes between two successive inputs may be computed using DeltaOf, for example for movement analysis.
+package input
@@ -0,0 +1,143 @@
+package input
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Flags holds the input flags of a PlayerAuthInput packet as named booleans. Each field corresponds to the
+// packet.InputFlag constant with the same name. Flags may be obtained from the InputData field of the packet
+// using FlagsOf and converted back using Flags.Bits.
+type Flags struct {
+	Ascend                    bool
+	Descend                   bool
+	NorthJump                 bool
+	JumpDown                  bool
+	SprintDown                bool
+	ChangeHeight              bool
+	Jumping                   bool
+	AutoJumpingInWater        bool
+	Sneaking                  bool
+	SneakDown                 bool
+	Up                        bool
+	Down                      bool
+	Left                      bool
+	Right                     bool
+	UpLeft                    bool
+	UpRight                   bool
+	WantUp                    bool
+	WantDown                  bool
+	WantDownSlow              bool
+	WantUpSlow                bool
+	Sprinting                 bool
+	AscendBlock               bool
+	DescendBlock              bool
+	SneakToggleDown           bool
+	PersistSneak              bool
+	StartSprinting            bool
+	StopSprinting             bool
+	StartSneaking             bool
+	StopSneaking              bool
+	StartSwimming             bool
+	StopSwimming              bool
+	StartJumping              bool
+	StartGliding              bool
+	StopGliding               bool
+	PerformItemInteraction    bool
+	PerformBlockActions       bool
+	PerformItemStackRequest   bool
+	HandledTeleport           bool
+	Emoting                   bool
+	MissedSwing               bool
+	StartCrawling             bool
+	StopCrawling              bool
+	StartFlying               bool
+	StopFlying                bool
+	ClientAckServerData       bool
+	ClientPredictedVehicle    bool
+	PaddlingLeft              bool
+	PaddlingRight             bool
+	BlockBreakingDelayEnabled bool
+}
+
+// FlagsOf returns the Flags set in the input bitset passed, typically the InputData field of a
+// PlayerAuthInput packet.
+func FlagsOf(bits uint64) Flags {
+	var f Flags
+	for _, field := range f.fields() {
+		*field.v = bits&field.flag != 0
+	}
+	return f
+}
+
+// Bits returns the input bitset holding the Flags, as used in the InputData field of a PlayerAuthInput
+// packet.
+func (f Flags) Bits() uint64 {
+	var bits uint64
+	for _, field := range f.fields() {
+		if *field.v {
+			bits |= field.flag
+		}
+	}
+	return bits
+}
+
+// flagField is a field of Flags together with the input flag it represents.
+type flagField struct {
+	flag uint64
+	v    *bool
+}
+
+// fields returns the fields of the Flags with the input flags they represent.
+func (f *Flags) fields() []flagField {
+	return []flagField{
+		{packet.InputFlagAscend, &f.Ascend},
+		{packet.InputFlagDescend, &f.Descend},
+		{packet.InputFlagNorthJump, &f.NorthJump},
+		{packet.InputFlagJumpDown, &f.JumpDown},
+		{packet.InputFlagSprintDown, &f.SprintDown},
+		{packet.InputFlagChangeHeight, &f.ChangeHeight},
+		{packet.InputFlagJumping, &f.Jumping},
+		{packet.InputFlagAutoJumpingInWater, &f.AutoJumpingInWater},
+		{packet.InputFlagSneaking, &f.Sneaking},
+		{packet.InputFlagSneakDown, &f.SneakDown},
+		{packet.InputFlagUp, &f.Up},
+		{packet.InputFlagDown, &f.Down},
+		{packet.InputFlagLeft, &f.Left},
+		{packet.InputFlagRight, &f.Right},
+		{packet.InputFlagUpLeft, &f.UpLeft},
+		{packet.InputFlagUpRight, &f.UpRight},
+		{packet.InputFlagWantUp, &f.WantUp},
+		{packet.InputFlagWantDown, &f.WantDown},
+		{packet.InputFlagWantDownSlow, &f.WantDownSlow},
+		{packet.InputFlagWantUpSlow, &f.WantUpSlow},
+		{packet.InputFlagSprinting, &f.Sprinting},
+		{packet.InputFlagAscendBlock, &f.AscendBlock},
+		{packet.InputFlagDescendBlock, &f.DescendBlock},
+		{packet.InputFlagSneakToggleDown, &f.SneakToggleDown},
+		{packet.InputFlagPersistSneak, &f.PersistSneak},
+		{packet.InputFlagStartSprinting, &f.StartSprinting},
+		{packet.InputFlagStopSprinting, &f.StopSprinting},
+		{packet.InputFlagStartSneaking, &f.StartSneaking},
+		{packet.InputFlagStopSneaking, &f.StopSneaking},
+		{packet.InputFlagStartSwimming, &f.StartSwimming},
+		{packet.InputFlagStopSwimming, &f.StopSwimming},
+		{packet.InputFlagStartJumping, &f.StartJumping},
+		{packet.InputFlagStartGliding, &f.StartGliding},
+		{packet.InputFlagStopGliding, &f.StopGliding},
+		{packet.InputFlagPerformItemInteraction, &f.PerformItemInteraction},
+		{packet.InputFlagPerformBlockActions, &f.PerformBlockActions},
+		{packet.InputFlagPerformItemStackRequest, &f.PerformItemStackRequest},
+		{packet.InputFlagHandledTeleport, &f.HandledTeleport},
+		{packet.InputFlagEmoting, &f.Emoting},
+		{packet.InputFlagMissedSwing, &f.MissedSwing},
+		{packet.InputFlagStartCrawling, &f.StartCrawling},
+		{packet.InputFlagStopCrawling, &f.StopCrawling},
+		{packet.InputFlagStartFlying, &f.StartFlying},
+		{packet.InputFlagStopFlying, &f.StopFlying},
+		{packet.InputFlagClientAckServerData, &f.ClientAckServerData},
+		{packet.InputFlagClientPredictedVehicle, &f.ClientPredictedVehicle},
+		{packet.InputFlagPaddlingLeft, &f.PaddlingLeft},
+		{packet.InputFlagPaddlingRight, &f.PaddlingRight},
+		{packet.InputFlagBlockBreakingDelayEnabled, &f.BlockBreakingDelayEnabled},
+	}
+}
@@ -0,0 +1,73 @@
+package input
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// ItemInteraction is an interaction of a player with the item it holds, as found in the ItemInteractionData
+// field of a PlayerAuthInput packet.
+type ItemInteraction struct {
+	// Type is the type of the interaction. It is one of the protocol.UseItemAction constants.
+	Type uint32
+	// BlockPosition and BlockFace are the position and face of the block clicked or broken. They are not
+	// set if Type is protocol.UseItemActionClickAir.
+	BlockPosition protocol.BlockPos
+	BlockFace     int32
+	// ClickedPosition is the position clicked relative to BlockPosition.
+	ClickedPosition mgl32.Vec3
+	// BlockRuntimeID is the runtime ID of the block clicked, as the client sees it.
+	BlockRuntimeID uint32
+	// HotBarSlot and HeldItem are the hot bar slot selected and the item held during the interaction.
+	HotBarSlot int32
+	HeldItem   protocol.ItemStack
+	// PlayerPosition is the position of the player during the interaction.
+	PlayerPosition mgl32.Vec3
+}
+
+// ItemInteractionOf returns the item interaction of the PlayerAuthInput packet passed. False is returned if
+// the packet does not have the packet.InputFlagPerformItemInteraction flag set.
+func ItemInteractionOf(pk *packet.PlayerAuthInput) (ItemInteraction, bool) {
+	if pk.InputData&packet.InputFlagPerformItemInteraction == 0 {
+		return ItemInteraction{}, false
+	}
+	data := pk.ItemInteractionData
+	return ItemInteraction{
+		Type:            data.ActionType,
+		BlockPosition:   data.BlockPosition,
+		BlockFace:       data.BlockFace,
+		ClickedPosition: data.ClickedPosition,
+		BlockRuntimeID:  data.BlockRuntimeID,
+		HotBarSlot:      data.HotBarSlot,
+		HeldItem:        data.HeldItem.Stack,
+		PlayerPosition:  data.Position,
+	}, true
+}
+
+// ClickedBlock checks if the player clicked a block.
+func (i ItemInteraction) ClickedBlock() bool {
+	return i.Type == protocol.UseItemActionClickBlock
+}
+
+// ClickedAir checks if the player used its item without clicking a block.
+func (i ItemInteraction) ClickedAir() bool {
+	return i.Type == protocol.UseItemActionClickAir
+}
+
+// BrokeBlock checks if the player broke a block.
+func (i ItemInteraction) BrokeBlock() bool {
+	return i.Type == protocol.UseItemActionBreakBlock
+}
+
+// Target returns the exact position in the world that the player clicked, which is ClickedPosition
+// relative to BlockPosition.
+func (i ItemInteraction) Target() mgl32.Vec3 {
+	return mgl32.Vec3{float32(i.BlockPosition[0]), float32(i.BlockPosition[1]), float32(i.BlockPosition[2])}.Add(i.ClickedPosition)
+}
+
+// PlacePosition returns the position at which a block would be placed by the interaction, which is the
+// block adjacent to BlockPosition on the face clicked.
+func (i ItemInteraction) PlacePosition() protocol.BlockPos {
+	return Side(i.BlockPosition, i.BlockFace)
+}
@@ -0,0 +1,82 @@
+package item
+
+// Components holds the component data of a custom item, as sent in the ItemComponent packet. The components
+// are held in a 'components' compound, with properties such as the maximum stack size of the item held in its
+// 'item_properties' compound.
+type Components map[string]any
+
+// Component returns the data of the component with the name passed, such as 'minecraft:durability'. False
+// is returned if the item does not have the component.
+func (c Components) Component(name string) (map[string]any, bool) {
+	components, _ := c["components"].(map[string]any)
+	data, ok := components[name].(map[string]any)
+	return data, ok
+}
+
+// Property returns the value of the item property with the name passed, such as 'max_stack_size'. False is
+// returned if the item does not have the property.
+func (c Components) Property(name string) (any, bool) {
+	properties, ok := c.Component("item_properties")
+	if !ok {
+		return nil, false
+	}
+	v, ok := properties[name]
+	return v, ok
+}
+
+// MaxStackSize returns the maximum number of items in a stack of the item. False is returned if the item does
+// not specify it.
+func (c Components) MaxStackSize() (int32, bool) {
+	v, _ := c.Property("max_stack_size")
+	return number(v)
+}
+
+// HandEquipped checks if the item is rendered like a tool when held.
+func (c Components) HandEquipped() bool {
+	v, _ := c.Property("hand_equipped")
+	n, _ := number(v)
+	return n != 0
+}
+
+// Icon returns the texture of the icon of the item. False is returned if the item does not specify it.
+func (c Components) Icon() (string, bool) {
+	icon, ok := c.Property("minecraft:icon")
+	if !ok {
+		return "", false
+	}
+	m, _ := icon.(map[string]any)
+	if texture, ok := m["texture"].(string); ok {
+		return texture, true
+	}
+	textures, _ := m["textures"].(map[string]any)
+	texture, ok := textures["default"].(string)
+	return texture, ok
+}
+
+// DisplayName returns the display name of the item. False is returned if the item does not specify it.
+func (c Components) DisplayName() (string, bool) {
+	data, _ := c.Component("minecraft:display_name")
+	name, ok := data["value"].(string)
+	return name, ok
+}
+
+// MaxDamage returns the durability of the item. False is returned if the item does not have durability.
+func (c Components) MaxDamage() (int32, bool) {
+	data, _ := c.Component("minecraft:durability")
+	return number(data["max_damage"])
+}
+
+// number converts an integer NBT value to an int32.
+func number(v any) (int32, bool) {
+	switch v := v.(type) {
+	case byte:
+		return int32(v), true
+	case int16:
+		return int32(v), true
+	case int32:
+		return v, true
+	case int64:
+		return int32(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,55 @@
+package item
+
+import (
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Creative builds the content of the creative inventory, assigning each item the creative network ID that
+// the client uses to refer to it, such as in the CraftCreative action of an ItemStackRequest. The zero value
+// of a Creative is ready to use.
+type Creative struct {
+	items []protocol.ItemStack
+}
+
+// Add adds the item stacks passed to the creative inventory, in the order they should be shown.
+func (c *Creative) Add(stacks ...protocol.ItemStack) {
+	c.items = append(c.items, stacks...)
+}
+
+// AddNamed adds items to the creative inventory by their identifiers, such as 'minecraft:stick', using the
+// metadata value 0 and the network IDs held by the Registry passed. An error is returned if an item is not
+// present in the Registry, in which case none of the items are added.
+func (c *Creative) AddNamed(r *Registry, names ...string) error {
+	stacks := make([]protocol.ItemStack, 0, len(names))
+	for _, name := range names {
+		t, ok := r.Type(name, 0)
+		if !ok {
+			return fmt.Errorf("add creative item %v: item not registered", name)
+		}
+		stacks = append(stacks, protocol.ItemStack{ItemType: t, Count: 1})
+	}
+	c.Add(stacks...)
+	return nil
+}
+
+// Item returns the item stack with the creative network ID passed. False is returned if no item has the
+// creative network ID.
+func (c *Creative) Item(creativeNetworkID uint32) (protocol.ItemStack, bool) {
+	if creativeNetworkID == 0 || int(creativeNetworkID) > len(c.items) {
+		return protocol.ItemStack{}, false
+	}
+	return c.items[creativeNetworkID-1], true
+}
+
+// Packet returns a CreativeContent packet holding all items added to the Creative. The items are assigned
+// creative network IDs starting at 1, in the order they were added.
+func (c *Creative) Packet() *packet.CreativeContent {
+	pk := &packet.CreativeContent{Items: make([]protocol.CreativeItem, len(c.items))}
+	for i, stack := range c.items {
+		pk.Items[i] = protocol.CreativeItem{CreativeItemNetworkID: uint32(i) + 1, Item: stack}
+	}
+	return pk
+}
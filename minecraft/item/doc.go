@@ -0,0 +1,5 @@
+// Package item implements a registry of the items sent by a server in the StartGame packet, mapping the
+// network IDs used in item stacks to the string identifiers of items and the other way around. It further
+// holds the components of custom items sent in the ItemComponent packet, with typed access to common
+// components.
+package item
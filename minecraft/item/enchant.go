@@ -0,0 +1,59 @@
+package item
+
+import (
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// EnchantOption is an option shown in the enchantment table when an item is put in.
+type EnchantOption struct {
+	// Cost is the number of experience levels required to select the option.
+	Cost uint32
+	// Name is the text shown on the button in the Standard Galactic Alphabet. It has no meaning.
+	Name string
+	// Enchantments holds the enchantments applied to the item when the option is selected.
+	Enchantments []protocol.EnchantmentInstance
+}
+
+// enchantmentActivation maps the IDs of enchantments to the index of the slice of
+// protocol.ItemEnchantments.Enchantments that they must be sent in: 0 for enchantments activated when
+// equipped, 1 for enchantments activated when held and 2 for self-activated enchantments.
+var enchantmentActivation = map[byte]int{
+	0: 0, 1: 0, 2: 0, 3: 0, 4: 0, 5: 0, 6: 0, 7: 0, 8: 0, 25: 0, 36: 0, 37: 0,
+	9: 1, 10: 1, 11: 1, 13: 1, 14: 1, 16: 1, 17: 1, 18: 1, 21: 1, 23: 1, 29: 1,
+	12: 2, 15: 2, 19: 2, 20: 2, 22: 2, 24: 2, 26: 2, 27: 2, 28: 2, 30: 2, 31: 2, 32: 2, 33: 2, 34: 2, 35: 2,
+}
+
+// EnchantOptions returns a PlayerEnchantOptions packet holding the options passed, in the order of the
+// buttons of the enchantment table. At most three options may be passed. The options are assigned the recipe
+// network IDs firstNetworkID, firstNetworkID+1 and so on, which the client submits in an ItemStackRequest
+// when an option is selected. These IDs must not be used by any other recipe. The enchantments of each option
+// are sorted into the activation slices expected by the client.
+func EnchantOptions(firstNetworkID uint32, options ...EnchantOption) (*packet.PlayerEnchantOptions, error) {
+	if len(options) > 3 {
+		return nil, fmt.Errorf("too many enchant options: %v exceeds maximum of 3", len(options))
+	}
+	pk := &packet.PlayerEnchantOptions{Options: make([]protocol.EnchantmentOption, len(options))}
+	for i, option := range options {
+		if len(option.Enchantments) == 0 {
+			return nil, fmt.Errorf("enchant option %v: option has no enchantments", i)
+		}
+		enchantments := protocol.ItemEnchantments{Slot: int32(i)}
+		for _, e := range option.Enchantments {
+			activation, ok := enchantmentActivation[e.Type]
+			if !ok {
+				return nil, fmt.Errorf("enchant option %v: unknown enchantment %v", i, e.Type)
+			}
+			enchantments.Enchantments[activation] = append(enchantments.Enchantments[activation], e)
+		}
+		pk.Options[i] = protocol.EnchantmentOption{
+			Cost:            option.Cost,
+			Enchantments:    enchantments,
+			Name:            option.Name,
+			RecipeNetworkID: firstNetworkID + uint32(i),
+		}
+	}
+	return pk, nil
+}
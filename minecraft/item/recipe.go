@@ -0,0 +1,406 @@
+package item
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Ingredient returns a recipe ingredient of the item type passed, requiring the count passed.
+func Ingredient(t protocol.ItemType, count int32) protocol.ItemDescriptorCount {
+	return protocol.ItemDescriptorCount{
+		Descriptor: &protocol.DefaultItemDescriptor{NetworkID: int16(t.NetworkID), MetadataValue: int16(t.MetadataValue)},
+		Count:      count,
+	}
+}
+
+// TagIngredient returns a recipe ingredient accepting any item with the item tag passed, such as
+// 'minecraft:planks', requiring the count passed.
+func TagIngredient(tag string, count int32) protocol.ItemDescriptorCount {
+	return protocol.ItemDescriptorCount{Descriptor: &protocol.ItemTagItemDescriptor{Tag: tag}, Count: count}
+}
+
+// EmptyIngredient returns an ingredient for an empty slot in the shape of a shaped recipe.
+func EmptyIngredient() protocol.ItemDescriptorCount {
+	return protocol.ItemDescriptorCount{Descriptor: &protocol.InvalidItemDescriptor{}}
+}
+
+// Shaped returns a shaped crafting table recipe with the ID passed. The input holds width*height ingredients,
+// row by row, with EmptyIngredient used for empty slots.
+func Shaped(id string, width, height int32, input []protocol.ItemDescriptorCount, output ...protocol.ItemStack) *protocol.ShapedRecipe {
+	return &protocol.ShapedRecipe{
+		RecipeID:          id,
+		Width:             width,
+		Height:            height,
+		Input:             input,
+		Output:            output,
+		Block:             "crafting_table",
+		UnlockRequirement: protocol.RecipeUnlockRequirement{Context: protocol.RecipeUnlockContextAlwaysUnlocked},
+	}
+}
+
+// Shapeless returns a shapeless crafting table recipe with the ID passed.
+func Shapeless(id string, input []protocol.ItemDescriptorCount, output ...protocol.ItemStack) *protocol.ShapelessRecipe {
+	return &protocol.ShapelessRecipe{
+		RecipeID:          id,
+		Input:             input,
+		Output:            output,
+		Block:             "crafting_table",
+		UnlockRequirement: protocol.RecipeUnlockRequirement{Context: protocol.RecipeUnlockContextAlwaysUnlocked},
+	}
+}
+
+// Furnace returns a recipe for the furnace-type block passed, such as 'furnace', 'blast_furnace', 'smoker' or
+// 'campfire', smelting items of the network ID of the input into the output, regardless of the metadata
+// value of the input.
+func Furnace(block string, input protocol.ItemType, output protocol.ItemStack) *protocol.FurnaceRecipe {
+	return &protocol.FurnaceRecipe{InputType: input, Output: output, Block: block}
+}
+
+// FurnaceData returns a recipe like Furnace, except that only items with the metadata value of the input are
+// smelted.
+func FurnaceData(block string, input protocol.ItemType, output protocol.ItemStack) *protocol.FurnaceDataRecipe {
+	return &protocol.FurnaceDataRecipe{FurnaceRecipe: *Furnace(block, input, output)}
+}
+
+// SmithingTransform returns a smithing table recipe with the ID passed that transforms the base into the
+// result, such as upgrading diamond armour to netherite armour.
+func SmithingTransform(id string, template, base, addition protocol.ItemDescriptorCount, result protocol.ItemStack) *protocol.SmithingTransformRecipe {
+	return &protocol.SmithingTransformRecipe{RecipeID: id, Template: template, Base: base, Addition: addition, Result: result, Block: "smithing_table"}
+}
+
+// SmithingTrim returns a smithing table recipe with the ID passed that applies an armour trim to the base.
+func SmithingTrim(id string, template, base, addition protocol.ItemDescriptorCount) *protocol.SmithingTrimRecipe {
+	return &protocol.SmithingTrimRecipe{RecipeID: id, Template: template, Base: base, Addition: addition, Block: "smithing_table"}
+}
+
+// Brewing returns a brewing stand recipe that brews the input potion with the reagent into the output
+// potion.
+func Brewing(input, reagent, output protocol.ItemType) protocol.PotionRecipe {
+	return protocol.PotionRecipe{
+		InputPotionID:        input.NetworkID,
+		InputPotionMetadata:  int32(input.MetadataValue),
+		ReagentItemID:        reagent.NetworkID,
+		ReagentItemMetadata:  int32(reagent.MetadataValue),
+		OutputPotionID:       output.NetworkID,
+		OutputPotionMetadata: int32(output.MetadataValue),
+	}
+}
+
+// Recipes builds a CraftingData packet from the recipes added to it, validating the recipes and assigning
+// each recipe its recipe network ID. The zero value of Recipes is ready to use.
+type Recipes struct {
+	recipes          []protocol.Recipe
+	potions          []protocol.PotionRecipe
+	containerChanges []protocol.PotionContainerChangeRecipe
+}
+
+// Add adds crafting recipes, such as those returned by Shaped, Shapeless and Furnace, to the Recipes.
+func (r *Recipes) Add(recipes ...protocol.Recipe) {
+	r.recipes = append(r.recipes, recipes...)
+}
+
+// AddBrewing adds brewing stand recipes, such as those returned by Brewing, to the Recipes.
+func (r *Recipes) AddBrewing(recipes ...protocol.PotionRecipe) {
+	r.potions = append(r.potions, recipes...)
+}
+
+// AddContainerChange adds recipes that change the container of a potion, such as turning a potion into a
+// splash potion, to the Recipes.
+func (r *Recipes) AddContainerChange(recipes ...protocol.PotionContainerChangeRecipe) {
+	r.containerChanges = append(r.containerChanges, recipes...)
+}
+
+// Recipe returns the recipe with the recipe network ID passed, as assigned by CraftingData and referred to
+// by the CraftRecipe actions of an ItemStackRequest. False is returned if no recipe has the network ID.
+func (r *Recipes) Recipe(networkID uint32) (protocol.Recipe, bool) {
+	for _, recipe := range r.recipes {
+		if id, ok := recipeNetworkID(recipe); ok && *id == networkID {
+			return recipe, true
+		}
+	}
+	return nil, false
+}
+
+// CraftingData validates all recipes added and returns a CraftingData packet holding them, with
+// ClearRecipes set to true. Recipes that have a recipe network ID are assigned a unique one starting at 1, in
+// the order they were added, overwriting the network ID previously set. An error is returned if any of the
+// recipes is invalid, describing which recipe is invalid and why.
+func (r *Recipes) CraftingData() (*packet.CraftingData, error) {
+	ids := make(map[string]struct{}, len(r.recipes))
+	networkID := uint32(1)
+	for i, recipe := range r.recipes {
+		if err := ValidateRecipe(recipe); err != nil {
+			return nil, fmt.Errorf("recipe %v: %w", i, err)
+		}
+		if id := recipeID(recipe); id != "" {
+			if _, ok := ids[id]; ok {
+				return nil, fmt.Errorf("recipe %v: duplicate recipe ID %v", i, id)
+			}
+			ids[id] = struct{}{}
+		}
+		if id, ok := recipeNetworkID(recipe); ok {
+			*id = networkID
+			networkID++
+		}
+	}
+	for i, potion := range r.potions {
+		if potion.InputPotionID == 0 || potion.ReagentItemID == 0 || potion.OutputPotionID == 0 {
+			return nil, fmt.Errorf("brewing recipe %v: input, reagent and output must all be set", i)
+		}
+	}
+	for i, change := range r.containerChanges {
+		if change.InputItemID == 0 || change.ReagentItemID == 0 || change.OutputItemID == 0 {
+			return nil, fmt.Errorf("container change recipe %v: input, reagent and output must all be set", i)
+		}
+	}
+	return &packet.CraftingData{
+		Recipes:                      r.recipes,
+		PotionRecipes:                r.potions,
+		PotionContainerChangeRecipes: r.containerChanges,
+		ClearRecipes:                 true,
+	}, nil
+}
+
+// ValidateRecipe checks if the recipe passed holds all fields the client requires to be valid, returning an
+// error describing the first invalid field found. The recipe network ID is not checked, as it is assigned by
+// Recipes.CraftingData.
+func ValidateRecipe(recipe protocol.Recipe) error {
+	switch recipe := recipe.(type) {
+	case *protocol.ShapedRecipe:
+		return validateShaped(recipe)
+	case *protocol.ShapedChemistryRecipe:
+		return validateShaped(&recipe.ShapedRecipe)
+	case *protocol.ShapelessRecipe:
+		return validateShapeless(recipe)
+	case *protocol.ShulkerBoxRecipe:
+		return validateShapeless(&recipe.ShapelessRecipe)
+	case *protocol.ShapelessChemistryRecipe:
+		return validateShapeless(&recipe.ShapelessRecipe)
+	case *protocol.FurnaceRecipe:
+		return validateFurnace(recipe)
+	case *protocol.FurnaceDataRecipe:
+		return validateFurnace(&recipe.FurnaceRecipe)
+	case *protocol.MultiRecipe:
+		return nil
+	case *protocol.SmithingTransformRecipe:
+		if err := validateSmithing(recipe.RecipeID, recipe.Block, recipe.Template, recipe.Base, recipe.Addition); err != nil {
+			return err
+		}
+		return validateOutput(recipe.Result)
+	case *protocol.SmithingTrimRecipe:
+		return validateSmithing(recipe.RecipeID, recipe.Block, recipe.Template, recipe.Base, recipe.Addition)
+	case nil:
+		return errors.New("recipe is nil")
+	}
+	return fmt.Errorf("unknown recipe type %T", recipe)
+}
+
+// validateShaped validates a shaped recipe.
+func validateShaped(recipe *protocol.ShapedRecipe) error {
+	if err := validateCommon(recipe.RecipeID, recipe.Block); err != nil {
+		return err
+	}
+	if recipe.Width < 1 || recipe.Width > 3 || recipe.Height < 1 || recipe.Height > 3 {
+		return fmt.Errorf("shape %vx%v must be between 1x1 and 3x3", recipe.Width, recipe.Height)
+	}
+	if len(recipe.Input) != int(recipe.Width*recipe.Height) {
+		return fmt.Errorf("shape %vx%v requires %v inputs, got %v", recipe.Width, recipe.Height, recipe.Width*recipe.Height, len(recipe.Input))
+	}
+	empty := true
+	for i, input := range recipe.Input {
+		if isEmpty(input.Descriptor) {
+			continue
+		}
+		empty = false
+		if err := validateIngredient(input); err != nil {
+			return fmt.Errorf("input %v: %w", i, err)
+		}
+	}
+	if empty {
+		return errors.New("all inputs are empty")
+	}
+	return validateOutputs(recipe.Output)
+}
+
+// validateShapeless validates a shapeless recipe.
+func validateShapeless(recipe *protocol.ShapelessRecipe) error {
+	if err := validateCommon(recipe.RecipeID, recipe.Block); err != nil {
+		return err
+	}
+	if len(recipe.Input) == 0 || len(recipe.Input) > 9 {
+		return fmt.Errorf("recipe must have between 1 and 9 inputs, got %v", len(recipe.Input))
+	}
+	for i, input := range recipe.Input {
+		if err := validateIngredient(input); err != nil {
+			return fmt.Errorf("input %v: %w", i, err)
+		}
+	}
+	return validateOutputs(recipe.Output)
+}
+
+// validateFurnace validates a furnace recipe.
+func validateFurnace(recipe *protocol.FurnaceRecipe) error {
+	if err := validateBlock(recipe.Block); err != nil {
+		return err
+	}
+	if recipe.InputType.NetworkID == 0 {
+		return errors.New("input has no network ID")
+	}
+	return validateOutput(recipe.Output)
+}
+
+// validateSmithing validates the fields shared by smithing recipes.
+func validateSmithing(id, block string, template, base, addition protocol.ItemDescriptorCount) error {
+	if err := validateCommon(id, block); err != nil {
+		return err
+	}
+	if err := validateIngredient(template); err != nil {
+		return fmt.Errorf("template: %w", err)
+	}
+	if err := validateIngredient(base); err != nil {
+		return fmt.Errorf("base: %w", err)
+	}
+	if err := validateIngredient(addition); err != nil {
+		return fmt.Errorf("addition: %w", err)
+	}
+	return nil
+}
+
+// validateCommon validates the recipe ID and block of a recipe.
+func validateCommon(id, block string) error {
+	if id == "" {
+		return errors.New("recipe ID is empty")
+	}
+	return validateBlock(block)
+}
+
+// validateBlock validates the block name required to craft a recipe.
+func validateBlock(block string) error {
+	if block == "" {
+		return errors.New("block is empty")
+	}
+	if strings.Contains(block, ":") {
+		return fmt.Errorf("block %v must not have a namespace", block)
+	}
+	return nil
+}
+
+// validateIngredient validates a non-empty recipe ingredient.
+func validateIngredient(input protocol.ItemDescriptorCount) error {
+	if input.Count <= 0 {
+		return fmt.Errorf("count must be positive, got %v", input.Count)
+	}
+	switch d := input.Descriptor.(type) {
+	case nil:
+		return errors.New("descriptor is nil")
+	case *protocol.InvalidItemDescriptor:
+		return errors.New("descriptor is invalid")
+	case *protocol.DefaultItemDescriptor:
+		if d.NetworkID == 0 {
+			return errors.New("descriptor has no network ID")
+		}
+	case *protocol.MoLangItemDescriptor:
+		if d.Expression == "" {
+			return errors.New("descriptor has no MoLang expression")
+		}
+	case *protocol.ItemTagItemDescriptor:
+		if d.Tag == "" {
+			return errors.New("descriptor has no item tag")
+		}
+	case *protocol.DeferredItemDescriptor:
+		if d.Name == "" {
+			return errors.New("descriptor has no item name")
+		}
+	case *protocol.ComplexAliasItemDescriptor:
+		if d.Name == "" {
+			return errors.New("descriptor has no item name")
+		}
+	default:
+		return fmt.Errorf("unknown descriptor type %T", d)
+	}
+	return nil
+}
+
+// isEmpty checks if an item descriptor describes an empty slot.
+func isEmpty(d protocol.ItemDescriptor) bool {
+	switch d := d.(type) {
+	case *protocol.InvalidItemDescriptor:
+		return true
+	case *protocol.DefaultItemDescriptor:
+		return d.NetworkID == 0
+	}
+	return false
+}
+
+// validateOutputs validates the outputs of a crafting recipe.
+func validateOutputs(output []protocol.ItemStack) error {
+	if len(output) == 0 {
+		return errors.New("recipe has no output")
+	}
+	for i, stack := range output {
+		if err := validateOutput(stack); err != nil {
+			return fmt.Errorf("output %v: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateOutput validates a single output item of a recipe.
+func validateOutput(stack protocol.ItemStack) error {
+	if stack.NetworkID == 0 {
+		return errors.New("output has no network ID")
+	}
+	if stack.Count == 0 {
+		return errors.New("output count is 0")
+	}
+	return nil
+}
+
+// recipeID returns the recipe ID of the recipe passed, or an empty string if it has none.
+func recipeID(recipe protocol.Recipe) string {
+	switch recipe := recipe.(type) {
+	case *protocol.ShapedRecipe:
+		return recipe.RecipeID
+	case *protocol.ShapedChemistryRecipe:
+		return recipe.RecipeID
+	case *protocol.ShapelessRecipe:
+		return recipe.RecipeID
+	case *protocol.ShulkerBoxRecipe:
+		return recipe.RecipeID
+	case *protocol.ShapelessChemistryRecipe:
+		return recipe.RecipeID
+	case *protocol.SmithingTransformRecipe:
+		return recipe.RecipeID
+	case *protocol.SmithingTrimRecipe:
+		return recipe.RecipeID
+	}
+	return ""
+}
+
+// recipeNetworkID returns a pointer to the recipe network ID of the recipe passed. False is returned if the
+// recipe has no recipe network ID, as is the case for furnace recipes.
+func recipeNetworkID(recipe protocol.Recipe) (*uint32, bool) {
+	switch recipe := recipe.(type) {
+	case *protocol.ShapedRecipe:
+		return &recipe.RecipeNetworkID, true
+	case *protocol.ShapedChemistryRecipe:
+		return &recipe.RecipeNetworkID, true
+	case *protocol.ShapelessRecipe:
+		return &recipe.RecipeNetworkID, true
+	case *protocol.ShulkerBoxRecipe:
+		return &recipe.RecipeNetworkID, true
+	case *protocol.ShapelessChemistryRecipe:
+		return &recipe.RecipeNetworkID, true
+	case *protocol.MultiRecipe:
+		return &recipe.RecipeNetworkID, true
+	case *protocol.SmithingTransformRecipe:
+		return &recipe.RecipeNetworkID, true
+	case *protocol.SmithingTrimRecipe:
+		return &recipe.RecipeNetworkID, true
+	}
+	return nil, false
+}
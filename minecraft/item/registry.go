@@ -0,0 +1,105 @@
+package item
+
+import (
+	"sync"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// Entry is an item held by a Registry.
+type Entry struct {
+	// Name is the identifier of the item, such as 'minecraft:stick'.
+	Name string
+	// NetworkID is the ID used to identify the item in item stacks, such as in the NetworkID of a
+	// protocol.ItemType.
+	NetworkID int32
+	// ComponentBased specifies if the item is a custom item created using components.
+	ComponentBased bool
+	// Components holds the components of the item, as sent in the ItemComponent packet. It is nil if no
+	// components were sent for the item.
+	Components Components
+}
+
+// Registry maps the network IDs of items to their identifiers and the other way around. A Registry is created
+// from the item table of the StartGame packet, typically found in the Items field of minecraft.GameData, and
+// may be extended with the components of custom items sent in the ItemComponent packet. A Registry is safe
+// for concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	byID   map[int32]*Entry
+	byName map[string]*Entry
+}
+
+// NewRegistry creates a Registry holding the items passed.
+func NewRegistry(items []protocol.ItemEntry) *Registry {
+	r := &Registry{byID: make(map[int32]*Entry, len(items)), byName: make(map[string]*Entry, len(items))}
+	for _, it := range items {
+		e := &Entry{Name: it.Name, NetworkID: int32(it.RuntimeID), ComponentBased: it.ComponentBased}
+		r.byID[e.NetworkID], r.byName[e.Name] = e, e
+	}
+	return r
+}
+
+// AddComponents sets the components of the items passed, as sent in the Items field of the ItemComponent
+// packet. Components of items not present in the Registry are ignored.
+func (r *Registry) AddComponents(entries []protocol.ItemComponentEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, entry := range entries {
+		if e, ok := r.byName[entry.Name]; ok {
+			e.Components = entry.Data
+		}
+	}
+}
+
+// ByNetworkID returns the Entry of the item with the network ID passed. False is returned if no such item
+// exists.
+func (r *Registry) ByNetworkID(id int32) (Entry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.byID[id]
+	if !ok {
+		return Entry{}, false
+	}
+	return *e, true
+}
+
+// ByName returns the Entry of the item with the identifier passed, such as 'minecraft:stick'. False is
+// returned if no such item exists.
+func (r *Registry) ByName(name string) (Entry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.byName[name]
+	if !ok {
+		return Entry{}, false
+	}
+	return *e, true
+}
+
+// Name returns the identifier of the item with the network ID passed. False is returned if no such item
+// exists.
+func (r *Registry) Name(id int32) (string, bool) {
+	e, ok := r.ByNetworkID(id)
+	return e.Name, ok
+}
+
+// NetworkID returns the network ID of the item with the identifier passed. False is returned if no such item
+// exists.
+func (r *Registry) NetworkID(name string) (int32, bool) {
+	e, ok := r.ByName(name)
+	return e.NetworkID, ok
+}
+
+// Type returns the protocol.ItemType of the item with the identifier and metadata value passed. False is
+// returned if no such item exists.
+func (r *Registry) Type(name string, meta uint32) (protocol.ItemType, bool) {
+	id, ok := r.NetworkID(name)
+	return protocol.ItemType{NetworkID: id, MetadataValue: meta}, ok
+}
+
+// Len returns the number of items in the Registry.
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.byID)
+}
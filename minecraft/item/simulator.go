@@ -0,0 +1,374 @@
+package item
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// createdOutputSlot is the slot of the protocol.ContainerCreatedOutput container that crafted and creative
+// items are created in.
+const createdOutputSlot = 50
+
+// Simulator applies ItemStackRequests sent by a client to a server-side view of the containers of the client,
+// validating each action and producing the ItemStackResponse to send back. A request is only applied if all
+// of its actions are valid: Otherwise it is rejected and the containers are left unchanged.
+// Supported actions are those that move items between slots, such as take, place, swap, drop, destroy and
+// consume, along with crafting recipes and creative items. Requests holding other actions are rejected. A
+// Simulator is not safe for concurrent use.
+type Simulator struct {
+	// Containers holds the contents of the containers of the client by container ID, such as
+	// protocol.ContainerCursor. Slots that are empty hold the zero protocol.ItemInstance.
+	Containers map[byte][]protocol.ItemInstance
+	// Aliases maps container IDs to the container ID in Containers that holds their contents. The client uses
+	// multiple container IDs for the same inventory, such as protocol.ContainerHotBar and
+	// protocol.ContainerInventory, which may be mapped to protocol.ContainerCombinedHotBarAndInventory.
+	Aliases map[byte]byte
+	// Creative holds the creative items that may be created using CraftCreative actions. If nil, these
+	// actions are rejected.
+	Creative *Creative
+	// Recipes holds the recipes that may be crafted using CraftRecipe and AutoCraftRecipe actions. If nil,
+	// these actions are rejected.
+	Recipes *Recipes
+	// MaxCount returns the maximum count of the stack passed. If nil, all stacks have a maximum count of 64.
+	MaxCount func(stack protocol.ItemStack) int
+
+	nextStackID int32
+	// lastRequest holds the ID of the request that last changed a slot, which the client may use as stack
+	// network ID before receiving the response to that request.
+	lastRequest map[slotKey]int32
+}
+
+// NewSimulator returns a Simulator for the containers passed. New stack network IDs are assigned starting
+// after the highest stack network ID present in the containers.
+func NewSimulator(containers map[byte][]protocol.ItemInstance) *Simulator {
+	s := &Simulator{Containers: containers, Aliases: map[byte]byte{}, lastRequest: map[slotKey]int32{}}
+	for _, c := range containers {
+		for _, inst := range c {
+			s.nextStackID = max(s.nextStackID, inst.StackNetworkID)
+		}
+	}
+	return s
+}
+
+// NewStackID returns a new unique stack network ID, which should be used for stacks added to the Containers
+// of the Simulator by the server.
+func (s *Simulator) NewStackID() int32 {
+	s.nextStackID++
+	return s.nextStackID
+}
+
+// Result is the result of applying an ItemStackRequest using a Simulator.
+type Result struct {
+	// Response is the ItemStackResponse that should be sent to the client.
+	Response protocol.ItemStackResponse
+	// Dropped holds the item stacks dropped by the client using Drop actions. The server is responsible for
+	// spawning these items in the world.
+	Dropped []protocol.ItemStack
+}
+
+// Apply applies the ItemStackRequest passed to the Containers of the Simulator. If any of the actions in the
+// request is invalid, the request is rejected: The Containers are left unchanged, a response with
+// protocol.ItemStackResponseStatusError is returned and the error returned describes why the request was
+// rejected. Otherwise, the response holds the new contents of all slots changed.
+func (s *Simulator) Apply(req protocol.ItemStackRequest) (Result, error) {
+	tx := &transaction{s: s, requestID: req.RequestID, containers: map[byte][]protocol.ItemInstance{}}
+	for i, action := range req.Actions {
+		if err := tx.apply(action); err != nil {
+			return Result{Response: protocol.ItemStackResponse{Status: protocol.ItemStackResponseStatusError, RequestID: req.RequestID}},
+				fmt.Errorf("request %v: action %v (%T): %w", req.RequestID, i, action, err)
+		}
+	}
+	return tx.commit(), nil
+}
+
+// slotKey identifies a slot in a container.
+type slotKey struct {
+	container, slot byte
+}
+
+// transaction holds the changes made by an ItemStackRequest being applied, which are committed to the
+// Simulator only if all actions are valid.
+type transaction struct {
+	s         *Simulator
+	requestID int32
+	// containers holds copies of the containers changed by the request.
+	containers map[byte][]protocol.ItemInstance
+	// created holds the items in the created output slot. Crafting a recipe may create more items than fit
+	// in a single stack, or multiple different outputs, so the slot may hold multiple stacks. Only the first
+	// stack that is not empty may be taken from the slot at a time.
+	created []protocol.ItemInstance
+	// changed holds the slots changed by the request, keyed by the container ID used in the request.
+	changed []slotKey
+	dropped []protocol.ItemStack
+}
+
+// apply applies a single action of the request.
+func (tx *transaction) apply(action protocol.StackRequestAction) error {
+	switch a := action.(type) {
+	case *protocol.TakeStackRequestAction:
+		return tx.transfer(a.Count, a.Source, a.Destination)
+	case *protocol.PlaceStackRequestAction:
+		return tx.transfer(a.Count, a.Source, a.Destination)
+	case *protocol.SwapStackRequestAction:
+		if a.Source.ContainerID == protocol.ContainerCreatedOutput || a.Destination.ContainerID == protocol.ContainerCreatedOutput {
+			return errors.New("cannot swap with the created output slot")
+		}
+		src, err := tx.slot(a.Source)
+		if err != nil {
+			return err
+		}
+		dst, err := tx.slot(a.Destination)
+		if err != nil {
+			return err
+		}
+		*src, *dst = *dst, *src
+		return nil
+	case *protocol.DropStackRequestAction:
+		stack, err := tx.remove(a.Count, a.Source)
+		if err == nil {
+			tx.dropped = append(tx.dropped, stack)
+		}
+		return err
+	case *protocol.DestroyStackRequestAction:
+		_, err := tx.remove(a.Count, a.Source)
+		return err
+	case *protocol.ConsumeStackRequestAction:
+		_, err := tx.remove(a.Count, a.Source)
+		return err
+	case *protocol.CraftCreativeStackRequestAction:
+		if tx.s.Creative == nil {
+			return errors.New("creative items not available")
+		}
+		stack, ok := tx.s.Creative.Item(a.CreativeItemNetworkID)
+		if !ok {
+			return fmt.Errorf("unknown creative item %v", a.CreativeItemNetworkID)
+		}
+		stack.Count = uint16(tx.s.maxCount(stack))
+		return tx.create(stack)
+	case *protocol.CraftRecipeStackRequestAction:
+		return tx.craft(a.RecipeNetworkID, 1)
+	case *protocol.AutoCraftRecipeStackRequestAction:
+		return tx.craft(a.RecipeNetworkID, int(a.TimesCrafted))
+	case *protocol.CraftResultsDeprecatedStackRequestAction:
+		// Sent along with crafting actions, holding the expected results. The results are computed from the
+		// recipe crafted instead.
+		return nil
+	}
+	return errors.New("action not supported")
+}
+
+// transfer moves count items from the source slot to the destination slot.
+func (tx *transaction) transfer(count byte, srcInfo, dstInfo protocol.StackRequestSlotInfo) error {
+	if dstInfo.ContainerID == protocol.ContainerCreatedOutput {
+		return errors.New("cannot place items in the created output slot")
+	}
+	src, err := tx.slot(srcInfo)
+	if err != nil {
+		return err
+	}
+	dst, err := tx.slot(dstInfo)
+	if err != nil {
+		return err
+	}
+	if src == dst {
+		return errors.New("source and destination are the same slot")
+	}
+	if count == 0 || int(count) > int(src.Stack.Count) {
+		return fmt.Errorf("cannot move %v items from stack of %v", count, src.Stack.Count)
+	}
+	if !empty(*dst) {
+		if !stackable(src.Stack, dst.Stack) {
+			return errors.New("destination holds a different item")
+		}
+		if int(dst.Stack.Count)+int(count) > tx.s.maxCount(dst.Stack) {
+			return fmt.Errorf("destination cannot hold %v more items", count)
+		}
+		dst.Stack.Count += uint16(count)
+	} else if int(count) > tx.s.maxCount(src.Stack) {
+		return fmt.Errorf("destination cannot hold %v items", count)
+	} else if uint16(count) == src.Stack.Count {
+		*dst = *src
+	} else {
+		*dst = protocol.ItemInstance{StackNetworkID: tx.s.NewStackID(), Stack: src.Stack}
+		dst.Stack.Count = uint16(count)
+	}
+	src.Stack.Count -= uint16(count)
+	if src.Stack.Count == 0 {
+		*src = protocol.ItemInstance{}
+	}
+	return nil
+}
+
+// remove removes count items from the source slot, returning the items removed.
+func (tx *transaction) remove(count byte, srcInfo protocol.StackRequestSlotInfo) (protocol.ItemStack, error) {
+	src, err := tx.slot(srcInfo)
+	if err != nil {
+		return protocol.ItemStack{}, err
+	}
+	if count == 0 || int(count) > int(src.Stack.Count) {
+		return protocol.ItemStack{}, fmt.Errorf("cannot remove %v items from stack of %v", count, src.Stack.Count)
+	}
+	removed := src.Stack
+	removed.Count = uint16(count)
+	src.Stack.Count -= uint16(count)
+	if src.Stack.Count == 0 {
+		*src = protocol.ItemInstance{}
+	}
+	return removed, nil
+}
+
+// craft creates the outputs of the recipe with the network ID passed, crafted the number of times passed, in
+// the created output slot. The outputs may exceed the maximum count of a stack, as the client spreads them
+// over multiple slots.
+func (tx *transaction) craft(networkID uint32, times int) error {
+	if tx.s.Recipes == nil {
+		return errors.New("recipes not available")
+	}
+	recipe, ok := tx.s.Recipes.Recipe(networkID)
+	if !ok {
+		return fmt.Errorf("unknown recipe %v", networkID)
+	}
+	var outputs []protocol.ItemStack
+	switch recipe := recipe.(type) {
+	case *protocol.ShapedRecipe:
+		outputs = recipe.Output
+	case *protocol.ShapedChemistryRecipe:
+		outputs = recipe.Output
+	case *protocol.ShapelessRecipe:
+		outputs = recipe.Output
+	case *protocol.ShapelessChemistryRecipe:
+		outputs = recipe.Output
+	case *protocol.SmithingTransformRecipe:
+		outputs = []protocol.ItemStack{recipe.Result}
+	default:
+		return fmt.Errorf("recipe %v of type %T cannot be crafted", networkID, recipe)
+	}
+	if times <= 0 {
+		return fmt.Errorf("cannot craft recipe %v times", times)
+	}
+	created := make([]protocol.ItemStack, len(outputs))
+	for i, output := range outputs {
+		if int(output.Count)*times > math.MaxUint16 {
+			return fmt.Errorf("cannot craft recipe %v times", times)
+		}
+		output.Count *= uint16(times)
+		created[i] = output
+	}
+	return tx.create(created...)
+}
+
+// create places the stacks passed in the created output slot.
+func (tx *transaction) create(stacks ...protocol.ItemStack) error {
+	if slices.ContainsFunc(tx.created, func(inst protocol.ItemInstance) bool { return !empty(inst) }) {
+		return errors.New("created output slot is not empty")
+	}
+	tx.created = tx.created[:0]
+	for _, stack := range stacks {
+		tx.created = append(tx.created, protocol.ItemInstance{StackNetworkID: tx.s.NewStackID(), Stack: stack})
+	}
+	return nil
+}
+
+// slot returns the item instance in the slot passed, checking if the stack network ID of the slot matches.
+// The slot is recorded as changed.
+func (tx *transaction) slot(info protocol.StackRequestSlotInfo) (*protocol.ItemInstance, error) {
+	if info.ContainerID == protocol.ContainerCreatedOutput {
+		if info.Slot != createdOutputSlot {
+			return nil, fmt.Errorf("invalid created output slot %v", info.Slot)
+		}
+		for i := range tx.created {
+			if !empty(tx.created[i]) {
+				return &tx.created[i], nil
+			}
+		}
+		return nil, errors.New("created output slot is empty")
+	}
+	id := info.ContainerID
+	if alias, ok := tx.s.Aliases[id]; ok {
+		id = alias
+	}
+	container, ok := tx.containers[id]
+	if !ok {
+		original, ok := tx.s.Containers[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown container %v", info.ContainerID)
+		}
+		container = slices.Clone(original)
+		tx.containers[id] = container
+	}
+	if int(info.Slot) >= len(container) {
+		return nil, fmt.Errorf("slot %v out of range for container %v of size %v", info.Slot, info.ContainerID, len(container))
+	}
+	inst := &container[info.Slot]
+	key := slotKey{container: id, slot: info.Slot}
+	if info.StackNetworkID != inst.StackNetworkID && !(info.StackNetworkID < 0 && tx.s.lastRequest[key] == info.StackNetworkID) {
+		return nil, fmt.Errorf("stack network ID %v does not match %v in slot %v of container %v", info.StackNetworkID, inst.StackNetworkID, info.Slot, info.ContainerID)
+	}
+	if reqKey := (slotKey{container: info.ContainerID, slot: info.Slot}); !slices.Contains(tx.changed, reqKey) {
+		tx.changed = append(tx.changed, reqKey)
+	}
+	return inst, nil
+}
+
+// commit commits the changes of the transaction to the Simulator and returns the Result of the request.
+func (tx *transaction) commit() Result {
+	for id, container := range tx.containers {
+		tx.s.Containers[id] = container
+	}
+	res := Result{Response: protocol.ItemStackResponse{Status: protocol.ItemStackResponseStatusOK, RequestID: tx.requestID}, Dropped: tx.dropped}
+	for _, key := range tx.changed {
+		id := key.container
+		if alias, ok := tx.s.Aliases[id]; ok {
+			id = alias
+		}
+		tx.s.lastRequest[slotKey{container: id, slot: key.slot}] = tx.requestID
+		inst := tx.s.Containers[id][key.slot]
+
+		i := slices.IndexFunc(res.Response.ContainerInfo, func(info protocol.StackResponseContainerInfo) bool {
+			return info.ContainerID == key.container
+		})
+		if i == -1 {
+			i = len(res.Response.ContainerInfo)
+			res.Response.ContainerInfo = append(res.Response.ContainerInfo, protocol.StackResponseContainerInfo{ContainerID: key.container})
+		}
+		res.Response.ContainerInfo[i].SlotInfo = append(res.Response.ContainerInfo[i].SlotInfo, protocol.StackResponseSlotInfo{
+			Slot:           key.slot,
+			HotbarSlot:     key.slot,
+			Count:          byte(inst.Stack.Count),
+			StackNetworkID: inst.StackNetworkID,
+			CustomName:     customName(inst.Stack),
+		})
+	}
+	return res
+}
+
+// maxCount returns the maximum count of the stack passed.
+func (s *Simulator) maxCount(stack protocol.ItemStack) int {
+	if s.MaxCount == nil {
+		return 64
+	}
+	return s.MaxCount(stack)
+}
+
+// empty checks if the item instance passed is an empty slot.
+func empty(inst protocol.ItemInstance) bool {
+	return inst.Stack.Count == 0 || inst.Stack.NetworkID == 0
+}
+
+// stackable checks if the two stacks passed hold the same item, so that they may be merged.
+func stackable(a, b protocol.ItemStack) bool {
+	return a.ItemType == b.ItemType && a.BlockRuntimeID == b.BlockRuntimeID && reflect.DeepEqual(a.NBTData, b.NBTData) &&
+		slices.Equal(a.CanBePlacedOn, b.CanBePlacedOn) && slices.Equal(a.CanBreak, b.CanBreak)
+}
+
+// customName returns the custom name of the stack passed, as held in its NBT data.
+func customName(stack protocol.ItemStack) string {
+	display, _ := stack.NBTData["display"].(map[string]any)
+	name, _ := display["Name"].(string)
+	return name
+}
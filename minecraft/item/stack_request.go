@@ -0,0 +1,101 @@
+package item
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// Slot returns a protocol.StackRequestSlotInfo pointing to the slot in the container passed, which the client
+// assumes to hold the stack with the network ID passed.
+func Slot(container, slot byte, stackNetworkID int32) protocol.StackRequestSlotInfo {
+	return protocol.StackRequestSlotInfo{ContainerID: container, Slot: slot, StackNetworkID: stackNetworkID}
+}
+
+// RequestBuilder composes the actions of an ItemStackRequest. Its methods return the RequestBuilder so that
+// calls may be chained.
+type RequestBuilder struct {
+	req protocol.ItemStackRequest
+}
+
+// NewRequest returns a RequestBuilder for an ItemStackRequest with the request ID passed. Clients typically
+// use negative, decrementing request IDs.
+func NewRequest(requestID int32) *RequestBuilder {
+	return &RequestBuilder{req: protocol.ItemStackRequest{RequestID: requestID}}
+}
+
+// Take adds an action taking count items from the source slot into the destination slot, which is typically
+// the cursor.
+func (b *RequestBuilder) Take(count byte, src, dst protocol.StackRequestSlotInfo) *RequestBuilder {
+	a := &protocol.TakeStackRequestAction{}
+	a.Count, a.Source, a.Destination = count, src, dst
+	return b.add(a)
+}
+
+// Place adds an action placing count items from the source slot, typically the cursor, into the destination
+// slot.
+func (b *RequestBuilder) Place(count byte, src, dst protocol.StackRequestSlotInfo) *RequestBuilder {
+	a := &protocol.PlaceStackRequestAction{}
+	a.Count, a.Source, a.Destination = count, src, dst
+	return b.add(a)
+}
+
+// Swap adds an action swapping the stacks in the source and destination slots.
+func (b *RequestBuilder) Swap(src, dst protocol.StackRequestSlotInfo) *RequestBuilder {
+	return b.add(&protocol.SwapStackRequestAction{Source: src, Destination: dst})
+}
+
+// Drop adds an action dropping count items from the source slot on the ground.
+func (b *RequestBuilder) Drop(count byte, src protocol.StackRequestSlotInfo) *RequestBuilder {
+	return b.add(&protocol.DropStackRequestAction{Count: count, Source: src})
+}
+
+// Destroy adds an action destroying count items from the source slot, as done in creative mode.
+func (b *RequestBuilder) Destroy(count byte, src protocol.StackRequestSlotInfo) *RequestBuilder {
+	return b.add(&protocol.DestroyStackRequestAction{Count: count, Source: src})
+}
+
+// Consume adds an action consuming count items from the source slot as the ingredient of a craft.
+func (b *RequestBuilder) Consume(count byte, src protocol.StackRequestSlotInfo) *RequestBuilder {
+	a := &protocol.ConsumeStackRequestAction{}
+	a.Count, a.Source = count, src
+	return b.add(a)
+}
+
+// Craft adds an action crafting the recipe with the recipe network ID passed. It is followed by actions
+// consuming the ingredients and taking the result.
+func (b *RequestBuilder) Craft(recipeNetworkID uint32) *RequestBuilder {
+	return b.add(&protocol.CraftRecipeStackRequestAction{RecipeNetworkID: recipeNetworkID})
+}
+
+// AutoCraft adds an action crafting the recipe with the recipe network ID passed a number of times at once,
+// as done when shift clicking a recipe in the recipe book.
+func (b *RequestBuilder) AutoCraft(recipeNetworkID uint32, times byte, ingredients ...protocol.ItemDescriptorCount) *RequestBuilder {
+	return b.add(&protocol.AutoCraftRecipeStackRequestAction{RecipeNetworkID: recipeNetworkID, TimesCrafted: times, Ingredients: ingredients})
+}
+
+// CraftCreative adds an action creating the creative item with the creative network ID passed. It is
+// followed by an action taking the item created.
+func (b *RequestBuilder) CraftCreative(creativeItemNetworkID uint32) *RequestBuilder {
+	return b.add(&protocol.CraftCreativeStackRequestAction{CreativeItemNetworkID: creativeItemNetworkID})
+}
+
+// Filter sets the filter strings and cause of the request, as used when renaming items in an anvil.
+func (b *RequestBuilder) Filter(cause int32, filterStrings ...string) *RequestBuilder {
+	b.req.FilterCause, b.req.FilterStrings = cause, filterStrings
+	return b
+}
+
+// Action adds any other action to the request.
+func (b *RequestBuilder) Action(a protocol.StackRequestAction) *RequestBuilder {
+	return b.add(a)
+}
+
+// Build returns the ItemStackRequest composed.
+func (b *RequestBuilder) Build() protocol.ItemStackRequest {
+	return b.req
+}
+
+// add appends an action to the request.
+func (b *RequestBuilder) add(a protocol.StackRequestAction) *RequestBuilder {
+	b.req.Actions = append(b.req.Actions, a)
+	return b
+}
@@ -0,0 +1,145 @@
+package item
+
+import (
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// TradeItem is an item bought or sold in a Trade. Unlike an ItemStack, items in trades are identified by
+// their string identifier, as they are encoded as NBT.
+type TradeItem struct {
+	// Name is the identifier of the item, such as 'minecraft:emerald'.
+	Name string
+	// Count is the amount of items.
+	Count uint8
+	// Meta is the metadata value of the item.
+	Meta int16
+	// NBT holds the user data of the item, such as its enchantments. It may be nil.
+	NBT map[string]any
+}
+
+// Trade is a single offer of a villager in the trading UI.
+type Trade struct {
+	// Input is the first item that the player must pay.
+	Input TradeItem
+	// SecondInput is the second item that the player must pay. If its Name is empty, the trade only requires
+	// Input.
+	SecondInput TradeItem
+	// Output is the item that the player receives.
+	Output TradeItem
+	// Tier is the tier of the villager at which the trade is unlocked, starting at 0.
+	Tier int32
+	// MaxUses is the number of times the trade may be used before it is locked. Uses is the number of times
+	// it was used so far.
+	MaxUses, Uses int32
+	// RewardExperience specifies if the player receives experience orbs when using the trade.
+	RewardExperience bool
+	// TraderExperience is the experience the villager gains when the trade is used.
+	TraderExperience int32
+	// Demand is the demand of the trade, which increases the price of Input if DemandBasedPrices is enabled
+	// in the UpdateTrade packet.
+	Demand int32
+	// PriceMultiplier and SecondPriceMultiplier are the multipliers by which the demand affects the price of
+	// Input and SecondInput.
+	PriceMultiplier, SecondPriceMultiplier float32
+}
+
+// Trades holds the offers of a villager, which are sent in the SerialisedOffers field of the UpdateTrade
+// packet.
+type Trades struct {
+	// Offers holds the trades offered.
+	Offers []Trade
+	// TierExperience holds the villager experience required to reach each tier, starting with tier 0. If
+	// empty, the vanilla requirements of 0, 10, 70, 150 and 250 are used.
+	TierExperience []int32
+}
+
+// defaultTierExperience holds the experience required for each villager tier in vanilla.
+var defaultTierExperience = []int32{0, 10, 70, 150, 250}
+
+// Marshal encodes the Trades to the network NBT format expected in the SerialisedOffers field of the
+// UpdateTrade packet.
+func (t Trades) Marshal() ([]byte, error) {
+	recipes := make([]any, 0, len(t.Offers))
+	for i, offer := range t.Offers {
+		if offer.Input.Name == "" || offer.Output.Name == "" {
+			return nil, fmt.Errorf("trade %v: input and output must both have a name", i)
+		}
+		recipe := map[string]any{
+			"buyA":             offer.Input.nbt(),
+			"buyCountA":        int32(offer.Input.Count),
+			"sell":             offer.Output.nbt(),
+			"tier":             offer.Tier,
+			"maxUses":          offer.MaxUses,
+			"uses":             offer.Uses,
+			"rewardExp":        boolByte(offer.RewardExperience),
+			"traderExp":        offer.TraderExperience,
+			"demand":           offer.Demand,
+			"priceMultiplierA": offer.PriceMultiplier,
+			"priceMultiplierB": offer.SecondPriceMultiplier,
+		}
+		if offer.SecondInput.Name != "" {
+			recipe["buyB"] = offer.SecondInput.nbt()
+			recipe["buyCountB"] = int32(offer.SecondInput.Count)
+		} else {
+			recipe["buyCountB"] = int32(0)
+		}
+		recipes = append(recipes, recipe)
+	}
+	tiers := t.TierExperience
+	if len(tiers) == 0 {
+		tiers = defaultTierExperience
+	}
+	requirements := make([]any, len(tiers))
+	for i, exp := range tiers {
+		requirements[i] = map[string]any{fmt.Sprint(i): exp}
+	}
+	return nbt.MarshalEncoding(map[string]any{"Recipes": recipes, "TierExpRequirements": requirements}, nbt.NetworkLittleEndian)
+}
+
+// Packet returns an UpdateTrade packet holding the Trades, to be sent when the player with the unique ID
+// passed opens the trading window with the ID passed for the villager passed. The display name is shown at
+// the top of the UI and the tier is the current tier of the villager.
+func (t Trades) Packet(windowID byte, villagerUniqueID, playerUniqueID int64, displayName string, tier int32) (*packet.UpdateTrade, error) {
+	offers, err := t.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return &packet.UpdateTrade{
+		WindowID:          windowID,
+		WindowType:        protocol.ContainerTypeTrade,
+		Size:              int32(len(t.Offers)),
+		TradeTier:         tier,
+		VillagerUniqueID:  villagerUniqueID,
+		EntityUniqueID:    playerUniqueID,
+		DisplayName:       displayName,
+		NewTradeUI:        true,
+		DemandBasedPrices: true,
+		SerialisedOffers:  offers,
+	}, nil
+}
+
+// nbt returns the NBT representation of the TradeItem.
+func (i TradeItem) nbt() map[string]any {
+	m := map[string]any{
+		"Name":        i.Name,
+		"Count":       i.Count,
+		"Damage":      i.Meta,
+		"WasPickedUp": uint8(0),
+	}
+	if len(i.NBT) != 0 {
+		m["tag"] = i.NBT
+	}
+	return m
+}
+
+// boolByte returns 1 if b is true and 0 otherwise.
+func boolByte(b bool) uint8 {
+	if b {
+		return 1
+	}
+	return 0
+}
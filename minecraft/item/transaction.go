@@ -0,0 +1,210 @@
+package item
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// ContainerAction returns an InventoryAction changing the item in a slot of the window passed, such as
+// protocol.WindowIDInventory, from the old item to the new item.
+func ContainerAction(windowID int32, slot uint32, old, new protocol.ItemInstance) protocol.InventoryAction {
+	return protocol.InventoryAction{SourceType: protocol.InventoryActionSourceContainer, WindowID: windowID, InventorySlot: slot, OldItem: old, NewItem: new}
+}
+
+// DropAction returns an InventoryAction dropping the item passed into the world. It is combined with a
+// ContainerAction removing the item from a slot.
+func DropAction(dropped protocol.ItemInstance) protocol.InventoryAction {
+	return protocol.InventoryAction{SourceType: protocol.InventoryActionSourceWorld, NewItem: dropped}
+}
+
+// CreativeAction returns an InventoryAction taking an item from the creative inventory, if old is the item
+// taken, or destroying an item by putting it in the creative inventory, if new is the item destroyed.
+func CreativeAction(old, new protocol.ItemInstance) protocol.InventoryAction {
+	return protocol.InventoryAction{SourceType: protocol.InventoryActionSourceCreative, OldItem: old, NewItem: new}
+}
+
+// NormalTransaction returns an InventoryTransaction packet holding the actions passed, which together must
+// form a balanced transaction.
+func NormalTransaction(actions ...protocol.InventoryAction) *packet.InventoryTransaction {
+	return &packet.InventoryTransaction{Actions: actions, TransactionData: &protocol.NormalTransactionData{}}
+}
+
+// MismatchTransaction returns an InventoryTransaction packet that tells the server the inventory of the
+// client is out of sync, so that the server sends the contents of the inventory again.
+func MismatchTransaction() *packet.InventoryTransaction {
+	return &packet.InventoryTransaction{TransactionData: &protocol.MismatchTransactionData{}}
+}
+
+// UseItemTransaction returns an InventoryTransaction packet holding the data passed, sent when using an
+// item on a block or in the air, or when breaking a block.
+func UseItemTransaction(data protocol.UseItemTransactionData) *packet.InventoryTransaction {
+	return &packet.InventoryTransaction{Actions: data.Actions, TransactionData: &data}
+}
+
+// UseItemOnEntityTransaction returns an InventoryTransaction packet holding the data passed, sent when
+// interacting with or attacking an entity.
+func UseItemOnEntityTransaction(data protocol.UseItemOnEntityTransactionData) *packet.InventoryTransaction {
+	return &packet.InventoryTransaction{TransactionData: &data}
+}
+
+// ReleaseItemTransaction returns an InventoryTransaction packet holding the data passed, sent when releasing
+// an item being used, such as a bow.
+func ReleaseItemTransaction(data protocol.ReleaseItemTransactionData) *packet.InventoryTransaction {
+	return &packet.InventoryTransaction{TransactionData: &data}
+}
+
+// Balanced checks if the actions passed form a balanced transaction: For each item, the number of items
+// removed from slots equals the number of items added to slots, counting items dropped into the world or
+// moved to or from the creative inventory.
+func Balanced(actions []protocol.InventoryAction) bool {
+	counts := make(map[itemKey]int)
+	for _, a := range actions {
+		if !empty(a.OldItem) {
+			counts[keyOf(a.OldItem.Stack)] -= int(a.OldItem.Stack.Count)
+		}
+		if !empty(a.NewItem) {
+			counts[keyOf(a.NewItem.Stack)] += int(a.NewItem.Stack.Count)
+		}
+	}
+	for _, n := range counts {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ToStackRequest converts a normal InventoryTransaction moving, dropping or destroying items into an
+// ItemStackRequest with the request ID passed, made up of Place, Drop and Destroy actions. slotOf converts a
+// window ID and slot of an InventoryAction to the container ID and slot used in ItemStackRequests. An error
+// is returned if the transaction is not balanced or holds actions that cannot be expressed as stack request
+// actions, such as taking items from the creative inventory.
+func ToStackRequest(pk *packet.InventoryTransaction, requestID int32, slotOf func(windowID int32, slot uint32) (container, containerSlot byte)) (protocol.ItemStackRequest, error) {
+	if _, ok := pk.TransactionData.(*protocol.NormalTransactionData); !ok && pk.TransactionData != nil {
+		return protocol.ItemStackRequest{}, fmt.Errorf("convert transaction: transaction data %T cannot be converted", pk.TransactionData)
+	}
+	if !Balanced(pk.Actions) {
+		return protocol.ItemStackRequest{}, errors.New("convert transaction: transaction is not balanced")
+	}
+
+	// Slots losing items are sources, while slots gaining items, the world and the creative inventory are
+	// destinations.
+	type source struct {
+		key   itemKey
+		info  protocol.StackRequestSlotInfo
+		count int
+	}
+	var sources []*source
+	type destination struct {
+		key   itemKey
+		info  protocol.StackRequestSlotInfo
+		count int
+		kind  uint32
+	}
+	var destinations []destination
+	for _, a := range pk.Actions {
+		switch a.SourceType {
+		case protocol.InventoryActionSourceWorld:
+			destinations = append(destinations, destination{key: keyOf(a.NewItem.Stack), count: int(a.NewItem.Stack.Count), kind: a.SourceType})
+			continue
+		case protocol.InventoryActionSourceCreative:
+			if !empty(a.OldItem) {
+				return protocol.ItemStackRequest{}, errors.New("convert transaction: taking items from the creative inventory requires a creative network ID")
+			}
+			destinations = append(destinations, destination{key: keyOf(a.NewItem.Stack), count: int(a.NewItem.Stack.Count), kind: a.SourceType})
+			continue
+		case protocol.InventoryActionSourceContainer:
+		default:
+			return protocol.ItemStackRequest{}, fmt.Errorf("convert transaction: action source %v cannot be converted", a.SourceType)
+		}
+		container, slot := slotOf(a.WindowID, a.InventorySlot)
+		info := Slot(container, slot, a.OldItem.StackNetworkID)
+
+		oldCount, newCount := int(a.OldItem.Stack.Count), int(a.NewItem.Stack.Count)
+		if empty(a.OldItem) {
+			oldCount = 0
+		}
+		if empty(a.NewItem) {
+			newCount = 0
+		}
+		if !empty(a.OldItem) && !empty(a.NewItem) && keyOf(a.OldItem.Stack) != keyOf(a.NewItem.Stack) {
+			return protocol.ItemStackRequest{}, errors.New("convert transaction: slot changes item type")
+		}
+		switch {
+		case newCount < oldCount:
+			sources = append(sources, &source{key: keyOf(a.OldItem.Stack), info: info, count: oldCount - newCount})
+		case newCount > oldCount:
+			destinations = append(destinations, destination{key: keyOf(a.NewItem.Stack), info: info, count: newCount - oldCount, kind: a.SourceType})
+		}
+	}
+
+	b := NewRequest(requestID)
+	for _, dst := range destinations {
+		for _, src := range sources {
+			if dst.count == 0 {
+				break
+			}
+			if src.count == 0 || src.key != dst.key {
+				continue
+			}
+			n := min(src.count, dst.count)
+			if n > 255 {
+				return protocol.ItemStackRequest{}, fmt.Errorf("convert transaction: cannot move %v items in one action", n)
+			}
+			switch dst.kind {
+			case protocol.InventoryActionSourceWorld:
+				b.Drop(byte(n), src.info)
+			case protocol.InventoryActionSourceCreative:
+				b.Destroy(byte(n), src.info)
+			default:
+				b.Place(byte(n), src.info, dst.info)
+			}
+			src.count, dst.count = src.count-n, dst.count-n
+		}
+	}
+	return b.Build(), nil
+}
+
+// Transaction converts the ItemStackRequest passed into a normal InventoryTransaction holding an action for
+// each slot changed and each item dropped, without applying the request to the Containers of the Simulator.
+// windowOf converts a container ID and slot used in ItemStackRequests to the window ID and slot used in
+// InventoryActions. An error is returned if the request is invalid.
+func (s *Simulator) Transaction(req protocol.ItemStackRequest, windowOf func(container, slot byte) (windowID int32, windowSlot uint32)) (*packet.InventoryTransaction, error) {
+	tx := &transaction{s: s, requestID: req.RequestID, containers: map[byte][]protocol.ItemInstance{}}
+	for i, action := range req.Actions {
+		if err := tx.apply(action); err != nil {
+			return nil, fmt.Errorf("request %v: action %v (%T): %w", req.RequestID, i, action, err)
+		}
+	}
+	var actions []protocol.InventoryAction
+	for _, key := range tx.changed {
+		id := key.container
+		if alias, ok := s.Aliases[id]; ok {
+			id = alias
+		}
+		old, new := s.Containers[id][key.slot], tx.containers[id][key.slot]
+		if empty(old) && empty(new) {
+			continue
+		}
+		windowID, slot := windowOf(key.container, key.slot)
+		actions = append(actions, ContainerAction(windowID, slot, old, new))
+	}
+	for _, stack := range tx.dropped {
+		actions = append(actions, DropAction(protocol.ItemInstance{Stack: stack}))
+	}
+	return NormalTransaction(actions...), nil
+}
+
+// itemKey identifies an item type for comparing item counts in a transaction.
+type itemKey struct {
+	t   protocol.ItemType
+	rid int32
+}
+
+// keyOf returns the itemKey of the stack passed.
+func keyOf(stack protocol.ItemStack) itemKey {
+	return itemKey{t: stack.ItemType, rid: stack.BlockRuntimeID}
+}
@@ -0,0 +1,167 @@
+package minecraft
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// idUnconnectedPing and idUnconnectedPong are the IDs of the RakNet packets used to discover servers.
+	idUnconnectedPing byte = 0x01
+	idUnconnectedPong byte = 0x1c
+)
+
+// lanPorts are the ports that vanilla LAN worlds are discovered on, for IPv4 and IPv6 respectively.
+var lanPorts = []int{19132, 19133}
+
+// unconnectedMagic is the magic sequence found in unconnected RakNet packets.
+var unconnectedMagic = []byte{0x00, 0xff, 0xff, 0x00, 0xfe, 0xfe, 0xfe, 0xfe, 0xfd, 0xfd, 0xfd, 0xfd, 0x12, 0x34, 0x56, 0x78}
+
+// LANWorld is a world found on the local network by DiscoverLAN.
+type LANWorld struct {
+	// Addr is the address that the world may be joined on.
+	Addr *net.UDPAddr
+	// Pong holds the status of the world, such as its name and player count.
+	Pong Pong
+}
+
+// DiscoverLAN discovers worlds hosted on the local network, such as vanilla worlds opened to LAN or
+// Listeners announcing themselves using Listener.AnnounceLAN. It broadcasts a ping every interval and reports
+// every world that responds, or that announces itself, on the channel returned. A world is reported again
+// every time it responds, so that changes to its status are picked up. The channel is closed once the
+// context passed is cancelled.
+func DiscoverLAN(ctx context.Context, interval time.Duration) (<-chan LANWorld, error) {
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, fmt.Errorf("discover lan: %w", err)
+	}
+	conns := []*net.UDPConn{conn}
+	// Announcements are broadcast to the LAN port, so we also try to listen on it. This fails if a server
+	// is already running on this machine, in which case we only find worlds by pinging them.
+	if passive, err := net.ListenUDP("udp4", &net.UDPAddr{Port: lanPorts[0]}); err == nil {
+		conns = append(conns, passive)
+	}
+	go func() {
+		<-ctx.Done()
+		for _, c := range conns {
+			_ = c.Close()
+		}
+	}()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		ping := make([]byte, 0, 33)
+		ping = append(ping, idUnconnectedPing)
+		ping = binary.BigEndian.AppendUint64(ping, 0)
+		ping = append(ping, unconnectedMagic...)
+		ping = binary.BigEndian.AppendUint64(ping, rand.Uint64())
+		for {
+			binary.BigEndian.PutUint64(ping[1:], uint64(time.Now().UnixMilli()))
+			for _, port := range lanPorts {
+				_, _ = conn.WriteToUDP(ping, &net.UDPAddr{IP: net.IPv4bcast, Port: port})
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	worlds := make(chan LANWorld)
+	var wg sync.WaitGroup
+	wg.Add(len(conns))
+	for _, c := range conns {
+		go func() {
+			defer wg.Done()
+			readLANWorlds(ctx, c, worlds)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(worlds)
+	}()
+	return worlds, nil
+}
+
+// readLANWorlds reads unconnected pongs from the connection passed and reports the worlds they describe on the
+// worlds channel until the connection is closed.
+func readLANWorlds(ctx context.Context, conn *net.UDPConn, worlds chan<- LANWorld) {
+	b := make([]byte, 1500)
+	for {
+		n, addr, err := conn.ReadFromUDP(b)
+		if err != nil {
+			return
+		}
+		pong, ok := parseUnconnectedPong(b[:n])
+		if !ok {
+			continue
+		}
+		world := LANWorld{Addr: addr, Pong: pong}
+		if pong.PortV4 != 0 {
+			world.Addr = &net.UDPAddr{IP: addr.IP, Port: pong.PortV4}
+		}
+		select {
+		case worlds <- world:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// parseUnconnectedPong parses an unconnected pong RakNet packet and the pong data held in it.
+func parseUnconnectedPong(b []byte) (Pong, bool) {
+	// ID (1) + ping time (8) + server GUID (8) + magic (16) + string length (2).
+	const headerSize = 35
+	if len(b) < headerSize || b[0] != idUnconnectedPong || !bytes.Equal(b[17:33], unconnectedMagic) {
+		return Pong{}, false
+	}
+	l := int(binary.BigEndian.Uint16(b[33:]))
+	if len(b) < headerSize+l {
+		return Pong{}, false
+	}
+	pong, err := ParsePong(b[headerSize : headerSize+l])
+	return pong, err == nil
+}
+
+// AnnounceLAN announces the Listener on the local network by broadcasting its pong data every interval, so
+// that it is found by LAN discovery without having to be pinged first. AnnounceLAN blocks until the context
+// passed is cancelled or the Listener is closed.
+func (listener *Listener) AnnounceLAN(ctx context.Context, interval time.Duration) error {
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return fmt.Errorf("announce lan: %w", err)
+	}
+	defer conn.Close()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		data := listener.pongData()
+		pong := make([]byte, 0, 35+len(data))
+		pong = append(pong, idUnconnectedPong)
+		pong = binary.BigEndian.AppendUint64(pong, uint64(time.Now().UnixMilli()))
+		pong = binary.BigEndian.AppendUint64(pong, uint64(listener.listener.ID()))
+		pong = append(pong, unconnectedMagic...)
+		pong = binary.BigEndian.AppendUint16(pong, uint16(len(data)))
+		pong = append(pong, data...)
+		for _, port := range lanPorts {
+			_, _ = conn.WriteToUDP(pong, &net.UDPAddr{IP: net.IPv4bcast, Port: port})
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-listener.close:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package minecraft
+
+import (
+	"net"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// latencySampleInterval is the interval at which the round trip time of a Conn is sampled.
+	latencySampleInterval = time.Second
+	// latencyWindow is the maximum amount of round trip time samples kept by a Conn. With a sample taken
+	// every second, this covers the last minute of the connection.
+	latencyWindow = 60
+)
+
+// LatencyStats holds statistics over the round trip times (RTT) of a Conn sampled recently. It may be
+// obtained by calling Conn.LatencyStats.
+type LatencyStats struct {
+	// Samples is the amount of samples that the statistics were computed over. All other fields are zero if
+	// Samples is 0.
+	Samples int
+	// Min, Max and Mean are the lowest, highest and mean round trip time sampled.
+	Min, Max, Mean time.Duration
+	// P50, P90 and P99 are the 50th, 90th and 99th percentiles of the round trip times sampled.
+	P50, P90, P99 time.Duration
+	// Jitter is the mean difference between consecutive round trip time samples. A high jitter indicates an
+	// unstable connection, even if the mean round trip time is low.
+	Jitter time.Duration
+}
+
+// LatencyHistory returns the round trip times of the Conn sampled over the last minute, from oldest to
+// newest. A sample is taken every second. The history is empty if the underlying net.Conn does not measure
+// its latency, such as for connections over TCP.
+func (conn *Conn) LatencyHistory() []time.Duration {
+	return conn.rtt.history()
+}
+
+// LatencyStats returns statistics over the round trip times of the Conn sampled over the last minute, such
+// as percentiles and jitter. Samples is 0 if the underlying net.Conn does not measure its latency.
+func (conn *Conn) LatencyStats() LatencyStats {
+	samples := conn.rtt.history()
+	if len(samples) == 0 {
+		return LatencyStats{}
+	}
+	stats := LatencyStats{Samples: len(samples)}
+	var sum, diff time.Duration
+	for i, sample := range samples {
+		sum += sample
+		if i > 0 {
+			d := sample - samples[i-1]
+			if d < 0 {
+				d = -d
+			}
+			diff += d
+		}
+	}
+	stats.Mean = sum / time.Duration(len(samples))
+	if len(samples) > 1 {
+		stats.Jitter = diff / time.Duration(len(samples)-1)
+	}
+
+	slices.Sort(samples)
+	percentile := func(p int) time.Duration {
+		return samples[(len(samples)-1)*p/100]
+	}
+	stats.Min, stats.Max = samples[0], samples[len(samples)-1]
+	stats.P50, stats.P90, stats.P99 = percentile(50), percentile(90), percentile(99)
+	return stats
+}
+
+// sampleLatency samples the round trip time of the Conn every latencySampleInterval until the Conn is
+// closed. It returns immediately if the underlying net.Conn does not measure its latency.
+func (conn *Conn) sampleLatency() {
+	l, ok := conn.conn.(interface{ Latency() time.Duration })
+	if !ok || !measuresLatency(conn.conn) {
+		return
+	}
+	ticker := time.NewTicker(latencySampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-conn.close:
+			return
+		case <-ticker.C:
+			// Latency is half the round trip time, so we double it to get the round trip time.
+			conn.rtt.add(l.Latency() * 2)
+		}
+	}
+}
+
+// measuresLatency checks if the net.Conn passed measures its latency. TCP connections implement Latency so
+// that Conn.Latency does not panic, but always return 0, so they are not sampled.
+func measuresLatency(c net.Conn) bool {
+	switch c := c.(type) {
+	case *tcpConn:
+		return false
+	case *throttledConn:
+		return measuresLatency(c.Conn)
+	case *throttledPacketConn:
+		return measuresLatency(c.Conn)
+	case *prefixedConn:
+		return measuresLatency(c.Conn)
+	case *resumableConn:
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.measures
+	}
+	_, ok := c.(interface{ Latency() time.Duration })
+	return ok
+}
+
+// rttWindow is a ring buffer holding the latencyWindow most recent round trip time samples of a Conn. It is
+// safe for concurrent use.
+type rttWindow struct {
+	mu      sync.Mutex
+	samples [latencyWindow]time.Duration
+	n, next int
+}
+
+// add adds a sample to the rttWindow, overwriting the oldest sample if the window is full.
+func (w *rttWindow) add(sample time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.samples[w.next] = sample
+	w.next = (w.next + 1) % latencyWindow
+	w.n = min(w.n+1, latencyWindow)
+}
+
+// history returns a copy of the samples in the rttWindow, from oldest to newest.
+func (w *rttWindow) history() []time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	history := make([]time.Duration, 0, w.n)
+	start := (w.next - w.n + latencyWindow) % latencyWindow
+	for i := 0; i < w.n; i++ {
+		history = append(history, w.samples[(start+i)%latencyWindow])
+	}
+	return history
+}
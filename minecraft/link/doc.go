@@ -0,0 +1,5 @@
+// Package link implements helpers for entity links, which make one entity ride another. Links are created
+// with Ride and Passenger and removed with Dismount, after which they may be sent using the SetActorLink
+// packet or in the link lists of the AddActor and AddPlayer packets. A Tracker maintains the links currently
+// active by observing these packets, for example in a proxy.
+package link
@@ -0,0 +1,77 @@
+package link
+
+import (
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Ride returns an EntityLink that makes the rider passed ride the vehicle passed while controlling it, such as
+// a player steering a boat or riding a horse. Both entities are identified by their entity unique ID.
+func Ride(vehicle, rider int64) protocol.EntityLink {
+	return protocol.EntityLink{RiddenEntityUniqueID: vehicle, RiderEntityUniqueID: rider, Type: protocol.EntityLinkRider, RiderInitiated: true}
+}
+
+// Passenger returns an EntityLink that makes the rider passed ride the vehicle passed without controlling it,
+// such as a player sitting in the back seat of a boat.
+func Passenger(vehicle, rider int64) protocol.EntityLink {
+	return protocol.EntityLink{RiddenEntityUniqueID: vehicle, RiderEntityUniqueID: rider, Type: protocol.EntityLinkPassenger, RiderInitiated: true}
+}
+
+// Dismount returns an EntityLink that removes the link between the vehicle and rider passed. If immediate is
+// true, the rider is dismounted immediately, which should be done if the vehicle was killed.
+func Dismount(vehicle, rider int64, immediate bool) protocol.EntityLink {
+	return protocol.EntityLink{RiddenEntityUniqueID: vehicle, RiderEntityUniqueID: rider, Type: protocol.EntityLinkRemove, Immediate: immediate}
+}
+
+// Packet returns a SetActorLink packet holding the EntityLink passed.
+func Packet(link protocol.EntityLink) *packet.SetActorLink {
+	return &packet.SetActorLink{EntityLink: link}
+}
+
+// TypeName returns the name of the link type passed, such as 'rider', or 'unknown' if the type is not one
+// of the protocol.EntityLink constants.
+func TypeName(linkType byte) string {
+	switch linkType {
+	case protocol.EntityLinkRemove:
+		return "remove"
+	case protocol.EntityLinkRider:
+		return "rider"
+	case protocol.EntityLinkPassenger:
+		return "passenger"
+	}
+	return "unknown"
+}
+
+// Validate checks if the EntityLink passed may be sent in a SetActorLink packet. An error is returned if the
+// type of the link is unknown or if the link makes an entity ride itself.
+func Validate(link protocol.EntityLink) error {
+	if link.Type > protocol.EntityLinkPassenger {
+		return fmt.Errorf("link %v -> %v: unknown link type %v", link.RiderEntityUniqueID, link.RiddenEntityUniqueID, link.Type)
+	}
+	if link.RiderEntityUniqueID == link.RiddenEntityUniqueID {
+		return fmt.Errorf("link %v -> %v: entity cannot ride itself", link.RiderEntityUniqueID, link.RiddenEntityUniqueID)
+	}
+	return nil
+}
+
+// ValidateList checks if the links passed may be sent in the EntityLinks field of an AddActor or AddPlayer
+// packet. In addition to the checks of Validate, links in these lists may not remove a link, and an entity
+// may not ride more than one vehicle.
+func ValidateList(links []protocol.EntityLink) error {
+	vehicles := make(map[int64]int64, len(links))
+	for _, link := range links {
+		if err := Validate(link); err != nil {
+			return err
+		}
+		if link.Type == protocol.EntityLinkRemove {
+			return fmt.Errorf("link %v -> %v: link lists cannot remove links", link.RiderEntityUniqueID, link.RiddenEntityUniqueID)
+		}
+		if vehicle, ok := vehicles[link.RiderEntityUniqueID]; ok {
+			return fmt.Errorf("link %v -> %v: entity already rides %v", link.RiderEntityUniqueID, link.RiddenEntityUniqueID, vehicle)
+		}
+		vehicles[link.RiderEntityUniqueID] = link.RiddenEntityUniqueID
+	}
+	return nil
+}
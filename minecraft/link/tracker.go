@@ -0,0 +1,132 @@
+package link
+
+import (
+	"slices"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Tracker tracks the entity links currently active, as seen in the packets passed to HandlePacket. It may
+// be used by a proxy to keep track of which entities ride which vehicle. A Tracker is not safe for
+// concurrent use.
+type Tracker struct {
+	// links maps the unique ID of every rider to its link with the vehicle it rides.
+	links map[int64]protocol.EntityLink
+	// riders maps the unique ID of every vehicle to the unique IDs of its riders, in the order they mounted.
+	riders map[int64][]int64
+}
+
+// NewTracker returns an empty Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{links: make(map[int64]protocol.EntityLink), riders: make(map[int64][]int64)}
+}
+
+// HandlePacket updates the links of the Tracker using the packet passed. The links in AddActor, AddPlayer and
+// SetActorLink packets are applied, and all links of entities removed using RemoveActor are removed. Links in
+// ChangeDimension packets are cleared, as the client loses all entities when changing dimension. Other
+// packets are ignored.
+func (t *Tracker) HandlePacket(pk packet.Packet) {
+	switch pk := packet.Unwrap(pk).(type) {
+	case *packet.AddActor:
+		t.apply(pk.EntityLinks...)
+	case *packet.AddPlayer:
+		t.apply(pk.EntityLinks...)
+	case *packet.SetActorLink:
+		t.apply(pk.EntityLink)
+	case *packet.RemoveActor:
+		t.Remove(pk.EntityUniqueID)
+	case *packet.ChangeDimension:
+		t.Reset()
+	}
+}
+
+// apply applies the links passed to the Tracker.
+func (t *Tracker) apply(links ...protocol.EntityLink) {
+	for _, link := range links {
+		if Validate(link) != nil {
+			continue
+		}
+		t.unlink(link.RiderEntityUniqueID)
+		if link.Type == protocol.EntityLinkRemove {
+			continue
+		}
+		t.links[link.RiderEntityUniqueID] = link
+		t.riders[link.RiddenEntityUniqueID] = append(t.riders[link.RiddenEntityUniqueID], link.RiderEntityUniqueID)
+	}
+}
+
+// unlink removes the link of the rider passed with the vehicle it rides, if any.
+func (t *Tracker) unlink(rider int64) {
+	link, ok := t.links[rider]
+	if !ok {
+		return
+	}
+	delete(t.links, rider)
+	vehicle := link.RiddenEntityUniqueID
+	riders := slices.DeleteFunc(t.riders[vehicle], func(id int64) bool { return id == rider })
+	if len(riders) == 0 {
+		delete(t.riders, vehicle)
+		return
+	}
+	t.riders[vehicle] = riders
+}
+
+// Remove removes all links of the entity passed, both as rider and as vehicle.
+func (t *Tracker) Remove(entityUniqueID int64) {
+	t.unlink(entityUniqueID)
+	for _, rider := range t.riders[entityUniqueID] {
+		delete(t.links, rider)
+	}
+	delete(t.riders, entityUniqueID)
+}
+
+// Reset removes all links from the Tracker.
+func (t *Tracker) Reset() {
+	clear(t.links)
+	clear(t.riders)
+}
+
+// Vehicle returns the unique ID of the vehicle that the entity passed rides. False is returned if the entity
+// does not ride anything.
+func (t *Tracker) Vehicle(rider int64) (int64, bool) {
+	link, ok := t.links[rider]
+	return link.RiddenEntityUniqueID, ok
+}
+
+// Link returns the link of the entity passed with the vehicle it rides. False is returned if the entity does
+// not ride anything.
+func (t *Tracker) Link(rider int64) (protocol.EntityLink, bool) {
+	link, ok := t.links[rider]
+	return link, ok
+}
+
+// Riders returns the unique IDs of the entities riding the vehicle passed, in the order they mounted it.
+func (t *Tracker) Riders(vehicle int64) []int64 {
+	return slices.Clone(t.riders[vehicle])
+}
+
+// Controller returns the unique ID of the rider in control of the vehicle passed, which is the first rider
+// linked with protocol.EntityLinkRider. False is returned if no rider controls the vehicle.
+func (t *Tracker) Controller(vehicle int64) (int64, bool) {
+	for _, rider := range t.riders[vehicle] {
+		if t.links[rider].Type == protocol.EntityLinkRider {
+			return rider, true
+		}
+	}
+	return 0, false
+}
+
+// Links returns the links of the riders of the vehicle passed, which may be sent in the EntityLinks field of
+// an AddActor or AddPlayer packet spawning the vehicle for another player.
+func (t *Tracker) Links(vehicle int64) []protocol.EntityLink {
+	riders := t.riders[vehicle]
+	if len(riders) == 0 {
+		return nil
+	}
+	links := make([]protocol.EntityLink, len(riders))
+	for i, rider := range riders {
+		links[i] = t.links[rider]
+	}
+	return links
+}
@@ -6,13 +6,16 @@ import (
 	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"os"
+	"slices"
 	"sync/atomic"
 	"time"
 
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 	"github.com/sandertv/gophertunnel/minecraft/resource"
 )
@@ -43,18 +46,60 @@ type ListenConfig struct {
 	// allowed. If false (by default), such packets lead to the connection being closed immediately. If true,
 	// packets with too many bytes will be returned while packets with too few bytes will be skipped.
 	AllowInvalidPackets bool
+	// ReaderLimits holds the limits on the lengths of slices and strings in packets read from clients, which
+	// protect against malicious length prefixes causing large allocations. By default, no limits are
+	// enforced. Length prefixes that exceed the remaining bytes of a packet are rejected regardless.
+	// protocol.DefaultReaderLimits may be used, but its MaxSliceLength may be too low for valid packets that
+	// hold many elements.
+	ReaderLimits protocol.ReaderLimits
+	// StrictDecoding specifies if errors returned for packets that could not be decoded, or that had bytes
+	// left after decoding, should include the path of the field being read, such as
+	// 'PlayerAuthInput.ItemStackRequest.Actions[0]'. This is useful when debugging protocol changes, but
+	// makes decoding packets slower.
+	StrictDecoding bool
+	// PreserveTrailingBytes specifies if bytes left after decoding a packet should be preserved rather than
+	// the packet being treated as invalid. Such packets are returned by ReadPacket as a *packet.Trailing that
+	// holds the decoded packet and the bytes left, which are written again when the packet is written, so that
+	// a proxy forwards packets unchanged even if the game added new fields to them.
+	PreserveTrailingBytes bool
 
 	// StatusProvider is the ServerStatusProvider of the Listener. When set to nil, the default provider,
-	// ListenerStatusProvider, is used as provider.
+	// ListenerStatusProvider, is used as provider. A ServerStatusFunc may be used to generate the status
+	// dynamically. If the network of the Listener supports it, the status is generated for every ping it
+	// receives. Otherwise, it is refreshed every few seconds and whenever a player joins or leaves.
 	StatusProvider ServerStatusProvider
 
+	// Interface, if not empty, is the name of the network interface, such as "eth0", that the Listener binds
+	// to. The host in the address passed to Listen is replaced with an address of the interface. An IPv6
+	// address is used if the host is an IPv6 address, and an IPv4 address otherwise.
+	Interface string
+	// AdvertisedPortV4 and AdvertisedPortV6 are the IPv4 and IPv6 ports sent to clients in the pong data, for
+	// example when the Listener is behind a NAT or proxy that forwards another external port. If zero, the
+	// port that the Listener listens on is sent.
+	AdvertisedPortV4, AdvertisedPortV6 int
+	// RakNet, if non-nil, holds the settings of the RakNet layer used when listening on the network "raknet".
+	RakNet *RakNet
+
 	// AcceptedProtocols is a slice of Protocol accepted by a Listener created with this ListenConfig. The current
 	// Protocol is always added to this slice. Clients with a protocol version that is not present in this slice will
 	// be disconnected.
+	// The Protocol of each client is negotiated using the protocol version it sends in the RequestNetworkSettings
+	// and Login packets. Packets read from and written to the Conn of that client are converted from and to that
+	// Protocol, which may be obtained using Conn.Protocol.
 	AcceptedProtocols []Protocol
 	// Compression is the packet.Compression to use for packets sent over this Conn. If set to nil, the compression
 	// will default to packet.flateCompression.
 	Compression packet.Compression // TODO: Change this to snappy once Windows crashes are resolved.
+	// CompressionThreshold is the minimum size in bytes of a batch of packets for it to be compressed. It is
+	// sent to clients, which apply the same threshold. Smaller batches are sent uncompressed, which saves
+	// CPU at the cost of bandwidth. If zero, a default of 512 is used. If negative, all batches are
+	// compressed. Thresholds above 65535 are clamped to 65535, the highest threshold that may be sent.
+	CompressionThreshold int
+	// CompressionFunc, if non-nil, is called for every connection to select the compression and
+	// compression threshold to use for it, overriding Compression and CompressionThreshold. It is called
+	// before the client logs in, so only the address and protocol of the connection are known. The threshold
+	// returned is clamped like CompressionThreshold.
+	CompressionFunc func(conn *Conn) (compression packet.Compression, threshold int)
 	// FlushRate is the rate at which packets sent are flushed. Packets are buffered for a duration up to
 	// FlushRate and are compressed/encrypted together to improve compression ratios. The lower this
 	// time.Duration, the lower the latency but the less efficient both network and cpu wise.
@@ -62,24 +107,156 @@ type ListenConfig struct {
 	// will not be flushed automatically. In this case, calling `(*Conn).Flush()` is required after any
 	// calls to `(*Conn).Write()` or `(*Conn).WritePacket()` to send the packets over network.
 	FlushRate time.Duration
+	// FlushSize is the total size in bytes of the packets buffered at which they are flushed immediately,
+	// rather than after FlushRate. It may be used to send large amounts of data in batches of a maximum size.
+	// If zero or lower, packets are only flushed after FlushRate.
+	FlushSize int
+	// FlushPacketIDs holds the IDs of packets, such as packet.IDPlayerAuthInput or packet.IDMovePlayer, after
+	// which all buffered packets are flushed immediately. This reduces latency for packets that are sensitive
+	// to it, without flushing every packet written.
+	FlushPacketIDs []uint32
+	// PacketPriorities holds the Priority of packets sent over the Conn by their ID. If non-nil, packets
+	// with a high priority are always sent first, while normal and low priority packets share the space left
+	// in a batch of up to PriorityBatchSize bytes, so that bulk data does not delay packets such as
+	// PlayerAuthInput. Packets of different priorities are not sent in the order they were written, so
+	// packets that depend on each other must share a Priority. DefaultPacketPriorities may be used as a
+	// sensible default. If nil, packets are sent in the order they are written.
+	PacketPriorities map[uint32]Priority
+	// PriorityBatchSize is the maximum amount of bytes of normal and low priority packets sent in a single
+	// batch if PacketPriorities is set. Packets that do not fit are sent in the next batch. If zero or lower,
+	// all packets are sent in every batch, with packets of a higher priority first.
+	PriorityBatchSize int
+	// ReadRawBatches specifies if batches received after the Conn spawned should not be decoded, but be
+	// returned undecoded by Conn.ReadRawBatch instead, for example so that a proxy can forward them using
+	// Conn.WriteRawBatch. If true, ReadPacket no longer returns packets once the Conn spawned, and packets
+	// such as Disconnect are no longer handled by the Conn.
+	ReadRawBatches bool
+	// DecodePool, if non-nil, decodes packets received by the Conn in parallel as soon as they arrive, instead
+	// of one by one when they are read. Packets are still returned by ReadPacket in the order they were
+	// received. A single DecodePool may be shared by many Dialers and Listeners.
+	DecodePool *DecodePool
+	// PooledPackets specifies if packets read using Conn.ReadPacket after logging in should be decoded from
+	// pooled buffers, so that byte slices in the packets, such as the payload of a LevelChunk packet, alias
+	// the buffer instead of being allocated. This greatly reduces allocations when reading many packets, but
+	// such byte slices are only valid until the next call to ReadPacket or Conn.Release. Conn.ClonePacket may
+	// be used to obtain a copy of a packet that remains valid.
+	PooledPackets bool
 
 	// ResourcePacks is a slice of resource packs that the listener may hold. Each client will be asked to
 	// download these resource packs upon joining.
 	// This field should not be edited during runtime of the Listener to avoid race conditions. Use
 	// Listener.AddResourcePack() to add a resource pack after having called Listener.Listen().
 	ResourcePacks []*resource.Pack
+	// PacksFor, if non-nil, is called for every connection right before the resource packs are sent to it.
+	// The identity data and client data that the client logged in with are available through
+	// Conn.IdentityData and Conn.ClientData. The resource packs returned are sent to that connection instead
+	// of ResourcePacks, so that different packs may be served to different clients, for example depending on
+	// their platform.
+	PacksFor func(conn *Conn) []*resource.Pack
+	// BaseGameVersion is the vanilla game version that clients apply before any resource packs, sent in the
+	// ResourcePackStack packet. If empty, protocol.CurrentVersion is used.
+	BaseGameVersion string
+	// Experiments holds the experiments that clients enable, sent in the ResourcePackStack packet. If nil,
+	// only the 'cameras' experiment is enabled. Set it to an empty, non-nil slice to enable no experiments.
+	Experiments []protocol.ExperimentData
+	// IncludeEditorPacks specifies if clients should include the packs of the Editor in the resource pack
+	// stack. This is only used by clients in Editor mode.
+	IncludeEditorPacks bool
+	// StackFunc, if non-nil, is called for every connection with the ResourcePackStack packet that is about to
+	// be sent to it. The packet may be modified freely, for example to change the order in which the texture
+	// packs are applied, which changes which pack wins if multiple packs change the same files, or to change
+	// the base game version and experiments for that connection specifically.
+	StackFunc func(conn *Conn, stack *packet.ResourcePackStack)
 	// Biomes contains information about all biomes that the server has registered, which the client can use
 	// to render the world more effectively. If these are nil, the default biome definitions will be used.
 	Biomes map[string]any
 	// TexturePacksRequired specifies if clients that join must accept the texture pack in order for them to
 	// be able to join the server. If they don't accept, they can only leave the server.
 	TexturePacksRequired bool
+	// MissingPacksMessage, if non-nil, returns the message that a client is disconnected with if
+	// TexturePacksRequired is true and the client refused or did not finish downloading the resource packs
+	// passed. The language of the client, which may be used to translate the message, is found in
+	// conn.ClientData().LanguageCode. If nil, a default message listing the names of the packs is used.
+	MissingPacksMessage func(conn *Conn, missing []*resource.Pack) string
+	// PackServer, if non-nil, is used to host the ResourcePacks of the Listener over HTTP(S). The download URL
+	// of each pack is set automatically, so that clients download the packs from the PackServer rather than
+	// over the Minecraft connection. If nil, packs are sent over the Minecraft connection in chunks, unless a
+	// pack already has a download URL set.
+	PackServer *PackServer
+	// ResourcePackHooks holds functions that are called during the phases of resource pack negotiation with
+	// each client, such as when a pack starts or finishes being sent.
+	ResourcePackHooks ResourcePackHooks
+	// ExemptedPacks holds the resource packs that clients ship with. These packs are always applied in the
+	// ResourcePackStack packet without being sent to the client. The set may be updated while the Listener
+	// is running. If nil, DefaultExemptedPacks is used.
+	ExemptedPacks *ExemptedPacks
+	// ResourcePackChunkSize is the size in bytes of the chunks that resource packs are split into when they
+	// are sent over the Minecraft connection. Larger chunks improve throughput on fast connections, whereas
+	// smaller chunks may help clients on lossy connections. If zero or lower, a default of 128 KiB is used.
+	ResourcePackChunkSize int
+	// ResourcePackRateLimit is the maximum amount of resource pack data in bytes that is sent to a single
+	// connection per second. Chunk requests of a client are delayed if this limit is exceeded. If zero or
+	// lower, the rate at which resource pack data is sent to a single connection is not limited.
+	ResourcePackRateLimit int
+	// ResourcePackGlobalRateLimit is the maximum amount of resource pack data in bytes that is sent to all
+	// connections of the Listener combined per second. It may be used to prevent upload bandwidth from being
+	// saturated when many clients join at once. If zero or lower, no global limit is imposed.
+	ResourcePackGlobalRateLimit int
 
 	// PacketFunc is called whenever a packet is read from or written to a connection returned when using
 	// Listener.Accept. It includes packets that are otherwise covered in the connection sequence, such as the
 	// Login packet. The function is called with the header of the packet and its raw payload, the address
 	// from which the packet originated, and the destination address.
 	PacketFunc func(header packet.Header, payload []byte, src, dst net.Addr)
+	// LoginTimeouts holds the timeouts of the login sequence. Clients that do not complete the sequence, or
+	// one of its phases, in time are disconnected. LoginTimeouts.Total limits the time until a client is
+	// accepted by the Listener, including resource pack downloads. The Spawn timeout applies once
+	// Conn.StartGame is called.
+	LoginTimeouts LoginTimeouts
+	// KeepAliveInterval is the interval at which a packet is sent to clients that spawned, so that idle
+	// connections are not dropped by middleboxes. If zero or lower, no keep alive packets are sent.
+	KeepAliveInterval time.Duration
+	// PacketLogger, if non-nil, logs packets read from and written to the Conn to a slog.Handler. Packets
+	// may be filtered by their ID and direction.
+	PacketLogger *PacketLogger
+	// KeyExchange, if non-nil, is called for every connection after the client logged in and returns the
+	// KeyExchange settings used to encrypt the connection. It may be used to disable encryption for trusted
+	// connections, such as ones from localhost, or to provide fixed key material for testing.
+	KeyExchange func(conn *Conn) KeyExchange
+
+	// UploadRateLimit and DownloadRateLimit limit the amount of bytes per second that may be sent to and
+	// received from each client. Limits are applied to every batch of packets, so that bursts of packets,
+	// for example during resource pack transfers or chunk sending, are spread out. If zero or lower, no
+	// limit is applied.
+	UploadRateLimit, DownloadRateLimit int
+	// RateLimitBurst is the maximum amount of bytes that may be sent to or received from a client at once
+	// without waiting for the UploadRateLimit or DownloadRateLimit. It is raised to the rate limit if lower.
+	RateLimitBurst int
+
+	// CaptureFunc, if non-nil, is called for every new connection with its remote address. If it returns a
+	// non-nil io.Writer, all packets read from and written to the connection are recorded to it, together
+	// with their direction and the time they were read or written. The capture may be read using
+	// NewCaptureReader. The io.Writer is closed when the connection is closed if it implements io.Closer.
+	CaptureFunc func(addr net.Addr) io.Writer
+
+	// AllowLogin, if non-nil, is called for every connection right after its login request was verified,
+	// before encryption is enabled and resource packs are sent. The identity data and client data of the
+	// connection are available through Conn.IdentityData and Conn.ClientData. If AllowLogin returns false,
+	// the connection is disconnected with the message returned. It may be used to implement allow- and
+	// deny-lists based on, for example, the XUID or device of the client.
+	AllowLogin func(conn *Conn) (message string, ok bool)
+	// LoginChainCache, if non-nil, caches the results of verifying the login chains of clients, so that the
+	// chain of a client that joined before is not verified again. This reduces the load of many clients
+	// joining in a short time.
+	LoginChainCache *login.ChainCache
+	// OnLoginVerified, if non-nil, is called for every login request verified, with the duration that parsing
+	// and verifying the request took and whether its login chain was found in the LoginChainCache. It may be
+	// used to record metrics.
+	OnLoginVerified func(conn *Conn, d time.Duration, cached bool)
+	// GameDataFunc, if non-nil, is called for every connection when Conn.StartGame is called, with a pointer
+	// to the GameData that the client is about to be spawned with. It may modify the GameData, for example to
+	// spawn each player in a world of their own.
+	GameDataFunc func(conn *Conn, data *GameData)
 
 	EarlyConnHandler func(*Conn)
 	OnClientData     func(*Conn)
@@ -99,18 +276,29 @@ type Listener struct {
 	incoming chan *Conn
 	close    chan struct{}
 
+	// packLimiter limits the rate at which resource pack data is sent to all connections combined. It is
+	// nil if ListenConfig.ResourcePackGlobalRateLimit is not set.
+	packLimiter *rateLimiter
+
 	key *ecdsa.PrivateKey
 }
 
-// Listen announces on the local network address. The network is typically "raknet".
+// Listen announces on the local network address. The network is typically "raknet", or "tcp" for connections
+// between trusted backends, such as proxies.
 // If the host in the address parameter is empty or a literal unspecified IP address, Listen listens on all
 // available unicast and anycast IP addresses of the local system.
 func (cfg ListenConfig) Listen(network string, address string) (*Listener, error) {
-	n, ok := networkByID(network)
+	n, ok := networkFor(network, cfg.RakNet)
 	if !ok {
 		return nil, fmt.Errorf("listen: no network under id %v", network)
 	}
 
+	if cfg.Interface != "" {
+		var err error
+		if address, err = interfaceAddress(cfg.Interface, address); err != nil {
+			return nil, fmt.Errorf("listen: %w", err)
+		}
+	}
 	netListener, err := n.Listen(address)
 	if err != nil {
 		return nil, err
@@ -125,9 +313,30 @@ func (cfg ListenConfig) Listen(network string, address string) (*Listener, error
 	if cfg.Compression == nil {
 		cfg.Compression = packet.DefaultCompression
 	}
+	if cfg.CompressionThreshold == 0 {
+		cfg.CompressionThreshold = 512
+	}
+	cfg.CompressionThreshold = clampCompressionThreshold(cfg.CompressionThreshold)
 	if cfg.FlushRate == 0 {
 		cfg.FlushRate = time.Second / 20
 	}
+	if cfg.BaseGameVersion == "" {
+		cfg.BaseGameVersion = protocol.CurrentVersion
+	}
+	if cfg.Experiments == nil {
+		cfg.Experiments = []protocol.ExperimentData{{Name: "cameras", Enabled: true}}
+	}
+	if cfg.ResourcePackChunkSize <= 0 {
+		cfg.ResourcePackChunkSize = packChunkSize
+	}
+	cfg.AcceptedProtocols = acceptedProtocols(cfg.AcceptedProtocols)
+	if cfg.PackServer != nil {
+		if err := cfg.PackServer.listen(); err != nil {
+			_ = netListener.Close()
+			return nil, err
+		}
+		cfg.ResourcePacks = cfg.PackServer.Add(cfg.ResourcePacks...)
+	}
 	key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
 	listener := &Listener{
 		cfg:      cfg,
@@ -135,6 +344,8 @@ func (cfg ListenConfig) Listen(network string, address string) (*Listener, error
 		incoming: make(chan *Conn),
 		close:    make(chan struct{}),
 		key:      key,
+
+		packLimiter: newRateLimiter(cfg.ResourcePackGlobalRateLimit, cfg.ResourcePackChunkSize),
 	}
 
 	// Actually start listening.
@@ -142,6 +353,18 @@ func (cfg ListenConfig) Listen(network string, address string) (*Listener, error
 	return listener, nil
 }
 
+// acceptedProtocols returns a copy of the Protocols passed with the current Protocol added, unless a Protocol
+// with the same ID is already present. If multiple Protocols share an ID, only the first is kept.
+func acceptedProtocols(protocols []Protocol) []Protocol {
+	accepted := make([]Protocol, 0, len(protocols)+1)
+	for _, pro := range append(slices.Clip(protocols), proto{}) {
+		if !slices.ContainsFunc(accepted, func(p Protocol) bool { return p.ID() == pro.ID() }) {
+			accepted = append(accepted, pro)
+		}
+	}
+	return accepted
+}
+
 // Listen announces on the local network address. The network must be "tcp", "tcp4", "tcp6", "unix",
 // "unixpacket" or "raknet". A Listener is returned which may be used to accept connections.
 // If the host in the address parameter is empty or a literal unspecified IP address, Listen listens on all
@@ -169,11 +392,7 @@ func (listener *Listener) Accept() (net.Conn, error) {
 // closing the connection after. If the message passed is empty, the client will be immediately sent to the
 // server list instead of a disconnect screen.
 func (listener *Listener) Disconnect(conn *Conn, message string) error {
-	_ = conn.WritePacket(&packet.Disconnect{
-		HideDisconnectionScreen: message == "",
-		Message:                 message,
-	})
-	return conn.Close()
+	return conn.Disconnect(message, message == "")
 }
 
 // Addr returns the address of the underlying listener.
@@ -182,23 +401,51 @@ func (listener *Listener) Addr() net.Addr {
 }
 
 // Close closes the listener and the underlying net.Listener. Pending calls to Accept will fail immediately.
+// If the Listener has a PackServer that was started by the Listener, it is closed too.
 func (listener *Listener) Close() error {
+	if listener.cfg.PackServer != nil {
+		_ = listener.cfg.PackServer.Close()
+	}
 	return listener.listener.Close()
 }
 
 // updatePongData updates the pong data of the listener using the current only players, maximum players and
 // server name of the listener, provided the listener isn't currently hijacking the pong of another server.
 func (listener *Listener) updatePongData() {
+	listener.listener.PongData(listener.pongData())
+}
+
+// pongData generates the pong data sent in response to pings, using the current status of the Listener.
+func (listener *Listener) pongData() []byte {
 	s := listener.status()
-	listener.listener.PongData([]byte(fmt.Sprintf("MCPE;%v;%v;%v;%v;%v;%v;%s;%v;%v;%v;%v;",
-		s.ServerName, protocol.CurrentProtocol, protocol.CurrentVersion, s.PlayerCount, s.MaxPlayers,
-		listener.listener.ID(), listener.status().ServerSubName, "Creative", 1, listener.Addr().(*net.UDPAddr).Port, listener.Addr().(*net.UDPAddr).Port,
-	)))
+	var port int
+	switch addr := listener.Addr().(type) {
+	case *net.UDPAddr:
+		port = addr.Port
+	case *net.TCPAddr:
+		port = addr.Port
+	}
+	portV4, portV6 := port, port
+	if listener.cfg.AdvertisedPortV4 != 0 {
+		portV4 = listener.cfg.AdvertisedPortV4
+	}
+	if listener.cfg.AdvertisedPortV6 != 0 {
+		portV6 = listener.cfg.AdvertisedPortV6
+	}
+	return []byte(fmt.Sprintf("MCPE;%v;%v;%v;%v;%v;%v;%s;%v;%v;%v;%v;",
+		s.ServerName, s.ProtocolVersion, s.Version, s.PlayerCount, s.MaxPlayers,
+		listener.listener.ID(), s.ServerSubName, s.GameMode, 1, portV4, portV6,
+	))
 }
 
 // listen starts listening for incoming connections and packets. When a player is fully connected, it submits
 // it to the accepted connections channel so that a call to Accept can pick it up.
 func (listener *Listener) listen() {
+	if l, ok := listener.listener.(interface{ PongDataFunc(f func() []byte) }); ok {
+		// The network supports generating the pong data for every ping, so we don't need to update it
+		// periodically.
+		l.PongDataFunc(listener.pongData)
+	}
 	listener.updatePongData()
 	go func() {
 		ticker := time.NewTicker(time.Second * 4)
@@ -231,23 +478,71 @@ func (listener *Listener) listen() {
 // createConn creates a connection for the net.Conn passed and adds it to the listener, so that it may be
 // accepted once its login sequence is complete.
 func (listener *Listener) createConn(netConn net.Conn) {
+	netConn = throttle(netConn, listener.cfg.UploadRateLimit, listener.cfg.DownloadRateLimit, listener.cfg.RateLimitBurst)
 	conn := newConn(netConn, listener.key, listener.cfg.ErrorLog, proto{}, listener.cfg.FlushRate, true)
-	conn.acceptedProto = append(listener.cfg.AcceptedProtocols, proto{})
+	conn.serverSide = true
+	conn.acceptedProto = listener.cfg.AcceptedProtocols
 	conn.compression = listener.cfg.Compression
+	conn.compressionThreshold = listener.cfg.CompressionThreshold
+	conn.compressionFunc = listener.cfg.CompressionFunc
 	conn.pool = conn.proto.Packets(true)
 
 	conn.onClientData = listener.cfg.OnClientData
+	conn.allowLogin = listener.cfg.AllowLogin
+	conn.chainCache, conn.onLoginVerified = listener.cfg.LoginChainCache, listener.cfg.OnLoginVerified
+	conn.onSubClient = listener.addSubClient
 	conn.packetFunc = listener.cfg.PacketFunc
+	conn.packetLogger = listener.cfg.PacketLogger
+	conn.keyExchange = listener.cfg.KeyExchange
+	conn.flushSize, conn.flushIDs = listener.cfg.FlushSize, listener.cfg.FlushPacketIDs
+	conn.decodePool, conn.pooledPackets = listener.cfg.DecodePool, listener.cfg.PooledPackets
+	conn.loginTimeouts, conn.keepAliveInterval = listener.cfg.LoginTimeouts, listener.cfg.KeepAliveInterval
+	conn.startLogin()
+	if listener.cfg.ReadRawBatches {
+		conn.rawBatches = make(chan []byte, 8)
+	}
+	if listener.cfg.PacketPriorities != nil {
+		conn.queue = &writeQueue{priorities: listener.cfg.PacketPriorities, batchSize: listener.cfg.PriorityBatchSize}
+	}
+	if listener.cfg.CaptureFunc != nil {
+		if w := listener.cfg.CaptureFunc(netConn.RemoteAddr()); w != nil {
+			captureWriter, err := NewCaptureWriter(w)
+			if err != nil {
+				listener.cfg.ErrorLog.Printf("capture %v: %v\n", netConn.RemoteAddr(), err)
+			}
+			conn.captureWriter = captureWriter
+		}
+	}
 	conn.texturePacksRequired = listener.cfg.TexturePacksRequired
+	conn.packHooks = listener.cfg.ResourcePackHooks
+	if listener.cfg.ExemptedPacks != nil {
+		conn.exemptedPacks = listener.cfg.ExemptedPacks
+	}
 	conn.ResourcePackHandler = &defaultResourcepackHandler{
-		resourcePacks: listener.cfg.ResourcePacks,
-		c:             conn,
+		resourcePacks:       listener.cfg.ResourcePacks,
+		c:                   conn,
+		chunkSize:           uint64(listener.cfg.ResourcePackChunkSize),
+		packsFor:            listener.packsFor,
+		stackFunc:           listener.cfg.StackFunc,
+		missingPacksMessage: listener.cfg.MissingPacksMessage,
+		stack: packet.ResourcePackStack{
+			BaseGameVersion:    listener.cfg.BaseGameVersion,
+			Experiments:        listener.cfg.Experiments,
+			IncludeEditorPacks: listener.cfg.IncludeEditorPacks,
+		},
+		limiters: []*rateLimiter{newRateLimiter(listener.cfg.ResourcePackRateLimit, listener.cfg.ResourcePackChunkSize), listener.packLimiter},
 	}
 	conn.biomes = listener.cfg.Biomes
 	conn.gameData.WorldName = listener.status().ServerName
+	if f := listener.cfg.GameDataFunc; f != nil {
+		conn.UpdateGameData(func(data *GameData) { f(conn, data) })
+	}
 	conn.authEnabled = !listener.cfg.AuthenticationDisabled
 	conn.disconnectOnUnknownPacket = !listener.cfg.AllowUnknownPackets
 	conn.disconnectOnInvalidPacket = !listener.cfg.AllowInvalidPackets
+	conn.readLimits = listener.cfg.ReaderLimits
+	conn.strictDecoding = listener.cfg.StrictDecoding
+	conn.preserveTrailing = listener.cfg.PreserveTrailingBytes
 
 	if listener.playerCount.Load() == int32(listener.cfg.MaximumPlayers) && listener.cfg.MaximumPlayers != 0 {
 		// The server was full. We kick the player immediately and close the connection.
@@ -261,6 +556,41 @@ func (listener *Listener) createConn(netConn net.Conn) {
 	go listener.handleConn(conn)
 }
 
+// addSubClient adds a sub-client that logged in over an existing connection to the listener, so that it may be
+// accepted like any other connection.
+func (listener *Listener) addSubClient(sub *Conn) {
+	listener.playerCount.Add(1)
+	listener.updatePongData()
+	go func() {
+		defer func() {
+			listener.playerCount.Add(-1)
+			listener.updatePongData()
+		}()
+		select {
+		case <-listener.close:
+			_ = sub.Close()
+			return
+		case <-sub.close:
+			return
+		case listener.incoming <- sub:
+		}
+		<-sub.close
+	}()
+}
+
+// packsFor returns the resource packs that should be sent to the connection passed. If ListenConfig.PacksFor
+// is nil, the ResourcePacks of the ListenConfig are returned.
+func (listener *Listener) packsFor(conn *Conn) []*resource.Pack {
+	if listener.cfg.PacksFor == nil {
+		return listener.cfg.ResourcePacks
+	}
+	packs := listener.cfg.PacksFor(conn)
+	if listener.cfg.PackServer != nil {
+		packs = listener.cfg.PackServer.Add(packs...)
+	}
+	return packs
+}
+
 // status returns the current ServerStatus of the Listener.
 func (listener *Listener) status() ServerStatus {
 	status := listener.cfg.StatusProvider.ServerStatus(int(listener.playerCount.Load()), listener.cfg.MaximumPlayers)
@@ -270,6 +600,15 @@ func (listener *Listener) status() ServerStatus {
 	if status.ServerSubName == "" {
 		status.ServerSubName = "Gophertunnel"
 	}
+	if status.GameMode == "" {
+		status.GameMode = "Creative"
+	}
+	if status.ProtocolVersion == 0 {
+		status.ProtocolVersion = protocol.CurrentProtocol
+	}
+	if status.Version == "" {
+		status.Version = protocol.CurrentVersion
+	}
 	return status
 }
 
@@ -285,11 +624,10 @@ func (listener *Listener) handleConn(conn *Conn) {
 	if listener.cfg.EarlyConnHandler != nil {
 		listener.cfg.EarlyConnHandler(conn)
 	}
-
 	for {
 		// We finally arrived at the packet decoding loop. We constantly decode packets that arrive
 		// and push them to the Conn so that they may be processed.
-		packets, err := conn.dec.Decode()
+		packets, err := conn.decode()
 		if err != nil {
 			if !errors.Is(err, net.ErrClosed) {
 				conn.log.Printf("listener conn: %v\n", err)
@@ -0,0 +1,71 @@
+package minecraft
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Direction is the direction in which a packet travels over a Conn.
+type Direction int
+
+const (
+	// DirectionRead is the Direction of packets read from a Conn, which were sent by the other end of the
+	// connection.
+	DirectionRead Direction = iota
+	// DirectionWrite is the Direction of packets written to a Conn, which are sent to the other end of the
+	// connection.
+	DirectionWrite
+)
+
+// String returns the Direction as a readable string.
+func (dir Direction) String() string {
+	if dir == DirectionWrite {
+		return "write"
+	}
+	return "read"
+}
+
+// Middleware is a function that intercepts packets read from or written to a Conn. It may return a different
+// or modified packet to use instead of the packet passed, or false to drop the packet entirely.
+type Middleware func(pk packet.Packet, dir Direction) (packet.Packet, bool)
+
+// Use adds middleware to the Conn. Middleware is applied to every packet read from and written to the Conn,
+// including packets handled and written internally during the login sequence and resource pack negotiation,
+// in the order that the middleware was added. Middleware applies to packets read after they are decoded and
+// to packets written before they are encoded.
+func (conn *Conn) Use(middleware ...Middleware) {
+	conn.middlewareMu.Lock()
+	defer conn.middlewareMu.Unlock()
+	conn.middleware = append(conn.middleware, middleware...)
+}
+
+// applyMiddleware passes the packet passed through all middleware of the Conn. The resulting packet is
+// returned, or false if one of the middleware dropped the packet.
+func (conn *Conn) applyMiddleware(pk packet.Packet, dir Direction) (packet.Packet, bool) {
+	conn.middlewareMu.RLock()
+	defer conn.middlewareMu.RUnlock()
+	for _, m := range conn.middleware {
+		var ok bool
+		if pk, ok = m(pk, dir); !ok || pk == nil {
+			return nil, false
+		}
+	}
+	return pk, true
+}
+
+// applyReadMiddleware passes the packets read passed through all middleware of the Conn and returns the
+// packets that were not dropped.
+func (conn *Conn) applyReadMiddleware(pks []packet.Packet) []packet.Packet {
+	conn.middlewareMu.RLock()
+	n := len(conn.middleware)
+	conn.middlewareMu.RUnlock()
+	if n == 0 {
+		return pks
+	}
+	filtered := pks[:0]
+	for _, pk := range pks {
+		if pk, ok := conn.applyMiddleware(pk, DirectionRead); ok {
+			filtered = append(filtered, pk)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,6 @@
+// Package molang implements helpers for the MoLang expressions and variable maps embedded in packets, such
+// as the StopCondition of the AnimateEntity packet, the MoLangItemDescriptor and the MoLangVariables of the
+// SpawnParticleEffect packet. Expressions may be checked for syntax errors using Validate and constructed
+// using the functions in this package, which take care of quoting strings and formatting numbers the way
+// MoLang expects them.
+package molang
@@ -0,0 +1,259 @@
+package molang
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Version is the MoLang version used in the version fields of packets, such as the Version of a
+// MoLangItemDescriptor.
+const Version = 10
+
+// String returns the string passed as a MoLang string literal, enclosed in single quotes. MoLang strings have
+// no escape sequences, so an error is returned if the string contains a single quote.
+func String(s string) (string, error) {
+	if strings.ContainsRune(s, '\'') {
+		return "", fmt.Errorf("molang string %q must not contain a single quote", s)
+	}
+	return "'" + s + "'", nil
+}
+
+// Float returns the MoLang literal of the number passed. MoLang does not support exponents, so the number is
+// always written in decimal notation. NaN and infinite numbers are written as 0.
+func Float(f float64) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "0"
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// Bool returns the MoLang literal of the bool passed. MoLang has no booleans, so 1.0 is used for true and 0.0
+// for false.
+func Bool(b bool) string {
+	if b {
+		return "1.0"
+	}
+	return "0.0"
+}
+
+// Call returns an expression calling the function passed, such as 'query.is_item_name_any', with the
+// arguments passed, which must themselves be valid MoLang expressions.
+func Call(function string, args ...string) string {
+	if len(args) == 0 {
+		return function
+	}
+	return function + "(" + strings.Join(args, ", ") + ")"
+}
+
+// Query returns an expression calling the query function passed, such as 'is_sneaking', with the arguments
+// passed.
+func Query(name string, args ...string) string {
+	return Call("query."+name, args...)
+}
+
+// Variable returns an expression referring to the variable passed, such as 'direction'.
+func Variable(name string) string {
+	return "variable." + name
+}
+
+// All returns an expression that is true if all the conditions passed are true.
+func All(conditions ...string) string {
+	return join(conditions, " && ")
+}
+
+// Any returns an expression that is true if any of the conditions passed is true.
+func Any(conditions ...string) string {
+	return join(conditions, " || ")
+}
+
+// join joins the expressions passed with the operator passed, enclosing each in parentheses.
+func join(expressions []string, op string) string {
+	if len(expressions) == 1 {
+		return expressions[0]
+	}
+	parts := make([]string, len(expressions))
+	for i, e := range expressions {
+		parts[i] = "(" + e + ")"
+	}
+	return strings.Join(parts, op)
+}
+
+// Validate checks the MoLang expression passed for syntax errors: Unknown characters, unterminated strings,
+// unbalanced brackets, misplaced operators and references to unknown namespaces. Validate does not check if
+// the queries and variables referred to exist.
+func Validate(expr string) error {
+	tokens, err := tokenise(expr)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return errors.New("molang expression is empty")
+	}
+	var (
+		brackets []byte
+		// operand is true if the previous token ended an operand, meaning a binary operator or closing
+		// bracket may follow.
+		operand bool
+	)
+	for _, t := range tokens {
+		switch t.kind {
+		case tokenNumber, tokenString, tokenIdentifier:
+			if operand {
+				return fmt.Errorf("molang expression: unexpected %q at offset %v", t.text, t.off)
+			}
+			if t.kind == tokenIdentifier {
+				if err := validIdentifier(t.text); err != nil {
+					return fmt.Errorf("molang expression: %w at offset %v", err, t.off)
+				}
+			}
+			// The return keyword is followed by the expression returned.
+			operand = !strings.EqualFold(t.text, "return")
+		case tokenOpen:
+			if operand && t.text != "(" && t.text != "[" {
+				return fmt.Errorf("molang expression: unexpected %q at offset %v", t.text, t.off)
+			}
+			brackets = append(brackets, closing[t.text[0]])
+			operand = false
+		case tokenClose:
+			if len(brackets) == 0 || brackets[len(brackets)-1] != t.text[0] {
+				return fmt.Errorf("molang expression: unbalanced %q at offset %v", t.text, t.off)
+			}
+			brackets = brackets[:len(brackets)-1]
+			operand = t.text != "}"
+		case tokenOperator:
+			switch {
+			case t.text == ";":
+				operand = false
+			case t.text == "!" || (!operand && t.text == "-"):
+				// Unary operators.
+				if operand {
+					return fmt.Errorf("molang expression: unexpected %q at offset %v", t.text, t.off)
+				}
+			default:
+				if !operand {
+					return fmt.Errorf("molang expression: unexpected operator %q at offset %v", t.text, t.off)
+				}
+				operand = false
+			}
+		}
+	}
+	if len(brackets) != 0 {
+		return fmt.Errorf("molang expression: missing %q", brackets[len(brackets)-1])
+	}
+	if last := tokens[len(tokens)-1]; !operand && last.text != ";" && last.text != "}" {
+		return fmt.Errorf("molang expression: unexpected end after %q", last.text)
+	}
+	return nil
+}
+
+// namespaces holds the namespaces that identifiers may start with, including the short aliases of query,
+// variable, temp and context.
+var namespaces = map[string]bool{
+	"query": true, "q": true,
+	"variable": true, "v": true,
+	"temp": true, "t": true,
+	"context": true, "c": true,
+	"math": true, "geometry": true, "material": true, "texture": true, "array": true,
+}
+
+// keywords holds the identifiers that need no namespace.
+var keywords = map[string]bool{
+	"return": true, "loop": true, "for_each": true, "break": true, "continue": true, "this": true, "true": true, "false": true,
+}
+
+// validIdentifier checks if the identifier passed, such as 'query.is_sneaking', has a known namespace.
+func validIdentifier(ident string) error {
+	if keywords[strings.ToLower(ident)] {
+		return nil
+	}
+	namespace, name, ok := strings.Cut(strings.ToLower(ident), ".")
+	if !ok || name == "" {
+		return fmt.Errorf("identifier %q has no namespace", ident)
+	}
+	if !namespaces[namespace] {
+		return fmt.Errorf("identifier %q has unknown namespace %q", ident, namespace)
+	}
+	return nil
+}
+
+const (
+	tokenNumber = iota
+	tokenString
+	tokenIdentifier
+	tokenOperator
+	tokenOpen
+	tokenClose
+)
+
+// closing maps opening brackets to their closing brackets.
+var closing = map[byte]byte{'(': ')', '[': ']', '{': '}'}
+
+// token is a token of a MoLang expression.
+type token struct {
+	kind int
+	text string
+	off  int
+}
+
+// operators holds the operators of MoLang, with longer operators first so that they take precedence.
+var operators = []string{"??", "->", "&&", "||", "==", "!=", "<=", ">=", "+", "-", "*", "/", "<", ">", "!", "?", ":", "=", ";", ","}
+
+// tokenise splits the MoLang expression passed into tokens.
+func tokenise(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'':
+			end := strings.IndexByte(expr[i+1:], '\'')
+			if end == -1 {
+				return nil, fmt.Errorf("molang expression: unterminated string at offset %v", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: expr[i : i+end+2], off: i})
+			i += end + 2
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(expr) && expr[i+1] >= '0' && expr[i+1] <= '9':
+			start := i
+			for i < len(expr) && (expr[i] >= '0' && expr[i] <= '9' || expr[i] == '.') {
+				i++
+			}
+			if i < len(expr) && expr[i] == 'f' {
+				i++
+			}
+			if _, err := strconv.ParseFloat(strings.TrimSuffix(expr[start:i], "f"), 64); err != nil {
+				return nil, fmt.Errorf("molang expression: invalid number %q at offset %v", expr[start:i], start)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: expr[start:i], off: start})
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			start := i
+			for i < len(expr) && (expr[i] == '_' || expr[i] == '.' || expr[i] >= 'a' && expr[i] <= 'z' || expr[i] >= 'A' && expr[i] <= 'Z' || expr[i] >= '0' && expr[i] <= '9') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: expr[start:i], off: start})
+		case c == '(' || c == '[' || c == '{':
+			tokens = append(tokens, token{kind: tokenOpen, text: expr[i : i+1], off: i})
+			i++
+		case c == ')' || c == ']' || c == '}':
+			tokens = append(tokens, token{kind: tokenClose, text: expr[i : i+1], off: i})
+			i++
+		default:
+			var op string
+			for _, o := range operators {
+				if strings.HasPrefix(expr[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("molang expression: unexpected character %q at offset %v", c, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, off: i})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,156 @@
+package molang
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// Variables is a map of MoLang variables, as held by the MoLangVariables field of the SpawnParticleEffect
+// packet. Variables are set using the Set methods and encoded to JSON using MarshalJSON or Optional. The
+// zero value of Variables holds no variables and is ready for use.
+type Variables struct {
+	entries []variableEntry
+}
+
+// variableEntry is a single variable in the JSON encoding of Variables.
+type variableEntry struct {
+	Name  string        `json:"name"`
+	Value variableValue `json:"value"`
+}
+
+// variableValue is the typed value of a variable in the JSON encoding of Variables. Value is a float64 for
+// the 'float' type and a []variableEntry for the 'member_array' type.
+type variableValue struct {
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+// SetFloat sets the variable with the name passed, such as 'variable.size', to a number.
+func (v *Variables) SetFloat(name string, f float64) {
+	v.set(name, variableValue{Type: "float", Value: f})
+}
+
+// SetVector3 sets the variable with the name passed to a vector, of which the members are accessible as
+// '.x', '.y' and '.z'.
+func (v *Variables) SetVector3(name string, vec [3]float64) {
+	v.setMembers(name, []string{".x", ".y", ".z"}, vec[:])
+}
+
+// SetColourRGB sets the variable with the name passed to an RGB colour, of which the members are accessible
+// as '.r', '.g' and '.b'. Each component is in the range 0-1.
+func (v *Variables) SetColourRGB(name string, rgb [3]float64) {
+	v.setMembers(name, []string{".r", ".g", ".b"}, rgb[:])
+}
+
+// SetColourRGBA sets the variable with the name passed to an RGBA colour, of which the members are accessible
+// as '.r', '.g', '.b' and '.a'. Each component is in the range 0-1.
+func (v *Variables) SetColourRGBA(name string, rgba [4]float64) {
+	v.setMembers(name, []string{".r", ".g", ".b", ".a"}, rgba[:])
+}
+
+// Float returns the number held by the variable with the name passed. False is returned if the variable is
+// not set or does not hold a number.
+func (v *Variables) Float(name string) (float64, bool) {
+	for _, e := range v.entries {
+		if e.Name == name {
+			f, ok := e.Value.Value.(float64)
+			return f, ok && e.Value.Type == "float"
+		}
+	}
+	return 0, false
+}
+
+// Len returns the number of variables set.
+func (v *Variables) Len() int {
+	return len(v.entries)
+}
+
+// setMembers sets the variable with the name passed to a member array with the members and values passed.
+func (v *Variables) setMembers(name string, members []string, values []float64) {
+	entries := make([]variableEntry, len(members))
+	for i, m := range members {
+		entries[i] = variableEntry{Name: m, Value: variableValue{Type: "float", Value: values[i]}}
+	}
+	v.set(name, variableValue{Type: "member_array", Value: entries})
+}
+
+// set sets the variable with the name passed, replacing its value if it was already set.
+func (v *Variables) set(name string, value variableValue) {
+	for i, e := range v.entries {
+		if e.Name == name {
+			v.entries[i].Value = value
+			return
+		}
+	}
+	v.entries = append(v.entries, variableEntry{Name: name, Value: value})
+}
+
+// MarshalJSON ...
+func (v *Variables) MarshalJSON() ([]byte, error) {
+	if v.entries == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(v.entries)
+}
+
+// UnmarshalJSON ...
+func (v *Variables) UnmarshalJSON(data []byte) error {
+	var entries []struct {
+		Name  string `json:"name"`
+		Value struct {
+			Type  string          `json:"type"`
+			Value json.RawMessage `json:"value"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("decode molang variables: %w", err)
+	}
+	v.entries = nil
+	for _, e := range entries {
+		value := variableValue{Type: e.Value.Type}
+		switch e.Value.Type {
+		case "float":
+			var f float64
+			if err := json.Unmarshal(e.Value.Value, &f); err != nil {
+				return fmt.Errorf("decode molang variables: variable %v: %w", e.Name, err)
+			}
+			value.Value = f
+		case "member_array":
+			var members Variables
+			if err := members.UnmarshalJSON(e.Value.Value); err != nil {
+				return fmt.Errorf("decode molang variables: variable %v: %w", e.Name, err)
+			}
+			value.Value = members.entries
+		default:
+			return fmt.Errorf("decode molang variables: variable %v: unknown type %q", e.Name, e.Value.Type)
+		}
+		v.entries = append(v.entries, variableEntry{Name: e.Name, Value: value})
+	}
+	return nil
+}
+
+// Optional returns the Variables encoded as JSON for use in the MoLangVariables field of the
+// SpawnParticleEffect packet. If no variables are set, an empty optional is returned.
+func (v *Variables) Optional() protocol.Optional[[]byte] {
+	if len(v.entries) == 0 {
+		return protocol.Optional[[]byte]{}
+	}
+	data, _ := v.MarshalJSON()
+	return protocol.Option(data)
+}
+
+// ParseVariables decodes the Variables held by the MoLangVariables field of the SpawnParticleEffect packet.
+// If the optional holds no value, empty Variables are returned.
+func ParseVariables(o protocol.Optional[[]byte]) (*Variables, error) {
+	v := &Variables{}
+	data, ok := o.Value()
+	if !ok || len(data) == 0 {
+		return v, nil
+	}
+	if err := v.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
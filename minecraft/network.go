@@ -43,7 +43,12 @@ type NetworkListener interface {
 // RegisterNetwork.
 var networks = map[string]Network{}
 
-// RegisterNetwork registers a network so that it can be used for Gophertunnel.
+// RegisterNetwork registers a network so that it can be used for Gophertunnel. Gophertunnel itself registers
+// the "raknet" and "tcp" networks. Transports that depend on third party stacks, such as NetherNet, which
+// runs over WebRTC data channels and is signaled through the franchise services, are not provided by
+// Gophertunnel, but may be implemented as a Network outside of it and registered using RegisterNetwork. The
+// same goes for a QUIC transport: Links between trusted backends that need congestion control may use the
+// "tcp" network, whereas a Network backed by a QUIC implementation may be registered to also get TLS.
 func RegisterNetwork(id string, n Network) {
 	networks[id] = n
 }
@@ -0,0 +1,151 @@
+package minecraft
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/sandertv/go-raknet"
+)
+
+// NetworkConditions describes the conditions of a simulated network link. They are applied to every
+// datagram sent, below the reliability layer of RakNet, so that resends and out-of-order delivery are
+// exercised like they would be over a real network.
+type NetworkConditions struct {
+	// Latency is the delay added to every datagram sent. Jitter is the maximum random delay added on top of
+	// Latency, which may cause datagrams to arrive out of order.
+	Latency, Jitter time.Duration
+	// Loss is the probability, from 0 to 1, that a datagram is dropped.
+	Loss float64
+	// Reorder is the probability, from 0 to 1, that a datagram is held back for an additional Latency, so
+	// that datagrams sent after it arrive first.
+	Reorder float64
+	// Seed is the seed of the random source used to apply the conditions, so that runs are reproducible.
+	Seed int64
+}
+
+// SimulatedRakNet is a Network that runs RakNet over UDP with the NetworkConditions set applied to every
+// datagram sent, both by dialers and listeners. It may be registered under a network ID of its own using
+// RegisterNetwork to test the resilience of a Dialer and Listener in the same process:
+//
+//	minecraft.RegisterNetwork("raknet-sim", minecraft.SimulatedRakNet{Conditions: minecraft.NetworkConditions{
+//		Latency: time.Millisecond * 50, Jitter: time.Millisecond * 20, Loss: 0.05,
+//	}})
+type SimulatedRakNet struct {
+	Conditions NetworkConditions
+}
+
+// DialContext ...
+func (s SimulatedRakNet) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	return raknet.Dialer{UpstreamDialer: simDialer{sim: s.simulator()}}.DialContext(ctx, address)
+}
+
+// PingContext ...
+func (s SimulatedRakNet) PingContext(ctx context.Context, address string) (response []byte, err error) {
+	return raknet.Dialer{UpstreamDialer: simDialer{sim: s.simulator()}}.PingContext(ctx, address)
+}
+
+// Listen ...
+func (s SimulatedRakNet) Listen(address string) (NetworkListener, error) {
+	return raknet.ListenConfig{UpstreamPacketListener: simListener{sim: s.simulator()}}.Listen(address)
+}
+
+// simulator returns a new simulator applying the NetworkConditions of the SimulatedRakNet.
+func (s SimulatedRakNet) simulator() *simulator {
+	return &simulator{c: s.Conditions, r: rand.New(rand.NewSource(s.Conditions.Seed))}
+}
+
+// simulator applies NetworkConditions to datagrams written.
+type simulator struct {
+	c NetworkConditions
+
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// send sends the datagram passed using the function passed, after the delay determined by the conditions of
+// the simulator. The datagram is copied, so it may be reused after send returns. False is returned if the
+// datagram was dropped.
+func (s *simulator) send(b []byte, write func(b []byte)) bool {
+	s.mu.Lock()
+	drop := s.r.Float64() < s.c.Loss
+	delay := s.c.Latency
+	if s.c.Jitter > 0 {
+		delay += time.Duration(s.r.Int63n(int64(s.c.Jitter)))
+	}
+	if s.r.Float64() < s.c.Reorder {
+		delay += s.c.Latency
+	}
+	s.mu.Unlock()
+	if drop {
+		return false
+	}
+
+	data := append([]byte(nil), b...)
+	if delay <= 0 {
+		write(data)
+		return true
+	}
+	time.AfterFunc(delay, func() { write(data) })
+	return true
+}
+
+// simDialer is a raknet.UpstreamDialer that dials UDP connections with simulated network conditions.
+type simDialer struct {
+	sim *simulator
+}
+
+// Dial ...
+func (d simDialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialContext ...
+func (d simDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	c, err := (&net.Dialer{}).DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	return &simConn{Conn: c, sim: d.sim}, nil
+}
+
+// simConn is a net.Conn of which written datagrams are subject to simulated network conditions.
+type simConn struct {
+	net.Conn
+	sim *simulator
+}
+
+// Write ...
+func (c *simConn) Write(b []byte) (int, error) {
+	c.sim.send(b, func(b []byte) { _, _ = c.Conn.Write(b) })
+	return len(b), nil
+}
+
+// simListener is a raknet.UpstreamPacketListener that listens for UDP datagrams with simulated network
+// conditions.
+type simListener struct {
+	sim *simulator
+}
+
+// ListenPacket ...
+func (l simListener) ListenPacket(network, address string) (net.PacketConn, error) {
+	c, err := net.ListenPacket(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return &simPacketConn{PacketConn: c, sim: l.sim}, nil
+}
+
+// simPacketConn is a net.PacketConn of which written datagrams are subject to simulated network conditions.
+type simPacketConn struct {
+	net.PacketConn
+	sim *simulator
+}
+
+// WriteTo ...
+func (c *simPacketConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	c.sim.send(b, func(b []byte) { _, _ = c.PacketConn.WriteTo(b, addr) })
+	return len(b), nil
+}
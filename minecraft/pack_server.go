@@ -0,0 +1,132 @@
+package minecraft
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/resource"
+)
+
+// PackServer is an http.Handler that hosts resource packs over HTTP(S), so that clients may download them
+// from a URL instead of through ResourcePackChunkData packets sent over the Minecraft connection, which is
+// considerably slower for big packs.
+// A PackServer may be set to ListenConfig.PackServer, in which case all resource packs of the Listener are
+// added to it and their download URLs are filled out automatically.
+type PackServer struct {
+	// Address is the address that the PackServer listens on when used by a Listener, such as ":8080". If
+	// left empty, the PackServer does not listen by itself, and it must instead be plugged into an existing
+	// HTTP server as an http.Handler.
+	Address string
+	// URL is the base URL that clients use to reach the PackServer, such as "https://example.com/packs".
+	// Each pack is served at URL/<UUID>_<version>.zip. If left empty, the URL is derived from Address, which
+	// then must contain a host.
+	URL string
+	// TLSConfig, if non-nil, makes the PackServer serve resource packs over HTTPS using the tls.Config
+	// passed. TLSConfig should at least hold one certificate or a GetCertificate function.
+	TLSConfig *tls.Config
+
+	mu    sync.RWMutex
+	packs map[string]*resource.Pack
+	srv   *http.Server
+}
+
+// Add adds the resource packs passed to the PackServer so that they may be downloaded. Add returns copies
+// of the packs with their download URL set to the URL that the PackServer serves them on.
+func (s *PackServer) Add(packs ...*resource.Pack) []*resource.Pack {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.packs == nil {
+		s.packs = make(map[string]*resource.Pack, len(packs))
+	}
+	hosted := make([]*resource.Pack, 0, len(packs))
+	for _, pack := range packs {
+		name := pack.UUID() + "_" + pack.Version() + ".zip"
+		s.packs[name] = pack
+		hosted = append(hosted, pack.WithDownloadURL(s.baseURL()+"/"+name))
+	}
+	return hosted
+}
+
+// Remove removes the resource pack with the UUID and version passed from the PackServer. Clients will no
+// longer be able to download it from the PackServer.
+func (s *PackServer) Remove(uuid, version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.packs, uuid+"_"+version+".zip")
+}
+
+// ServeHTTP serves the resource pack requested in the path of the http.Request passed.
+func (s *PackServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	name := r.URL.Path[strings.LastIndexByte(r.URL.Path, '/')+1:]
+
+	s.mu.RLock()
+	pack, ok := s.packs[name]
+	s.mu.RUnlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	// The content of a Pack is shared between connections, so we make sure to only ever use ReadAt, which
+	// is safe for concurrent use, rather than seeking in the pack itself.
+	http.ServeContent(w, r, name, time.Time{}, io.NewSectionReader(pack, 0, int64(pack.Len())))
+}
+
+// listen starts listening on the Address of the PackServer if it is non-empty. An error is returned if the
+// PackServer could not start listening or if no URL could be derived.
+func (s *PackServer) listen() error {
+	if s.URL == "" {
+		host, _, err := net.SplitHostPort(s.Address)
+		if ip := net.ParseIP(host); err != nil || host == "" || (ip != nil && ip.IsUnspecified()) {
+			return fmt.Errorf("pack server: URL must be set if address %q has no specific host", s.Address)
+		}
+	}
+	if s.Address == "" {
+		return nil
+	}
+	l, err := net.Listen("tcp", s.Address)
+	if err != nil {
+		return fmt.Errorf("pack server: %w", err)
+	}
+	if s.TLSConfig != nil {
+		l = tls.NewListener(l, s.TLSConfig)
+	}
+	s.srv = &http.Server{Handler: s, ReadHeaderTimeout: time.Second * 10}
+	go func() {
+		_ = s.srv.Serve(l)
+	}()
+	return nil
+}
+
+// Close closes the HTTP server started by the PackServer, if any. Close does not close HTTP servers that
+// the PackServer was plugged into as an http.Handler.
+func (s *PackServer) Close() error {
+	if s.srv == nil {
+		return nil
+	}
+	return s.srv.Close()
+}
+
+// baseURL returns the base URL that packs of the PackServer are served on, without a trailing slash.
+func (s *PackServer) baseURL() string {
+	if s.URL != "" {
+		return strings.TrimSuffix(s.URL, "/")
+	}
+	scheme := "http"
+	if s.TLSConfig != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + s.Address
+}
@@ -0,0 +1,105 @@
+package minecraft
+
+import (
+	"sync"
+	"time"
+)
+
+// PackTransfer holds metrics of the transfer of a single resource pack over a Conn.
+type PackTransfer struct {
+	// UUID and Version identify the resource pack transferred.
+	UUID, Version string
+	// Size is the size of the resource pack in bytes.
+	Size uint64
+	// Started is the time at which the transfer started.
+	Started time.Time
+	// Duration is the time it took for the transfer to finish. It is zero if the transfer has not yet
+	// finished.
+	Duration time.Duration
+	// Err is non-nil if the transfer failed.
+	Err error
+}
+
+// PackTransferStats holds metrics of the resource pack transfers of a Conn. It may be obtained by calling
+// Conn.PackTransferStats.
+type PackTransferStats struct {
+	// BytesSent and BytesReceived are the amount of bytes of resource pack data sent and received over the
+	// connection in ResourcePackChunkData packets.
+	BytesSent, BytesReceived uint64
+	// ChunksRerequested is the amount of chunks that were requested again after not arriving in time.
+	ChunksRerequested int
+	// Transfers holds metrics of every resource pack transferred, in the order the transfers started.
+	Transfers []PackTransfer
+}
+
+// Throughput returns the aggregate throughput of all transfers that finished successfully, in bytes per
+// second.
+func (stats PackTransferStats) Throughput() float64 {
+	var size uint64
+	var duration time.Duration
+	for _, transfer := range stats.Transfers {
+		if transfer.Duration > 0 && transfer.Err == nil {
+			size += transfer.Size
+			duration += transfer.Duration
+		}
+	}
+	if duration == 0 {
+		return 0
+	}
+	return float64(size) / duration.Seconds()
+}
+
+// packStats records the PackTransferStats of a Conn. It is safe for concurrent use.
+type packStats struct {
+	mu    sync.Mutex
+	stats PackTransferStats
+}
+
+// sent records n bytes of resource pack data being sent.
+func (s *packStats) sent(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.BytesSent += uint64(n)
+}
+
+// received records n bytes of resource pack data being received.
+func (s *packStats) received(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.BytesReceived += uint64(n)
+}
+
+// rerequested records a chunk being requested again.
+func (s *packStats) rerequested() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.ChunksRerequested++
+}
+
+// started records the start of the transfer of a resource pack.
+func (s *packStats) started(uuid, version string, size uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.Transfers = append(s.stats.Transfers, PackTransfer{UUID: uuid, Version: version, Size: size, Started: time.Now()})
+}
+
+// finished records the end of the latest transfer of the resource pack passed.
+func (s *packStats) finished(uuid, version string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.stats.Transfers) - 1; i >= 0; i-- {
+		if transfer := &s.stats.Transfers[i]; transfer.UUID == uuid && transfer.Version == version && transfer.Duration == 0 {
+			transfer.Duration, transfer.Err = time.Since(transfer.Started), err
+			return
+		}
+	}
+}
+
+// snapshot returns a copy of the PackTransferStats recorded so far.
+func (s *packStats) snapshot() PackTransferStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats
+	stats.Transfers = append([]PackTransfer(nil), s.stats.Transfers...)
+	return stats
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
 
@@ -14,6 +15,17 @@ type packetData struct {
 	h       *packet.Header
 	full    []byte
 	payload *bytes.Buffer
+
+	// conn and done are set if the packetData is decoded by a DecodePool. done is closed once decoding is
+	// complete, after which pks and err hold the result.
+	conn *Conn
+	done chan struct{}
+	pks  []packet.Packet
+	err  error
+
+	// pooled, if non-nil, is the pooled buffer holding the data of the packet, which byte slices of the
+	// packets decoded alias. It is returned to the pool by release.
+	pooled *bytes.Buffer
 }
 
 // ParseData parses the packet data slice passed into a packetData struct.
@@ -40,15 +52,40 @@ func (err unknownPacketError) Error() string {
 	return fmt.Sprintf("unexpected packet (ID=%v)", err.id)
 }
 
+// decode decodes the packet payload held in the packetData using the settings of the Conn passed, after which
+// the packets decoded are passed through the middleware of the Conn.
 func (p *packetData) decode(conn *Conn) (pks []packet.Packet, err error) {
-	return p.Decode(conn.pool, conn.proto, conn.Close, conn.disconnectOnUnknownPacket, conn.disconnectOnInvalidPacket, conn.shieldID.Load())
+	if p.done != nil {
+		pks, err = p.result()
+	} else {
+		pks, err = p.Decode(conn.pool, conn.proto, conn.Close, conn.disconnectOnUnknownPacket, conn.disconnectOnInvalidPacket, conn.shieldID.Load(), conn.readLimits, conn.strictDecoding, conn.preserveTrailing)
+	}
+	if conn.ticks != nil {
+		for _, pk := range pks {
+			conn.ticks.observe(pk)
+		}
+	}
+	pks = conn.filterEducationPackets(pks)
+	pks = conn.applyReadMiddleware(pks)
+	for _, pk := range pks {
+		conn.packetLogger.log(conn, DirectionRead, pk)
+	}
+	return pks, err
 }
 
 // decode decodes the packet payload held in the packetData and returns the packet.Packet decoded.
-func (p *packetData) Decode(pool packet.Pool, proto Protocol, close func() error, DisconnectOnUnknownPacket, DisconnectOnInvalidPacket bool, ShieldID int32) (pks []packet.Packet, err error) {
+func (p *packetData) Decode(pool packet.Pool, proto Protocol, close func() error, DisconnectOnUnknownPacket, DisconnectOnInvalidPacket bool, ShieldID int32, limits protocol.ReaderLimits, strict, preserveTrailing bool) (pks []packet.Packet, err error) {
+	var (
+		pk packet.Packet
+		rd *protocol.Reader
+	)
 	defer func() {
 		if recoveredErr := recover(); recoveredErr != nil {
-			err = fmt.Errorf("decode packet %v: %w", p.h.PacketID, recoveredErr.(error))
+			if path := fieldPath(rd, pk); path != "" {
+				err = fmt.Errorf("decode packet %v: field %v: %w", p.h.PacketID, path, recoveredErr.(error))
+			} else {
+				err = fmt.Errorf("decode packet %v: %w", p.h.PacketID, recoveredErr.(error))
+			}
 		}
 		if err == nil {
 			return
@@ -60,7 +97,6 @@ func (p *packetData) Decode(pool packet.Pool, proto Protocol, close func() error
 
 	// Attempt to fetch the packet with the right packet ID from the pool.
 	pkFunc, ok := pool[p.h.PacketID]
-	var pk packet.Packet
 	if !ok {
 		// No packet with the ID. This may be a custom packet of some sorts.
 		pk = &packet.Unknown{PacketID: p.h.PacketID}
@@ -71,13 +107,40 @@ func (p *packetData) Decode(pool packet.Pool, proto Protocol, close func() error
 		pk = pkFunc()
 	}
 
-	r := proto.NewReader(p.payload, ShieldID, false)
-	pk.Marshal(r)
+	var src ByteReader = p.payload
+	if p.pooled != nil {
+		src = protocol.AliasingBuffer{Buffer: p.payload}
+	}
+	r := proto.NewReader(src, ShieldID, false)
+	if rd, ok = r.(*protocol.Reader); ok {
+		rd.SetLimits(limits)
+		rd.SetStrict(strict)
+	}
+	packet.Marshal(pk, r)
+	if p.payload.Len() != 0 && preserveTrailing {
+		// The bytes left are likely fields not yet implemented, so we keep them to be written when the packet
+		// is encoded again.
+		pk = &packet.Trailing{Packet: pk, Data: bytes.Clone(p.payload.Bytes())}
+		p.payload.Reset()
+	}
 	if p.payload.Len() != 0 {
-		err = fmt.Errorf("decode packet %T: %v unread bytes left: 0x%x", pk, p.payload.Len(), p.payload.Bytes())
+		if path := fieldPath(rd, pk); path != "" {
+			err = fmt.Errorf("decode packet %T: %v unread bytes left after field %v: 0x%x", pk, p.payload.Len(), path, p.payload.Bytes())
+		} else {
+			err = fmt.Errorf("decode packet %T: %v unread bytes left: 0x%x", pk, p.payload.Len(), p.payload.Bytes())
+		}
 	}
 	if DisconnectOnInvalidPacket && err != nil {
 		return nil, err
 	}
 	return proto.ConvertToLatest(pk, nil), err
 }
+
+// fieldPath returns the path of the field of pk last read by the protocol.Reader passed, or an empty string
+// if rd is nil or not in strict mode.
+func fieldPath(rd *protocol.Reader, pk packet.Packet) string {
+	if rd == nil || pk == nil {
+		return ""
+	}
+	return rd.FieldPath(pk)
+}
@@ -0,0 +1,81 @@
+package minecraft
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// PacketLogger logs packets read from and written to a Conn to a slog.Handler. It may be set using
+// Dialer.PacketLogger or ListenConfig.PacketLogger. Each packet is logged as a record with the message
+// "packet" and the attributes "dir", "id" and "type", and the addresses of the connection as "local" and
+// "remote".
+type PacketLogger struct {
+	// Handler is the slog.Handler that packets are logged to. If nil, no packets are logged.
+	Handler slog.Handler
+	// Level is the level that packets are logged at. By default, this is slog.LevelInfo.
+	Level slog.Level
+
+	// Include holds the IDs of the packets that are logged. If empty, all packets are logged, except for the
+	// ones in Exclude.
+	Include []uint32
+	// Exclude holds the IDs of packets that are never logged, such as packet.IDLevelChunk.
+	Exclude []uint32
+	// Directions holds the Directions of the packets that are logged. If empty, packets of both Directions are
+	// logged.
+	Directions []Direction
+
+	// Verbose specifies if the full contents of every packet should be logged in the "packet" attribute.
+	Verbose bool
+	// HexDumpUnknown specifies if the payload of packets that could not be decoded because their ID is not
+	// known should be logged as a hex dump in the "payload" attribute.
+	HexDumpUnknown bool
+}
+
+// enabled checks if a packet with the ID passed travelling in the Direction passed should be logged.
+func (l *PacketLogger) enabled(id uint32, dir Direction) bool {
+	if l == nil || l.Handler == nil {
+		return false
+	}
+	if len(l.Directions) != 0 && !slices.Contains(l.Directions, dir) {
+		return false
+	}
+	if len(l.Include) != 0 && !slices.Contains(l.Include, id) {
+		return false
+	}
+	return !slices.Contains(l.Exclude, id)
+}
+
+// log logs the packet passed, which was read from or written to the Conn passed, if the PacketLogger is
+// enabled for it.
+func (l *PacketLogger) log(conn *Conn, dir Direction, pk packet.Packet) {
+	if !l.enabled(pk.ID(), dir) {
+		return
+	}
+	ctx := context.Background()
+	if !l.Handler.Enabled(ctx, l.Level) {
+		return
+	}
+	r := slog.NewRecord(time.Now(), l.Level, "packet", 0)
+	r.AddAttrs(
+		slog.String("dir", dir.String()),
+		slog.Uint64("id", uint64(pk.ID())),
+		slog.String("type", fmt.Sprintf("%T", pk)),
+		slog.Any("local", conn.LocalAddr()),
+		slog.Any("remote", conn.RemoteAddr()),
+	)
+	if l.Verbose {
+		r.AddAttrs(slog.String("packet", fmt.Sprintf("%+v", pk)))
+	}
+	if unknown, ok := pk.(*packet.Unknown); ok && l.HexDumpUnknown {
+		r.AddAttrs(slog.String("payload", hex.Dump(unknown.Payload)))
+	}
+	if err := l.Handler.Handle(ctx, r); err != nil {
+		conn.log.Printf("log packet: %v\n", err)
+	}
+}
@@ -0,0 +1,88 @@
+package minecraft
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Pong holds the status of a server as shown in the server list, obtained using Ping.
+type Pong struct {
+	// Edition is the edition of the server, which is "MCPE" for Bedrock Edition servers and "MCEE" for
+	// Education Edition servers.
+	Edition string
+	// MOTD is the first line of the MOTD of the server, usually its name.
+	MOTD string
+	// SubMOTD is the second line of the MOTD of the server. Vanilla servers use it for the name of the level.
+	SubMOTD string
+	// ProtocolVersion and Version are the protocol version and game version of the server.
+	ProtocolVersion int32
+	Version         string
+	// PlayerCount is the amount of players currently online, and MaxPlayers the maximum amount of players
+	// that may be online at the same time.
+	PlayerCount, MaxPlayers int
+	// ServerID is the unique ID of the server. It is typically the ID of the RakNet listener.
+	ServerID string
+	// GameMode is the name of the default game mode of the server, such as "Survival", and GameModeID its
+	// numeric ID.
+	GameMode   string
+	GameModeID int
+	// PortV4 and PortV6 are the IPv4 and IPv6 ports that the server listens on. They are 0 if the server did
+	// not send them.
+	PortV4, PortV6 int
+}
+
+// Ping sends a ping to the server at the address passed over RakNet and returns its status, without logging
+// in. Ping returns an error if the context passed is cancelled before the server responds, so a timeout
+// should always be attached to it.
+func Ping(ctx context.Context, address string) (Pong, error) {
+	return PingNetwork(ctx, "raknet", address)
+}
+
+// PingNetwork sends a ping to the server at the address passed over the network passed, such as "raknet",
+// and returns its status, without logging in.
+func PingNetwork(ctx context.Context, network, address string) (Pong, error) {
+	n, ok := networkByID(network)
+	if !ok {
+		return Pong{}, fmt.Errorf("ping: no network under id %v", network)
+	}
+	data, err := n.PingContext(ctx, address)
+	if err != nil {
+		return Pong{}, fmt.Errorf("ping: %w", err)
+	}
+	return ParsePong(data)
+}
+
+// ParsePong parses the raw pong data sent by a server in response to a ping. An error is returned if the
+// data does not hold at least the edition, MOTD, protocol, version and player counts of the server. The
+// fields that follow are optional.
+func ParsePong(data []byte) (Pong, error) {
+	frag := splitPong(string(data))
+	if len(frag) < 6 {
+		return Pong{}, fmt.Errorf("parse pong: expected at least 6 fields, got %v", len(frag))
+	}
+	pong := Pong{Edition: frag[0], MOTD: frag[1], Version: frag[3]}
+	protocolVersion, err := strconv.ParseInt(frag[2], 10, 32)
+	if err != nil {
+		return Pong{}, fmt.Errorf("parse pong: invalid protocol version %q", frag[2])
+	}
+	pong.ProtocolVersion = int32(protocolVersion)
+	if pong.PlayerCount, err = strconv.Atoi(frag[4]); err != nil {
+		return Pong{}, fmt.Errorf("parse pong: invalid player count %q", frag[4])
+	}
+	if pong.MaxPlayers, err = strconv.Atoi(frag[5]); err != nil {
+		return Pong{}, fmt.Errorf("parse pong: invalid max player count %q", frag[5])
+	}
+	optional := func(i int) string {
+		if len(frag) > i {
+			return frag[i]
+		}
+		return ""
+	}
+	pong.ServerID, pong.SubMOTD, pong.GameMode = optional(6), optional(7), optional(8)
+	// The numeric fields that follow are not always sent, or sent empty, so we don't treat them as errors.
+	pong.GameModeID, _ = strconv.Atoi(optional(9))
+	pong.PortV4, _ = strconv.Atoi(optional(10))
+	pong.PortV6, _ = strconv.Atoi(optional(11))
+	return pong, nil
+}
@@ -0,0 +1,85 @@
+package minecraft
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+
+	"github.com/sandertv/gophertunnel/minecraft/internal"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Release releases the pooled buffer of the packet last returned by ReadPacket of a Conn created with the
+// PooledPackets field of a Dialer or ListenConfig set to true. Byte slices held by that packet, and by other
+// packets decoded from the same data, must no longer be used after Release is called. The buffer is released
+// automatically when the next packet is read, so calling Release is only needed to return the buffer to the
+// pool early. Release must not be called concurrently with ReadPacket.
+func (conn *Conn) Release() {
+	if conn.lastRead != nil {
+		conn.lastRead.release()
+		conn.lastRead = nil
+	}
+}
+
+// ClonePacket returns a deep copy of the packet passed, which must be a packet read from the Conn. Unlike the
+// packet passed, the copy does not share any memory with pooled buffers, so it may be retained after the next
+// packet is read or Release is called, even if the Conn has PooledPackets enabled.
+func (conn *Conn) ClonePacket(pk packet.Packet) packet.Packet {
+	switch pk := pk.(type) {
+	case *packet.Unknown:
+		return &packet.Unknown{PacketID: pk.PacketID, Payload: slices.Clone(pk.Payload)}
+	case *packet.Trailing:
+		// A new Trailing has no Packet to decode into, so we clone the packet it wraps separately.
+		return &packet.Trailing{Packet: conn.ClonePacket(pk.Packet), Data: slices.Clone(pk.Data)}
+	}
+	buf := internal.BufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		// Reset the buffer, so we can return it to the buffer pool safely.
+		buf.Reset()
+		internal.BufferPool.Put(buf)
+	}()
+	shieldID := conn.shieldID.Load()
+	w := protocol.GetWriter(buf, shieldID)
+	packet.Marshal(pk, w)
+	protocol.PutWriter(w)
+
+	clone := reflect.New(reflect.TypeOf(pk).Elem()).Interface().(packet.Packet)
+	packet.Marshal(clone, protocol.NewReader(buf, shieldID, false))
+	return clone
+}
+
+// pool copies the data of the packetData into a pooled buffer, so that byte slices of the packets decoded
+// from it alias the pooled buffer instead of being allocated separately.
+func (p *packetData) pool() {
+	buf := internal.BufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	buf.Write(p.full)
+
+	headerLen := len(p.full) - p.payload.Len()
+	p.pooled, p.full = buf, buf.Bytes()
+	p.payload = bytes.NewBuffer(p.full[headerLen:])
+}
+
+// release returns the pooled buffer of the packetData to the pool, if it has one.
+func (p *packetData) release() {
+	if p.pooled == nil {
+		return
+	}
+	if p.done != nil {
+		// The packet may still be decoded by a DecodePool, which reads from the buffer.
+		<-p.done
+	}
+	p.pooled.Reset()
+	internal.BufferPool.Put(p.pooled)
+	p.pooled, p.full, p.payload = nil, nil, nil
+}
+
+// read marks the packetData passed as the one last returned by ReadPacket, releasing the pooled buffer of the
+// packetData read before it.
+func (conn *Conn) read(p *packetData) {
+	conn.Release()
+	if p.pooled != nil {
+		conn.lastRead = p
+	}
+}
@@ -0,0 +1,130 @@
+package minecraft
+
+import (
+	"bytes"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Priority is the priority with which a packet is sent over a Conn. Packets with a higher priority are sent
+// before packets with a lower priority, which may be delayed to later batches if a batch is full.
+//
+// Packets are only sent in the order they were written if they have the same Priority. The client
+// processes packets in the order they arrive, so a packet must never get a different Priority than a packet
+// that it depends on. A high priority MoveActorAbsolute packet may otherwise arrive before the AddActor
+// packet of the entity, and a low priority LevelChunk packet after an UpdateBlock packet that changes it.
+type Priority int
+
+const (
+	// PriorityNormal is the Priority of packets that have no Priority configured.
+	PriorityNormal Priority = iota
+	// PriorityHigh is the Priority of packets that are sensitive to latency. They are always sent in the
+	// next batch, ahead of any packets written before them, so only packets that no other packet depends on
+	// and that depend on no other packet may be given a high priority.
+	PriorityHigh
+	// PriorityLow is the Priority of bulk data, such as chunks and resource packs, which may be delayed in
+	// favour of other packets. Packets written after a low priority packet may be sent before it.
+	PriorityLow
+)
+
+// DefaultPacketPriorities returns a map of packet priorities that gives bulk data a low priority, so that it
+// does not delay other packets. It may be used as PacketPriorities in a Dialer or ListenConfig. Only chunks
+// and resource pack data are delayed: Packets that change blocks in chunks already sent, such as UpdateBlock,
+// may arrive before the chunk if they are sent right after it, so servers that do so should not use the
+// defaults. No packets get a high priority, because movement and inventory packets, among others, depend on
+// packets with a normal priority, such as AddActor and ItemStackRequest.
+func DefaultPacketPriorities() map[uint32]Priority {
+	return map[uint32]Priority{
+		packet.IDLevelChunk:            PriorityLow,
+		packet.IDSubChunk:              PriorityLow,
+		packet.IDResourcePackChunkData: PriorityLow,
+	}
+}
+
+const (
+	// normalWeight and lowWeight are the weights with which the space left in a batch after high priority
+	// packets is divided between normal and low priority packets.
+	normalWeight, lowWeight = 3, 1
+)
+
+// writeQueue holds packets waiting to be sent over a Conn, grouped by their Priority. It is not safe for
+// concurrent use: The send mutex of the Conn must be held.
+type writeQueue struct {
+	priorities map[uint32]Priority
+	// batchSize is the maximum amount of bytes of normal and low priority packets sent in a single batch.
+	batchSize int
+
+	high, normal, low []outgoingPacket
+}
+
+// push adds the serialised packet passed to the queue of its Priority.
+func (q *writeQueue) push(pk outgoingPacket) {
+	switch q.priorities[pk.id] {
+	case PriorityHigh:
+		q.high = append(q.high, pk)
+	case PriorityLow:
+		q.low = append(q.low, pk)
+	default:
+		q.normal = append(q.normal, pk)
+	}
+}
+
+// next appends the packets that should be sent in the next batch to the batch passed, and their buffers to
+// bufs, and removes them from the queue. All high priority packets are sent, after which the space left in
+// the batch is divided between normal and low priority packets by their weight. Space not used by one of
+// them is used by the other. Packets of the same Priority are always sent in the order they were written.
+func (q *writeQueue) next(batch [][]byte, bufs []*bytes.Buffer) ([][]byte, []*bytes.Buffer) {
+	batch, bufs = takeQueued(batch, bufs, &q.high, -1)
+	if q.batchSize <= 0 {
+		batch, bufs = takeQueued(batch, bufs, &q.normal, -1)
+		return takeQueued(batch, bufs, &q.low, -1)
+	}
+	normalBudget := q.batchSize * normalWeight / (normalWeight + lowWeight)
+	lowBudget := q.batchSize - normalBudget
+	if queueSize(q.normal) < normalBudget {
+		lowBudget += normalBudget - queueSize(q.normal)
+	} else if queueSize(q.low) < lowBudget {
+		normalBudget += lowBudget - queueSize(q.low)
+	}
+	batch, bufs = takeQueued(batch, bufs, &q.normal, normalBudget)
+	return takeQueued(batch, bufs, &q.low, lowBudget)
+}
+
+// size returns the total size in bytes of the packets in the queue.
+func (q *writeQueue) size() int {
+	return queueSize(q.high) + queueSize(q.normal) + queueSize(q.low)
+}
+
+// takeQueued moves packets from the front of the queue passed to the batch, and their buffers to bufs, until
+// budget bytes were moved. At least one packet is always moved if the queue is not empty, so that packets
+// larger than the budget are still sent. If budget is negative, all packets are moved.
+func takeQueued(batch [][]byte, bufs []*bytes.Buffer, queue *[]outgoingPacket, budget int) ([][]byte, []*bytes.Buffer) {
+	q := *queue
+	n, taken := 0, 0
+	for n < len(q) && (n == 0 || budget < 0 || taken+len(q[n].data) <= budget) {
+		batch, bufs = append(batch, q[n].data), append(bufs, q[n].buf)
+		taken += len(q[n].data)
+		n++
+	}
+	// Copy the packets left to the front of the queue, so that its backing array may be reused.
+	left := copy(q, q[n:])
+	clear(q[left:])
+	*queue = q[:left]
+	return batch, bufs
+}
+
+// queueSize returns the total size in bytes of the packets passed.
+func queueSize(pks []outgoingPacket) int {
+	n := 0
+	for _, pk := range pks {
+		n += len(pk.data)
+	}
+	return n
+}
+
+// queued checks if the Conn has packets in its write queue that did not fit in the previous batch.
+func (conn *Conn) queued() bool {
+	conn.sendMu.Lock()
+	defer conn.sendMu.Unlock()
+	return conn.queue != nil && conn.queue.size() > 0
+}
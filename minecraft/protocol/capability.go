@@ -0,0 +1,107 @@
+package protocol
+
+// Protocol versions of the Minecraft releases in which packets or fields tracked by Supports and
+// SupportsField were added.
+const (
+	Protocol1_19_0  = 527
+	Protocol1_19_10 = 534
+	Protocol1_19_20 = 544
+	Protocol1_19_30 = 554
+	Protocol1_19_40 = 557
+	Protocol1_19_50 = 560
+	Protocol1_19_70 = 575
+	Protocol1_19_80 = 582
+	Protocol1_20_10 = 594
+	Protocol1_20_30 = 618
+	Protocol1_20_50 = 630
+	Protocol1_20_60 = 649
+	Protocol1_20_70 = 662
+	Protocol1_20_80 = 671
+	Protocol1_21_0  = 685
+)
+
+// versionRange is a range of protocol versions. Added is the first version that supports a packet or field.
+// Removed is the first version that no longer supports it, or 0 if it is still supported in CurrentProtocol.
+type versionRange struct {
+	Added, Removed int32
+}
+
+// contains checks if the protocol version passed falls within the versionRange.
+func (r versionRange) contains(proto int32) bool {
+	return proto >= r.Added && (r.Removed == 0 || proto < r.Removed)
+}
+
+// packetVersions maps the IDs of packets added or removed since v1.19.0 to the range of protocol versions
+// that support them. Packets not present are supported by every protocol version.
+var packetVersions = map[uint32]versionRange{
+	184: {Added: Protocol1_19_0},  // RequestAbility
+	185: {Added: Protocol1_19_0},  // RequestPermissions
+	186: {Added: Protocol1_19_0},  // ToastRequest
+	187: {Added: Protocol1_19_10}, // UpdateAbilities
+	188: {Added: Protocol1_19_10}, // UpdateAdventureSettings
+	189: {Added: Protocol1_19_10}, // DeathInfo
+	190: {Added: Protocol1_19_10}, // EditorNetwork
+	191: {Added: Protocol1_19_20}, // FeatureRegistry
+	192: {Added: Protocol1_19_20}, // ServerStats
+	193: {Added: Protocol1_19_30}, // RequestNetworkSettings
+	194: {Added: Protocol1_19_30}, // GameTestRequest
+	195: {Added: Protocol1_19_30}, // GameTestResults
+	196: {Added: Protocol1_19_40}, // UpdateClientInputLocks
+	197: {Added: Protocol1_19_50}, // ClientCheatAbility
+	198: {Added: Protocol1_19_70}, // CameraPresets
+	199: {Added: Protocol1_19_70}, // UnlockedRecipes
+	300: {Added: Protocol1_19_70}, // CameraInstruction
+	301: {Added: Protocol1_19_80}, // CompressedBiomeDefinitionList
+	302: {Added: Protocol1_19_80}, // TrimData
+	303: {Added: Protocol1_19_80}, // OpenSign
+	304: {Added: Protocol1_20_10}, // AgentAnimation
+	305: {Added: Protocol1_20_30}, // RefreshEntitlements
+	306: {Added: Protocol1_20_50}, // PlayerToggleCrafterSlotRequest
+	307: {Added: Protocol1_20_60}, // SetPlayerInventoryOptions
+	308: {Added: Protocol1_20_60}, // SetHud
+	309: {Added: Protocol1_20_70}, // AwardAchievement
+}
+
+// Field identifies a field of a packet that is not encoded in every protocol version. Fields are named after
+// the packet and the field in the packet package, such as 'LevelChunk.Dimension'.
+type Field string
+
+const (
+	FieldLevelChunkDimension                   Field = "LevelChunk.Dimension"
+	FieldPlayerAuthInputClientPredictedVehicle Field = "PlayerAuthInput.ClientPredictedVehicle"
+	FieldStartGameEmoteChatMuted               Field = "StartGame.EmoteChatMuted"
+	FieldContainerCloseContainerType           Field = "ContainerClose.ContainerType"
+	FieldCodeBuilderSourceCodeStatus           Field = "CodeBuilderSource.CodeStatus"
+)
+
+// fieldVersions maps the Fields tracked to the range of protocol versions that encode them.
+var fieldVersions = map[Field]versionRange{
+	FieldLevelChunkDimension:                   {Added: Protocol1_20_60},
+	FieldPlayerAuthInputClientPredictedVehicle: {Added: Protocol1_20_70},
+	FieldStartGameEmoteChatMuted:               {Added: Protocol1_20_80},
+	FieldContainerCloseContainerType:           {Added: Protocol1_21_0},
+	FieldCodeBuilderSourceCodeStatus:           {Added: Protocol1_21_0},
+}
+
+// Supports checks if the protocol version passed supports the packet with the ID passed. Packets that are not
+// tracked, either because they predate v1.19.0 or because they are unknown, are assumed to be supported by
+// every version. Supports allows translations for older protocol versions to decide which packets to drop
+// without checking protocol versions in many places.
+func Supports(proto int32, packetID uint32) bool {
+	r, ok := packetVersions[packetID]
+	return !ok || r.contains(proto)
+}
+
+// SupportsField checks if the protocol version passed encodes the Field passed. Fields that are not tracked
+// are assumed to be encoded by every version.
+func SupportsField(proto int32, field Field) bool {
+	r, ok := fieldVersions[field]
+	return !ok || r.contains(proto)
+}
+
+// PacketAdded returns the protocol version in which the packet with the ID passed was added. False is
+// returned if the packet is not tracked, in which case it predates v1.19.0.
+func PacketAdded(packetID uint32) (int32, bool) {
+	r, ok := packetVersions[packetID]
+	return r.Added, ok
+}
@@ -0,0 +1,247 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Builder builds an AvailableCommands packet from the commands added to it. The zero value of a Builder is
+// ready to use.
+type Builder struct {
+	commands []Command
+}
+
+// Add adds the commands passed to the Builder. Commands are sent in the order they are added.
+func (b *Builder) Add(commands ...Command) {
+	b.commands = append(b.commands, commands...)
+}
+
+// AvailableCommands builds an AvailableCommands packet holding all commands added to the Builder. Enum values,
+// enums, suffixes and chained subcommands shared by commands are written to the packet only once. An error is
+// returned if a command is invalid, for example if two enums with the same Type hold different values.
+func (b *Builder) AvailableCommands() (*packet.AvailableCommands, error) {
+	t := &tables{
+		pk:            &packet.AvailableCommands{},
+		values:        make(map[string]uint),
+		enums:         make(map[string]enumEntry),
+		suffixes:      make(map[string]uint32),
+		chainedValues: make(map[string]uint16),
+		chained:       make(map[string]chainedEntry),
+	}
+	for _, c := range b.commands {
+		cmd, err := t.command(c)
+		if err != nil {
+			return nil, fmt.Errorf("command %v: %w", c.Name, err)
+		}
+		t.pk.Commands = append(t.pk.Commands, cmd)
+	}
+	return t.pk, nil
+}
+
+// Update returns an UpdateSoftEnum packet that changes the values of a Soft Enum previously sent to the client
+// using the action passed, such as packet.SoftEnumActionSet. The values passed are added, removed or set
+// depending on the action.
+func (e *Enum) Update(action byte, values ...string) *packet.UpdateSoftEnum {
+	return &packet.UpdateSoftEnum{EnumType: e.Type, Options: values, ActionType: action}
+}
+
+// tables holds the tables of an AvailableCommands packet being built, along with the indices of the entries
+// already present in them, so that each entry is written only once.
+type tables struct {
+	pk *packet.AvailableCommands
+
+	values        map[string]uint
+	enums         map[string]enumEntry
+	suffixes      map[string]uint32
+	chainedValues map[string]uint16
+	chained       map[string]chainedEntry
+}
+
+// enumEntry is an Enum written to the tables, along with its index in either the Enums or DynamicEnums.
+type enumEntry struct {
+	e     Enum
+	index uint32
+}
+
+// chainedEntry is a ChainedSubcommand written to the tables, along with its index in the ChainedSubcommands.
+type chainedEntry struct {
+	c     ChainedSubcommand
+	index uint16
+}
+
+// command converts a Command to a protocol.Command, adding the enums and chained subcommands it uses to the
+// tables.
+func (t *tables) command(c Command) (protocol.Command, error) {
+	cmd := protocol.Command{
+		Name:            c.Name,
+		Description:     c.Description,
+		Flags:           c.Flags,
+		PermissionLevel: c.PermissionLevel,
+		AliasesOffset:   math.MaxUint32,
+	}
+	if len(c.Aliases) > 0 {
+		index, err := t.enum(Enum{Type: c.Name + "Aliases", Values: append([]string{c.Name}, c.Aliases...)})
+		if err != nil {
+			return cmd, fmt.Errorf("aliases: %w", err)
+		}
+		cmd.AliasesOffset = index
+	}
+	for _, sub := range c.ChainedSubcommands {
+		index, err := t.chainedSubcommand(sub)
+		if err != nil {
+			return cmd, fmt.Errorf("chained subcommand %v: %w", sub.Name, err)
+		}
+		cmd.ChainedSubcommandOffsets = append(cmd.ChainedSubcommandOffsets, index)
+	}
+	for i, o := range c.Overloads {
+		if o.Chaining && len(c.ChainedSubcommands) == 0 {
+			return cmd, fmt.Errorf("overload %v: chaining overload in command without chained subcommands", i)
+		}
+		overload := protocol.CommandOverload{Chaining: o.Chaining, Parameters: make([]protocol.CommandParameter, 0, len(o.Parameters))}
+		optional := false
+		for _, p := range o.Parameters {
+			if optional && !p.Optional {
+				return cmd, fmt.Errorf("overload %v: parameter %v: mandatory parameter after optional parameter", i, p.Name)
+			}
+			optional = p.Optional
+
+			param, err := t.parameter(p)
+			if err != nil {
+				return cmd, fmt.Errorf("overload %v: parameter %v: %w", i, p.Name, err)
+			}
+			overload.Parameters = append(overload.Parameters, param)
+		}
+		cmd.Overloads = append(cmd.Overloads, overload)
+	}
+	return cmd, nil
+}
+
+// parameter converts a Parameter to a protocol.CommandParameter, composing its Type from the basic type, enum
+// or suffix of the Parameter.
+func (t *tables) parameter(p Parameter) (protocol.CommandParameter, error) {
+	param := protocol.CommandParameter{Name: p.Name, Optional: p.Optional, Options: p.Options}
+	set := 0
+	for _, ok := range []bool{p.Type != 0, p.Enum != nil, p.Suffix != ""} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return param, fmt.Errorf("exactly one of Type, Enum and Suffix must be set, got %v", set)
+	}
+	switch {
+	case p.Enum != nil:
+		index, err := t.enum(*p.Enum)
+		if err != nil {
+			return param, err
+		}
+		if p.Enum.Soft {
+			param.Type = protocol.CommandArgValid | protocol.CommandArgSoftEnum | index
+		} else {
+			param.Type = protocol.CommandArgValid | protocol.CommandArgEnum | index
+		}
+	case p.Suffix != "":
+		param.Type = protocol.CommandArgSuffixed | t.suffix(p.Suffix)
+	default:
+		if p.Type > math.MaxUint16 {
+			return param, fmt.Errorf("type %#x holds flags", p.Type)
+		}
+		param.Type = protocol.CommandArgValid | p.Type
+	}
+	return param, nil
+}
+
+// enum returns the index of the Enum passed in either the Enums or the DynamicEnums of the packet, adding it
+// if it was not yet present.
+func (t *tables) enum(e Enum) (uint32, error) {
+	if e.Type == "" {
+		return 0, errors.New("enum has no type")
+	}
+	if len(e.Values) == 0 && !e.Soft {
+		return 0, fmt.Errorf("enum %v has no values", e.Type)
+	}
+	if entry, ok := t.enums[e.Type]; ok {
+		if entry.e.Soft != e.Soft || !slices.Equal(entry.e.Values, e.Values) {
+			return 0, fmt.Errorf("enum %v defined multiple times with different values", e.Type)
+		}
+		return entry.index, nil
+	}
+
+	var index uint32
+	if e.Soft {
+		index = uint32(len(t.pk.DynamicEnums))
+		t.pk.DynamicEnums = append(t.pk.DynamicEnums, protocol.DynamicEnum{Type: e.Type, Values: slices.Clone(e.Values)})
+	} else {
+		index = uint32(len(t.pk.Enums))
+		enum := protocol.CommandEnum{Type: e.Type, ValueIndices: make([]uint, len(e.Values))}
+		for i, v := range e.Values {
+			enum.ValueIndices[i] = t.value(v)
+		}
+		t.pk.Enums = append(t.pk.Enums, enum)
+	}
+	if index > math.MaxUint16 {
+		return 0, errors.New("too many enums")
+	}
+	t.enums[e.Type] = enumEntry{e: Enum{Type: e.Type, Values: slices.Clone(e.Values), Soft: e.Soft}, index: index}
+	return index, nil
+}
+
+// value returns the index of the enum value passed in the EnumValues of the packet, adding it if it was not
+// yet present.
+func (t *tables) value(v string) uint {
+	if index, ok := t.values[v]; ok {
+		return index
+	}
+	index := uint(len(t.pk.EnumValues))
+	t.pk.EnumValues = append(t.pk.EnumValues, v)
+	t.values[v] = index
+	return index
+}
+
+// suffix returns the index of the suffix passed in the Suffixes of the packet, adding it if it was not yet
+// present.
+func (t *tables) suffix(s string) uint32 {
+	if index, ok := t.suffixes[s]; ok {
+		return index
+	}
+	index := uint32(len(t.pk.Suffixes))
+	t.pk.Suffixes = append(t.pk.Suffixes, s)
+	t.suffixes[s] = index
+	return index
+}
+
+// chainedSubcommand returns the index of the ChainedSubcommand passed in the ChainedSubcommands of the
+// packet, adding it and its values if it was not yet present.
+func (t *tables) chainedSubcommand(c ChainedSubcommand) (uint16, error) {
+	if entry, ok := t.chained[c.Name]; ok {
+		if !slices.Equal(entry.c.Values, c.Values) {
+			return 0, errors.New("defined multiple times with different values")
+		}
+		return entry.index, nil
+	}
+	if len(t.pk.ChainedSubcommands) >= math.MaxUint16 {
+		return 0, errors.New("too many chained subcommands")
+	}
+	sub := protocol.ChainedSubcommand{Name: c.Name, Values: make([]protocol.ChainedSubcommandValue, len(c.Values))}
+	for i, v := range c.Values {
+		index, ok := t.chainedValues[v.Name]
+		if !ok {
+			if len(t.pk.ChainedSubcommandValues) >= math.MaxUint16 {
+				return 0, errors.New("too many chained subcommand values")
+			}
+			index = uint16(len(t.pk.ChainedSubcommandValues))
+			t.pk.ChainedSubcommandValues = append(t.pk.ChainedSubcommandValues, v.Name)
+			t.chainedValues[v.Name] = index
+		}
+		sub.Values[i] = protocol.ChainedSubcommandValue{Index: index, Value: v.Type}
+	}
+	index := uint16(len(t.pk.ChainedSubcommands))
+	t.pk.ChainedSubcommands = append(t.pk.ChainedSubcommands, sub)
+	t.chained[c.Name] = chainedEntry{c: ChainedSubcommand{Name: c.Name, Values: slices.Clone(c.Values)}, index: index}
+	return index, nil
+}
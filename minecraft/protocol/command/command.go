@@ -0,0 +1,111 @@
+package command
+
+// Command is a command that is shown to a client, in the /help list and when auto-completing commands.
+type Command struct {
+	// Name is the name of the command, used to execute it. The client appears to crash if the name contains
+	// uppercase letters.
+	Name string
+	// Description is the description of the command, shown in the /help list and when starting to write the
+	// command.
+	Description string
+	// Aliases holds alternative names that may be used to execute the command.
+	Aliases []string
+	// Flags is a combination of flags not currently known. Leaving it empty appears to work.
+	Flags uint16
+	// PermissionLevel is the permission level required to execute the command. The client does not appear to
+	// use it, so permissions should be checked server-side.
+	PermissionLevel byte
+	// ChainedSubcommands holds the subcommands of the command that may be followed by another command, such as
+	// the subcommands of /execute. Overloads with Chaining set to true use these subcommands.
+	ChainedSubcommands []ChainedSubcommand
+	// Overloads holds the ways in which the command may be executed. Each overload has its own parameters.
+	Overloads []Overload
+}
+
+// Overload is a single usage of a Command, holding the parameters accepted in that usage.
+type Overload struct {
+	// Chaining specifies if the overload is followed by one of the ChainedSubcommands of the Command.
+	Chaining bool
+	// Parameters holds the parameters of the overload in the order they must be entered. A parameter that is
+	// not optional may not follow an optional parameter.
+	Parameters []Parameter
+}
+
+// Parameter is a parameter of an Overload. Exactly one of the Type, Enum and Suffix fields must be set, and
+// determines the values the parameter accepts.
+type Parameter struct {
+	// Name is the name of the parameter, shown in the usage of the command as <Name: Type>.
+	Name string
+	// Type is the basic type of the parameter, such as protocol.CommandArgTypeInt or
+	// protocol.CommandArgTypeTarget. It must not hold any of the protocol.CommandArg flags: These are added by
+	// the Builder.
+	Type uint32
+	// Enum is the enum holding the values accepted by the parameter. If the Enum is Soft, its values may be
+	// changed using an UpdateSoftEnum packet after the commands are sent.
+	Enum *Enum
+	// Suffix is the suffix of an integer parameter, such as the 'L' in '/xp 5L'.
+	Suffix string
+	// Optional specifies if the parameter may be left out. Optional parameters are shown in the usage of the
+	// command as [Name: Type].
+	Optional bool
+	// Options is a combination of the protocol.ParamOption options applied to the parameter.
+	Options byte
+}
+
+// Enum is a set of values accepted by a Parameter. Enums are identified by their Type: Enums with the same
+// Type used by multiple parameters or commands are sent only once, and must therefore hold the same values.
+type Enum struct {
+	// Type is the name of the enum, shown in the usage of a command as the type of the parameter.
+	Type string
+	// Values holds the values accepted by the enum.
+	Values []string
+	// Soft specifies if the enum is a soft enum, also known as a dynamic enum, whose values may be changed
+	// after the commands are sent using the UpdateSoftEnum packet returned by Enum.Update.
+	Soft bool
+}
+
+// ChainedSubcommand is a subcommand of a Command that may be followed by another command, such as the
+// subcommands of /execute. ChainedSubcommands are identified by their Name: ChainedSubcommands with the same
+// Name used by multiple commands are sent only once, and must therefore hold the same values.
+type ChainedSubcommand struct {
+	// Name is the name of the chained subcommand.
+	Name string
+	// Values holds the values of the chained subcommand, such as 'as' and 'at' for /execute.
+	Values []ChainedSubcommandValue
+}
+
+// ChainedSubcommandValue is a value of a ChainedSubcommand.
+type ChainedSubcommandValue struct {
+	// Name is the name of the value, which is entered to use it.
+	Name string
+	// Type is the basic type of the value, such as protocol.CommandArgTypeTarget. Like the Type of a
+	// Parameter, it must not hold any of the protocol.CommandArg flags.
+	Type uint16
+}
+
+// Arg returns a mandatory Parameter with the name and basic type passed, such as protocol.CommandArgTypeInt.
+func Arg(name string, t uint32) Parameter {
+	return Parameter{Name: name, Type: t}
+}
+
+// EnumArg returns a mandatory Parameter with the name passed that accepts the values of the Enum passed.
+func EnumArg(name string, e *Enum) Parameter {
+	return Parameter{Name: name, Enum: e}
+}
+
+// SuffixedArg returns a mandatory integer Parameter with the name and suffix passed.
+func SuffixedArg(name, suffix string) Parameter {
+	return Parameter{Name: name, Suffix: suffix}
+}
+
+// Subcommand returns a mandatory Parameter that accepts only the name passed. It is an enum with a single
+// value, which is how subcommands such as the 'add' in '/tag @s add' are represented.
+func Subcommand(name string) Parameter {
+	return Parameter{Name: name, Enum: &Enum{Type: name, Values: []string{name}}}
+}
+
+// Opt returns a copy of the Parameter that is optional.
+func (p Parameter) Opt() Parameter {
+	p.Optional = true
+	return p
+}
@@ -0,0 +1,5 @@
+// Package command implements a builder for the commands sent to a client in the AvailableCommands packet.
+// Commands are defined using the Command, Overload and Parameter types, after which a Builder turns them
+// into an AvailableCommands packet, deduplicating the enum values, enums, suffixes and chained subcommands
+// shared by commands and composing the Type of each parameter from the flags and indices the client expects.
+package command
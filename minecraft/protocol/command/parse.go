@@ -0,0 +1,496 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+var (
+	// ErrMissingArgument is the error of an ArgumentError returned when no argument was passed for a
+	// mandatory parameter.
+	ErrMissingArgument = errors.New("missing argument")
+	// ErrTooManyArguments is the error of an ArgumentError returned when more arguments were passed than the
+	// overload has parameters.
+	ErrTooManyArguments = errors.New("too many arguments")
+	// ErrNotInEnum is the error of an ArgumentError returned when the argument passed for an enum parameter is
+	// not one of the values of the enum.
+	ErrNotInEnum = errors.New("value not in enum")
+)
+
+// ArgumentError is returned by Parse if an argument of a command line could not be parsed as the parameter it
+// was passed for. If multiple overloads were tried, it is the error of the overload that parsed the most
+// arguments successfully.
+type ArgumentError struct {
+	// Overload is the index of the overload in the Command that the argument was parsed for.
+	Overload int
+	// Parameter is the parameter that the argument was parsed for. For ErrTooManyArguments, it is the zero
+	// Parameter.
+	Parameter Parameter
+	// Value is the argument that could not be parsed. It is empty for ErrMissingArgument.
+	Value string
+	// Err is the reason the argument could not be parsed, such as ErrNotInEnum or a *strconv.NumError.
+	Err error
+}
+
+// Error ...
+func (err *ArgumentError) Error() string {
+	if err.Parameter.Name == "" {
+		return fmt.Sprintf("parse argument %q: %v", err.Value, err.Err)
+	}
+	return fmt.Sprintf("parse argument %q for parameter %v: %v", err.Value, err.Parameter.Name, err.Err)
+}
+
+// Unwrap returns the Err of the ArgumentError.
+func (err *ArgumentError) Unwrap() error {
+	return err.Err
+}
+
+// Position is the value of a position parameter, such as one of type protocol.CommandArgTypePosition. Each
+// coordinate may be relative to the position of the source of the command, written as '~5', or local to its
+// rotation, written as '^5'.
+type Position struct {
+	// Coords holds the X, Y and Z coordinates of the position, or the offsets if the coordinate is relative
+	// or local.
+	Coords [3]float64
+	// Relative specifies for each coordinate if it is relative to the position of the source of the command.
+	Relative [3]bool
+	// Local specifies if all coordinates are local: Left, up and forward relative to the rotation of the
+	// source of the command.
+	Local bool
+}
+
+// Target is the value of a target parameter, such as one of type protocol.CommandArgTypeTarget. It is either
+// the name of a player, such as 'Steve', or a target selector, such as '@a' or '@e[type=cow,r=5]'.
+type Target struct {
+	// Name is the name of the player targeted. It is empty if the target is a selector.
+	Name string
+	// Selector is the variable of the target selector without the '@', such as 'a', 'e' or 'initiator'. It
+	// is empty if the target is the name of a player.
+	Selector string
+	// Arguments holds the arguments of the target selector in the order they were written. Arguments may be
+	// passed more than once, such as 'tag=a,tag=b'.
+	Arguments []SelectorArgument
+	// Wildcard specifies if the target was '*', which is only accepted for parameters of type
+	// protocol.CommandArgTypeWildcardTarget.
+	Wildcard bool
+}
+
+// SelectorArgument is a single argument of a target selector, such as 'r=5' in '@e[r=5]'.
+type SelectorArgument struct {
+	// Key is the name of the argument, such as 'r' or 'type'.
+	Key string
+	// Value is the value of the argument with quotes removed, such as '5' or '!cow'. Values such as those of
+	// the 'scores' argument are kept as written, including their braces.
+	Value string
+}
+
+// Parse parses a command line, such as the CommandLine of a CommandRequest packet, executing the Command
+// passed. The arguments are parsed according to the first Overload of the Command that accepts them, and stored
+// in the struct that v points to. The fields of the struct are matched with parameters using the 'cmd' struct
+// tag holding the name of the parameter, such as `cmd:"amount"`. Fields may be strings, booleans, integers,
+// floats, Positions or Targets, or pointers to them, which are set only if an argument is passed for the parameter.
+// Parameters without a field, such as subcommands, are parsed but not stored.
+// Parse returns the index of the Overload used. If no overload accepts the arguments, an *ArgumentError is
+// returned.
+func Parse(c Command, line string, v any) (int, error) {
+	dst := reflect.ValueOf(v)
+	if dst.Kind() != reflect.Pointer || dst.Elem().Kind() != reflect.Struct {
+		return 0, fmt.Errorf("parse command %v: expected pointer to struct, got %T", c.Name, v)
+	}
+	line = strings.TrimPrefix(strings.TrimSpace(line), "/")
+	args := split(line)
+	if len(args) == 0 || !c.named(args[0].value) {
+		return 0, fmt.Errorf("parse command %v: command line %q does not execute command", c.Name, line)
+	}
+	args = args[1:]
+
+	var best *ArgumentError
+	bestN := -1
+	for i, o := range c.Overloads {
+		values, n, err := parseOverload(o, line, args)
+		if err != nil {
+			if err.Overload = i; n > bestN {
+				best, bestN = err, n
+			}
+			continue
+		}
+		if err := store(dst.Elem(), values); err != nil {
+			return i, fmt.Errorf("parse command %v: %w", c.Name, err)
+		}
+		return i, nil
+	}
+	if best == nil {
+		return 0, fmt.Errorf("parse command %v: command has no overloads", c.Name)
+	}
+	return best.Overload, best
+}
+
+// named checks if the name passed is the name or one of the aliases of the Command.
+func (c Command) named(name string) bool {
+	if strings.EqualFold(name, c.Name) {
+		return true
+	}
+	for _, alias := range c.Aliases {
+		if strings.EqualFold(name, alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOverload parses the arguments passed according to the parameters of an Overload. It returns the values
+// parsed by parameter name and the number of parameters parsed successfully.
+func parseOverload(o Overload, line string, args []argument) (map[string]any, int, *ArgumentError) {
+	values := make(map[string]any, len(o.Parameters))
+	for n, p := range o.Parameters {
+		if len(args) == 0 {
+			if p.Optional {
+				return values, n, nil
+			}
+			return nil, n, &ArgumentError{Parameter: p, Err: ErrMissingArgument}
+		}
+		val, consumed, err := parseArgument(p, line, args)
+		if err != nil {
+			value := args[0].value
+			if consumed > 1 {
+				value = strings.Join(argValues(args[:min(consumed, len(args))]), " ")
+			}
+			return nil, n, &ArgumentError{Parameter: p, Value: value, Err: err}
+		}
+		values[p.Name], args = val, args[consumed:]
+	}
+	if len(args) > 0 {
+		return nil, len(o.Parameters), &ArgumentError{Value: args[0].value, Err: ErrTooManyArguments}
+	}
+	return values, len(o.Parameters), nil
+}
+
+// parseArgument parses the first of the arguments passed as the Parameter passed. It returns the value parsed
+// and the number of arguments consumed.
+func parseArgument(p Parameter, line string, args []argument) (any, int, error) {
+	arg := args[0].value
+	switch {
+	case p.Enum != nil:
+		if p.Enum.Soft {
+			// The values of soft enums may change after the commands were sent, so any value is accepted.
+			return arg, 1, nil
+		}
+		for _, v := range p.Enum.Values {
+			if strings.EqualFold(v, arg) {
+				return v, 1, nil
+			}
+		}
+		return nil, 1, ErrNotInEnum
+	case p.Suffix != "":
+		if len(arg) < len(p.Suffix) || !strings.EqualFold(arg[len(arg)-len(p.Suffix):], p.Suffix) {
+			return nil, 1, fmt.Errorf("missing suffix %v", p.Suffix)
+		}
+		v, err := strconv.ParseInt(arg[:len(arg)-len(p.Suffix)], 10, 64)
+		return v, 1, err
+	}
+	switch p.Type {
+	case protocol.CommandArgTypeInt:
+		v, err := strconv.ParseInt(arg, 10, 64)
+		return v, 1, err
+	case protocol.CommandArgTypeFloat, protocol.CommandArgTypeValue:
+		v, err := strconv.ParseFloat(arg, 64)
+		return v, 1, err
+	case protocol.CommandArgTypeWildcardInt:
+		if arg == "*" {
+			return arg, 1, nil
+		}
+		v, err := strconv.ParseInt(arg, 10, 64)
+		return v, 1, err
+	case protocol.CommandArgTypeTarget:
+		v, err := parseTarget(arg)
+		return v, 1, err
+	case protocol.CommandArgTypeWildcardTarget:
+		if arg == "*" {
+			return Target{Wildcard: true}, 1, nil
+		}
+		v, err := parseTarget(arg)
+		return v, 1, err
+	case protocol.CommandArgTypePosition, protocol.CommandArgTypeBlockPosition:
+		coords, consumed, err := splitPosition(args)
+		if err != nil {
+			return nil, consumed, err
+		}
+		v, err := parsePosition(coords)
+		return v, consumed, err
+	case protocol.CommandArgTypeMessage, protocol.CommandArgTypeRawText, protocol.CommandArgTypeJSON, protocol.CommandArgTypeCommand:
+		// These parameters consume the rest of the command line, including its whitespace.
+		return strings.TrimSpace(line[args[0].start:]), len(args), nil
+	}
+	return arg, 1, nil
+}
+
+// splitPosition splits the first arguments passed into the three coordinates of a position. Like the client,
+// it accepts coordinates written without whitespace between them, such as '~~~', '~1~2~3' or '^^^'. It
+// returns the coordinates and the number of arguments consumed.
+func splitPosition(args []argument) ([]string, int, error) {
+	coords := make([]string, 0, 3)
+	for n, arg := range args {
+		if len(coords) == 3 {
+			return coords, n, nil
+		}
+		v := arg.value
+		for i := 1; i < len(v); i++ {
+			if v[i] == '~' || v[i] == '^' {
+				coords, v, i = append(coords, v[:i]), v[i:], 0
+			}
+		}
+		if coords = append(coords, v); len(coords) > 3 {
+			return nil, n + 1, errors.New("too many coordinates")
+		}
+	}
+	if len(coords) < 3 {
+		return nil, len(args), ErrMissingArgument
+	}
+	return coords, len(args), nil
+}
+
+// parsePosition parses the three coordinates passed as a Position.
+func parsePosition(coords []string) (Position, error) {
+	var pos Position
+	for i, v := range coords {
+		local := strings.HasPrefix(v, "^")
+		if i > 0 && local != pos.Local {
+			return pos, errors.New("local and world coordinates mixed")
+		}
+		switch {
+		case local:
+			pos.Local, v = true, v[1:]
+		case strings.HasPrefix(v, "~"):
+			pos.Relative[i], v = true, v[1:]
+		}
+		if v == "" && (pos.Local || pos.Relative[i]) {
+			// A '~' or '^' without a number is an offset of 0.
+			continue
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return pos, err
+		}
+		pos.Coords[i] = f
+	}
+	return pos, nil
+}
+
+// parseTarget parses the argument passed as a Target.
+func parseTarget(arg string) (Target, error) {
+	if !strings.HasPrefix(arg, "@") {
+		if arg == "" {
+			return Target{}, errors.New("empty target")
+		}
+		return Target{Name: arg}, nil
+	}
+	name, args, hasArgs := strings.Cut(arg[1:], "[")
+	if name == "" {
+		return Target{}, errors.New("missing selector variable")
+	}
+	t := Target{Selector: name}
+	if !hasArgs {
+		return t, nil
+	}
+	args, ok := strings.CutSuffix(args, "]")
+	if !ok {
+		return t, errors.New("unterminated selector arguments")
+	}
+	var (
+		start, depth int
+		quoted       bool
+	)
+	for i := 0; i <= len(args); i++ {
+		if i < len(args) {
+			switch c := args[i]; {
+			case c == '"':
+				quoted = !quoted
+				continue
+			case !quoted && (c == '[' || c == '{'):
+				depth++
+				continue
+			case !quoted && (c == ']' || c == '}') && depth > 0:
+				depth--
+				continue
+			case quoted || depth > 0 || c != ',':
+				continue
+			}
+		}
+		if a := strings.TrimSpace(args[start:i]); a != "" {
+			key, value, ok := strings.Cut(a, "=")
+			if !ok {
+				return t, fmt.Errorf("selector argument %q has no value", a)
+			}
+			value = strings.TrimSpace(value)
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				value = unquoted
+			} else if strings.HasPrefix(value, "!") {
+				if unquoted, err := strconv.Unquote(value[1:]); err == nil {
+					value = "!" + unquoted
+				}
+			}
+			t.Arguments = append(t.Arguments, SelectorArgument{Key: strings.TrimSpace(key), Value: value})
+		}
+		start = i + 1
+	}
+	if quoted || depth != 0 {
+		return t, errors.New("unterminated selector arguments")
+	}
+	return t, nil
+}
+
+// store stores the values passed in the fields of the struct passed with a matching 'cmd' struct tag.
+func store(dst reflect.Value, values map[string]any) error {
+	t := dst.Type()
+	for i := range t.NumField() {
+		name, ok := t.Field(i).Tag.Lookup("cmd")
+		if !ok || name == "-" {
+			continue
+		}
+		val, ok := values[name]
+		if !ok {
+			continue
+		}
+		field := dst.Field(i)
+		if field.Kind() == reflect.Pointer {
+			field.Set(reflect.New(field.Type().Elem()))
+			field = field.Elem()
+		}
+		if err := set(field, val); err != nil {
+			return fmt.Errorf("store parameter %v in field %v: %w", name, t.Field(i).Name, err)
+		}
+	}
+	return nil
+}
+
+// set sets the field passed to the value parsed, converting it to the type of the field.
+func set(field reflect.Value, val any) error {
+	switch v := val.(type) {
+	case Position:
+		if field.Type() != reflect.TypeOf(Position{}) {
+			break
+		}
+		field.Set(reflect.ValueOf(v))
+		return nil
+	case Target:
+		switch {
+		case field.Type() == reflect.TypeOf(Target{}):
+			field.Set(reflect.ValueOf(v))
+			return nil
+		case field.Kind() == reflect.String && v.Selector == "" && !v.Wildcard:
+			// Fields of type string may still hold the name of a player.
+			field.SetString(v.Name)
+			return nil
+		}
+	case string:
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(v)
+			return nil
+		case reflect.Bool:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return err
+			}
+			field.SetBool(b)
+			return nil
+		}
+	case int64:
+		switch {
+		case field.CanInt():
+			if field.OverflowInt(v) {
+				return fmt.Errorf("value %v overflows %v", v, field.Type())
+			}
+			field.SetInt(v)
+			return nil
+		case field.CanUint():
+			if v < 0 || field.OverflowUint(uint64(v)) {
+				return fmt.Errorf("value %v overflows %v", v, field.Type())
+			}
+			field.SetUint(uint64(v))
+			return nil
+		case field.CanFloat():
+			field.SetFloat(float64(v))
+			return nil
+		}
+	case float64:
+		if field.CanFloat() {
+			field.SetFloat(v)
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot store %T in field of type %v", val, field.Type())
+}
+
+// argument is a single argument of a command line.
+type argument struct {
+	// value is the argument, with quotes removed.
+	value string
+	// start is the offset of the argument in the command line.
+	start int
+}
+
+// split splits a command line into arguments. Arguments are separated by whitespace, unless the whitespace is
+// enclosed in quotes or in brackets, such as in the target selector '@a[name="a b"]'.
+func split(line string) []argument {
+	var (
+		args   []argument
+		b      strings.Builder
+		start  = -1
+		depth  int
+		quoted bool
+	)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(line):
+			i++
+			b.WriteByte(line[i])
+			continue
+		case c == '"':
+			if start == -1 {
+				start = i
+			}
+			quoted = !quoted
+			if depth > 0 {
+				// Quotes inside of selectors are kept, so that the selector may be parsed later.
+				b.WriteByte(c)
+			}
+			continue
+		case !quoted && (c == '[' || c == '{'):
+			depth++
+		case !quoted && (c == ']' || c == '}') && depth > 0:
+			depth--
+		case !quoted && depth == 0 && unicode.IsSpace(rune(c)):
+			if start != -1 {
+				args = append(args, argument{value: b.String(), start: start})
+				b.Reset()
+				start = -1
+			}
+			continue
+		}
+		if start == -1 {
+			start = i
+		}
+		b.WriteByte(c)
+	}
+	if start != -1 {
+		args = append(args, argument{value: b.String(), start: start})
+	}
+	return args
+}
+
+// argValues returns the values of the arguments passed.
+func argValues(args []argument) []string {
+	values := make([]string, len(args))
+	for i, arg := range args {
+		values[i] = arg.value
+	}
+	return values
+}
@@ -15,7 +15,8 @@ func (x *EducationSharedResourceURI) Marshal(r IO) {
 	r.String(&x.LinkURI)
 }
 
-// EducationExternalLinkSettings ...
+// EducationExternalLinkSettings holds an external link shown to education edition clients, as sent in the
+// EducationSettings packet.
 type EducationExternalLinkSettings struct {
 	// URL is the external link URL.
 	URL string
@@ -301,3 +301,139 @@ func (m EntityMetadata) Flag(key uint32, index uint8) bool {
 		return v.(int64)&(1<<int64(index)) != 0
 	}
 }
+
+// EntityFlag checks if the entity flag passed, one of the EntityDataFlag constants, is set. Flags 0-63 are
+// held in the EntityDataKeyFlags field and flags from 64 on in the EntityDataKeyFlagsTwo field. False is
+// returned if the field holding the flag is not present.
+func (m EntityMetadata) EntityFlag(flag uint32) bool {
+	key, index := entityFlagKey(flag)
+	v, _ := metadataValue[int64](m, key)
+	return v&(1<<index) != 0
+}
+
+// SetEntityFlag sets the entity flag passed, one of the EntityDataFlag constants, to the value passed. Unlike
+// SetFlag, it does not toggle the flag, and it adds the field holding the flag if it is not yet present.
+func (m EntityMetadata) SetEntityFlag(flag uint32, value bool) {
+	key, index := entityFlagKey(flag)
+	v, _ := metadataValue[int64](m, key)
+	if value {
+		m[key] = v | 1<<index
+	} else {
+		m[key] = v &^ (1 << index)
+	}
+}
+
+// entityFlagKey returns the key of the field holding the entity flag passed and the index of its bit.
+func entityFlagKey(flag uint32) (key uint32, index uint32) {
+	if flag >= 64 {
+		return EntityDataKeyFlagsTwo, flag - 64
+	}
+	return EntityDataKeyFlags, flag
+}
+
+// Flags returns the EntityDataKeyFlags field of the entity metadata, which holds the entity flags 0-63 as
+// bits. FlagsTwo returns the EntityDataKeyFlagsTwo field, which holds the flags from 64 on.
+func (m EntityMetadata) Flags() int64 {
+	v, _ := metadataValue[int64](m, EntityDataKeyFlags)
+	return v
+}
+
+// FlagsTwo returns the EntityDataKeyFlagsTwo field of the entity metadata. See Flags.
+func (m EntityMetadata) FlagsTwo() int64 {
+	v, _ := metadataValue[int64](m, EntityDataKeyFlagsTwo)
+	return v
+}
+
+// SetFlags sets the EntityDataKeyFlags and EntityDataKeyFlagsTwo fields of the entity metadata.
+func (m EntityMetadata) SetFlags(flags, flagsTwo int64) {
+	m[EntityDataKeyFlags], m[EntityDataKeyFlagsTwo] = flags, flagsTwo
+}
+
+// NameTag returns the name tag shown above the entity, or an empty string if it has none.
+func (m EntityMetadata) NameTag() string {
+	v, _ := metadataValue[string](m, EntityDataKeyName)
+	return v
+}
+
+// SetNameTag sets the name tag shown above the entity.
+func (m EntityMetadata) SetNameTag(name string) {
+	m[EntityDataKeyName] = name
+}
+
+// Scale returns the scale of the entity. If the entity metadata holds no scale, 1 is returned.
+func (m EntityMetadata) Scale() float32 {
+	if v, ok := metadataValue[float32](m, EntityDataKeyScale); ok {
+		return v
+	}
+	return 1
+}
+
+// SetScale sets the scale of the entity, with 1 being the regular size.
+func (m EntityMetadata) SetScale(scale float32) {
+	m[EntityDataKeyScale] = scale
+}
+
+// BoundingBox returns the width and height of the bounding box of the entity. The second return value is
+// false if the entity metadata does not hold both of them.
+func (m EntityMetadata) BoundingBox() (width, height float32, ok bool) {
+	width, okWidth := metadataValue[float32](m, EntityDataKeyWidth)
+	height, okHeight := metadataValue[float32](m, EntityDataKeyHeight)
+	return width, height, okWidth && okHeight
+}
+
+// SetBoundingBox sets the width and height of the bounding box of the entity.
+func (m EntityMetadata) SetBoundingBox(width, height float32) {
+	m[EntityDataKeyWidth], m[EntityDataKeyHeight] = width, height
+}
+
+// Variant returns the variant of the entity, such as the type of a cat or horse.
+func (m EntityMetadata) Variant() int32 {
+	v, _ := metadataValue[int32](m, EntityDataKeyVariant)
+	return v
+}
+
+// SetVariant sets the variant of the entity.
+func (m EntityMetadata) SetVariant(variant int32) {
+	m[EntityDataKeyVariant] = variant
+}
+
+// Owner returns the unique ID of the owner of the entity, such as the player that tamed it or the entity that
+// shot it. The second return value is false if the entity has no owner.
+func (m EntityMetadata) Owner() (int64, bool) {
+	return metadataValue[int64](m, EntityDataKeyOwner)
+}
+
+// SetOwner sets the unique ID of the owner of the entity.
+func (m EntityMetadata) SetOwner(uniqueID int64) {
+	m[EntityDataKeyOwner] = uniqueID
+}
+
+// Target returns the unique ID of the entity targeted by the entity. The second return value is false if the
+// entity has no target.
+func (m EntityMetadata) Target() (int64, bool) {
+	return metadataValue[int64](m, EntityDataKeyTarget)
+}
+
+// SetTarget sets the unique ID of the entity targeted by the entity.
+func (m EntityMetadata) SetTarget(uniqueID int64) {
+	m[EntityDataKeyTarget] = uniqueID
+}
+
+// AirSupply returns the remaining air supply of the entity in ticks and the maximum air supply.
+func (m EntityMetadata) AirSupply() (air, maxAir int16) {
+	air, _ = metadataValue[int16](m, EntityDataKeyAirSupply)
+	maxAir, _ = metadataValue[int16](m, EntityDataKeyAirSupplyMax)
+	return air, maxAir
+}
+
+// SetAirSupply sets the remaining and maximum air supply of the entity in ticks.
+func (m EntityMetadata) SetAirSupply(air, maxAir int16) {
+	m[EntityDataKeyAirSupply], m[EntityDataKeyAirSupplyMax] = air, maxAir
+}
+
+// metadataValue returns the value of the entity metadata field with the key passed. False is returned if the
+// field is not present or if its value is not of type T.
+func metadataValue[T any](m EntityMetadata, key uint32) (T, bool) {
+	v, ok := m[key].(T)
+	return v, ok
+}
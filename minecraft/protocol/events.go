@@ -156,12 +156,29 @@ func lookupEventType(x Event, eventType *int32) bool {
 }
 
 // Event represents an object that holds data specific to an event.
-// The data it holds depends on the type.
+// The data it holds depends on the type. Event is implemented by the *...Event types in this package, such
+// as *AchievementAwardedEvent, which may be used in a type switch to consume the data of an event.
 type Event interface {
 	// Marshal encodes/decodes a serialised event data object.
 	Marshal(r IO)
 }
 
+// NewEvent returns a new, empty Event holding the data of the event type passed, which is one of the
+// EventType constants above. False is returned if the event type is unknown or has no data implemented.
+func NewEvent(eventType int32) (Event, bool) {
+	var x Event
+	ok := lookupEvent(eventType, &x)
+	return x, ok
+}
+
+// EventTypeOf returns the event type, which is one of the EventType constants above, of the Event passed.
+// False is returned if the Event is not one of the event types implemented in this package.
+func EventTypeOf(x Event) (int32, bool) {
+	var t int32
+	ok := lookupEventType(x, &t)
+	return t, ok
+}
+
 // AchievementAwardedEvent is the event data sent for achievements.
 type AchievementAwardedEvent struct {
 	// AchievementID is the ID for the achievement.
@@ -0,0 +1,119 @@
+package protocol
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// maxFieldPathDepth is the maximum depth FieldPath descends into a value to find a field.
+const maxFieldPathDepth = 64
+
+// FieldPath returns the path of the field most recently read by the Reader within root, which must be a
+// pointer to the value being decoded, such as a packet. The path is formatted like
+// 'AvailableCommands.Enums[3].Options[1]'. FieldPath returns an empty string if the Reader is not in strict
+// mode or if none of the fields most recently read are part of root.
+func (r *Reader) FieldPath(root any) string {
+	if !r.strict || root == nil {
+		return ""
+	}
+	v := reflect.ValueOf(root)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return ""
+	}
+	v = v.Elem()
+	// Fields read into local variables, such as those read by Int8, are not part of root, so we resolve the
+	// most recently read field that is.
+	for i := 1; i <= len(r.fields) && i <= r.fieldsN; i++ {
+		target := r.fields[(r.fieldsN-i)%len(r.fields)]
+		if path, ok := findField(v, target, v.Type().Name(), 0); ok {
+			return path
+		}
+	}
+	return ""
+}
+
+// findField attempts to find the field located at the address target within v, returning its path
+// appended to the path passed if found.
+func findField(v reflect.Value, target uintptr, path string, depth int) (string, bool) {
+	if depth > maxFieldPathDepth {
+		return "", false
+	}
+	if v.CanAddr() && contains(v.UnsafeAddr(), v.Type().Size(), target) {
+		return containedField(v, target, path, depth)
+	}
+	if !indirect(v.Type()) {
+		return "", false
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return "", false
+		}
+		return findField(v.Elem(), target, path, depth+1)
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if p, ok := findField(v.Field(i), target, path+"."+v.Type().Field(i).Name, depth+1); ok {
+				return p, true
+			}
+		}
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return "", false
+		}
+		if size := v.Type().Elem().Size(); contains(v.Index(0).UnsafeAddr(), size*uintptr(v.Len()), target) {
+			i := int((target - v.Index(0).UnsafeAddr()) / size)
+			return findField(v.Index(i), target, fmt.Sprintf("%v[%v]", path, i), depth+1)
+		}
+		fallthrough
+	case reflect.Array:
+		for i := range v.Len() {
+			if p, ok := findField(v.Index(i), target, fmt.Sprintf("%v[%v]", path, i), depth+1); ok {
+				return p, true
+			}
+		}
+	}
+	return "", false
+}
+
+// containedField returns the path of the field located at the address target, which lies within the memory
+// of v. If v is a struct or array, the path of the field or element holding target is returned.
+func containedField(v reflect.Value, target uintptr, path string, depth int) (string, bool) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := range v.NumField() {
+			f := v.Field(i)
+			if contains(f.UnsafeAddr(), f.Type().Size(), target) {
+				return findField(f, target, path+"."+v.Type().Field(i).Name, depth+1)
+			}
+		}
+	case reflect.Array:
+		if size := v.Type().Elem().Size(); size != 0 {
+			i := int((target - v.UnsafeAddr()) / size)
+			return findField(v.Index(i), target, fmt.Sprintf("%v[%v]", path, i), depth+1)
+		}
+	}
+	return path, true
+}
+
+// contains checks if the address target lies within the size bytes starting at addr.
+func contains(addr, size, target uintptr) bool {
+	return target >= addr && target < addr+size
+}
+
+// indirect checks if values of the type t may refer to memory outside the value itself, in which case
+// fields of the value may be located outside it.
+func indirect(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice:
+		return true
+	case reflect.Array:
+		return indirect(t.Elem())
+	case reflect.Struct:
+		for i := range t.NumField() {
+			if indirect(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package protocol
+
+import (
+	"strings"
+)
+
+// GameRules is a typed representation of a list of GameRule, as sent in the StartGame and GameRulesChanged
+// packets. Known game rules are held in fields, which are only set if the game rule was present. Game rules
+// not known are preserved in Unknown. GameRules are created from a list of GameRule using NewGameRules and
+// converted back using GameRules.List, while GameRules.Changes returns only the game rules that changed.
+type GameRules struct {
+	// The fields below hold the known game rules, named after the game rule they hold. A field is only set if
+	// the game rule is present.
+	CommandBlockOutput        Optional[bool]
+	CommandBlocksEnabled      Optional[bool]
+	DoDaylightCycle           Optional[bool]
+	DoEntityDrops             Optional[bool]
+	DoFireTick                Optional[bool]
+	DoImmediateRespawn        Optional[bool]
+	DoInsomnia                Optional[bool]
+	DoLimitedCrafting         Optional[bool]
+	DoMobLoot                 Optional[bool]
+	DoMobSpawning             Optional[bool]
+	DoTileDrops               Optional[bool]
+	DoWeatherCycle            Optional[bool]
+	DrowningDamage            Optional[bool]
+	FallDamage                Optional[bool]
+	FireDamage                Optional[bool]
+	FreezeDamage              Optional[bool]
+	KeepInventory             Optional[bool]
+	MobGriefing               Optional[bool]
+	NaturalRegeneration       Optional[bool]
+	ProjectilesCanBreakBlocks Optional[bool]
+	PVP                       Optional[bool]
+	RecipesUnlock             Optional[bool]
+	RespawnBlocksExplode      Optional[bool]
+	SendCommandFeedback       Optional[bool]
+	ShowBorderEffect          Optional[bool]
+	ShowCoordinates           Optional[bool]
+	ShowDaysPlayed            Optional[bool]
+	ShowDeathMessages         Optional[bool]
+	ShowRecipeMessages        Optional[bool]
+	ShowTags                  Optional[bool]
+	TNTExplodes               Optional[bool]
+	TNTExplosionDropDecay     Optional[bool]
+	FunctionCommandLimit      Optional[uint32]
+	MaxCommandChainLength     Optional[uint32]
+	PlayersSleepingPercentage Optional[uint32]
+	RandomTickSpeed           Optional[uint32]
+	SpawnRadius               Optional[uint32]
+
+	// Unknown holds the game rules that are not known, in the order they were found.
+	Unknown []GameRule
+	// Modifiable holds the names, in lowercase, of the known game rules that may be modified by the player
+	// through the in-game UI.
+	Modifiable map[string]bool
+}
+
+// boolGameRules maps the lowercase names of known game rules holding a bool to their field in GameRules.
+var boolGameRules = []struct {
+	name  string
+	field func(g *GameRules) *Optional[bool]
+}{
+	{"commandblockoutput", func(g *GameRules) *Optional[bool] { return &g.CommandBlockOutput }},
+	{"commandblocksenabled", func(g *GameRules) *Optional[bool] { return &g.CommandBlocksEnabled }},
+	{"dodaylightcycle", func(g *GameRules) *Optional[bool] { return &g.DoDaylightCycle }},
+	{"doentitydrops", func(g *GameRules) *Optional[bool] { return &g.DoEntityDrops }},
+	{"dofiretick", func(g *GameRules) *Optional[bool] { return &g.DoFireTick }},
+	{"doimmediaterespawn", func(g *GameRules) *Optional[bool] { return &g.DoImmediateRespawn }},
+	{"doinsomnia", func(g *GameRules) *Optional[bool] { return &g.DoInsomnia }},
+	{"dolimitedcrafting", func(g *GameRules) *Optional[bool] { return &g.DoLimitedCrafting }},
+	{"domobloot", func(g *GameRules) *Optional[bool] { return &g.DoMobLoot }},
+	{"domobspawning", func(g *GameRules) *Optional[bool] { return &g.DoMobSpawning }},
+	{"dotiledrops", func(g *GameRules) *Optional[bool] { return &g.DoTileDrops }},
+	{"doweathercycle", func(g *GameRules) *Optional[bool] { return &g.DoWeatherCycle }},
+	{"drowningdamage", func(g *GameRules) *Optional[bool] { return &g.DrowningDamage }},
+	{"falldamage", func(g *GameRules) *Optional[bool] { return &g.FallDamage }},
+	{"firedamage", func(g *GameRules) *Optional[bool] { return &g.FireDamage }},
+	{"freezedamage", func(g *GameRules) *Optional[bool] { return &g.FreezeDamage }},
+	{"keepinventory", func(g *GameRules) *Optional[bool] { return &g.KeepInventory }},
+	{"mobgriefing", func(g *GameRules) *Optional[bool] { return &g.MobGriefing }},
+	{"naturalregeneration", func(g *GameRules) *Optional[bool] { return &g.NaturalRegeneration }},
+	{"projectilescanbreakblocks", func(g *GameRules) *Optional[bool] { return &g.ProjectilesCanBreakBlocks }},
+	{"pvp", func(g *GameRules) *Optional[bool] { return &g.PVP }},
+	{"recipesunlock", func(g *GameRules) *Optional[bool] { return &g.RecipesUnlock }},
+	{"respawnblocksexplode", func(g *GameRules) *Optional[bool] { return &g.RespawnBlocksExplode }},
+	{"sendcommandfeedback", func(g *GameRules) *Optional[bool] { return &g.SendCommandFeedback }},
+	{"showbordereffect", func(g *GameRules) *Optional[bool] { return &g.ShowBorderEffect }},
+	{"showcoordinates", func(g *GameRules) *Optional[bool] { return &g.ShowCoordinates }},
+	{"showdaysplayed", func(g *GameRules) *Optional[bool] { return &g.ShowDaysPlayed }},
+	{"showdeathmessages", func(g *GameRules) *Optional[bool] { return &g.ShowDeathMessages }},
+	{"showrecipemessages", func(g *GameRules) *Optional[bool] { return &g.ShowRecipeMessages }},
+	{"showtags", func(g *GameRules) *Optional[bool] { return &g.ShowTags }},
+	{"tntexplodes", func(g *GameRules) *Optional[bool] { return &g.TNTExplodes }},
+	{"tntexplosiondropdecay", func(g *GameRules) *Optional[bool] { return &g.TNTExplosionDropDecay }},
+}
+
+// intGameRules maps the lowercase names of known game rules holding an int to their field in GameRules.
+var intGameRules = []struct {
+	name  string
+	field func(g *GameRules) *Optional[uint32]
+}{
+	{"functioncommandlimit", func(g *GameRules) *Optional[uint32] { return &g.FunctionCommandLimit }},
+	{"maxcommandchainlength", func(g *GameRules) *Optional[uint32] { return &g.MaxCommandChainLength }},
+	{"playerssleepingpercentage", func(g *GameRules) *Optional[uint32] { return &g.PlayersSleepingPercentage }},
+	{"randomtickspeed", func(g *GameRules) *Optional[uint32] { return &g.RandomTickSpeed }},
+	{"spawnradius", func(g *GameRules) *Optional[uint32] { return &g.SpawnRadius }},
+}
+
+// NewGameRules creates GameRules from the list of GameRule passed. Game rule names are matched case
+// insensitively. Known game rules of which the value has an unexpected type are kept in Unknown.
+func NewGameRules(rules []GameRule) GameRules {
+	var g GameRules
+	for _, rule := range rules {
+		if !g.set(rule) {
+			g.Unknown = append(g.Unknown, rule)
+		}
+	}
+	return g
+}
+
+// set sets the known game rule passed, returning false if it is not known or its value has an unexpected
+// type.
+func (g *GameRules) set(rule GameRule) bool {
+	name := strings.ToLower(rule.Name)
+	for _, r := range boolGameRules {
+		if v, ok := rule.Value.(bool); ok && r.name == name {
+			*r.field(g) = Option(v)
+			g.setModifiable(name, rule.CanBeModifiedByPlayer)
+			return true
+		}
+	}
+	for _, r := range intGameRules {
+		if v, ok := rule.Value.(uint32); ok && r.name == name {
+			*r.field(g) = Option(v)
+			g.setModifiable(name, rule.CanBeModifiedByPlayer)
+			return true
+		}
+	}
+	return false
+}
+
+// setModifiable sets whether the known game rule with the lowercase name passed may be modified by the
+// player.
+func (g *GameRules) setModifiable(name string, modifiable bool) {
+	if !modifiable {
+		delete(g.Modifiable, name)
+		return
+	}
+	if g.Modifiable == nil {
+		g.Modifiable = make(map[string]bool)
+	}
+	g.Modifiable[name] = true
+}
+
+// List returns the GameRules as a list of GameRule, holding all known game rules that are set followed by
+// the Unknown game rules.
+func (g GameRules) List() []GameRule {
+	var rules []GameRule
+	for _, r := range boolGameRules {
+		if v, ok := r.field(&g).Value(); ok {
+			rules = append(rules, GameRule{Name: r.name, CanBeModifiedByPlayer: g.Modifiable[r.name], Value: v})
+		}
+	}
+	for _, r := range intGameRules {
+		if v, ok := r.field(&g).Value(); ok {
+			rules = append(rules, GameRule{Name: r.name, CanBeModifiedByPlayer: g.Modifiable[r.name], Value: v})
+		}
+	}
+	return append(rules, g.Unknown...)
+}
+
+// Changes returns the game rules of g that differ from those in prev, for use in a GameRulesChanged packet.
+// Known game rules are included if they are set in g and either not set in prev, set to a different value or
+// differ in whether they may be modified by the player. Unknown game rules are included if no unknown game
+// rule with the same name, value and modifiability is present in prev. Game rules set in prev but not in g
+// are not included, as the protocol has no way of unsetting a game rule.
+func (g GameRules) Changes(prev GameRules) []GameRule {
+	var rules []GameRule
+	for _, r := range boolGameRules {
+		v, ok := r.field(&g).Value()
+		old, oldOK := r.field(&prev).Value()
+		if ok && (!oldOK || v != old || g.Modifiable[r.name] != prev.Modifiable[r.name]) {
+			rules = append(rules, GameRule{Name: r.name, CanBeModifiedByPlayer: g.Modifiable[r.name], Value: v})
+		}
+	}
+	for _, r := range intGameRules {
+		v, ok := r.field(&g).Value()
+		old, oldOK := r.field(&prev).Value()
+		if ok && (!oldOK || v != old || g.Modifiable[r.name] != prev.Modifiable[r.name]) {
+			rules = append(rules, GameRule{Name: r.name, CanBeModifiedByPlayer: g.Modifiable[r.name], Value: v})
+		}
+	}
+	for _, rule := range g.Unknown {
+		changed := true
+		for _, old := range prev.Unknown {
+			if strings.EqualFold(old.Name, rule.Name) && old.Value == rule.Value && old.CanBeModifiedByPlayer == rule.CanBeModifiedByPlayer {
+				changed = false
+				break
+			}
+		}
+		if changed {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
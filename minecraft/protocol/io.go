@@ -44,7 +44,7 @@ type IO interface {
 	RGB(x *color.RGBA)
 	RGBA(x *color.RGBA)
 	VarRGBA(x *color.RGBA)
-	EntityMetadata(x *map[uint32]any)
+	EntityMetadata(x *EntityMetadata)
 	Item(x *ItemStack)
 	ItemInstance(i *ItemInstance)
 	ItemDescriptorCount(i *ItemDescriptorCount)
@@ -63,7 +63,11 @@ type IO interface {
 	InvalidValue(value any, forField, reason string)
 }
 
-// Marshaler is a type that can be written to or read from an IO.
+//go:generate go run ./packet/internal/marshalgen -out marshal_gen.go
+
+// Marshaler is a type that can be written to or read from an IO. If the package is built with the
+// protocolgen build tag, Marshalers read or written using helpers such as Slice and Single are instead
+// marshaled by methods generated by marshalgen, if they have them.
 type Marshaler interface {
 	Marshal(r IO)
 }
@@ -144,25 +148,23 @@ const maxSliceLength = 1024
 func SliceOfLen[T any, S ~*[]T, A PtrMarshaler[T]](r IO, l uint32, x S) {
 	rd, reader := r.(*Reader)
 	if reader {
-		if rd.limitsEnabled && l > maxSliceLength {
-			rd.panicf("slice length was too long: length of %v", l)
-		}
+		rd.checkLength(l, "slice")
 		*x = make([]T, l)
+		rd.enter()
+		defer rd.exit()
 	}
 
-	for i := uint32(0); i < l; i++ {
-		A(&(*x)[i]).Marshal(r)
-	}
+	marshalSlice[T, A](r, *x)
 }
 
 // FuncSliceOfLen reads/writes the elements of a slice of type T with length l using func f.
 func FuncSliceOfLen[T any, S ~*[]T](r IO, l uint32, x S, f func(*T)) {
 	rd, reader := r.(*Reader)
 	if reader {
-		if rd.limitsEnabled && l > maxSliceLength {
-			rd.panicf("slice length was too long: length of %v", l)
-		}
+		rd.checkLength(l, "slice")
 		*x = make([]T, l)
+		rd.enter()
+		defer rd.exit()
 	}
 
 	for i := uint32(0); i < l; i++ {
@@ -185,7 +187,7 @@ type PtrMarshaler[T any] interface {
 
 // Single reads/writes a single Marshaler x.
 func Single[T any, S PtrMarshaler[T]](r IO, x S) {
-	x.Marshal(r)
+	marshal[T, S](r, x)
 }
 
 // Optional is an optional type in the protocol. If not set, only a false bool is written. If set, a true bool is
@@ -227,6 +229,6 @@ func OptionalFuncIO[T any](r IO, x *Optional[T], f func(IO, *T)) any {
 func OptionalMarshaler[T any, A PtrMarshaler[T]](r IO, x *Optional[T]) {
 	r.Bool(&x.set)
 	if x.set {
-		A(&x.val).Marshal(r)
+		marshal[T, A](r, &x.val)
 	}
 }
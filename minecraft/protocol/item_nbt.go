@@ -0,0 +1,221 @@
+package protocol
+
+// ItemEnchantment is an enchantment stored in the NBT data of an ItemStack.
+type ItemEnchantment struct {
+	// ID is the numerical ID of the enchantment type, such as 9 for sharpness.
+	ID int16
+	// Level is the level of the enchantment, starting at 1.
+	Level int16
+}
+
+// DisplayName returns the custom name of the ItemStack, as found in the 'display' compound of its NBT data.
+// False is returned if the ItemStack has no custom name.
+func (x *ItemStack) DisplayName() (string, bool) {
+	name, ok := x.display()["Name"].(string)
+	return name, ok
+}
+
+// SetDisplayName sets the custom name of the ItemStack. Passing an empty name removes the custom name.
+func (x *ItemStack) SetDisplayName(name string) {
+	if name == "" {
+		x.deleteDisplay("Name")
+		return
+	}
+	x.setDisplay("Name", name)
+}
+
+// Lore returns the lines of lore of the ItemStack, as found in the 'display' compound of its NBT data.
+func (x *ItemStack) Lore() []string {
+	return nbtStrings(x.display()["Lore"])
+}
+
+// SetLore sets the lines of lore of the ItemStack. Passing no lines removes the lore.
+func (x *ItemStack) SetLore(lines ...string) {
+	if len(lines) == 0 {
+		x.deleteDisplay("Lore")
+		return
+	}
+	x.setDisplay("Lore", append([]string(nil), lines...))
+}
+
+// Enchantments returns the enchantments of the ItemStack, as found in the 'ench' list of its NBT data.
+func (x *ItemStack) Enchantments() []ItemEnchantment {
+	var enchantments []ItemEnchantment
+	for _, v := range nbtList(x.NBTData["ench"]) {
+		m, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		id, _ := nbtInt(m["id"])
+		lvl, _ := nbtInt(m["lvl"])
+		enchantments = append(enchantments, ItemEnchantment{ID: int16(id), Level: int16(lvl)})
+	}
+	return enchantments
+}
+
+// SetEnchantments sets the enchantments of the ItemStack, replacing any it had before. Passing no
+// enchantments removes all enchantments.
+func (x *ItemStack) SetEnchantments(enchantments ...ItemEnchantment) {
+	if len(enchantments) == 0 {
+		delete(x.NBTData, "ench")
+		return
+	}
+	list := make([]any, len(enchantments))
+	for i, e := range enchantments {
+		list[i] = map[string]any{"id": e.ID, "lvl": e.Level}
+	}
+	x.nbt()["ench"] = list
+}
+
+// AddEnchantment adds an enchantment to the ItemStack. If the ItemStack already has an enchantment with the
+// same ID, its level is replaced.
+func (x *ItemStack) AddEnchantment(enchantment ItemEnchantment) {
+	enchantments := x.Enchantments()
+	for i, e := range enchantments {
+		if e.ID == enchantment.ID {
+			enchantments[i] = enchantment
+			x.SetEnchantments(enchantments...)
+			return
+		}
+	}
+	x.SetEnchantments(append(enchantments, enchantment)...)
+}
+
+// Damage returns the damage of the ItemStack, as found in the 'Damage' tag of its NBT data. Damage is 0 if
+// the ItemStack has no damage.
+func (x *ItemStack) Damage() int32 {
+	damage, _ := nbtInt(x.NBTData["Damage"])
+	return int32(damage)
+}
+
+// SetDamage sets the damage of the ItemStack. Passing 0 removes the damage.
+func (x *ItemStack) SetDamage(damage int32) {
+	if damage == 0 {
+		delete(x.NBTData, "Damage")
+		return
+	}
+	x.nbt()["Damage"] = damage
+}
+
+// CanPlaceOn returns the block identifiers in the 'CanPlaceOn' list of the NBT data of the ItemStack. Unlike
+// the CanBePlacedOn field, which is sent separately, this list is stored with the item, for example in a
+// world.
+func (x *ItemStack) CanPlaceOn() []string {
+	return nbtStrings(x.NBTData["CanPlaceOn"])
+}
+
+// SetCanPlaceOn sets the 'CanPlaceOn' list of the NBT data of the ItemStack. Passing no blocks removes the
+// list.
+func (x *ItemStack) SetCanPlaceOn(blocks ...string) {
+	x.setStrings("CanPlaceOn", blocks)
+}
+
+// CanDestroy returns the block identifiers in the 'CanDestroy' list of the NBT data of the ItemStack. Unlike
+// the CanBreak field, which is sent separately, this list is stored with the item, for example in a world.
+func (x *ItemStack) CanDestroy() []string {
+	return nbtStrings(x.NBTData["CanDestroy"])
+}
+
+// SetCanDestroy sets the 'CanDestroy' list of the NBT data of the ItemStack. Passing no blocks removes the
+// list.
+func (x *ItemStack) SetCanDestroy(blocks ...string) {
+	x.setStrings("CanDestroy", blocks)
+}
+
+// nbt returns the NBT data of the ItemStack, creating it if it is nil.
+func (x *ItemStack) nbt() map[string]any {
+	if x.NBTData == nil {
+		x.NBTData = make(map[string]any)
+	}
+	return x.NBTData
+}
+
+// display returns the 'display' compound of the NBT data of the ItemStack, or nil if it has none.
+func (x *ItemStack) display() map[string]any {
+	m, _ := x.NBTData["display"].(map[string]any)
+	return m
+}
+
+// setDisplay sets a value in the 'display' compound of the NBT data of the ItemStack, creating the compound
+// if it does not yet exist.
+func (x *ItemStack) setDisplay(key string, v any) {
+	m := x.display()
+	if m == nil {
+		m = make(map[string]any)
+		x.nbt()["display"] = m
+	}
+	m[key] = v
+}
+
+// deleteDisplay removes a value from the 'display' compound of the NBT data of the ItemStack. The compound
+// itself is removed if it is empty after.
+func (x *ItemStack) deleteDisplay(key string) {
+	m := x.display()
+	if m == nil {
+		return
+	}
+	delete(m, key)
+	if len(m) == 0 {
+		delete(x.NBTData, "display")
+	}
+}
+
+// setStrings sets a list of strings in the NBT data of the ItemStack, or removes it if the list is empty.
+func (x *ItemStack) setStrings(key string, s []string) {
+	if len(s) == 0 {
+		delete(x.NBTData, key)
+		return
+	}
+	x.nbt()[key] = append([]string(nil), s...)
+}
+
+// nbtList returns the elements of a decoded NBT list. Lists decoded from NBT are either a []any or a slice
+// of a concrete type, depending on the type of their elements.
+func nbtList(v any) []any {
+	switch v := v.(type) {
+	case []any:
+		return v
+	case []map[string]any:
+		l := make([]any, len(v))
+		for i, e := range v {
+			l[i] = e
+		}
+		return l
+	case []string:
+		l := make([]any, len(v))
+		for i, e := range v {
+			l[i] = e
+		}
+		return l
+	}
+	return nil
+}
+
+// nbtStrings returns the strings of a decoded NBT list. Elements that are not strings are skipped.
+func nbtStrings(v any) []string {
+	if s, ok := v.([]string); ok {
+		return append([]string(nil), s...)
+	}
+	var s []string
+	for _, e := range nbtList(v) {
+		if str, ok := e.(string); ok {
+			s = append(s, str)
+		}
+	}
+	return s
+}
+
+// nbtInt returns the value of a decoded NBT integer of any size.
+func nbtInt(v any) (int64, bool) {
+	switch v := v.(type) {
+	case uint8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	}
+	return 0, false
+}
@@ -0,0 +1,102 @@
+package login
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChainCache caches the results of verifying login chains, so that the signatures of a chain that was
+// verified before do not have to be verified again. Verifying the chain is the most expensive part of parsing
+// a login request, so a ChainCache reduces the load of many clients joining in a short time, for example
+// when they reconnect after a restart. The client data of a request is always verified.
+// A ChainCache is safe for concurrent use.
+type ChainCache struct {
+	ttl time.Duration
+	max int
+
+	mu      sync.Mutex
+	entries map[[32]byte]chainCacheEntry
+}
+
+// chainCacheEntry is a verified chain held by a ChainCache, together with the time at which it may no longer
+// be used.
+type chainCacheEntry struct {
+	v       verifiedChain
+	expires time.Time
+}
+
+// NewChainCache returns a ChainCache that holds a verified chain for at most the duration passed, or until
+// one of the claims in the chain expires. It holds at most maxEntries chains. If maxEntries is 0 or lower, a
+// default of 4096 is used.
+func NewChainCache(ttl time.Duration, maxEntries int) *ChainCache {
+	if maxEntries <= 0 {
+		maxEntries = 4096
+	}
+	return &ChainCache{ttl: ttl, max: maxEntries, entries: make(map[[32]byte]chainCacheEntry)}
+}
+
+// Parse parses and verifies the login request passed like Parse does, but only verifies the login chain if it
+// was not verified before within the TTL of the ChainCache. The bool returned specifies if the verified chain
+// was found in the ChainCache.
+func (c *ChainCache) Parse(request []byte) (IdentityData, ClientData, AuthResult, bool, error) {
+	req, err := parseLoginRequest(request)
+	if err != nil {
+		return IdentityData{}, ClientData{}, AuthResult{}, false, fmt.Errorf("parse login request: %w", err)
+	}
+	hash := sha256.Sum256([]byte(strings.Join(req.Chain, ".")))
+	v, ok := c.lookup(hash)
+	if !ok {
+		if v, err = verifyChain(req.Chain); err != nil {
+			return IdentityData{}, ClientData{}, AuthResult{}, false, err
+		}
+		c.store(hash, v)
+	}
+	iData, cData, res, err := parseClientData(req, v)
+	return iData, cData, res, ok, err
+}
+
+// lookup looks up the verified chain with the hash passed. False is returned if it is not in the ChainCache
+// or if it expired.
+func (c *ChainCache) lookup(hash [32]byte) (verifiedChain, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[hash]
+	if !ok {
+		return verifiedChain{}, false
+	}
+	if time.Now().After(e.expires) {
+		delete(c.entries, hash)
+		return verifiedChain{}, false
+	}
+	return e.v, true
+}
+
+// store stores the verified chain passed under its hash. If the ChainCache is full, expired chains are
+// removed first, and a random chain if none had expired.
+func (c *ChainCache) store(hash [32]byte, v verifiedChain) {
+	now := time.Now()
+	expires := now.Add(c.ttl)
+	if !v.expiry.IsZero() && v.expiry.Before(expires) {
+		expires = v.expiry
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= c.max {
+		for h, e := range c.entries {
+			if now.After(e.expires) {
+				delete(c.entries, h)
+			}
+		}
+		for h := range c.entries {
+			if len(c.entries) < c.max {
+				break
+			}
+			delete(c.entries, h)
+		}
+	}
+	c.entries[hash] = chainCacheEntry{v: v, expires: expires}
+}
@@ -53,76 +53,119 @@ type AuthResult struct {
 // the client. Rather, it is obtained from an authentication endpoint. The ClientData can, however, be edited
 // freely by the client.
 func Parse(request []byte) (IdentityData, ClientData, AuthResult, error) {
-	var (
-		iData IdentityData
-		cData ClientData
-		res   AuthResult
-		key   = &ecdsa.PublicKey{}
-	)
 	req, err := parseLoginRequest(request)
 	if err != nil {
-		return iData, cData, res, fmt.Errorf("parse login request: %w", err)
+		return IdentityData{}, ClientData{}, AuthResult{}, fmt.Errorf("parse login request: %w", err)
+	}
+	v, err := verifyChain(req.Chain)
+	if err != nil {
+		return IdentityData{}, ClientData{}, AuthResult{}, err
 	}
-	tok, err := jwt.ParseSigned(req.Chain[0])
+	return parseClientData(req, v)
+}
+
+// verifiedChain holds the result of verifying the login chain of a request.
+type verifiedChain struct {
+	identityData  IdentityData
+	key           *ecdsa.PublicKey
+	authenticated bool
+	// expiry is the time at which the first claim in the chain expires. It is the zero time.Time if none of
+	// the claims expire.
+	expiry time.Time
+}
+
+// verifyChain verifies the signatures and claims of the login chain passed and returns the identity data
+// and identity public key found in it.
+func verifyChain(chain chain) (verifiedChain, error) {
+	var (
+		res verifiedChain
+		key = &ecdsa.PublicKey{}
+	)
+	tok, err := jwt.ParseSigned(chain[0])
 	if err != nil {
-		return iData, cData, res, fmt.Errorf("parse token 0: %w", err)
+		return res, fmt.Errorf("parse token 0: %w", err)
 	}
 
 	// The first token holds the client's public key in the x5u (it's self signed).
 	//lint:ignore S1005 Double assignment is done explicitly to prevent panics.
 	raw, _ := tok.Headers[0].ExtraHeaders["x5u"]
 	if err := parseAsKey(raw, key); err != nil {
-		return iData, cData, res, fmt.Errorf("parse x5u: %w", err)
+		return res, fmt.Errorf("parse x5u: %w", err)
 	}
 
 	var identityClaims identityClaims
 	var authenticated bool
 	t, iss := time.Now(), "Mojang"
 
-	switch len(req.Chain) {
+	switch len(chain) {
 	case 1:
 		// Player was not authenticated with XBOX Live, meaning the one token in here is self-signed.
-		if err := parseFullClaim(req.Chain[0], key, &identityClaims); err != nil {
-			return iData, cData, res, err
+		if err := parseFullClaim(chain[0], key, &identityClaims); err != nil {
+			return res, err
 		}
 		if err := identityClaims.Validate(jwt.Expected{Time: t}); err != nil {
-			return iData, cData, res, fmt.Errorf("validate token 0: %w", err)
+			return res, fmt.Errorf("validate token 0: %w", err)
 		}
 	case 3:
 		// Player was (or should be) authenticated with XBOX Live, meaning the chain is exactly 3 tokens
 		// long.
 		var c jwt.Claims
-		if err := parseFullClaim(req.Chain[0], key, &c); err != nil {
-			return iData, cData, res, fmt.Errorf("parse token 0: %w", err)
+		if err := parseFullClaim(chain[0], key, &c); err != nil {
+			return res, fmt.Errorf("parse token 0: %w", err)
 		}
 		if err := c.Validate(jwt.Expected{Time: t}); err != nil {
-			return iData, cData, res, fmt.Errorf("validate token 0: %w", err)
+			return res, fmt.Errorf("validate token 0: %w", err)
 		}
+		res.expiry = earliestExpiry(res.expiry, c)
 		authenticated = bytes.Equal(key.X.Bytes(), mojangKey.X.Bytes()) && bytes.Equal(key.Y.Bytes(), mojangKey.Y.Bytes())
 
-		if err := parseFullClaim(req.Chain[1], key, &c); err != nil {
-			return iData, cData, res, fmt.Errorf("parse token 1: %w", err)
+		if err := parseFullClaim(chain[1], key, &c); err != nil {
+			return res, fmt.Errorf("parse token 1: %w", err)
 		}
 		if err := c.Validate(jwt.Expected{Time: t, Issuer: iss}); err != nil {
-			return iData, cData, res, fmt.Errorf("validate token 1: %w", err)
+			return res, fmt.Errorf("validate token 1: %w", err)
 		}
-		if err := parseFullClaim(req.Chain[2], key, &identityClaims); err != nil {
-			return iData, cData, res, fmt.Errorf("parse token 2: %w", err)
+		res.expiry = earliestExpiry(res.expiry, c)
+		if err := parseFullClaim(chain[2], key, &identityClaims); err != nil {
+			return res, fmt.Errorf("parse token 2: %w", err)
 		}
 		if err := identityClaims.Validate(jwt.Expected{Time: t, Issuer: iss}); err != nil {
-			return iData, cData, res, fmt.Errorf("validate token 2: %w", err)
+			return res, fmt.Errorf("validate token 2: %w", err)
 		}
 		if authenticated != (identityClaims.ExtraData.XUID != "") {
-			return iData, cData, res, fmt.Errorf("identity data must have an XUID when logged into XBOX Live only")
+			return res, fmt.Errorf("identity data must have an XUID when logged into XBOX Live only")
 		}
 		if authenticated != (identityClaims.ExtraData.TitleID != "") {
-			return iData, cData, res, fmt.Errorf("identity data must have a title ID when logged into XBOX Live only")
+			return res, fmt.Errorf("identity data must have a title ID when logged into XBOX Live only")
 		}
 	default:
-		return iData, cData, res, fmt.Errorf("unexpected login chain length %v", len(req.Chain))
+		return res, fmt.Errorf("unexpected login chain length %v", len(chain))
+	}
+	res.expiry = earliestExpiry(res.expiry, identityClaims.Claims)
+	res.identityData, res.key, res.authenticated = identityClaims.ExtraData, key, authenticated
+	return res, nil
+}
+
+// earliestExpiry returns the earliest of the expiry passed and the expiry of the claims passed. A zero expiry
+// is treated as never expiring.
+func earliestExpiry(expiry time.Time, c jwt.Claims) time.Time {
+	if c.Expiry == nil {
+		return expiry
+	}
+	if exp := c.Expiry.Time(); expiry.IsZero() || exp.Before(expiry) {
+		return exp
 	}
-	if err := parseFullClaim(req.RawToken, key, &cData); err != nil {
-		return iData, cData, res, fmt.Errorf("parse client data: %w", err)
+	return expiry
+}
+
+// parseClientData parses and verifies the client data of the request passed using the identity public key
+// of the verified chain passed.
+func parseClientData(req *request, v verifiedChain) (IdentityData, ClientData, AuthResult, error) {
+	var cData ClientData
+	// Copy the key, as parseFullClaim may replace it and the verified chain may be shared.
+	key := *v.key
+	if err := parseFullClaim(req.RawToken, &key, &cData); err != nil {
+		return IdentityData{}, cData, AuthResult{}, fmt.Errorf("parse client data: %w", err)
 	}
 	if strings.Count(cData.ServerAddress, ":") > 1 && cData.ServerAddress[0] != '[' {
 		// IPv6: We can't net.ResolveUDPAddr this directly, because Mojang does
@@ -132,9 +175,9 @@ func Parse(request []byte) (IdentityData, ClientData, AuthResult, error) {
 		cData.ServerAddress = "[" + cData.ServerAddress[:ind] + "]" + cData.ServerAddress[ind:]
 	}
 	if err := cData.Validate(); err != nil {
-		return iData, cData, res, fmt.Errorf("validate client data: %w", err)
+		return IdentityData{}, cData, AuthResult{}, fmt.Errorf("validate client data: %w", err)
 	}
-	return identityClaims.ExtraData, cData, AuthResult{PublicKey: key, XBOXLiveAuthenticated: authenticated}, nil
+	return v.identityData, cData, AuthResult{PublicKey: &key, XBOXLiveAuthenticated: v.authenticated}, nil
 }
 
 // parseLoginRequest parses the structure of a login request from the data passed and returns it.
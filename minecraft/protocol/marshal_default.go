@@ -0,0 +1,15 @@
+//go:build !protocolgen
+
+package protocol
+
+// marshal marshals x using its Marshal method.
+func marshal[T any, A PtrMarshaler[T]](r IO, x A) {
+	x.Marshal(r)
+}
+
+// marshalSlice marshals the elements of the slice passed using their Marshal methods.
+func marshalSlice[T any, A PtrMarshaler[T]](r IO, s []T) {
+	for i := range s {
+		A(&s[i]).Marshal(r)
+	}
+}
@@ -0,0 +1,1553 @@
+// Code generated by marshalgen. DO NOT EDIT.
+
+//go:build protocolgen
+
+package protocol
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+func (x *AbilityData) marshalReader(r *Reader) {
+	r.Int64(&x.EntityUniqueID)
+	r.Uint8(&x.PlayerPermissions)
+	r.Uint8(&x.CommandPermissions)
+	SliceUint8Length(r, &x.Layers)
+}
+
+func (x *AbilityData) marshalWriter(r *Writer) {
+	r.Int64(&x.EntityUniqueID)
+	r.Uint8(&x.PlayerPermissions)
+	r.Uint8(&x.CommandPermissions)
+	SliceUint8Length(r, &x.Layers)
+}
+
+func (x *AbilityLayer) marshalReader(r *Reader) {
+	r.Uint16(&x.Type)
+	r.Uint32(&x.Abilities)
+	r.Uint32(&x.Values)
+	r.Float32(&x.FlySpeed)
+	r.Float32(&x.WalkSpeed)
+}
+
+func (x *AbilityLayer) marshalWriter(r *Writer) {
+	r.Uint16(&x.Type)
+	r.Uint32(&x.Abilities)
+	r.Uint32(&x.Values)
+	r.Float32(&x.FlySpeed)
+	r.Float32(&x.WalkSpeed)
+}
+
+func (a *AchievementAwardedEvent) marshalReader(r *Reader) {
+	r.Varint32(&a.AchievementID)
+}
+
+func (a *AchievementAwardedEvent) marshalWriter(r *Writer) {
+	r.Varint32(&a.AchievementID)
+}
+
+func (a *AgentCommandEvent) marshalReader(r *Reader) {
+	r.Varint32(&a.AgentResult)
+	r.Varint32(&a.DataValue)
+	r.String(&a.Command)
+	r.String(&a.DataKey)
+	r.String(&a.Output)
+}
+
+func (a *AgentCommandEvent) marshalWriter(r *Writer) {
+	r.Varint32(&a.AgentResult)
+	r.Varint32(&a.DataValue)
+	r.String(&a.Command)
+	r.String(&a.DataKey)
+	r.String(&a.Output)
+}
+
+func (x *Attribute) marshalReader(r *Reader) {
+	r.Float32(&x.Min)
+	r.Float32(&x.Max)
+	r.Float32(&x.Value)
+	r.Float32(&x.Default)
+	r.String(&x.Name)
+	Slice(r, &x.Modifiers)
+}
+
+func (x *Attribute) marshalWriter(r *Writer) {
+	r.Float32(&x.Min)
+	r.Float32(&x.Max)
+	r.Float32(&x.Value)
+	r.Float32(&x.Default)
+	r.String(&x.Name)
+	Slice(r, &x.Modifiers)
+}
+
+func (x *AttributeModifier) marshalReader(r *Reader) {
+	r.String(&x.ID)
+	r.String(&x.Name)
+	r.Float32(&x.Amount)
+	r.Int32(&x.Operation)
+	r.Int32(&x.Operand)
+	r.Bool(&x.Serializable)
+}
+
+func (x *AttributeModifier) marshalWriter(r *Writer) {
+	r.String(&x.ID)
+	r.String(&x.Name)
+	r.Float32(&x.Amount)
+	r.Int32(&x.Operation)
+	r.Int32(&x.Operand)
+	r.Bool(&x.Serializable)
+}
+
+func (x *AttributeValue) marshalReader(r *Reader) {
+	r.String(&x.Name)
+	r.Float32(&x.Min)
+	r.Float32(&x.Value)
+	r.Float32(&x.Max)
+}
+
+func (x *AttributeValue) marshalWriter(r *Writer) {
+	r.String(&x.Name)
+	r.Float32(&x.Min)
+	r.Float32(&x.Value)
+	r.Float32(&x.Max)
+}
+
+func (a *AutoCraftRecipeStackRequestAction) marshalReader(r *Reader) {
+	r.Varuint32(&a.RecipeNetworkID)
+	r.Uint8(&a.TimesCrafted)
+	FuncSlice(r, &a.Ingredients, r.ItemDescriptorCount)
+}
+
+func (a *AutoCraftRecipeStackRequestAction) marshalWriter(r *Writer) {
+	r.Varuint32(&a.RecipeNetworkID)
+	r.Uint8(&a.TimesCrafted)
+	FuncSlice(r, &a.Ingredients, r.ItemDescriptorCount)
+}
+
+func (a *BeaconPaymentStackRequestAction) marshalReader(r *Reader) {
+	r.Varint32(&a.PrimaryEffect)
+	r.Varint32(&a.SecondaryEffect)
+}
+
+func (a *BeaconPaymentStackRequestAction) marshalWriter(r *Writer) {
+	r.Varint32(&a.PrimaryEffect)
+	r.Varint32(&a.SecondaryEffect)
+}
+
+func (x *BehaviourPackInfo) marshalReader(r *Reader) {
+	r.String(&x.UUID)
+	r.String(&x.Version)
+	r.Uint64(&x.Size)
+	r.String(&x.ContentKey)
+	r.String(&x.SubPackName)
+	r.String(&x.ContentIdentity)
+	r.Bool(&x.HasScripts)
+}
+
+func (x *BehaviourPackInfo) marshalWriter(r *Writer) {
+	r.String(&x.UUID)
+	r.String(&x.Version)
+	r.Uint64(&x.Size)
+	r.String(&x.ContentKey)
+	r.String(&x.SubPackName)
+	r.String(&x.ContentIdentity)
+	r.Bool(&x.HasScripts)
+}
+
+func (b *BellUsedEvent) marshalReader(r *Reader) {
+	r.Varint32(&b.ItemID)
+}
+
+func (b *BellUsedEvent) marshalWriter(r *Writer) {
+	r.Varint32(&b.ItemID)
+}
+
+func (x *BlockChangeEntry) marshalReader(r *Reader) {
+	r.BlockPos(&x.BlockPos)
+	r.Varuint32(&x.BlockRuntimeID)
+	r.Varuint32(&x.Flags)
+	r.Varuint64(&x.SyncedUpdateEntityUniqueID)
+	r.Varuint32(&x.SyncedUpdateType)
+}
+
+func (x *BlockChangeEntry) marshalWriter(r *Writer) {
+	r.BlockPos(&x.BlockPos)
+	r.Varuint32(&x.BlockRuntimeID)
+	r.Varuint32(&x.Flags)
+	r.Varuint64(&x.SyncedUpdateEntityUniqueID)
+	r.Varuint32(&x.SyncedUpdateType)
+}
+
+func (x *BlockEntry) marshalReader(r *Reader) {
+	r.String(&x.Name)
+	r.NBT(&x.Properties, nbt.NetworkLittleEndian)
+}
+
+func (x *BlockEntry) marshalWriter(r *Writer) {
+	r.String(&x.Name)
+	r.NBT(&x.Properties, nbt.NetworkLittleEndian)
+}
+
+func (b *BossKilledEvent) marshalReader(r *Reader) {
+	r.Varint64(&b.BossEntityUniqueID)
+	r.Varint32(&b.PlayerPartySize)
+	r.Varint32(&b.InteractionEntityType)
+}
+
+func (b *BossKilledEvent) marshalWriter(r *Writer) {
+	r.Varint64(&b.BossEntityUniqueID)
+	r.Varint32(&b.PlayerPartySize)
+	r.Varint32(&b.InteractionEntityType)
+}
+
+func (x *CacheBlob) marshalReader(r *Reader) {
+	r.Uint64(&x.Hash)
+	r.ByteSlice(&x.Payload)
+}
+
+func (x *CacheBlob) marshalWriter(r *Writer) {
+	r.Uint64(&x.Hash)
+	r.ByteSlice(&x.Payload)
+}
+
+func (x *CameraEase) marshalReader(r *Reader) {
+	r.Uint8(&x.Type)
+	r.Float32(&x.Duration)
+}
+
+func (x *CameraEase) marshalWriter(r *Writer) {
+	r.Uint8(&x.Type)
+	r.Float32(&x.Duration)
+}
+
+func (x *CameraFadeTimeData) marshalReader(r *Reader) {
+	r.Float32(&x.FadeInDuration)
+	r.Float32(&x.WaitDuration)
+	r.Float32(&x.FadeOutDuration)
+}
+
+func (x *CameraFadeTimeData) marshalWriter(r *Writer) {
+	r.Float32(&x.FadeInDuration)
+	r.Float32(&x.WaitDuration)
+	r.Float32(&x.FadeOutDuration)
+}
+
+func (x *CameraInstructionFade) marshalReader(r *Reader) {
+	OptionalMarshaler(r, &x.TimeData)
+	OptionalFunc(r, &x.Colour, r.RGB)
+}
+
+func (x *CameraInstructionFade) marshalWriter(r *Writer) {
+	OptionalMarshaler(r, &x.TimeData)
+	OptionalFunc(r, &x.Colour, r.RGB)
+}
+
+func (x *CameraInstructionSet) marshalReader(r *Reader) {
+	r.Uint32(&x.Preset)
+	OptionalMarshaler(r, &x.Ease)
+	OptionalFunc(r, &x.Position, r.Vec3)
+	OptionalFunc(r, &x.Rotation, r.Vec2)
+	OptionalFunc(r, &x.Facing, r.Vec3)
+	OptionalFunc(r, &x.Default, r.Bool)
+}
+
+func (x *CameraInstructionSet) marshalWriter(r *Writer) {
+	r.Uint32(&x.Preset)
+	OptionalMarshaler(r, &x.Ease)
+	OptionalFunc(r, &x.Position, r.Vec3)
+	OptionalFunc(r, &x.Rotation, r.Vec2)
+	OptionalFunc(r, &x.Facing, r.Vec3)
+	OptionalFunc(r, &x.Default, r.Bool)
+}
+
+func (x *CameraPreset) marshalReader(r *Reader) {
+	r.String(&x.Name)
+	r.String(&x.Parent)
+	OptionalFunc(r, &x.PosX, r.Float32)
+	OptionalFunc(r, &x.PosY, r.Float32)
+	OptionalFunc(r, &x.PosZ, r.Float32)
+	OptionalFunc(r, &x.RotX, r.Float32)
+	OptionalFunc(r, &x.RotY, r.Float32)
+	OptionalFunc(r, &x.AudioListener, r.Uint8)
+	OptionalFunc(r, &x.PlayerEffects, r.Bool)
+}
+
+func (x *CameraPreset) marshalWriter(r *Writer) {
+	r.String(&x.Name)
+	r.String(&x.Parent)
+	OptionalFunc(r, &x.PosX, r.Float32)
+	OptionalFunc(r, &x.PosY, r.Float32)
+	OptionalFunc(r, &x.PosZ, r.Float32)
+	OptionalFunc(r, &x.RotX, r.Float32)
+	OptionalFunc(r, &x.RotY, r.Float32)
+	OptionalFunc(r, &x.AudioListener, r.Uint8)
+	OptionalFunc(r, &x.PlayerEffects, r.Bool)
+}
+
+func (c *CauldronInteractEvent) marshalReader(r *Reader) {
+	r.Varint32(&c.BlockInteractionType)
+	r.Varint32(&c.ItemID)
+}
+
+func (c *CauldronInteractEvent) marshalWriter(r *Writer) {
+	r.Varint32(&c.BlockInteractionType)
+	r.Varint32(&c.ItemID)
+}
+
+func (c *CauldronUsedEvent) marshalReader(r *Reader) {
+	r.Varint32(&c.PotionID)
+	r.Varint32(&c.Colour)
+	r.Varint32(&c.FillLevel)
+}
+
+func (c *CauldronUsedEvent) marshalWriter(r *Writer) {
+	r.Varint32(&c.PotionID)
+	r.Varint32(&c.Colour)
+	r.Varint32(&c.FillLevel)
+}
+
+func (x *ChainedSubcommand) marshalReader(r *Reader) {
+	r.String(&x.Name)
+	Slice(r, &x.Values)
+}
+
+func (x *ChainedSubcommand) marshalWriter(r *Writer) {
+	r.String(&x.Name)
+	Slice(r, &x.Values)
+}
+
+func (x *ChainedSubcommandValue) marshalReader(r *Reader) {
+	r.Uint16(&x.Index)
+	r.Uint16(&x.Value)
+}
+
+func (x *ChainedSubcommandValue) marshalWriter(r *Writer) {
+	r.Uint16(&x.Index)
+	r.Uint16(&x.Value)
+}
+
+func (c *Command) marshalReader(r *Reader) {
+	r.String(&c.Name)
+	r.String(&c.Description)
+	r.Uint16(&c.Flags)
+	r.Uint8(&c.PermissionLevel)
+	r.Uint32(&c.AliasesOffset)
+	FuncSlice(r, &c.ChainedSubcommandOffsets, r.Uint16)
+	Slice(r, &c.Overloads)
+}
+
+func (c *Command) marshalWriter(r *Writer) {
+	r.String(&c.Name)
+	r.String(&c.Description)
+	r.Uint16(&c.Flags)
+	r.Uint8(&c.PermissionLevel)
+	r.Uint32(&c.AliasesOffset)
+	FuncSlice(r, &c.ChainedSubcommandOffsets, r.Uint16)
+	Slice(r, &c.Overloads)
+}
+
+func (c *CommandEnumConstraint) marshalReader(r *Reader) {
+	r.Uint32(&c.EnumValueIndex)
+	r.Uint32(&c.EnumIndex)
+	r.ByteSlice(&c.Constraints)
+}
+
+func (c *CommandEnumConstraint) marshalWriter(r *Writer) {
+	r.Uint32(&c.EnumValueIndex)
+	r.Uint32(&c.EnumIndex)
+	r.ByteSlice(&c.Constraints)
+}
+
+func (x *CommandOutputMessage) marshalReader(r *Reader) {
+	r.Bool(&x.Success)
+	r.String(&x.Message)
+	FuncSlice(r, &x.Parameters, r.String)
+}
+
+func (x *CommandOutputMessage) marshalWriter(r *Writer) {
+	r.Bool(&x.Success)
+	r.String(&x.Message)
+	FuncSlice(r, &x.Parameters, r.String)
+}
+
+func (c *CommandOverload) marshalReader(r *Reader) {
+	r.Bool(&c.Chaining)
+	Slice(r, &c.Parameters)
+}
+
+func (c *CommandOverload) marshalWriter(r *Writer) {
+	r.Bool(&c.Chaining)
+	Slice(r, &c.Parameters)
+}
+
+func (c *CommandParameter) marshalReader(r *Reader) {
+	r.String(&c.Name)
+	r.Uint32(&c.Type)
+	r.Bool(&c.Optional)
+	r.Uint8(&c.Options)
+}
+
+func (c *CommandParameter) marshalWriter(r *Writer) {
+	r.String(&c.Name)
+	r.Uint32(&c.Type)
+	r.Bool(&c.Optional)
+	r.Uint8(&c.Options)
+}
+
+func (x *ComplexAliasItemDescriptor) marshalReader(r *Reader) {
+	r.String(&x.Name)
+}
+
+func (x *ComplexAliasItemDescriptor) marshalWriter(r *Writer) {
+	r.String(&x.Name)
+}
+
+func (c *ComposterInteractEvent) marshalReader(r *Reader) {
+	r.Varint32(&c.BlockInteractionType)
+	r.Varint32(&c.ItemID)
+}
+
+func (c *ComposterInteractEvent) marshalWriter(r *Writer) {
+	r.Varint32(&c.BlockInteractionType)
+	r.Varint32(&c.ItemID)
+}
+
+func (a *CraftCreativeStackRequestAction) marshalReader(r *Reader) {
+	r.Varuint32(&a.CreativeItemNetworkID)
+}
+
+func (a *CraftCreativeStackRequestAction) marshalWriter(r *Writer) {
+	r.Varuint32(&a.CreativeItemNetworkID)
+}
+
+func (c *CraftGrindstoneRecipeStackRequestAction) marshalReader(r *Reader) {
+	r.Varuint32(&c.RecipeNetworkID)
+	r.Varint32(&c.Cost)
+}
+
+func (c *CraftGrindstoneRecipeStackRequestAction) marshalWriter(r *Writer) {
+	r.Varuint32(&c.RecipeNetworkID)
+	r.Varint32(&c.Cost)
+}
+
+func (c *CraftLoomRecipeStackRequestAction) marshalReader(r *Reader) {
+	r.String(&c.Pattern)
+}
+
+func (c *CraftLoomRecipeStackRequestAction) marshalWriter(r *Writer) {
+	r.String(&c.Pattern)
+}
+
+func (c *CraftRecipeOptionalStackRequestAction) marshalReader(r *Reader) {
+	r.Varuint32(&c.RecipeNetworkID)
+	r.Int32(&c.FilterStringIndex)
+}
+
+func (c *CraftRecipeOptionalStackRequestAction) marshalWriter(r *Writer) {
+	r.Varuint32(&c.RecipeNetworkID)
+	r.Int32(&c.FilterStringIndex)
+}
+
+func (a *CraftRecipeStackRequestAction) marshalReader(r *Reader) {
+	r.Varuint32(&a.RecipeNetworkID)
+}
+
+func (a *CraftRecipeStackRequestAction) marshalWriter(r *Writer) {
+	r.Varuint32(&a.RecipeNetworkID)
+}
+
+func (a *CraftResultsDeprecatedStackRequestAction) marshalReader(r *Reader) {
+	FuncSlice(r, &a.ResultItems, r.Item)
+	r.Uint8(&a.TimesCrafted)
+}
+
+func (a *CraftResultsDeprecatedStackRequestAction) marshalWriter(r *Writer) {
+	FuncSlice(r, &a.ResultItems, r.Item)
+	r.Uint8(&a.TimesCrafted)
+}
+
+func (a *CreateStackRequestAction) marshalReader(r *Reader) {
+	r.Uint8(&a.ResultsSlot)
+}
+
+func (a *CreateStackRequestAction) marshalWriter(r *Writer) {
+	r.Uint8(&a.ResultsSlot)
+}
+
+func (x *CreativeItem) marshalReader(r *Reader) {
+	r.Varuint32(&x.CreativeItemNetworkID)
+	r.Item(&x.Item)
+}
+
+func (x *CreativeItem) marshalWriter(r *Writer) {
+	r.Varuint32(&x.CreativeItemNetworkID)
+	r.Item(&x.Item)
+}
+
+func (x *DefaultItemDescriptor) marshalReader(r *Reader) {
+	r.Int16(&x.NetworkID)
+	if x.NetworkID != 0 {
+		r.Int16(&x.MetadataValue)
+	}
+}
+
+func (x *DefaultItemDescriptor) marshalWriter(r *Writer) {
+	r.Int16(&x.NetworkID)
+	if x.NetworkID != 0 {
+		r.Int16(&x.MetadataValue)
+	}
+}
+
+func (x *DeferredItemDescriptor) marshalReader(r *Reader) {
+	r.String(&x.Name)
+	r.Int16(&x.MetadataValue)
+}
+
+func (x *DeferredItemDescriptor) marshalWriter(r *Writer) {
+	r.String(&x.Name)
+	r.Int16(&x.MetadataValue)
+}
+
+func (a *DestroyStackRequestAction) marshalReader(r *Reader) {
+	r.Uint8(&a.Count)
+	StackReqSlotInfo(r, &a.Source)
+}
+
+func (a *DestroyStackRequestAction) marshalWriter(r *Writer) {
+	r.Uint8(&a.Count)
+	StackReqSlotInfo(r, &a.Source)
+}
+
+func (x *DimensionDefinition) marshalReader(r *Reader) {
+	r.String(&x.Name)
+	r.Varint32(&x.Range[0])
+	r.Varint32(&x.Range[1])
+	r.Varint32(&x.Generator)
+}
+
+func (x *DimensionDefinition) marshalWriter(r *Writer) {
+	r.String(&x.Name)
+	r.Varint32(&x.Range[0])
+	r.Varint32(&x.Range[1])
+	r.Varint32(&x.Generator)
+}
+
+func (a *DropStackRequestAction) marshalReader(r *Reader) {
+	r.Uint8(&a.Count)
+	StackReqSlotInfo(r, &a.Source)
+	r.Bool(&a.Randomly)
+}
+
+func (a *DropStackRequestAction) marshalWriter(r *Writer) {
+	r.Uint8(&a.Count)
+	StackReqSlotInfo(r, &a.Source)
+	r.Bool(&a.Randomly)
+}
+
+func (x *DynamicEnum) marshalReader(r *Reader) {
+	r.String(&x.Type)
+	FuncSlice(r, &x.Values, r.String)
+}
+
+func (x *DynamicEnum) marshalWriter(r *Writer) {
+	r.String(&x.Type)
+	FuncSlice(r, &x.Values, r.String)
+}
+
+func (x *EducationExternalLinkSettings) marshalReader(r *Reader) {
+	r.String(&x.URL)
+	r.String(&x.DisplayName)
+}
+
+func (x *EducationExternalLinkSettings) marshalWriter(r *Writer) {
+	r.String(&x.URL)
+	r.String(&x.DisplayName)
+}
+
+func (x *EducationSharedResourceURI) marshalReader(r *Reader) {
+	r.String(&x.ButtonName)
+	r.String(&x.LinkURI)
+}
+
+func (x *EducationSharedResourceURI) marshalWriter(r *Writer) {
+	r.String(&x.ButtonName)
+	r.String(&x.LinkURI)
+}
+
+func (x *EnchantmentInstance) marshalReader(r *Reader) {
+	r.Uint8(&x.Type)
+	r.Uint8(&x.Level)
+}
+
+func (x *EnchantmentInstance) marshalWriter(r *Writer) {
+	r.Uint8(&x.Type)
+	r.Uint8(&x.Level)
+}
+
+func (x *EnchantmentOption) marshalReader(r *Reader) {
+	r.Varuint32(&x.Cost)
+	Single(r, &x.Enchantments)
+	r.String(&x.Name)
+	r.Varuint32(&x.RecipeNetworkID)
+}
+
+func (x *EnchantmentOption) marshalWriter(r *Writer) {
+	r.Varuint32(&x.Cost)
+	Single(r, &x.Enchantments)
+	r.String(&x.Name)
+	r.Varuint32(&x.RecipeNetworkID)
+}
+
+func (e *EntityDefinitionTriggerEvent) marshalReader(r *Reader) {
+	r.String(&e.EventName)
+}
+
+func (e *EntityDefinitionTriggerEvent) marshalWriter(r *Writer) {
+	r.String(&e.EventName)
+}
+
+func (e *EntityInteractEvent) marshalReader(r *Reader) {
+	r.Varint32(&e.InteractionType)
+	r.Varint32(&e.InteractionEntityType)
+	r.Varint32(&e.EntityVariant)
+	r.Uint8(&e.EntityColour)
+}
+
+func (e *EntityInteractEvent) marshalWriter(r *Writer) {
+	r.Varint32(&e.InteractionType)
+	r.Varint32(&e.InteractionEntityType)
+	r.Varint32(&e.EntityVariant)
+	r.Uint8(&e.EntityColour)
+}
+
+func (x *EntityLink) marshalReader(r *Reader) {
+	r.Varint64(&x.RiddenEntityUniqueID)
+	r.Varint64(&x.RiderEntityUniqueID)
+	r.Uint8(&x.Type)
+	r.Bool(&x.Immediate)
+	r.Bool(&x.RiderInitiated)
+}
+
+func (x *EntityLink) marshalWriter(r *Writer) {
+	r.Varint64(&x.RiddenEntityUniqueID)
+	r.Varint64(&x.RiderEntityUniqueID)
+	r.Uint8(&x.Type)
+	r.Bool(&x.Immediate)
+	r.Bool(&x.RiderInitiated)
+}
+
+func (x *ExperimentData) marshalReader(r *Reader) {
+	r.String(&x.Name)
+	r.Bool(&x.Enabled)
+}
+
+func (x *ExperimentData) marshalWriter(r *Writer) {
+	r.String(&x.Name)
+	r.Bool(&x.Enabled)
+}
+
+func (f *FishBucketedEvent) marshalReader(r *Reader) {
+	r.Varint32(&f.Pattern)
+	r.Varint32(&f.Preset)
+	r.Varint32(&f.BucketedEntityType)
+	r.Bool(&f.Release)
+}
+
+func (f *FishBucketedEvent) marshalWriter(r *Writer) {
+	r.Varint32(&f.Pattern)
+	r.Varint32(&f.Preset)
+	r.Varint32(&f.BucketedEntityType)
+	r.Bool(&f.Release)
+}
+
+func (x *GenerationFeature) marshalReader(r *Reader) {
+	r.String(&x.Name)
+	r.ByteSlice(&x.JSON)
+}
+
+func (x *GenerationFeature) marshalWriter(r *Writer) {
+	r.String(&x.Name)
+	r.ByteSlice(&x.JSON)
+}
+
+func (x *InventoryAction) marshalReader(r *Reader) {
+	r.Varuint32(&x.SourceType)
+	switch x.SourceType {
+	case InventoryActionSourceContainer, InventoryActionSourceTODO:
+		r.Varint32(&x.WindowID)
+	case InventoryActionSourceWorld:
+		r.Varuint32(&x.SourceFlags)
+	}
+	r.Varuint32(&x.InventorySlot)
+	r.ItemInstance(&x.OldItem)
+	r.ItemInstance(&x.NewItem)
+}
+
+func (x *InventoryAction) marshalWriter(r *Writer) {
+	r.Varuint32(&x.SourceType)
+	switch x.SourceType {
+	case InventoryActionSourceContainer, InventoryActionSourceTODO:
+		r.Varint32(&x.WindowID)
+	case InventoryActionSourceWorld:
+		r.Varuint32(&x.SourceFlags)
+	}
+	r.Varuint32(&x.InventorySlot)
+	r.ItemInstance(&x.OldItem)
+	r.ItemInstance(&x.NewItem)
+}
+
+func (x *ItemComponentEntry) marshalReader(r *Reader) {
+	r.String(&x.Name)
+	r.NBT(&x.Data, nbt.NetworkLittleEndian)
+}
+
+func (x *ItemComponentEntry) marshalWriter(r *Writer) {
+	r.String(&x.Name)
+	r.NBT(&x.Data, nbt.NetworkLittleEndian)
+}
+
+func (x *ItemEnchantments) marshalReader(r *Reader) {
+	r.Int32(&x.Slot)
+	for i := 0; i < 3; i++ {
+		Slice(r, &x.Enchantments[i])
+	}
+}
+
+func (x *ItemEnchantments) marshalWriter(r *Writer) {
+	r.Int32(&x.Slot)
+	for i := 0; i < 3; i++ {
+		Slice(r, &x.Enchantments[i])
+	}
+}
+
+func (x *ItemEntry) marshalReader(r *Reader) {
+	r.String(&x.Name)
+	r.Int16(&x.RuntimeID)
+	r.Bool(&x.ComponentBased)
+}
+
+func (x *ItemEntry) marshalWriter(r *Writer) {
+	r.String(&x.Name)
+	r.Int16(&x.RuntimeID)
+	r.Bool(&x.ComponentBased)
+}
+
+func (x *ItemStackRequest) marshalReader(r *Reader) {
+	r.Varint32(&x.RequestID)
+	FuncSlice(r, &x.Actions, r.StackRequestAction)
+	FuncSlice(r, &x.FilterStrings, r.String)
+	r.Int32(&x.FilterCause)
+}
+
+func (x *ItemStackRequest) marshalWriter(r *Writer) {
+	r.Varint32(&x.RequestID)
+	FuncSlice(r, &x.Actions, r.StackRequestAction)
+	FuncSlice(r, &x.FilterStrings, r.String)
+	r.Int32(&x.FilterCause)
+}
+
+func (x *ItemStackResponse) marshalReader(r *Reader) {
+	r.Uint8(&x.Status)
+	r.Varint32(&x.RequestID)
+	if x.Status == ItemStackResponseStatusOK {
+		Slice(r, &x.ContainerInfo)
+	}
+}
+
+func (x *ItemStackResponse) marshalWriter(r *Writer) {
+	r.Uint8(&x.Status)
+	r.Varint32(&x.RequestID)
+	if x.Status == ItemStackResponseStatusOK {
+		Slice(r, &x.ContainerInfo)
+	}
+}
+
+func (x *ItemTagItemDescriptor) marshalReader(r *Reader) {
+	r.String(&x.Tag)
+}
+
+func (x *ItemTagItemDescriptor) marshalWriter(r *Writer) {
+	r.String(&x.Tag)
+}
+
+func (i *ItemUsedEvent) marshalReader(r *Reader) {
+	r.Int16(&i.ItemID)
+	r.Varint32(&i.ItemAux)
+	r.Varint32(&i.UseMethod)
+	r.Varint32(&i.UseCount)
+}
+
+func (i *ItemUsedEvent) marshalWriter(r *Writer) {
+	r.Int16(&i.ItemID)
+	r.Varint32(&i.ItemAux)
+	r.Varint32(&i.UseMethod)
+	r.Varint32(&i.UseCount)
+}
+
+func (x *LegacySetItemSlot) marshalReader(r *Reader) {
+	r.Uint8(&x.ContainerID)
+	r.ByteSlice(&x.Slots)
+}
+
+func (x *LegacySetItemSlot) marshalWriter(r *Writer) {
+	r.Uint8(&x.ContainerID)
+	r.ByteSlice(&x.Slots)
+}
+
+func (x *MapDecoration) marshalReader(r *Reader) {
+	r.Uint8(&x.Type)
+	r.Uint8(&x.Rotation)
+	r.Uint8(&x.X)
+	r.Uint8(&x.Y)
+	r.String(&x.Label)
+	r.VarRGBA(&x.Colour)
+}
+
+func (x *MapDecoration) marshalWriter(r *Writer) {
+	r.Uint8(&x.Type)
+	r.Uint8(&x.Rotation)
+	r.Uint8(&x.X)
+	r.Uint8(&x.Y)
+	r.String(&x.Label)
+	r.VarRGBA(&x.Colour)
+}
+
+func (x *MapTrackedObject) marshalReader(r *Reader) {
+	r.Int32(&x.Type)
+	switch x.Type {
+	case MapObjectTypeEntity:
+		r.Varint64(&x.EntityUniqueID)
+	case MapObjectTypeBlock:
+		r.UBlockPos(&x.BlockPosition)
+	default:
+		r.UnknownEnumOption(x.Type, "map tracked object type")
+	}
+}
+
+func (x *MapTrackedObject) marshalWriter(r *Writer) {
+	r.Int32(&x.Type)
+	switch x.Type {
+	case MapObjectTypeEntity:
+		r.Varint64(&x.EntityUniqueID)
+	case MapObjectTypeBlock:
+		r.UBlockPos(&x.BlockPosition)
+	default:
+		r.UnknownEnumOption(x.Type, "map tracked object type")
+	}
+}
+
+func (x *MaterialReducerOutput) marshalReader(r *Reader) {
+	r.Varint32(&x.NetworkID)
+	r.Varint32(&x.Count)
+}
+
+func (x *MaterialReducerOutput) marshalWriter(r *Writer) {
+	r.Varint32(&x.NetworkID)
+	r.Varint32(&x.Count)
+}
+
+func (a *MineBlockStackRequestAction) marshalReader(r *Reader) {
+	r.Varint32(&a.HotbarSlot)
+	r.Varint32(&a.PredictedDurability)
+	r.Varint32(&a.StackNetworkID)
+}
+
+func (a *MineBlockStackRequestAction) marshalWriter(r *Writer) {
+	r.Varint32(&a.HotbarSlot)
+	r.Varint32(&a.PredictedDurability)
+	r.Varint32(&a.StackNetworkID)
+}
+
+func (x *MoLangItemDescriptor) marshalReader(r *Reader) {
+	r.String(&x.Expression)
+	r.Uint8(&x.Version)
+}
+
+func (x *MoLangItemDescriptor) marshalWriter(r *Writer) {
+	r.String(&x.Expression)
+	r.Uint8(&x.Version)
+}
+
+func (m *MobBornEvent) marshalReader(r *Reader) {
+	r.Varint32(&m.EntityType)
+	r.Varint32(&m.Variant)
+	r.Uint8(&m.Colour)
+}
+
+func (m *MobBornEvent) marshalWriter(r *Writer) {
+	r.Varint32(&m.EntityType)
+	r.Varint32(&m.Variant)
+	r.Uint8(&m.Colour)
+}
+
+func (m *MobKilledEvent) marshalReader(r *Reader) {
+	r.Varint64(&m.KillerEntityUniqueID)
+	r.Varint64(&m.VictimEntityUniqueID)
+	r.Varint32(&m.KillerEntityType)
+	r.Varint32(&m.EntityDamageCause)
+	r.Varint32(&m.VillagerTradeTier)
+	r.String(&m.VillagerDisplayName)
+}
+
+func (m *MobKilledEvent) marshalWriter(r *Writer) {
+	r.Varint64(&m.KillerEntityUniqueID)
+	r.Varint64(&m.VictimEntityUniqueID)
+	r.Varint32(&m.KillerEntityType)
+	r.Varint32(&m.EntityDamageCause)
+	r.Varint32(&m.VillagerTradeTier)
+	r.String(&m.VillagerDisplayName)
+}
+
+func (m *MovementAnomalyEvent) marshalReader(r *Reader) {
+	r.Uint8(&m.EventType)
+	r.Float32(&m.CheatingScore)
+	r.Float32(&m.AveragePositionDelta)
+	r.Float32(&m.TotalPositionDelta)
+	r.Float32(&m.MinPositionDelta)
+	r.Float32(&m.MaxPositionDelta)
+}
+
+func (m *MovementAnomalyEvent) marshalWriter(r *Writer) {
+	r.Uint8(&m.EventType)
+	r.Float32(&m.CheatingScore)
+	r.Float32(&m.AveragePositionDelta)
+	r.Float32(&m.TotalPositionDelta)
+	r.Float32(&m.MinPositionDelta)
+	r.Float32(&m.MaxPositionDelta)
+}
+
+func (m *MovementCorrectedEvent) marshalReader(r *Reader) {
+	r.Float32(&m.PositionDelta)
+	r.Float32(&m.CheatingScore)
+	r.Float32(&m.ScoreThreshold)
+	r.Float32(&m.DistanceThreshold)
+	r.Varint32(&m.DurationThreshold)
+}
+
+func (m *MovementCorrectedEvent) marshalWriter(r *Writer) {
+	r.Float32(&m.PositionDelta)
+	r.Float32(&m.CheatingScore)
+	r.Float32(&m.ScoreThreshold)
+	r.Float32(&m.DistanceThreshold)
+	r.Varint32(&m.DurationThreshold)
+}
+
+func (x *PackURL) marshalReader(r *Reader) {
+	r.String(&x.UUIDVersion)
+	r.String(&x.URL)
+}
+
+func (x *PackURL) marshalWriter(r *Writer) {
+	r.String(&x.UUIDVersion)
+	r.String(&x.URL)
+}
+
+func (p *PatternRemovedEvent) marshalReader(r *Reader) {
+	r.Varint32(&p.ItemID)
+	r.Varint32(&p.AuxValue)
+	r.Varint32(&p.PatternsSize)
+	r.Varint32(&p.PatternIndex)
+	r.Varint32(&p.PatternColour)
+}
+
+func (p *PatternRemovedEvent) marshalWriter(r *Writer) {
+	r.Varint32(&p.ItemID)
+	r.Varint32(&p.AuxValue)
+	r.Varint32(&p.PatternsSize)
+	r.Varint32(&p.PatternIndex)
+	r.Varint32(&p.PatternColour)
+}
+
+func (x *PersonaPiece) marshalReader(r *Reader) {
+	r.String(&x.PieceID)
+	r.String(&x.PieceType)
+	r.String(&x.PackID)
+	r.Bool(&x.Default)
+	r.String(&x.ProductID)
+}
+
+func (x *PersonaPiece) marshalWriter(r *Writer) {
+	r.String(&x.PieceID)
+	r.String(&x.PieceType)
+	r.String(&x.PackID)
+	r.Bool(&x.Default)
+	r.String(&x.ProductID)
+}
+
+func (x *PersonaPieceTintColour) marshalReader(r *Reader) {
+	r.String(&x.PieceType)
+	FuncSliceUint32Length(r, &x.Colours, r.String)
+}
+
+func (x *PersonaPieceTintColour) marshalWriter(r *Writer) {
+	r.String(&x.PieceType)
+	FuncSliceUint32Length(r, &x.Colours, r.String)
+}
+
+func (p *PetDiedEvent) marshalReader(r *Reader) {
+	r.Bool(&p.KilledByOwner)
+	r.Varint64(&p.KillerEntityUniqueID)
+	r.Varint64(&p.PetEntityUniqueID)
+	r.Varint32(&p.EntityDamageCause)
+	r.Varint32(&p.PetEntityType)
+}
+
+func (p *PetDiedEvent) marshalWriter(r *Writer) {
+	r.Bool(&p.KilledByOwner)
+	r.Varint64(&p.KillerEntityUniqueID)
+	r.Varint64(&p.PetEntityUniqueID)
+	r.Varint32(&p.EntityDamageCause)
+	r.Varint32(&p.PetEntityType)
+}
+
+func (x *PixelRequest) marshalReader(r *Reader) {
+	r.RGBA(&x.Colour)
+	r.Uint16(&x.Index)
+}
+
+func (x *PixelRequest) marshalWriter(r *Writer) {
+	r.RGBA(&x.Colour)
+	r.Uint16(&x.Index)
+}
+
+func (x *PlayerBlockAction) marshalReader(r *Reader) {
+	r.Varint32(&x.Action)
+	switch x.Action {
+	case PlayerActionStartBreak, PlayerActionAbortBreak, PlayerActionCrackBreak, PlayerActionPredictDestroyBlock, PlayerActionContinueDestroyBlock:
+		r.BlockPos(&x.BlockPos)
+		r.Varint32(&x.Face)
+	}
+}
+
+func (x *PlayerBlockAction) marshalWriter(r *Writer) {
+	r.Varint32(&x.Action)
+	switch x.Action {
+	case PlayerActionStartBreak, PlayerActionAbortBreak, PlayerActionCrackBreak, PlayerActionPredictDestroyBlock, PlayerActionContinueDestroyBlock:
+		r.BlockPos(&x.BlockPos)
+		r.Varint32(&x.Face)
+	}
+}
+
+func (p *PlayerDiedEvent) marshalReader(r *Reader) {
+	r.Varint32(&p.AttackerEntityID)
+	r.Varint32(&p.AttackerVariant)
+	r.Varint32(&p.EntityDamageCause)
+	r.Bool(&p.InRaid)
+}
+
+func (p *PlayerDiedEvent) marshalWriter(r *Writer) {
+	r.Varint32(&p.AttackerEntityID)
+	r.Varint32(&p.AttackerVariant)
+	r.Varint32(&p.EntityDamageCause)
+	r.Bool(&p.InRaid)
+}
+
+func (x *PlayerListEntry) marshalReader(r *Reader) {
+	r.UUID(&x.UUID)
+	r.Varint64(&x.EntityUniqueID)
+	r.String(&x.Username)
+	r.String(&x.XUID)
+	r.String(&x.PlatformChatID)
+	r.Int32(&x.BuildPlatform)
+	Single(r, &x.Skin)
+	r.Bool(&x.Teacher)
+	r.Bool(&x.Host)
+	r.Bool(&x.SubClient)
+}
+
+func (x *PlayerListEntry) marshalWriter(r *Writer) {
+	r.UUID(&x.UUID)
+	r.Varint64(&x.EntityUniqueID)
+	r.String(&x.Username)
+	r.String(&x.XUID)
+	r.String(&x.PlatformChatID)
+	r.Int32(&x.BuildPlatform)
+	Single(r, &x.Skin)
+	r.Bool(&x.Teacher)
+	r.Bool(&x.Host)
+	r.Bool(&x.SubClient)
+}
+
+func (p *PortalBuiltEvent) marshalReader(r *Reader) {
+	r.Varint32(&p.DimensionID)
+}
+
+func (p *PortalBuiltEvent) marshalWriter(r *Writer) {
+	r.Varint32(&p.DimensionID)
+}
+
+func (p *PortalUsedEvent) marshalReader(r *Reader) {
+	r.Varint32(&p.FromDimensionID)
+	r.Varint32(&p.ToDimensionID)
+}
+
+func (p *PortalUsedEvent) marshalWriter(r *Writer) {
+	r.Varint32(&p.FromDimensionID)
+	r.Varint32(&p.ToDimensionID)
+}
+
+func (x *PotionContainerChangeRecipe) marshalReader(r *Reader) {
+	r.Varint32(&x.InputItemID)
+	r.Varint32(&x.ReagentItemID)
+	r.Varint32(&x.OutputItemID)
+}
+
+func (x *PotionContainerChangeRecipe) marshalWriter(r *Writer) {
+	r.Varint32(&x.InputItemID)
+	r.Varint32(&x.ReagentItemID)
+	r.Varint32(&x.OutputItemID)
+}
+
+func (x *PotionRecipe) marshalReader(r *Reader) {
+	r.Varint32(&x.InputPotionID)
+	r.Varint32(&x.InputPotionMetadata)
+	r.Varint32(&x.ReagentItemID)
+	r.Varint32(&x.ReagentItemMetadata)
+	r.Varint32(&x.OutputPotionID)
+	r.Varint32(&x.OutputPotionMetadata)
+}
+
+func (x *PotionRecipe) marshalWriter(r *Writer) {
+	r.Varint32(&x.InputPotionID)
+	r.Varint32(&x.InputPotionMetadata)
+	r.Varint32(&x.ReagentItemID)
+	r.Varint32(&x.ReagentItemMetadata)
+	r.Varint32(&x.OutputPotionID)
+	r.Varint32(&x.OutputPotionMetadata)
+}
+
+func (ra *RaidUpdateEvent) marshalReader(r *Reader) {
+	r.Varint32(&ra.CurrentRaidWave)
+	r.Varint32(&ra.TotalRaidWaves)
+	r.Bool(&ra.WonRaid)
+}
+
+func (ra *RaidUpdateEvent) marshalWriter(r *Writer) {
+	r.Varint32(&ra.CurrentRaidWave)
+	r.Varint32(&ra.TotalRaidWaves)
+	r.Bool(&ra.WonRaid)
+}
+
+func (x *RecipeUnlockRequirement) marshalReader(r *Reader) {
+	r.Uint8(&x.Context)
+	if x.Context == RecipeUnlockContextNone {
+		FuncSlice(r, &x.Ingredients, r.ItemDescriptorCount)
+	}
+}
+
+func (x *RecipeUnlockRequirement) marshalWriter(r *Writer) {
+	r.Uint8(&x.Context)
+	if x.Context == RecipeUnlockContextNone {
+		FuncSlice(r, &x.Ingredients, r.ItemDescriptorCount)
+	}
+}
+
+func (data *ReleaseItemTransactionData) marshalReader(r *Reader) {
+	r.Varuint32(&data.ActionType)
+	r.Varint32(&data.HotBarSlot)
+	r.ItemInstance(&data.HeldItem)
+	r.Vec3(&data.HeadPosition)
+}
+
+func (data *ReleaseItemTransactionData) marshalWriter(r *Writer) {
+	r.Varuint32(&data.ActionType)
+	r.Varint32(&data.HotBarSlot)
+	r.ItemInstance(&data.HeldItem)
+	r.Vec3(&data.HeadPosition)
+}
+
+func (x *ScoreboardEntry) marshalReader(r *Reader) {
+	ScoreRemoveEntry(r, x)
+	r.Uint8(&x.IdentityType)
+	switch x.IdentityType {
+	case ScoreboardIdentityEntity, ScoreboardIdentityPlayer:
+		r.Varint64(&x.EntityUniqueID)
+	case ScoreboardIdentityFakePlayer:
+		r.String(&x.DisplayName)
+	default:
+		r.UnknownEnumOption(x.IdentityType, "scoreboard entry identity type")
+	}
+}
+
+func (x *ScoreboardEntry) marshalWriter(r *Writer) {
+	ScoreRemoveEntry(r, x)
+	r.Uint8(&x.IdentityType)
+	switch x.IdentityType {
+	case ScoreboardIdentityEntity, ScoreboardIdentityPlayer:
+		r.Varint64(&x.EntityUniqueID)
+	case ScoreboardIdentityFakePlayer:
+		r.String(&x.DisplayName)
+	default:
+		r.UnknownEnumOption(x.IdentityType, "scoreboard entry identity type")
+	}
+}
+
+func (x *ScoreboardIdentityEntry) marshalReader(r *Reader) {
+	r.Varint64(&x.EntryID)
+	r.Varint64(&x.EntityUniqueID)
+}
+
+func (x *ScoreboardIdentityEntry) marshalWriter(r *Writer) {
+	r.Varint64(&x.EntryID)
+	r.Varint64(&x.EntityUniqueID)
+}
+
+func (x *Skin) marshalReader(r *Reader) {
+	r.String(&x.SkinID)
+	r.String(&x.PlayFabID)
+	r.ByteSlice(&x.SkinResourcePatch)
+	r.Uint32(&x.SkinImageWidth)
+	r.Uint32(&x.SkinImageHeight)
+	r.ByteSlice(&x.SkinData)
+	SliceUint32Length(r, &x.Animations)
+	r.Uint32(&x.CapeImageWidth)
+	r.Uint32(&x.CapeImageHeight)
+	r.ByteSlice(&x.CapeData)
+	r.ByteSlice(&x.SkinGeometry)
+	r.ByteSlice(&x.GeometryDataEngineVersion)
+	r.ByteSlice(&x.AnimationData)
+	r.String(&x.CapeID)
+	r.String(&x.FullID)
+	r.String(&x.ArmSize)
+	r.String(&x.SkinColour)
+	SliceUint32Length(r, &x.PersonaPieces)
+	SliceUint32Length(r, &x.PieceTintColours)
+
+	if len(x.SkinData) > int(x.SkinImageHeight*x.SkinImageWidth*4) {
+		width_height := uint32(math.Sqrt(float64(len(x.SkinData) / 4)))
+		x.SkinImageWidth = width_height
+		x.SkinImageHeight = width_height
+		log.Printf("Warn: Skin %v server sent invalid width and height, fixed internally", x.SkinID)
+	}
+
+	cape_expected_length := int(x.CapeImageHeight * x.CapeImageWidth * 4)
+	if len(x.CapeData) > cape_expected_length {
+		x.CapeData = x.CapeData[:cape_expected_length]
+	}
+
+	if err := x.validate(); err != nil {
+		r.InvalidValue(fmt.Sprintf("Skin %v", x.SkinID), "serialised skin", err.Error())
+	}
+	r.Bool(&x.PremiumSkin)
+	r.Bool(&x.PersonaSkin)
+	r.Bool(&x.PersonaCapeOnClassicSkin)
+	r.Bool(&x.PrimaryUser)
+	r.Bool(&x.OverrideAppearance)
+}
+
+func (x *Skin) marshalWriter(r *Writer) {
+	r.String(&x.SkinID)
+	r.String(&x.PlayFabID)
+	r.ByteSlice(&x.SkinResourcePatch)
+	r.Uint32(&x.SkinImageWidth)
+	r.Uint32(&x.SkinImageHeight)
+	r.ByteSlice(&x.SkinData)
+	SliceUint32Length(r, &x.Animations)
+	r.Uint32(&x.CapeImageWidth)
+	r.Uint32(&x.CapeImageHeight)
+	r.ByteSlice(&x.CapeData)
+	r.ByteSlice(&x.SkinGeometry)
+	r.ByteSlice(&x.GeometryDataEngineVersion)
+	r.ByteSlice(&x.AnimationData)
+	r.String(&x.CapeID)
+	r.String(&x.FullID)
+	r.String(&x.ArmSize)
+	r.String(&x.SkinColour)
+	SliceUint32Length(r, &x.PersonaPieces)
+	SliceUint32Length(r, &x.PieceTintColours)
+
+	if len(x.SkinData) > int(x.SkinImageHeight*x.SkinImageWidth*4) {
+		width_height := uint32(math.Sqrt(float64(len(x.SkinData) / 4)))
+		x.SkinImageWidth = width_height
+		x.SkinImageHeight = width_height
+		log.Printf("Warn: Skin %v server sent invalid width and height, fixed internally", x.SkinID)
+	}
+
+	cape_expected_length := int(x.CapeImageHeight * x.CapeImageWidth * 4)
+	if len(x.CapeData) > cape_expected_length {
+		x.CapeData = x.CapeData[:cape_expected_length]
+	}
+
+	if err := x.validate(); err != nil {
+		r.InvalidValue(fmt.Sprintf("Skin %v", x.SkinID), "serialised skin", err.Error())
+	}
+	r.Bool(&x.PremiumSkin)
+	r.Bool(&x.PersonaSkin)
+	r.Bool(&x.PersonaCapeOnClassicSkin)
+	r.Bool(&x.PrimaryUser)
+	r.Bool(&x.OverrideAppearance)
+}
+
+func (x *SkinAnimation) marshalReader(r *Reader) {
+	r.Uint32(&x.ImageWidth)
+	r.Uint32(&x.ImageHeight)
+	r.ByteSlice(&x.ImageData)
+	r.Uint32(&x.AnimationType)
+	r.Float32(&x.FrameCount)
+	r.Uint32(&x.ExpressionType)
+}
+
+func (x *SkinAnimation) marshalWriter(r *Writer) {
+	r.Uint32(&x.ImageWidth)
+	r.Uint32(&x.ImageHeight)
+	r.ByteSlice(&x.ImageData)
+	r.Uint32(&x.AnimationType)
+	r.Float32(&x.FrameCount)
+	r.Uint32(&x.ExpressionType)
+}
+
+func (s *SlashCommandExecutedEvent) marshalReader(r *Reader) {
+	r.Varint32(&s.SuccessCount)
+	r.Varint32(&s.MessageCount)
+	r.String(&s.CommandName)
+	r.String(&s.OutputMessages)
+}
+
+func (s *SlashCommandExecutedEvent) marshalWriter(r *Writer) {
+	r.Varint32(&s.SuccessCount)
+	r.Varint32(&s.MessageCount)
+	r.String(&s.CommandName)
+	r.String(&s.OutputMessages)
+}
+
+func (u *SneakCloseToSculkSensorEvent) marshalReader(r *Reader) {
+}
+
+func (u *SneakCloseToSculkSensorEvent) marshalWriter(r *Writer) {
+}
+
+func (x *StackResourcePack) marshalReader(r *Reader) {
+	r.String(&x.UUID)
+	r.String(&x.Version)
+	r.String(&x.SubPackName)
+}
+
+func (x *StackResourcePack) marshalWriter(r *Writer) {
+	r.String(&x.UUID)
+	r.String(&x.Version)
+	r.String(&x.SubPackName)
+}
+
+func (x *StackResponseContainerInfo) marshalReader(r *Reader) {
+	r.Uint8(&x.ContainerID)
+	Slice(r, &x.SlotInfo)
+}
+
+func (x *StackResponseContainerInfo) marshalWriter(r *Writer) {
+	r.Uint8(&x.ContainerID)
+	Slice(r, &x.SlotInfo)
+}
+
+func (x *StackResponseSlotInfo) marshalReader(r *Reader) {
+	r.Uint8(&x.Slot)
+	r.Uint8(&x.HotbarSlot)
+	r.Uint8(&x.Count)
+	r.Varint32(&x.StackNetworkID)
+	if x.Slot != x.HotbarSlot {
+		r.InvalidValue(x.HotbarSlot, "hotbar slot", "hot bar slot must be equal to normal slot")
+	}
+	r.String(&x.CustomName)
+	r.Varint32(&x.DurabilityCorrection)
+}
+
+func (x *StackResponseSlotInfo) marshalWriter(r *Writer) {
+	r.Uint8(&x.Slot)
+	r.Uint8(&x.HotbarSlot)
+	r.Uint8(&x.Count)
+	r.Varint32(&x.StackNetworkID)
+	if x.Slot != x.HotbarSlot {
+		r.InvalidValue(x.HotbarSlot, "hotbar slot", "hot bar slot must be equal to normal slot")
+	}
+	r.String(&x.CustomName)
+	r.Varint32(&x.DurabilityCorrection)
+}
+
+func (x *StructureSettings) marshalReader(r *Reader) {
+	r.String(&x.PaletteName)
+	r.Bool(&x.IgnoreEntities)
+	r.Bool(&x.IgnoreBlocks)
+	r.Bool(&x.AllowNonTickingChunks)
+	r.UBlockPos(&x.Size)
+	r.UBlockPos(&x.Offset)
+	r.Varint64(&x.LastEditingPlayerUniqueID)
+	r.Uint8(&x.Rotation)
+	r.Uint8(&x.Mirror)
+	r.Uint8(&x.AnimationMode)
+	r.Float32(&x.AnimationDuration)
+	r.Float32(&x.Integrity)
+	r.Uint32(&x.Seed)
+	r.Vec3(&x.Pivot)
+}
+
+func (x *StructureSettings) marshalWriter(r *Writer) {
+	r.String(&x.PaletteName)
+	r.Bool(&x.IgnoreEntities)
+	r.Bool(&x.IgnoreBlocks)
+	r.Bool(&x.AllowNonTickingChunks)
+	r.UBlockPos(&x.Size)
+	r.UBlockPos(&x.Offset)
+	r.Varint64(&x.LastEditingPlayerUniqueID)
+	r.Uint8(&x.Rotation)
+	r.Uint8(&x.Mirror)
+	r.Uint8(&x.AnimationMode)
+	r.Float32(&x.AnimationDuration)
+	r.Float32(&x.Integrity)
+	r.Uint32(&x.Seed)
+	r.Vec3(&x.Pivot)
+}
+
+func (x *SubChunkEntry) marshalReader(r *Reader) {
+	Single(r, &x.Offset)
+	r.Uint8(&x.Result)
+	if x.Result != SubChunkResultSuccessAllAir {
+		r.ByteSlice(&x.RawPayload)
+	}
+	r.Uint8(&x.HeightMapType)
+	if x.HeightMapType == HeightMapDataHasData {
+		FuncSliceOfLen(r, 256, &x.HeightMapData, r.Int8)
+	}
+	r.Uint64(&x.BlobHash)
+}
+
+func (x *SubChunkEntry) marshalWriter(r *Writer) {
+	Single(r, &x.Offset)
+	r.Uint8(&x.Result)
+	if x.Result != SubChunkResultSuccessAllAir {
+		r.ByteSlice(&x.RawPayload)
+	}
+	r.Uint8(&x.HeightMapType)
+	if x.HeightMapType == HeightMapDataHasData {
+		FuncSliceOfLen(r, 256, &x.HeightMapData, r.Int8)
+	}
+	r.Uint64(&x.BlobHash)
+}
+
+func (x *SubChunkOffset) marshalReader(r *Reader) {
+	r.Int8(&x[0])
+	r.Int8(&x[1])
+	r.Int8(&x[2])
+}
+
+func (x *SubChunkOffset) marshalWriter(r *Writer) {
+	r.Int8(&x[0])
+	r.Int8(&x[1])
+	r.Int8(&x[2])
+}
+
+func (a *SwapStackRequestAction) marshalReader(r *Reader) {
+	StackReqSlotInfo(r, &a.Source)
+	StackReqSlotInfo(r, &a.Destination)
+}
+
+func (a *SwapStackRequestAction) marshalWriter(r *Writer) {
+	StackReqSlotInfo(r, &a.Source)
+	StackReqSlotInfo(r, &a.Destination)
+}
+
+func (x *TexturePackInfo) marshalReader(r *Reader) {
+	r.String(&x.UUID)
+	r.String(&x.Version)
+	r.Uint64(&x.Size)
+	r.String(&x.ContentKey)
+	r.String(&x.SubPackName)
+	r.String(&x.ContentIdentity)
+	r.Bool(&x.HasScripts)
+	r.Bool(&x.RTXEnabled)
+}
+
+func (x *TexturePackInfo) marshalWriter(r *Writer) {
+	r.String(&x.UUID)
+	r.String(&x.Version)
+	r.Uint64(&x.Size)
+	r.String(&x.ContentKey)
+	r.String(&x.SubPackName)
+	r.String(&x.ContentIdentity)
+	r.Bool(&x.HasScripts)
+	r.Bool(&x.RTXEnabled)
+}
+
+func (x *TrimMaterial) marshalReader(r *Reader) {
+	r.String(&x.MaterialID)
+	r.String(&x.Colour)
+	r.String(&x.ItemName)
+}
+
+func (x *TrimMaterial) marshalWriter(r *Writer) {
+	r.String(&x.MaterialID)
+	r.String(&x.Colour)
+	r.String(&x.ItemName)
+}
+
+func (x *TrimPattern) marshalReader(r *Reader) {
+	r.String(&x.ItemName)
+	r.String(&x.PatternID)
+}
+
+func (x *TrimPattern) marshalWriter(r *Writer) {
+	r.String(&x.ItemName)
+	r.String(&x.PatternID)
+}
+
+func (data *UseItemOnEntityTransactionData) marshalReader(r *Reader) {
+	r.Varuint64(&data.TargetEntityRuntimeID)
+	r.Varuint32(&data.ActionType)
+	r.Varint32(&data.HotBarSlot)
+	r.ItemInstance(&data.HeldItem)
+	r.Vec3(&data.Position)
+	r.Vec3(&data.ClickedPosition)
+}
+
+func (data *UseItemOnEntityTransactionData) marshalWriter(r *Writer) {
+	r.Varuint64(&data.TargetEntityRuntimeID)
+	r.Varuint32(&data.ActionType)
+	r.Varint32(&data.HotBarSlot)
+	r.ItemInstance(&data.HeldItem)
+	r.Vec3(&data.Position)
+	r.Vec3(&data.ClickedPosition)
+}
+
+func (data *UseItemTransactionData) marshalReader(r *Reader) {
+	r.Varuint32(&data.ActionType)
+	r.UBlockPos(&data.BlockPosition)
+	r.Varint32(&data.BlockFace)
+	r.Varint32(&data.HotBarSlot)
+	r.ItemInstance(&data.HeldItem)
+	r.Vec3(&data.Position)
+	r.Vec3(&data.ClickedPosition)
+	r.Varuint32(&data.BlockRuntimeID)
+}
+
+func (data *UseItemTransactionData) marshalWriter(r *Writer) {
+	r.Varuint32(&data.ActionType)
+	r.UBlockPos(&data.BlockPosition)
+	r.Varint32(&data.BlockFace)
+	r.Varint32(&data.HotBarSlot)
+	r.ItemInstance(&data.HeldItem)
+	r.Vec3(&data.Position)
+	r.Vec3(&data.ClickedPosition)
+	r.Varuint32(&data.BlockRuntimeID)
+}
+
+func (w *WaxedOrUnwaxedCopperEvent) marshalReader(r *Reader) {
+	r.Uint16(&w.Type)
+}
+
+func (w *WaxedOrUnwaxedCopperEvent) marshalWriter(r *Writer) {
+	r.Uint16(&w.Type)
+}
+
+func (a *transferStackRequestAction) marshalReader(r *Reader) {
+	r.Uint8(&a.Count)
+	StackReqSlotInfo(r, &a.Source)
+	StackReqSlotInfo(r, &a.Destination)
+}
+
+func (a *transferStackRequestAction) marshalWriter(r *Writer) {
+	r.Uint8(&a.Count)
+	StackReqSlotInfo(r, &a.Source)
+	StackReqSlotInfo(r, &a.Destination)
+}
@@ -0,0 +1,58 @@
+//go:build protocolgen
+
+package protocol
+
+// readerMarshaler is implemented by types with a generated method for decoding using a *Reader.
+type readerMarshaler interface {
+	marshalReader(r *Reader)
+}
+
+// writerMarshaler is implemented by types with a generated method for encoding using a *Writer.
+type writerMarshaler interface {
+	marshalWriter(w *Writer)
+}
+
+// marshal marshals x using its generated methods if the IO is a *Reader or *Writer and x has them, or using
+// its Marshal method otherwise.
+func marshal[T any, A PtrMarshaler[T]](r IO, x A) {
+	switch r := r.(type) {
+	case *Reader:
+		if m, ok := any(x).(readerMarshaler); ok {
+			m.marshalReader(r)
+			return
+		}
+	case *Writer:
+		if m, ok := any(x).(writerMarshaler); ok {
+			m.marshalWriter(r)
+			return
+		}
+	}
+	x.Marshal(r)
+}
+
+// marshalSlice marshals the elements of the slice passed like marshal. Whether the elements have generated
+// methods is checked once for the whole slice, as all elements are of the same type.
+func marshalSlice[T any, A PtrMarshaler[T]](r IO, s []T) {
+	if len(s) == 0 {
+		return
+	}
+	switch r := r.(type) {
+	case *Reader:
+		if _, ok := any(A(&s[0])).(readerMarshaler); ok {
+			for i := range s {
+				any(A(&s[i])).(readerMarshaler).marshalReader(r)
+			}
+			return
+		}
+	case *Writer:
+		if _, ok := any(A(&s[0])).(writerMarshaler); ok {
+			for i := range s {
+				any(A(&s[i])).(writerMarshaler).marshalWriter(r)
+			}
+			return
+		}
+	}
+	for i := range s {
+		A(&s[i]).Marshal(r)
+	}
+}
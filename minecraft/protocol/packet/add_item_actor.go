@@ -27,7 +27,7 @@ type AddItemActor struct {
 	// EntityMetadata is a map of entity metadata, which includes flags and data properties that alter in
 	// particular the way the entity looks. Flags include ones such as 'on fire' and 'sprinting'.
 	// The metadata values are indexed by their property key.
-	EntityMetadata map[uint32]any
+	EntityMetadata protocol.EntityMetadata
 	// FromFishing specifies if the item was obtained by fishing it up using a fishing rod. It is not clear
 	// why the client needs to know this.
 	FromFishing bool
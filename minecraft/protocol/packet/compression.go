@@ -24,6 +24,9 @@ var (
 	// FlateCompression is the implementation of the Flate compression
 	// algorithm. This was used by default until v1.19.30.
 	FlateCompression flateCompression
+	// NopCompression is a Compression that leaves data uncompressed. It may be used to disable compression
+	// of packets entirely, trading bandwidth for CPU usage.
+	NopCompression nopCompression
 	// SnappyCompression is the implementation of the Snappy compression
 	// algorithm. This is used by default.
 	SnappyCompression snappyCompression
@@ -36,6 +39,8 @@ type (
 	flateCompression struct{}
 	// snappyCompression is the implementation of the Snappy compression algorithm. This is used by default.
 	snappyCompression struct{}
+	// nopCompression is a Compression that does not compress data at all.
+	nopCompression struct{}
 )
 
 // flateDecompressPool is a sync.Pool for io.ReadCloser flate readers. These are
@@ -127,10 +132,26 @@ func (snappyCompression) Decompress(compressed []byte) ([]byte, error) {
 	return decompressed, nil
 }
 
+// EncodeCompression ...
+func (nopCompression) EncodeCompression() uint16 {
+	return CompressionAlgorithmNone
+}
+
+// Compress ...
+func (nopCompression) Compress(decompressed []byte) ([]byte, error) {
+	return decompressed, nil
+}
+
+// Decompress ...
+func (nopCompression) Decompress(compressed []byte) ([]byte, error) {
+	return compressed, nil
+}
+
 // init registers all valid compressions with the protocol.
 func init() {
 	RegisterCompression(flateCompression{})
 	RegisterCompression(snappyCompression{})
+	RegisterCompression(nopCompression{})
 }
 
 var compressions = map[uint16]Compression{}
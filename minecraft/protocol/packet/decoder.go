@@ -77,7 +77,24 @@ const (
 // Decode decodes one 'packet' from the io.Reader passed in NewDecoder(), producing a slice of packets that it
 // held and an error if not successful.
 func (decoder *Decoder) Decode() (packets [][]byte, err error) {
-	var data []byte
+	data, err := decoder.DecodeRaw()
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+	if packets, err = SplitBatch(data); err != nil {
+		return nil, err
+	}
+	if len(packets) > maximumInBatch && decoder.checkPacketLimit {
+		return nil, fmt.Errorf("decode batch: number of packets %v exceeds max=%v", len(packets), maximumInBatch)
+	}
+	return packets, nil
+}
+
+// DecodeRaw decodes one 'packet' from the io.Reader passed in NewDecoder(), decrypting and decompressing it,
+// but without splitting it into the packets it holds. The batch returned holds the packets, each prefixed
+// with their length as a varuint32, and may be passed to SplitBatch or Encoder.EncodeRaw. It is only valid
+// until the next call to Decode or DecodeRaw.
+func (decoder *Decoder) DecodeRaw() (data []byte, err error) {
 	if decoder.pr == nil {
 		var n int
 		n, err = decoder.r.Read(decoder.buf)
@@ -119,7 +136,13 @@ func (decoder *Decoder) Decode() (packets [][]byte, err error) {
 		}
 	}
 
-	b := bytes.NewBuffer(data)
+	return data, nil
+}
+
+// SplitBatch splits a decompressed batch, as returned by Decoder.DecodeRaw, into the packets it holds. The
+// packets returned share their memory with the batch passed.
+func SplitBatch(batch []byte) (packets [][]byte, err error) {
+	b := bytes.NewBuffer(batch)
 	for b.Len() != 0 {
 		var length uint32
 		if err := protocol.Varuint32(b, &length); err != nil {
@@ -127,8 +150,5 @@ func (decoder *Decoder) Decode() (packets [][]byte, err error) {
 		}
 		packets = append(packets, b.Next(int(length)))
 	}
-	if len(packets) > maximumInBatch && decoder.checkPacketLimit {
-		return nil, fmt.Errorf("decode batch: number of packets %v exceeds max=%v", len(packets), maximumInBatch)
-	}
 	return packets, nil
 }
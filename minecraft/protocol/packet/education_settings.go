@@ -15,19 +15,23 @@ type EducationSettings struct {
 	// CanResizeCodeBuilder specifies if clients connected to the world should be able to resize the code
 	// builder when it is opened.
 	CanResizeCodeBuilder bool
-	// DisableLegacyTitleBar ...
+	// DisableLegacyTitleBar specifies if the legacy title bar of the code builder should be hidden.
 	DisableLegacyTitleBar bool
-	// PostProcessFilter ...
+	// PostProcessFilter is the name of a post-processing filter applied to the screen of clients in the world.
+	// It is empty if no filter is applied.
 	PostProcessFilter string
-	// ScreenshotBorderPath ...
+	// ScreenshotBorderPath is the path of the resource pack texture drawn as border around screenshots taken
+	// in the world. It is empty if no border is drawn.
 	ScreenshotBorderPath string
-	// CanModifyBlocks ...
+	// CanModifyBlocks specifies if players in the world are able to modify blocks. If not set, the client
+	// keeps its current setting.
 	CanModifyBlocks protocol.Optional[bool]
-	// OverrideURI ...
+	// OverrideURI is a URI that overrides the CodeBuilderDefaultURI if set.
 	OverrideURI protocol.Optional[string]
 	// HasQuiz specifies if the world has a quiz connected to it.
 	HasQuiz bool
-	// ExternalLinkSettings ...
+	// ExternalLinkSettings holds the URL and display name of an external link shown to clients in the world.
+	// If not set, no link is shown.
 	ExternalLinkSettings protocol.Optional[protocol.EducationExternalLinkSettings]
 }
 
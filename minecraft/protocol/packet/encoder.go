@@ -15,6 +15,7 @@ type Encoder struct {
 	w io.Writer
 
 	compression Compression
+	threshold   int
 	encrypt     *encrypt
 }
 
@@ -38,6 +39,13 @@ func (encoder *Encoder) EnableCompression(compression Compression) {
 	encoder.compression = compression
 }
 
+// SetCompressionThreshold sets the minimum size in bytes of a batch for it to be compressed by the Encoder.
+// Smaller batches are sent uncompressed, as compressing them barely saves any bandwidth. By default, all
+// batches are compressed once compression is enabled.
+func (encoder *Encoder) SetCompressionThreshold(threshold int) {
+	encoder.threshold = threshold
+}
+
 // Encode encodes the packets passed. It writes all of them as a single packet which is  compressed and
 // optionally encrypted.
 func (encoder *Encoder) Encode(packets [][]byte) error {
@@ -59,9 +67,23 @@ func (encoder *Encoder) Encode(packets [][]byte) error {
 		}
 	}
 
-	data := buf.Bytes()
+	return encoder.write(buf.Bytes())
+}
+
+// EncodeRaw encodes a batch of packets that were already prefixed with their length, such as a batch returned
+// by Decoder.DecodeRaw. The batch is compressed and optionally encrypted like in Encode, allowing batches
+// to be forwarded to another connection without splitting them into separate packets first.
+func (encoder *Encoder) EncodeRaw(batch []byte) error {
+	return encoder.write(batch)
+}
+
+// write compresses and optionally encrypts the batch passed and writes it to the io.Writer of the Encoder.
+func (encoder *Encoder) write(data []byte) error {
 	prepend := []byte{header}
-	if encoder.compression != nil {
+	if encoder.compression != nil && len(data) < encoder.threshold {
+		// The batch is too small to be worth compressing, so we send it uncompressed.
+		prepend = append(prepend, 0xff)
+	} else if encoder.compression != nil {
 		prepend = append(prepend, byte(encoder.compression.EncodeCompression()))
 		var err error
 		data, err = encoder.compression.Compress(data)
@@ -13,7 +13,8 @@ type Event struct {
 	// UsePlayerID ...
 	// TODO: Figure out what UsePlayerID is for.
 	UsePlayerID byte
-	// Event is the event that is transmitted.
+	// Event is the event that is transmitted. It is one of the *...Event types in the protocol package, such
+	// as *protocol.AchievementAwardedEvent, which determines the event type written.
 	Event protocol.Event
 }
 
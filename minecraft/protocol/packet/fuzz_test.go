@@ -0,0 +1,70 @@
+package packet
+
+import (
+	"bytes"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// fuzzPackets returns the IDs of all packets sent by either the client or the server, sorted, along with a
+// function to create a packet for each ID.
+func fuzzPackets() ([]uint32, map[uint32]func() Packet) {
+	packets := make(map[uint32]func() Packet, len(packetsFromServer)+len(packetsFromClient))
+	for id, pk := range packetsFromServer {
+		packets[id] = pk
+	}
+	for id, pk := range packetsFromClient {
+		packets[id] = pk
+	}
+	ids := make([]uint32, 0, len(packets))
+	for id := range packets {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids, packets
+}
+
+// FuzzDecode decodes arbitrary data as every packet sent by the client or the server, with the
+// protocol.DefaultReaderLimits enforced. Decoding may fail, but must never panic with a runtime error, such as
+// an out of range index or an allocation that is too large. The packet decoded is picked using index, so
+// that every input decodes a packet that exists. The corpus is seeded with every packet encoded with its
+// zero value.
+func FuzzDecode(f *testing.F) {
+	ids, packets := fuzzPackets()
+	for i, id := range ids {
+		buf := bytes.NewBuffer(nil)
+		if encodeZero(packets[id](), buf) {
+			f.Add(uint16(i), buf.Bytes())
+		}
+		f.Add(uint16(i), []byte(nil))
+	}
+	f.Fuzz(func(t *testing.T, index uint16, data []byte) {
+		pk := packets[ids[int(index)%len(ids)]]()
+		defer func() {
+			if r := recover(); r != nil {
+				if _, ok := r.(runtime.Error); ok {
+					t.Fatalf("decode %T: %v", pk, r)
+				}
+				if _, ok := r.(error); !ok {
+					t.Fatalf("decode %T: panic with non-error %v", pk, r)
+				}
+			}
+		}()
+		Marshal(pk, protocol.NewReader(bytes.NewBuffer(data), 0, true))
+	})
+}
+
+// encodeZero encodes the packet passed to buf. False is returned if the packet could not be encoded, which
+// is the case for packets that do not support encoding their zero value.
+func encodeZero(pk Packet, buf *bytes.Buffer) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	Marshal(pk, protocol.NewWriter(buf, 0))
+	return true
+}
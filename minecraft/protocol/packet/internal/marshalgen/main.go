@@ -0,0 +1,226 @@
+// Command marshalgen generates specialised marshaling methods for the packets in the packet package and the
+// types in the protocol package. For every type with a Marshal(protocol.IO) method, it emits a
+// marshalReader(*protocol.Reader) and a marshalWriter(*protocol.Writer) method with the same body, so that the
+// operations of the reader and writer are called directly instead of through the protocol.IO interface. The
+// methods generated are used by packet.Marshal and by helpers such as protocol.Slice if the packages are
+// built with the protocolgen build tag. Marshal methods that use type assertions on the protocol.IO are
+// skipped, as these do not compile for a concrete reader or writer.
+//
+// marshalgen is run using go generate from the packet and protocol packages:
+//
+//	go generate ./minecraft/protocol/...
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the packet package")
+	out := flag.String("out", "marshal_gen.go", "file to write the generated methods to, relative to dir")
+	flag.Parse()
+
+	src, err := generate(*dir, *out)
+	if err != nil {
+		log.Fatalf("marshalgen: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(*dir, *out), src, 0644); err != nil {
+		log.Fatalf("marshalgen: %v", err)
+	}
+}
+
+// method is a Marshal method of a type found in the package that methods are generated for.
+type method struct {
+	typeName, recv, param string
+	body                  *ast.BlockStmt
+}
+
+// generate parses the Go files in the directory passed and returns the source of the file holding the
+// generated methods. The output file itself is skipped.
+func generate(dir, out string) ([]byte, error) {
+	fset := token.NewFileSet()
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	var (
+		methods []method
+		pkg     string
+	)
+	imports := map[string]string{}
+	for _, path := range matches {
+		if strings.HasSuffix(path, "_test.go") || filepath.Base(path) == out {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if ignored(f) {
+			continue
+		}
+		pkg = f.Name.Name
+		fileImports := map[string]string{}
+		for _, imp := range f.Imports {
+			p, _ := strconv.Unquote(imp.Path.Value)
+			name := p[strings.LastIndex(p, "/")+1:]
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			fileImports[name] = p
+		}
+		for _, decl := range f.Decls {
+			m, ok := marshalMethod(decl, pkg)
+			if !ok {
+				continue
+			}
+			methods = append(methods, m)
+			for name := range usedPackages(m.body) {
+				if p, ok := fileImports[name]; ok && name != pkg {
+					imports[name] = p
+				}
+			}
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i].typeName < methods[j].typeName })
+
+	buf := bytes.NewBuffer(nil)
+	// Types in the protocol package refer to the reader and writer without qualifier.
+	qualifier := "protocol."
+	if pkg == "protocol" {
+		qualifier = ""
+	}
+	fmt.Fprintf(buf, "// Code generated by marshalgen. DO NOT EDIT.\n\n//go:build protocolgen\n\npackage %v\n\nimport (\n", pkg)
+	names := make([]string, 0, len(imports))
+	for name := range imports {
+		names = append(names, name)
+	}
+	// Imports of the standard library are written first, separated from other imports by an empty line.
+	sort.Slice(names, func(i, j int) bool {
+		if a, b := standard(imports[names[i]]), standard(imports[names[j]]); a != b {
+			return a
+		}
+		return imports[names[i]] < imports[names[j]]
+	})
+	for i, name := range names {
+		p := imports[name]
+		if i > 0 && standard(imports[names[i-1]]) && !standard(p) {
+			buf.WriteString("\n")
+		}
+		if p[strings.LastIndex(p, "/")+1:] != name {
+			fmt.Fprintf(buf, "\t%v %q\n", name, p)
+			continue
+		}
+		fmt.Fprintf(buf, "\t%q\n", p)
+	}
+	buf.WriteString(")\n")
+	for _, m := range methods {
+		body := bytes.NewBuffer(nil)
+		if err := format.Node(body, fset, m.body); err != nil {
+			return nil, fmt.Errorf("format %v: %w", m.typeName, err)
+		}
+		fmt.Fprintf(buf, "\nfunc (%v *%v) marshalReader(%v *%vReader) %s\n", m.recv, m.typeName, m.param, qualifier, body)
+		fmt.Fprintf(buf, "\nfunc (%v *%v) marshalWriter(%v *%vWriter) %s\n", m.recv, m.typeName, m.param, qualifier, body)
+	}
+	return format.Source(buf.Bytes())
+}
+
+// standard checks if the import path passed is a package of the standard library.
+func standard(path string) bool {
+	return !strings.Contains(strings.Split(path, "/")[0], ".")
+}
+
+// ignored checks if the file passed should not be parsed for Marshal methods, which is the case for files
+// with build constraints, such as the files holding the marshaling code for the protocolgen build tag.
+func ignored(f *ast.File) bool {
+	for _, group := range f.Comments {
+		if group.Pos() >= f.Package {
+			break
+		}
+		for _, c := range group.List {
+			if strings.HasPrefix(c.Text, "//go:build") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// marshalMethod checks if the declaration passed is a Marshal(protocol.IO) method with a pointer receiver and
+// returns it if so. In the protocol package itself, the parameter type is IO rather than protocol.IO.
+// Methods that use a type assertion on their protocol.IO parameter are not returned.
+func marshalMethod(decl ast.Decl, pkg string) (method, bool) {
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok || fn.Recv == nil || fn.Name.Name != "Marshal" || fn.Body == nil || len(fn.Type.Params.List) != 1 {
+		return method{}, false
+	}
+	param := fn.Type.Params.List[0]
+	if !isIO(param.Type, pkg) || len(param.Names) != 1 || assertsType(fn.Body, param.Names[0].Name) {
+		return method{}, false
+	}
+	recv := fn.Recv.List[0]
+	star, ok := recv.Type.(*ast.StarExpr)
+	if !ok || len(recv.Names) != 1 {
+		return method{}, false
+	}
+	typeName, ok := star.X.(*ast.Ident)
+	if !ok {
+		return method{}, false
+	}
+	return method{typeName: typeName.Name, recv: recv.Names[0].Name, param: param.Names[0].Name, body: fn.Body}, true
+}
+
+// isIO checks if the type expression passed refers to protocol.IO from within the package passed.
+func isIO(expr ast.Expr, pkg string) bool {
+	if pkg == "protocol" {
+		ident, ok := expr.(*ast.Ident)
+		return ok && ident.Name == "IO"
+	}
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "IO" {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && x.Name == "protocol"
+}
+
+// assertsType checks if the node passed holds a type assertion or type switch on the identifier with the
+// name passed.
+func assertsType(node ast.Node, name string) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if a, ok := n.(*ast.TypeAssertExpr); ok {
+			if x, ok := a.X.(*ast.Ident); ok && x.Name == name {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// usedPackages returns the names of the packages referred to in the node passed.
+func usedPackages(node ast.Node) map[string]struct{} {
+	names := map[string]struct{}{"protocol": {}}
+	ast.Inspect(node, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok {
+				names[x.Name] = struct{}{}
+			}
+		}
+		return true
+	})
+	return names
+}
@@ -10,6 +10,22 @@ const (
 	LabTableActionReset
 )
 
+const (
+	LabTableReactionNone = iota
+	LabTableReactionIceBomb
+	LabTableReactionBleach
+	LabTableReactionElephantToothpaste
+	LabTableReactionFertilizer
+	LabTableReactionHeatBlock
+	LabTableReactionMagnesiumSalts
+	LabTableReactionMiscFire
+	LabTableReactionMiscExplosion
+	LabTableReactionMiscLava
+	LabTableReactionMiscMystical
+	LabTableReactionMiscSmoke
+	LabTableReactionMiscLargeSmoke
+)
+
 // LabTable is sent by the client to let the server know it started a chemical reaction in Education Edition,
 // and is sent by the server to other clients to show the effects.
 // The packet is only functional if Education features are enabled.
@@ -21,7 +37,7 @@ type LabTable struct {
 	Position protocol.BlockPos
 	// ReactionType is the type of the reaction that took place as a result of the items put into the lab
 	// table. The reaction type can be either that of an item or a particle, depending on whatever the result
-	// was of the reaction.
+	// was of the reaction. It is one of the LabTableReaction constants above.
 	ReactionType byte
 }
 
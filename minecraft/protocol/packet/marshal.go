@@ -0,0 +1,16 @@
+package packet
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+//go:generate go run ./internal/marshalgen -out marshal_gen.go
+
+// Marshal encodes or decodes the Packet passed using the protocol.IO passed, as done by the Marshal method of
+// the Packet. If the package is built with the protocolgen build tag, packets read using a *protocol.Reader or
+// written using a *protocol.Writer are instead marshaled by methods generated by marshalgen, which call the
+// reader or writer directly rather than through the protocol.IO interface. This reduces the CPU time spent
+// decoding and encoding packets. The generated methods are kept up to date by running go generate.
+func Marshal(pk Packet, io protocol.IO) {
+	marshal(pk, io)
+}
@@ -0,0 +1,12 @@
+//go:build !protocolgen
+
+package packet
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// marshal marshals the Packet using its Marshal method.
+func marshal(pk Packet, io protocol.IO) {
+	pk.Marshal(io)
+}
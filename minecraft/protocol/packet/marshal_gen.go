@@ -0,0 +1,3014 @@
+// Code generated by marshalgen. DO NOT EDIT.
+
+//go:build protocolgen
+
+package packet
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+func (pk *ActorEvent) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Uint8(&pk.EventType)
+	io.Varint32(&pk.EventData)
+}
+
+func (pk *ActorEvent) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Uint8(&pk.EventType)
+	io.Varint32(&pk.EventData)
+}
+
+func (pk *ActorPickRequest) marshalReader(io *protocol.Reader) {
+	io.Int64(&pk.EntityUniqueID)
+	io.Uint8(&pk.HotBarSlot)
+	io.Bool(&pk.WithData)
+}
+
+func (pk *ActorPickRequest) marshalWriter(io *protocol.Writer) {
+	io.Int64(&pk.EntityUniqueID)
+	io.Uint8(&pk.HotBarSlot)
+	io.Bool(&pk.WithData)
+}
+
+func (pk *AddActor) marshalReader(io *protocol.Reader) {
+	io.Varint64(&pk.EntityUniqueID)
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.String(&pk.EntityType)
+	io.Vec3(&pk.Position)
+	io.Vec3(&pk.Velocity)
+	io.Float32(&pk.Pitch)
+	io.Float32(&pk.Yaw)
+	io.Float32(&pk.HeadYaw)
+	io.Float32(&pk.BodyYaw)
+	protocol.Slice(io, &pk.Attributes)
+	io.EntityMetadata(&pk.EntityMetadata)
+	protocol.Single(io, &pk.EntityProperties)
+	protocol.Slice(io, &pk.EntityLinks)
+}
+
+func (pk *AddActor) marshalWriter(io *protocol.Writer) {
+	io.Varint64(&pk.EntityUniqueID)
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.String(&pk.EntityType)
+	io.Vec3(&pk.Position)
+	io.Vec3(&pk.Velocity)
+	io.Float32(&pk.Pitch)
+	io.Float32(&pk.Yaw)
+	io.Float32(&pk.HeadYaw)
+	io.Float32(&pk.BodyYaw)
+	protocol.Slice(io, &pk.Attributes)
+	io.EntityMetadata(&pk.EntityMetadata)
+	protocol.Single(io, &pk.EntityProperties)
+	protocol.Slice(io, &pk.EntityLinks)
+}
+
+func (pk *AddBehaviourTree) marshalReader(io *protocol.Reader) {
+	io.String(&pk.BehaviourTree)
+}
+
+func (pk *AddBehaviourTree) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.BehaviourTree)
+}
+
+func (pk *AddItemActor) marshalReader(io *protocol.Reader) {
+	io.Varint64(&pk.EntityUniqueID)
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.ItemInstance(&pk.Item)
+	io.Vec3(&pk.Position)
+	io.Vec3(&pk.Velocity)
+	io.EntityMetadata(&pk.EntityMetadata)
+	io.Bool(&pk.FromFishing)
+}
+
+func (pk *AddItemActor) marshalWriter(io *protocol.Writer) {
+	io.Varint64(&pk.EntityUniqueID)
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.ItemInstance(&pk.Item)
+	io.Vec3(&pk.Position)
+	io.Vec3(&pk.Velocity)
+	io.EntityMetadata(&pk.EntityMetadata)
+	io.Bool(&pk.FromFishing)
+}
+
+func (pk *AddPainting) marshalReader(io *protocol.Reader) {
+	io.Varint64(&pk.EntityUniqueID)
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Vec3(&pk.Position)
+	io.Varint32(&pk.Direction)
+	io.String(&pk.Title)
+}
+
+func (pk *AddPainting) marshalWriter(io *protocol.Writer) {
+	io.Varint64(&pk.EntityUniqueID)
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Vec3(&pk.Position)
+	io.Varint32(&pk.Direction)
+	io.String(&pk.Title)
+}
+
+func (pk *AddPlayer) marshalReader(io *protocol.Reader) {
+	io.UUID(&pk.UUID)
+	io.String(&pk.Username)
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.String(&pk.PlatformChatID)
+	io.Vec3(&pk.Position)
+	io.Vec3(&pk.Velocity)
+	io.Float32(&pk.Pitch)
+	io.Float32(&pk.Yaw)
+	io.Float32(&pk.HeadYaw)
+	io.ItemInstance(&pk.HeldItem)
+	io.Varint32(&pk.GameType)
+	io.EntityMetadata(&pk.EntityMetadata)
+	protocol.Single(io, &pk.EntityProperties)
+	protocol.Single(io, &pk.AbilityData)
+	protocol.Slice(io, &pk.EntityLinks)
+	io.String(&pk.DeviceID)
+	io.Int32(&pk.BuildPlatform)
+}
+
+func (pk *AddPlayer) marshalWriter(io *protocol.Writer) {
+	io.UUID(&pk.UUID)
+	io.String(&pk.Username)
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.String(&pk.PlatformChatID)
+	io.Vec3(&pk.Position)
+	io.Vec3(&pk.Velocity)
+	io.Float32(&pk.Pitch)
+	io.Float32(&pk.Yaw)
+	io.Float32(&pk.HeadYaw)
+	io.ItemInstance(&pk.HeldItem)
+	io.Varint32(&pk.GameType)
+	io.EntityMetadata(&pk.EntityMetadata)
+	protocol.Single(io, &pk.EntityProperties)
+	protocol.Single(io, &pk.AbilityData)
+	protocol.Slice(io, &pk.EntityLinks)
+	io.String(&pk.DeviceID)
+	io.Int32(&pk.BuildPlatform)
+}
+
+func (pk *AddVolumeEntity) marshalReader(io *protocol.Reader) {
+	io.Uint64(&pk.EntityRuntimeID)
+	io.NBT(&pk.EntityMetadata, nbt.NetworkLittleEndian)
+	io.String(&pk.EncodingIdentifier)
+	io.String(&pk.InstanceIdentifier)
+	io.UBlockPos(&pk.Bounds[0])
+	io.UBlockPos(&pk.Bounds[1])
+	io.Varint32(&pk.Dimension)
+	io.String(&pk.EngineVersion)
+}
+
+func (pk *AddVolumeEntity) marshalWriter(io *protocol.Writer) {
+	io.Uint64(&pk.EntityRuntimeID)
+	io.NBT(&pk.EntityMetadata, nbt.NetworkLittleEndian)
+	io.String(&pk.EncodingIdentifier)
+	io.String(&pk.InstanceIdentifier)
+	io.UBlockPos(&pk.Bounds[0])
+	io.UBlockPos(&pk.Bounds[1])
+	io.Varint32(&pk.Dimension)
+	io.String(&pk.EngineVersion)
+}
+
+func (pk *AdventureSettings) marshalReader(io *protocol.Reader) {
+	io.Varuint32(&pk.Flags)
+	io.Varuint32(&pk.CommandPermissionLevel)
+	io.Varuint32(&pk.ActionPermissions)
+	io.Varuint32(&pk.PermissionLevel)
+	io.Varuint32(&pk.CustomStoredPermissions)
+	io.Int64(&pk.PlayerUniqueID)
+}
+
+func (pk *AdventureSettings) marshalWriter(io *protocol.Writer) {
+	io.Varuint32(&pk.Flags)
+	io.Varuint32(&pk.CommandPermissionLevel)
+	io.Varuint32(&pk.ActionPermissions)
+	io.Varuint32(&pk.PermissionLevel)
+	io.Varuint32(&pk.CustomStoredPermissions)
+	io.Int64(&pk.PlayerUniqueID)
+}
+
+func (pk *AgentAction) marshalReader(io *protocol.Reader) {
+	io.String(&pk.Identifier)
+	io.Varint32(&pk.Action)
+	io.ByteSlice(&pk.Response)
+}
+
+func (pk *AgentAction) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.Identifier)
+	io.Varint32(&pk.Action)
+	io.ByteSlice(&pk.Response)
+}
+
+func (pk *AgentAnimation) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.Animation)
+	io.Varuint64(&pk.EntityRuntimeID)
+}
+
+func (pk *AgentAnimation) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.Animation)
+	io.Varuint64(&pk.EntityRuntimeID)
+}
+
+func (pk *Animate) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.ActionType)
+	io.Varuint64(&pk.EntityRuntimeID)
+	if pk.ActionType&0x80 != 0 {
+		io.Float32(&pk.BoatRowingTime)
+	}
+}
+
+func (pk *Animate) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.ActionType)
+	io.Varuint64(&pk.EntityRuntimeID)
+	if pk.ActionType&0x80 != 0 {
+		io.Float32(&pk.BoatRowingTime)
+	}
+}
+
+func (pk *AnimateEntity) marshalReader(io *protocol.Reader) {
+	io.String(&pk.Animation)
+	io.String(&pk.NextState)
+	io.String(&pk.StopCondition)
+	io.Int32(&pk.StopConditionVersion)
+	io.String(&pk.Controller)
+	io.Float32(&pk.BlendOutTime)
+	protocol.FuncSlice(io, &pk.EntityRuntimeIDs, io.Varuint64)
+}
+
+func (pk *AnimateEntity) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.Animation)
+	io.String(&pk.NextState)
+	io.String(&pk.StopCondition)
+	io.Int32(&pk.StopConditionVersion)
+	io.String(&pk.Controller)
+	io.Float32(&pk.BlendOutTime)
+	protocol.FuncSlice(io, &pk.EntityRuntimeIDs, io.Varuint64)
+}
+
+func (pk *AnvilDamage) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.Damage)
+	io.UBlockPos(&pk.AnvilPosition)
+}
+
+func (pk *AnvilDamage) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.Damage)
+	io.UBlockPos(&pk.AnvilPosition)
+}
+
+func (pk *AutomationClientConnect) marshalReader(io *protocol.Reader) {
+	io.String(&pk.ServerURI)
+}
+
+func (pk *AutomationClientConnect) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.ServerURI)
+}
+
+func (pk *AvailableActorIdentifiers) marshalReader(io *protocol.Reader) {
+	io.Bytes(&pk.SerialisedEntityIdentifiers)
+}
+
+func (pk *AvailableActorIdentifiers) marshalWriter(io *protocol.Writer) {
+	io.Bytes(&pk.SerialisedEntityIdentifiers)
+}
+
+func (pk *AvailableCommands) marshalReader(io *protocol.Reader) {
+	protocol.FuncSlice(io, &pk.EnumValues, io.String)
+	protocol.FuncSlice(io, &pk.ChainedSubcommandValues, io.String)
+	protocol.FuncSlice(io, &pk.Suffixes, io.String)
+	protocol.FuncIOSlice(io, &pk.Enums, protocol.CommandEnumContext{EnumValues: pk.EnumValues}.Marshal)
+	protocol.Slice(io, &pk.ChainedSubcommands)
+	protocol.Slice(io, &pk.Commands)
+	protocol.Slice(io, &pk.DynamicEnums)
+	protocol.Slice(io, &pk.Constraints)
+}
+
+func (pk *AvailableCommands) marshalWriter(io *protocol.Writer) {
+	protocol.FuncSlice(io, &pk.EnumValues, io.String)
+	protocol.FuncSlice(io, &pk.ChainedSubcommandValues, io.String)
+	protocol.FuncSlice(io, &pk.Suffixes, io.String)
+	protocol.FuncIOSlice(io, &pk.Enums, protocol.CommandEnumContext{EnumValues: pk.EnumValues}.Marshal)
+	protocol.Slice(io, &pk.ChainedSubcommands)
+	protocol.Slice(io, &pk.Commands)
+	protocol.Slice(io, &pk.DynamicEnums)
+	protocol.Slice(io, &pk.Constraints)
+}
+
+func (pk *AwardAchievement) marshalReader(io *protocol.Reader) {
+	io.Int32(&pk.AchievementID)
+}
+
+func (pk *AwardAchievement) marshalWriter(io *protocol.Writer) {
+	io.Int32(&pk.AchievementID)
+}
+
+func (pk *BiomeDefinitionList) marshalReader(io *protocol.Reader) {
+	io.Bytes(&pk.SerialisedBiomeDefinitions)
+}
+
+func (pk *BiomeDefinitionList) marshalWriter(io *protocol.Writer) {
+	io.Bytes(&pk.SerialisedBiomeDefinitions)
+}
+
+func (pk *BlockActorData) marshalReader(io *protocol.Reader) {
+	io.UBlockPos(&pk.Position)
+	io.NBT(&pk.NBTData, nbt.NetworkLittleEndian)
+}
+
+func (pk *BlockActorData) marshalWriter(io *protocol.Writer) {
+	io.UBlockPos(&pk.Position)
+	io.NBT(&pk.NBTData, nbt.NetworkLittleEndian)
+}
+
+func (pk *BlockEvent) marshalReader(io *protocol.Reader) {
+	io.UBlockPos(&pk.Position)
+	io.Varint32(&pk.EventType)
+	io.Varint32(&pk.EventData)
+}
+
+func (pk *BlockEvent) marshalWriter(io *protocol.Writer) {
+	io.UBlockPos(&pk.Position)
+	io.Varint32(&pk.EventType)
+	io.Varint32(&pk.EventData)
+}
+
+func (pk *BlockPickRequest) marshalReader(io *protocol.Reader) {
+	io.BlockPos(&pk.Position)
+	io.Bool(&pk.AddBlockNBT)
+	io.Uint8(&pk.HotBarSlot)
+}
+
+func (pk *BlockPickRequest) marshalWriter(io *protocol.Writer) {
+	io.BlockPos(&pk.Position)
+	io.Bool(&pk.AddBlockNBT)
+	io.Uint8(&pk.HotBarSlot)
+}
+
+func (pk *BookEdit) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.ActionType)
+	io.Uint8(&pk.InventorySlot)
+	switch pk.ActionType {
+	case BookActionReplacePage, BookActionAddPage:
+		io.Uint8(&pk.PageNumber)
+		io.String(&pk.Text)
+		io.String(&pk.PhotoName)
+	case BookActionDeletePage:
+		io.Uint8(&pk.PageNumber)
+	case BookActionSwapPages:
+		io.Uint8(&pk.PageNumber)
+		io.Uint8(&pk.SecondaryPageNumber)
+	case BookActionSign:
+		io.String(&pk.Title)
+		io.String(&pk.Author)
+		io.String(&pk.XUID)
+	default:
+		io.UnknownEnumOption(pk.ActionType, "book edit action type")
+	}
+}
+
+func (pk *BookEdit) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.ActionType)
+	io.Uint8(&pk.InventorySlot)
+	switch pk.ActionType {
+	case BookActionReplacePage, BookActionAddPage:
+		io.Uint8(&pk.PageNumber)
+		io.String(&pk.Text)
+		io.String(&pk.PhotoName)
+	case BookActionDeletePage:
+		io.Uint8(&pk.PageNumber)
+	case BookActionSwapPages:
+		io.Uint8(&pk.PageNumber)
+		io.Uint8(&pk.SecondaryPageNumber)
+	case BookActionSign:
+		io.String(&pk.Title)
+		io.String(&pk.Author)
+		io.String(&pk.XUID)
+	default:
+		io.UnknownEnumOption(pk.ActionType, "book edit action type")
+	}
+}
+
+func (pk *BossEvent) marshalReader(io *protocol.Reader) {
+	io.Varint64(&pk.BossEntityUniqueID)
+	io.Varuint32(&pk.EventType)
+	switch pk.EventType {
+	case BossEventShow:
+		io.String(&pk.BossBarTitle)
+		io.Float32(&pk.HealthPercentage)
+		io.Uint16(&pk.ScreenDarkening)
+		io.Varuint32(&pk.Colour)
+		io.Varuint32(&pk.Overlay)
+	case BossEventRegisterPlayer, BossEventUnregisterPlayer, BossEventRequest:
+		io.Varint64(&pk.PlayerUniqueID)
+	case BossEventHide:
+
+	case BossEventHealthPercentage:
+		io.Float32(&pk.HealthPercentage)
+	case BossEventTitle:
+		io.String(&pk.BossBarTitle)
+	case BossEventAppearanceProperties:
+		io.Uint16(&pk.ScreenDarkening)
+		io.Varuint32(&pk.Colour)
+		io.Varuint32(&pk.Overlay)
+	case BossEventTexture:
+		io.Varuint32(&pk.Colour)
+		io.Varuint32(&pk.Overlay)
+	default:
+		io.UnknownEnumOption(pk.EventType, "boss event type")
+	}
+}
+
+func (pk *BossEvent) marshalWriter(io *protocol.Writer) {
+	io.Varint64(&pk.BossEntityUniqueID)
+	io.Varuint32(&pk.EventType)
+	switch pk.EventType {
+	case BossEventShow:
+		io.String(&pk.BossBarTitle)
+		io.Float32(&pk.HealthPercentage)
+		io.Uint16(&pk.ScreenDarkening)
+		io.Varuint32(&pk.Colour)
+		io.Varuint32(&pk.Overlay)
+	case BossEventRegisterPlayer, BossEventUnregisterPlayer, BossEventRequest:
+		io.Varint64(&pk.PlayerUniqueID)
+	case BossEventHide:
+
+	case BossEventHealthPercentage:
+		io.Float32(&pk.HealthPercentage)
+	case BossEventTitle:
+		io.String(&pk.BossBarTitle)
+	case BossEventAppearanceProperties:
+		io.Uint16(&pk.ScreenDarkening)
+		io.Varuint32(&pk.Colour)
+		io.Varuint32(&pk.Overlay)
+	case BossEventTexture:
+		io.Varuint32(&pk.Colour)
+		io.Varuint32(&pk.Overlay)
+	default:
+		io.UnknownEnumOption(pk.EventType, "boss event type")
+	}
+}
+
+func (pk *Camera) marshalReader(io *protocol.Reader) {
+	io.Varint64(&pk.CameraEntityUniqueID)
+	io.Varint64(&pk.TargetPlayerUniqueID)
+}
+
+func (pk *Camera) marshalWriter(io *protocol.Writer) {
+	io.Varint64(&pk.CameraEntityUniqueID)
+	io.Varint64(&pk.TargetPlayerUniqueID)
+}
+
+func (pk *CameraInstruction) marshalReader(io *protocol.Reader) {
+	protocol.OptionalMarshaler(io, &pk.Set)
+	protocol.OptionalFunc(io, &pk.Clear, io.Bool)
+	protocol.OptionalMarshaler(io, &pk.Fade)
+}
+
+func (pk *CameraInstruction) marshalWriter(io *protocol.Writer) {
+	protocol.OptionalMarshaler(io, &pk.Set)
+	protocol.OptionalFunc(io, &pk.Clear, io.Bool)
+	protocol.OptionalMarshaler(io, &pk.Fade)
+}
+
+func (pk *CameraPresets) marshalReader(io *protocol.Reader) {
+	protocol.Slice(io, &pk.Presets)
+}
+
+func (pk *CameraPresets) marshalWriter(io *protocol.Writer) {
+	protocol.Slice(io, &pk.Presets)
+}
+
+func (pk *CameraShake) marshalReader(io *protocol.Reader) {
+	io.Float32(&pk.Intensity)
+	io.Float32(&pk.Duration)
+	io.Uint8(&pk.Type)
+	io.Uint8(&pk.Action)
+}
+
+func (pk *CameraShake) marshalWriter(io *protocol.Writer) {
+	io.Float32(&pk.Intensity)
+	io.Float32(&pk.Duration)
+	io.Uint8(&pk.Type)
+	io.Uint8(&pk.Action)
+}
+
+func (pk *ChangeDimension) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.Dimension)
+	io.Vec3(&pk.Position)
+	io.Bool(&pk.Respawn)
+}
+
+func (pk *ChangeDimension) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.Dimension)
+	io.Vec3(&pk.Position)
+	io.Bool(&pk.Respawn)
+}
+
+func (pk *ChangeMobProperty) marshalReader(io *protocol.Reader) {
+	io.Uint64(&pk.EntityUniqueID)
+	io.String(&pk.Property)
+	io.Bool(&pk.BoolValue)
+	io.String(&pk.StringValue)
+	io.Varint32(&pk.IntValue)
+	io.Float32(&pk.FloatValue)
+}
+
+func (pk *ChangeMobProperty) marshalWriter(io *protocol.Writer) {
+	io.Uint64(&pk.EntityUniqueID)
+	io.String(&pk.Property)
+	io.Bool(&pk.BoolValue)
+	io.String(&pk.StringValue)
+	io.Varint32(&pk.IntValue)
+	io.Float32(&pk.FloatValue)
+}
+
+func (pk *ChunkRadiusUpdated) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.ChunkRadius)
+}
+
+func (pk *ChunkRadiusUpdated) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.ChunkRadius)
+}
+
+func (pk *ClientBoundDebugRenderer) marshalReader(io *protocol.Reader) {
+	io.Uint32(&pk.Type)
+	if pk.Type == ClientBoundDebugRendererAddCube {
+		io.String(&pk.Text)
+		io.Vec3(&pk.Position)
+		io.Float32(&pk.Red)
+		io.Float32(&pk.Green)
+		io.Float32(&pk.Blue)
+		io.Float32(&pk.Alpha)
+		io.Uint64(&pk.Duration)
+	}
+}
+
+func (pk *ClientBoundDebugRenderer) marshalWriter(io *protocol.Writer) {
+	io.Uint32(&pk.Type)
+	if pk.Type == ClientBoundDebugRendererAddCube {
+		io.String(&pk.Text)
+		io.Vec3(&pk.Position)
+		io.Float32(&pk.Red)
+		io.Float32(&pk.Green)
+		io.Float32(&pk.Blue)
+		io.Float32(&pk.Alpha)
+		io.Uint64(&pk.Duration)
+	}
+}
+
+func (pk *ClientBoundMapItemData) marshalReader(io *protocol.Reader) {
+	io.Varint64(&pk.MapID)
+	io.Varuint32(&pk.UpdateFlags)
+	io.Uint8(&pk.Dimension)
+	io.Bool(&pk.LockedMap)
+	io.BlockPos(&pk.Origin)
+
+	if pk.UpdateFlags&MapUpdateFlagInitialisation != 0 {
+		protocol.FuncSlice(io, &pk.MapsIncludedIn, io.Varint64)
+	}
+	if pk.UpdateFlags&(MapUpdateFlagInitialisation|MapUpdateFlagDecoration|MapUpdateFlagTexture) != 0 {
+		io.Uint8(&pk.Scale)
+	}
+	if pk.UpdateFlags&MapUpdateFlagDecoration != 0 {
+		protocol.Slice(io, &pk.TrackedObjects)
+		protocol.Slice(io, &pk.Decorations)
+	}
+	if pk.UpdateFlags&MapUpdateFlagTexture != 0 {
+		io.Varint32(&pk.Width)
+		io.Varint32(&pk.Height)
+		io.Varint32(&pk.XOffset)
+		io.Varint32(&pk.YOffset)
+		protocol.FuncSlice(io, &pk.Pixels, io.VarRGBA)
+	}
+}
+
+func (pk *ClientBoundMapItemData) marshalWriter(io *protocol.Writer) {
+	io.Varint64(&pk.MapID)
+	io.Varuint32(&pk.UpdateFlags)
+	io.Uint8(&pk.Dimension)
+	io.Bool(&pk.LockedMap)
+	io.BlockPos(&pk.Origin)
+
+	if pk.UpdateFlags&MapUpdateFlagInitialisation != 0 {
+		protocol.FuncSlice(io, &pk.MapsIncludedIn, io.Varint64)
+	}
+	if pk.UpdateFlags&(MapUpdateFlagInitialisation|MapUpdateFlagDecoration|MapUpdateFlagTexture) != 0 {
+		io.Uint8(&pk.Scale)
+	}
+	if pk.UpdateFlags&MapUpdateFlagDecoration != 0 {
+		protocol.Slice(io, &pk.TrackedObjects)
+		protocol.Slice(io, &pk.Decorations)
+	}
+	if pk.UpdateFlags&MapUpdateFlagTexture != 0 {
+		io.Varint32(&pk.Width)
+		io.Varint32(&pk.Height)
+		io.Varint32(&pk.XOffset)
+		io.Varint32(&pk.YOffset)
+		protocol.FuncSlice(io, &pk.Pixels, io.VarRGBA)
+	}
+}
+
+func (pk *ClientCacheBlobStatus) marshalReader(io *protocol.Reader) {
+	missLen, hitLen := uint32(len(pk.MissHashes)), uint32(len(pk.HitHashes))
+	io.Varuint32(&missLen)
+	io.Varuint32(&hitLen)
+	protocol.FuncSliceOfLen(io, missLen, &pk.MissHashes, io.Uint64)
+	protocol.FuncSliceOfLen(io, hitLen, &pk.HitHashes, io.Uint64)
+}
+
+func (pk *ClientCacheBlobStatus) marshalWriter(io *protocol.Writer) {
+	missLen, hitLen := uint32(len(pk.MissHashes)), uint32(len(pk.HitHashes))
+	io.Varuint32(&missLen)
+	io.Varuint32(&hitLen)
+	protocol.FuncSliceOfLen(io, missLen, &pk.MissHashes, io.Uint64)
+	protocol.FuncSliceOfLen(io, hitLen, &pk.HitHashes, io.Uint64)
+}
+
+func (pk *ClientCacheMissResponse) marshalReader(io *protocol.Reader) {
+	protocol.Slice(io, &pk.Blobs)
+}
+
+func (pk *ClientCacheMissResponse) marshalWriter(io *protocol.Writer) {
+	protocol.Slice(io, &pk.Blobs)
+}
+
+func (pk *ClientCacheStatus) marshalReader(io *protocol.Reader) {
+	io.Bool(&pk.Enabled)
+}
+
+func (pk *ClientCacheStatus) marshalWriter(io *protocol.Writer) {
+	io.Bool(&pk.Enabled)
+}
+
+func (pk *ClientCheatAbility) marshalReader(io *protocol.Reader) {
+	protocol.Single(io, &pk.AbilityData)
+}
+
+func (pk *ClientCheatAbility) marshalWriter(io *protocol.Writer) {
+	protocol.Single(io, &pk.AbilityData)
+}
+
+func (pk *ClientStartItemCooldown) marshalReader(io *protocol.Reader) {
+	io.String(&pk.Category)
+	io.Varint32(&pk.Duration)
+}
+
+func (pk *ClientStartItemCooldown) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.Category)
+	io.Varint32(&pk.Duration)
+}
+
+func (pk *CodeBuilder) marshalReader(io *protocol.Reader) {
+	io.String(&pk.URL)
+	io.Bool(&pk.ShouldOpenCodeBuilder)
+}
+
+func (pk *CodeBuilder) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.URL)
+	io.Bool(&pk.ShouldOpenCodeBuilder)
+}
+
+func (pk *CodeBuilderSource) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.Operation)
+	io.Uint8(&pk.Category)
+	io.Uint8(&pk.CodeStatus)
+}
+
+func (pk *CodeBuilderSource) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.Operation)
+	io.Uint8(&pk.Category)
+	io.Uint8(&pk.CodeStatus)
+}
+
+func (pk *CommandBlockUpdate) marshalReader(io *protocol.Reader) {
+	io.Bool(&pk.Block)
+	if pk.Block {
+		io.UBlockPos(&pk.Position)
+		io.Varuint32(&pk.Mode)
+		io.Bool(&pk.NeedsRedstone)
+		io.Bool(&pk.Conditional)
+	} else {
+		io.Varuint64(&pk.MinecartEntityRuntimeID)
+	}
+	io.String(&pk.Command)
+	io.String(&pk.LastOutput)
+	io.String(&pk.Name)
+	io.Bool(&pk.ShouldTrackOutput)
+	io.Int32(&pk.TickDelay)
+	io.Bool(&pk.ExecuteOnFirstTick)
+}
+
+func (pk *CommandBlockUpdate) marshalWriter(io *protocol.Writer) {
+	io.Bool(&pk.Block)
+	if pk.Block {
+		io.UBlockPos(&pk.Position)
+		io.Varuint32(&pk.Mode)
+		io.Bool(&pk.NeedsRedstone)
+		io.Bool(&pk.Conditional)
+	} else {
+		io.Varuint64(&pk.MinecartEntityRuntimeID)
+	}
+	io.String(&pk.Command)
+	io.String(&pk.LastOutput)
+	io.String(&pk.Name)
+	io.Bool(&pk.ShouldTrackOutput)
+	io.Int32(&pk.TickDelay)
+	io.Bool(&pk.ExecuteOnFirstTick)
+}
+
+func (pk *CommandOutput) marshalReader(io *protocol.Reader) {
+	protocol.CommandOriginData(io, &pk.CommandOrigin)
+	io.Uint8(&pk.OutputType)
+	io.Varuint32(&pk.SuccessCount)
+	protocol.Slice(io, &pk.OutputMessages)
+	if pk.OutputType == CommandOutputTypeDataSet {
+		io.String(&pk.DataSet)
+	}
+}
+
+func (pk *CommandOutput) marshalWriter(io *protocol.Writer) {
+	protocol.CommandOriginData(io, &pk.CommandOrigin)
+	io.Uint8(&pk.OutputType)
+	io.Varuint32(&pk.SuccessCount)
+	protocol.Slice(io, &pk.OutputMessages)
+	if pk.OutputType == CommandOutputTypeDataSet {
+		io.String(&pk.DataSet)
+	}
+}
+
+func (pk *CommandRequest) marshalReader(io *protocol.Reader) {
+	io.String(&pk.CommandLine)
+	protocol.CommandOriginData(io, &pk.CommandOrigin)
+	io.Bool(&pk.Internal)
+	io.Varint32(&pk.Version)
+}
+
+func (pk *CommandRequest) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.CommandLine)
+	protocol.CommandOriginData(io, &pk.CommandOrigin)
+	io.Bool(&pk.Internal)
+	io.Varint32(&pk.Version)
+}
+
+func (pk *CompletedUsingItem) marshalReader(io *protocol.Reader) {
+	io.Int16(&pk.UsedItemID)
+	io.Int32(&pk.UseMethod)
+}
+
+func (pk *CompletedUsingItem) marshalWriter(io *protocol.Writer) {
+	io.Int16(&pk.UsedItemID)
+	io.Int32(&pk.UseMethod)
+}
+
+func (pk *CompressedBiomeDefinitionList) marshalReader(io *protocol.Reader) {
+	io.CompressedBiomeDefinitions(&pk.Biomes)
+}
+
+func (pk *CompressedBiomeDefinitionList) marshalWriter(io *protocol.Writer) {
+	io.CompressedBiomeDefinitions(&pk.Biomes)
+}
+
+func (pk *ContainerClose) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.WindowID)
+	io.Uint8(&pk.ContainerType)
+	io.Bool(&pk.ServerSide)
+}
+
+func (pk *ContainerClose) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.WindowID)
+	io.Uint8(&pk.ContainerType)
+	io.Bool(&pk.ServerSide)
+}
+
+func (pk *ContainerOpen) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.WindowID)
+	io.Uint8(&pk.ContainerType)
+	io.UBlockPos(&pk.ContainerPosition)
+	io.Varint64(&pk.ContainerEntityUniqueID)
+}
+
+func (pk *ContainerOpen) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.WindowID)
+	io.Uint8(&pk.ContainerType)
+	io.UBlockPos(&pk.ContainerPosition)
+	io.Varint64(&pk.ContainerEntityUniqueID)
+}
+
+func (pk *ContainerSetData) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.WindowID)
+	io.Varint32(&pk.Key)
+	io.Varint32(&pk.Value)
+}
+
+func (pk *ContainerSetData) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.WindowID)
+	io.Varint32(&pk.Key)
+	io.Varint32(&pk.Value)
+}
+
+func (pk *CorrectPlayerMovePrediction) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.PredictionType)
+	io.Vec3(&pk.Position)
+	io.Vec3(&pk.Delta)
+	if pk.PredictionType == PredictionTypeVehicle {
+		io.Vec2(&pk.Rotation)
+	}
+	io.Bool(&pk.OnGround)
+	io.Varuint64(&pk.Tick)
+}
+
+func (pk *CorrectPlayerMovePrediction) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.PredictionType)
+	io.Vec3(&pk.Position)
+	io.Vec3(&pk.Delta)
+	if pk.PredictionType == PredictionTypeVehicle {
+		io.Vec2(&pk.Rotation)
+	}
+	io.Bool(&pk.OnGround)
+	io.Varuint64(&pk.Tick)
+}
+
+func (pk *CraftingData) marshalReader(io *protocol.Reader) {
+	protocol.FuncSlice(io, &pk.Recipes, io.Recipe)
+	protocol.Slice(io, &pk.PotionRecipes)
+	protocol.Slice(io, &pk.PotionContainerChangeRecipes)
+	protocol.FuncSlice(io, &pk.MaterialReducers, io.MaterialReducer)
+	io.Bool(&pk.ClearRecipes)
+}
+
+func (pk *CraftingData) marshalWriter(io *protocol.Writer) {
+	protocol.FuncSlice(io, &pk.Recipes, io.Recipe)
+	protocol.Slice(io, &pk.PotionRecipes)
+	protocol.Slice(io, &pk.PotionContainerChangeRecipes)
+	protocol.FuncSlice(io, &pk.MaterialReducers, io.MaterialReducer)
+	io.Bool(&pk.ClearRecipes)
+}
+
+func (pk *CreatePhoto) marshalReader(io *protocol.Reader) {
+	io.Int64(&pk.EntityUniqueID)
+	io.String(&pk.PhotoName)
+	io.String(&pk.ItemName)
+}
+
+func (pk *CreatePhoto) marshalWriter(io *protocol.Writer) {
+	io.Int64(&pk.EntityUniqueID)
+	io.String(&pk.PhotoName)
+	io.String(&pk.ItemName)
+}
+
+func (pk *CreativeContent) marshalReader(io *protocol.Reader) {
+	protocol.Slice(io, &pk.Items)
+}
+
+func (pk *CreativeContent) marshalWriter(io *protocol.Writer) {
+	protocol.Slice(io, &pk.Items)
+}
+
+func (pk *DeathInfo) marshalReader(io *protocol.Reader) {
+	io.String(&pk.Cause)
+	protocol.FuncSlice(io, &pk.Messages, io.String)
+}
+
+func (pk *DeathInfo) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.Cause)
+	protocol.FuncSlice(io, &pk.Messages, io.String)
+}
+
+func (pk *DebugInfo) marshalReader(io *protocol.Reader) {
+	io.Varint64(&pk.PlayerUniqueID)
+	io.ByteSlice(&pk.Data)
+}
+
+func (pk *DebugInfo) marshalWriter(io *protocol.Writer) {
+	io.Varint64(&pk.PlayerUniqueID)
+	io.ByteSlice(&pk.Data)
+}
+
+func (pk *DimensionData) marshalReader(io *protocol.Reader) {
+	protocol.Slice(io, &pk.Definitions)
+}
+
+func (pk *DimensionData) marshalWriter(io *protocol.Writer) {
+	protocol.Slice(io, &pk.Definitions)
+}
+
+func (pk *Disconnect) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.Reason)
+	io.Bool(&pk.HideDisconnectionScreen)
+	if !pk.HideDisconnectionScreen {
+		io.String(&pk.Message)
+	}
+}
+
+func (pk *Disconnect) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.Reason)
+	io.Bool(&pk.HideDisconnectionScreen)
+	if !pk.HideDisconnectionScreen {
+		io.String(&pk.Message)
+	}
+}
+
+func (pk *EditorNetwork) marshalReader(io *protocol.Reader) {
+	io.NBT(&pk.Payload, nbt.NetworkLittleEndian)
+}
+
+func (pk *EditorNetwork) marshalWriter(io *protocol.Writer) {
+	io.NBT(&pk.Payload, nbt.NetworkLittleEndian)
+}
+
+func (pk *EducationResourceURI) marshalReader(io *protocol.Reader) {
+	protocol.Single(io, &pk.Resource)
+}
+
+func (pk *EducationResourceURI) marshalWriter(io *protocol.Writer) {
+	protocol.Single(io, &pk.Resource)
+}
+
+func (pk *EducationSettings) marshalReader(io *protocol.Reader) {
+	io.String(&pk.CodeBuilderDefaultURI)
+	io.String(&pk.CodeBuilderTitle)
+	io.Bool(&pk.CanResizeCodeBuilder)
+	io.Bool(&pk.DisableLegacyTitleBar)
+	io.String(&pk.PostProcessFilter)
+	io.String(&pk.ScreenshotBorderPath)
+	protocol.OptionalFunc(io, &pk.CanModifyBlocks, io.Bool)
+	protocol.OptionalFunc(io, &pk.OverrideURI, io.String)
+	io.Bool(&pk.HasQuiz)
+	protocol.OptionalMarshaler(io, &pk.ExternalLinkSettings)
+}
+
+func (pk *EducationSettings) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.CodeBuilderDefaultURI)
+	io.String(&pk.CodeBuilderTitle)
+	io.Bool(&pk.CanResizeCodeBuilder)
+	io.Bool(&pk.DisableLegacyTitleBar)
+	io.String(&pk.PostProcessFilter)
+	io.String(&pk.ScreenshotBorderPath)
+	protocol.OptionalFunc(io, &pk.CanModifyBlocks, io.Bool)
+	protocol.OptionalFunc(io, &pk.OverrideURI, io.String)
+	io.Bool(&pk.HasQuiz)
+	protocol.OptionalMarshaler(io, &pk.ExternalLinkSettings)
+}
+
+func (pk *Emote) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.String(&pk.EmoteID)
+	io.String(&pk.XUID)
+	io.String(&pk.PlatformID)
+	io.Uint8(&pk.Flags)
+}
+
+func (pk *Emote) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.String(&pk.EmoteID)
+	io.String(&pk.XUID)
+	io.String(&pk.PlatformID)
+	io.Uint8(&pk.Flags)
+}
+
+func (pk *EmoteList) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.PlayerRuntimeID)
+	protocol.FuncSlice(io, &pk.EmotePieces, io.UUID)
+}
+
+func (pk *EmoteList) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.PlayerRuntimeID)
+	protocol.FuncSlice(io, &pk.EmotePieces, io.UUID)
+}
+
+func (pk *Event) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.EventType(&pk.Event)
+	io.Uint8(&pk.UsePlayerID)
+	pk.Event.Marshal(io)
+}
+
+func (pk *Event) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.EventType(&pk.Event)
+	io.Uint8(&pk.UsePlayerID)
+	pk.Event.Marshal(io)
+}
+
+func (pk *FeatureRegistry) marshalReader(io *protocol.Reader) {
+	protocol.Slice(io, &pk.Features)
+}
+
+func (pk *FeatureRegistry) marshalWriter(io *protocol.Writer) {
+	protocol.Slice(io, &pk.Features)
+}
+
+func (pk *FilterText) marshalReader(io *protocol.Reader) {
+	io.String(&pk.Text)
+	io.Bool(&pk.FromServer)
+}
+
+func (pk *FilterText) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.Text)
+	io.Bool(&pk.FromServer)
+}
+
+func (pk *GUIDataPickItem) marshalReader(io *protocol.Reader) {
+	io.String(&pk.ItemName)
+	io.String(&pk.ItemEffects)
+	io.Int32(&pk.HotBarSlot)
+}
+
+func (pk *GUIDataPickItem) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.ItemName)
+	io.String(&pk.ItemEffects)
+	io.Int32(&pk.HotBarSlot)
+}
+
+func (pk *GameRulesChanged) marshalReader(io *protocol.Reader) {
+	protocol.FuncSlice(io, &pk.GameRules, io.GameRule)
+}
+
+func (pk *GameRulesChanged) marshalWriter(io *protocol.Writer) {
+	protocol.FuncSlice(io, &pk.GameRules, io.GameRule)
+}
+
+func (pk *GameTestRequest) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.MaxTestsPerBatch)
+	io.Varint32(&pk.Repetitions)
+	io.Uint8(&pk.Rotation)
+	io.Bool(&pk.StopOnError)
+	io.BlockPos(&pk.Position)
+	io.Varint32(&pk.TestsPerRow)
+	io.String(&pk.Name)
+}
+
+func (pk *GameTestRequest) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.MaxTestsPerBatch)
+	io.Varint32(&pk.Repetitions)
+	io.Uint8(&pk.Rotation)
+	io.Bool(&pk.StopOnError)
+	io.BlockPos(&pk.Position)
+	io.Varint32(&pk.TestsPerRow)
+	io.String(&pk.Name)
+}
+
+func (pk *GameTestResults) marshalReader(io *protocol.Reader) {
+	io.Bool(&pk.Succeeded)
+	io.String(&pk.Error)
+	io.String(&pk.Name)
+}
+
+func (pk *GameTestResults) marshalWriter(io *protocol.Writer) {
+	io.Bool(&pk.Succeeded)
+	io.String(&pk.Error)
+	io.String(&pk.Name)
+}
+
+func (pk *HurtArmour) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.Cause)
+	io.Varint32(&pk.Damage)
+	io.Varint64(&pk.ArmourSlots)
+}
+
+func (pk *HurtArmour) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.Cause)
+	io.Varint32(&pk.Damage)
+	io.Varint64(&pk.ArmourSlots)
+}
+
+func (pk *Interact) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.ActionType)
+	io.Varuint64(&pk.TargetEntityRuntimeID)
+	if pk.ActionType == InteractActionMouseOverEntity || pk.ActionType == InteractActionLeaveVehicle {
+		io.Vec3(&pk.Position)
+	}
+}
+
+func (pk *Interact) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.ActionType)
+	io.Varuint64(&pk.TargetEntityRuntimeID)
+	if pk.ActionType == InteractActionMouseOverEntity || pk.ActionType == InteractActionLeaveVehicle {
+		io.Vec3(&pk.Position)
+	}
+}
+
+func (pk *InventoryContent) marshalReader(io *protocol.Reader) {
+	io.Varuint32(&pk.WindowID)
+	protocol.FuncSlice(io, &pk.Content, io.ItemInstance)
+}
+
+func (pk *InventoryContent) marshalWriter(io *protocol.Writer) {
+	io.Varuint32(&pk.WindowID)
+	protocol.FuncSlice(io, &pk.Content, io.ItemInstance)
+}
+
+func (pk *InventorySlot) marshalReader(io *protocol.Reader) {
+	io.Varuint32(&pk.WindowID)
+	io.Varuint32(&pk.Slot)
+	io.ItemInstance(&pk.NewItem)
+}
+
+func (pk *InventorySlot) marshalWriter(io *protocol.Writer) {
+	io.Varuint32(&pk.WindowID)
+	io.Varuint32(&pk.Slot)
+	io.ItemInstance(&pk.NewItem)
+}
+
+func (pk *InventoryTransaction) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.LegacyRequestID)
+	if pk.LegacyRequestID != 0 {
+		protocol.Slice(io, &pk.LegacySetItemSlots)
+	}
+	io.TransactionDataType(&pk.TransactionData)
+	protocol.Slice(io, &pk.Actions)
+	pk.TransactionData.Marshal(io)
+}
+
+func (pk *InventoryTransaction) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.LegacyRequestID)
+	if pk.LegacyRequestID != 0 {
+		protocol.Slice(io, &pk.LegacySetItemSlots)
+	}
+	io.TransactionDataType(&pk.TransactionData)
+	protocol.Slice(io, &pk.Actions)
+	pk.TransactionData.Marshal(io)
+}
+
+func (pk *ItemComponent) marshalReader(io *protocol.Reader) {
+	protocol.Slice(io, &pk.Items)
+}
+
+func (pk *ItemComponent) marshalWriter(io *protocol.Writer) {
+	protocol.Slice(io, &pk.Items)
+}
+
+func (pk *ItemStackRequest) marshalReader(io *protocol.Reader) {
+	protocol.Slice(io, &pk.Requests)
+}
+
+func (pk *ItemStackRequest) marshalWriter(io *protocol.Writer) {
+	protocol.Slice(io, &pk.Requests)
+}
+
+func (pk *ItemStackResponse) marshalReader(io *protocol.Reader) {
+	protocol.Slice(io, &pk.Responses)
+}
+
+func (pk *ItemStackResponse) marshalWriter(io *protocol.Writer) {
+	protocol.Slice(io, &pk.Responses)
+}
+
+func (pk *LabTable) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.ActionType)
+	io.BlockPos(&pk.Position)
+	io.Uint8(&pk.ReactionType)
+}
+
+func (pk *LabTable) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.ActionType)
+	io.BlockPos(&pk.Position)
+	io.Uint8(&pk.ReactionType)
+}
+
+func (pk *LecternUpdate) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.Page)
+	io.Uint8(&pk.PageCount)
+	io.UBlockPos(&pk.Position)
+}
+
+func (pk *LecternUpdate) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.Page)
+	io.Uint8(&pk.PageCount)
+	io.UBlockPos(&pk.Position)
+}
+
+func (pk *LessonProgress) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.Action)
+	io.Varint32(&pk.Score)
+	io.String(&pk.Identifier)
+}
+
+func (pk *LessonProgress) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.Action)
+	io.Varint32(&pk.Score)
+	io.String(&pk.Identifier)
+}
+
+func (pk *LevelChunk) marshalReader(io *protocol.Reader) {
+	io.ChunkPos(&pk.Position)
+	io.Varint32(&pk.Dimension)
+	io.Varuint32(&pk.SubChunkCount)
+	if pk.SubChunkCount == protocol.SubChunkRequestModeLimited {
+		io.Uint16(&pk.HighestSubChunk)
+	}
+	io.Bool(&pk.CacheEnabled)
+	if pk.CacheEnabled {
+		protocol.FuncSlice(io, &pk.BlobHashes, io.Uint64)
+	}
+	io.ByteSlice(&pk.RawPayload)
+}
+
+func (pk *LevelChunk) marshalWriter(io *protocol.Writer) {
+	io.ChunkPos(&pk.Position)
+	io.Varint32(&pk.Dimension)
+	io.Varuint32(&pk.SubChunkCount)
+	if pk.SubChunkCount == protocol.SubChunkRequestModeLimited {
+		io.Uint16(&pk.HighestSubChunk)
+	}
+	io.Bool(&pk.CacheEnabled)
+	if pk.CacheEnabled {
+		protocol.FuncSlice(io, &pk.BlobHashes, io.Uint64)
+	}
+	io.ByteSlice(&pk.RawPayload)
+}
+
+func (pk *LevelEvent) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.EventType)
+	io.Vec3(&pk.Position)
+	io.Varint32(&pk.EventData)
+}
+
+func (pk *LevelEvent) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.EventType)
+	io.Vec3(&pk.Position)
+	io.Varint32(&pk.EventData)
+}
+
+func (pk *LevelEventGeneric) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.EventID)
+	io.Bytes(&pk.SerialisedEventData)
+}
+
+func (pk *LevelEventGeneric) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.EventID)
+	io.Bytes(&pk.SerialisedEventData)
+}
+
+func (pk *LevelSoundEvent) marshalReader(io *protocol.Reader) {
+	io.Varuint32(&pk.SoundType)
+	io.Vec3(&pk.Position)
+	io.Varint32(&pk.ExtraData)
+	io.String(&pk.EntityType)
+	io.Bool(&pk.BabyMob)
+	io.Bool(&pk.DisableRelativeVolume)
+}
+
+func (pk *LevelSoundEvent) marshalWriter(io *protocol.Writer) {
+	io.Varuint32(&pk.SoundType)
+	io.Vec3(&pk.Position)
+	io.Varint32(&pk.ExtraData)
+	io.String(&pk.EntityType)
+	io.Bool(&pk.BabyMob)
+	io.Bool(&pk.DisableRelativeVolume)
+}
+
+func (pk *Login) marshalReader(io *protocol.Reader) {
+	io.BEInt32(&pk.ClientProtocol)
+	io.ByteSlice(&pk.ConnectionRequest)
+}
+
+func (pk *Login) marshalWriter(io *protocol.Writer) {
+	io.BEInt32(&pk.ClientProtocol)
+	io.ByteSlice(&pk.ConnectionRequest)
+}
+
+func (pk *MapCreateLockedCopy) marshalReader(io *protocol.Reader) {
+	io.Varint64(&pk.OriginalMapID)
+	io.Varint64(&pk.NewMapID)
+}
+
+func (pk *MapCreateLockedCopy) marshalWriter(io *protocol.Writer) {
+	io.Varint64(&pk.OriginalMapID)
+	io.Varint64(&pk.NewMapID)
+}
+
+func (pk *MapInfoRequest) marshalReader(io *protocol.Reader) {
+	io.Varint64(&pk.MapID)
+	protocol.SliceUint32Length(io, &pk.ClientPixels)
+}
+
+func (pk *MapInfoRequest) marshalWriter(io *protocol.Writer) {
+	io.Varint64(&pk.MapID)
+	protocol.SliceUint32Length(io, &pk.ClientPixels)
+}
+
+func (pk *MobArmourEquipment) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.ItemInstance(&pk.Helmet)
+	io.ItemInstance(&pk.Chestplate)
+	io.ItemInstance(&pk.Leggings)
+	io.ItemInstance(&pk.Boots)
+}
+
+func (pk *MobArmourEquipment) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.ItemInstance(&pk.Helmet)
+	io.ItemInstance(&pk.Chestplate)
+	io.ItemInstance(&pk.Leggings)
+	io.ItemInstance(&pk.Boots)
+}
+
+func (pk *MobEffect) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Uint8(&pk.Operation)
+	io.Varint32(&pk.EffectType)
+	io.Varint32(&pk.Amplifier)
+	io.Bool(&pk.Particles)
+	io.Varint32(&pk.Duration)
+	io.Uint64(&pk.Tick)
+}
+
+func (pk *MobEffect) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Uint8(&pk.Operation)
+	io.Varint32(&pk.EffectType)
+	io.Varint32(&pk.Amplifier)
+	io.Bool(&pk.Particles)
+	io.Varint32(&pk.Duration)
+	io.Uint64(&pk.Tick)
+}
+
+func (pk *MobEquipment) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.ItemInstance(&pk.NewItem)
+	io.Uint8(&pk.InventorySlot)
+	io.Uint8(&pk.HotBarSlot)
+	io.Uint8(&pk.WindowID)
+}
+
+func (pk *MobEquipment) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.ItemInstance(&pk.NewItem)
+	io.Uint8(&pk.InventorySlot)
+	io.Uint8(&pk.HotBarSlot)
+	io.Uint8(&pk.WindowID)
+}
+
+func (pk *ModalFormRequest) marshalReader(io *protocol.Reader) {
+	io.Varuint32(&pk.FormID)
+	io.ByteSlice(&pk.FormData)
+}
+
+func (pk *ModalFormRequest) marshalWriter(io *protocol.Writer) {
+	io.Varuint32(&pk.FormID)
+	io.ByteSlice(&pk.FormData)
+}
+
+func (pk *ModalFormResponse) marshalReader(io *protocol.Reader) {
+	io.Varuint32(&pk.FormID)
+	protocol.OptionalFunc(io, &pk.ResponseData, io.ByteSlice)
+	protocol.OptionalFunc(io, &pk.CancelReason, io.Uint8)
+}
+
+func (pk *ModalFormResponse) marshalWriter(io *protocol.Writer) {
+	io.Varuint32(&pk.FormID)
+	protocol.OptionalFunc(io, &pk.ResponseData, io.ByteSlice)
+	protocol.OptionalFunc(io, &pk.CancelReason, io.Uint8)
+}
+
+func (pk *MotionPredictionHints) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Vec3(&pk.Velocity)
+	io.Bool(&pk.OnGround)
+}
+
+func (pk *MotionPredictionHints) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Vec3(&pk.Velocity)
+	io.Bool(&pk.OnGround)
+}
+
+func (pk *MoveActorAbsolute) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Uint8(&pk.Flags)
+	io.Vec3(&pk.Position)
+	io.ByteFloat(&pk.Rotation[0])
+	io.ByteFloat(&pk.Rotation[1])
+	io.ByteFloat(&pk.Rotation[2])
+}
+
+func (pk *MoveActorAbsolute) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Uint8(&pk.Flags)
+	io.Vec3(&pk.Position)
+	io.ByteFloat(&pk.Rotation[0])
+	io.ByteFloat(&pk.Rotation[1])
+	io.ByteFloat(&pk.Rotation[2])
+}
+
+func (pk *MoveActorDelta) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Uint16(&pk.Flags)
+	if pk.Flags&MoveActorDeltaFlagHasX != 0 {
+		io.Float32(&pk.Position[0])
+	} else {
+		pk.Position[0] = 0
+	}
+	if pk.Flags&MoveActorDeltaFlagHasY != 0 {
+		io.Float32(&pk.Position[1])
+	} else {
+		pk.Position[1] = 0
+	}
+	if pk.Flags&MoveActorDeltaFlagHasZ != 0 {
+		io.Float32(&pk.Position[2])
+	} else {
+		pk.Position[2] = 0
+	}
+	if pk.Flags&MoveActorDeltaFlagHasRotX != 0 {
+		io.ByteFloat(&pk.Rotation[0])
+	} else {
+		pk.Rotation[0] = 0
+	}
+	if pk.Flags&MoveActorDeltaFlagHasRotY != 0 {
+		io.ByteFloat(&pk.Rotation[1])
+	} else {
+		pk.Rotation[1] = 0
+	}
+	if pk.Flags&MoveActorDeltaFlagHasRotZ != 0 {
+		io.ByteFloat(&pk.Rotation[2])
+	} else {
+		pk.Rotation[2] = 0
+	}
+}
+
+func (pk *MoveActorDelta) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Uint16(&pk.Flags)
+	if pk.Flags&MoveActorDeltaFlagHasX != 0 {
+		io.Float32(&pk.Position[0])
+	} else {
+		pk.Position[0] = 0
+	}
+	if pk.Flags&MoveActorDeltaFlagHasY != 0 {
+		io.Float32(&pk.Position[1])
+	} else {
+		pk.Position[1] = 0
+	}
+	if pk.Flags&MoveActorDeltaFlagHasZ != 0 {
+		io.Float32(&pk.Position[2])
+	} else {
+		pk.Position[2] = 0
+	}
+	if pk.Flags&MoveActorDeltaFlagHasRotX != 0 {
+		io.ByteFloat(&pk.Rotation[0])
+	} else {
+		pk.Rotation[0] = 0
+	}
+	if pk.Flags&MoveActorDeltaFlagHasRotY != 0 {
+		io.ByteFloat(&pk.Rotation[1])
+	} else {
+		pk.Rotation[1] = 0
+	}
+	if pk.Flags&MoveActorDeltaFlagHasRotZ != 0 {
+		io.ByteFloat(&pk.Rotation[2])
+	} else {
+		pk.Rotation[2] = 0
+	}
+}
+
+func (pk *MovePlayer) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Vec3(&pk.Position)
+	io.Float32(&pk.Pitch)
+	io.Float32(&pk.Yaw)
+	io.Float32(&pk.HeadYaw)
+	io.Uint8(&pk.Mode)
+	io.Bool(&pk.OnGround)
+	io.Varuint64(&pk.RiddenEntityRuntimeID)
+	if pk.Mode == MoveModeTeleport {
+		io.Int32(&pk.TeleportCause)
+		io.Int32(&pk.TeleportSourceEntityType)
+	}
+	io.Varuint64(&pk.Tick)
+}
+
+func (pk *MovePlayer) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Vec3(&pk.Position)
+	io.Float32(&pk.Pitch)
+	io.Float32(&pk.Yaw)
+	io.Float32(&pk.HeadYaw)
+	io.Uint8(&pk.Mode)
+	io.Bool(&pk.OnGround)
+	io.Varuint64(&pk.RiddenEntityRuntimeID)
+	if pk.Mode == MoveModeTeleport {
+		io.Int32(&pk.TeleportCause)
+		io.Int32(&pk.TeleportSourceEntityType)
+	}
+	io.Varuint64(&pk.Tick)
+}
+
+func (pk *MultiPlayerSettings) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.ActionType)
+}
+
+func (pk *MultiPlayerSettings) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.ActionType)
+}
+
+func (pk *NPCDialogue) marshalReader(io *protocol.Reader) {
+	io.Uint64(&pk.EntityUniqueID)
+	io.Varint32(&pk.ActionType)
+	io.String(&pk.Dialogue)
+	io.String(&pk.SceneName)
+	io.String(&pk.NPCName)
+	io.String(&pk.ActionJSON)
+}
+
+func (pk *NPCDialogue) marshalWriter(io *protocol.Writer) {
+	io.Uint64(&pk.EntityUniqueID)
+	io.Varint32(&pk.ActionType)
+	io.String(&pk.Dialogue)
+	io.String(&pk.SceneName)
+	io.String(&pk.NPCName)
+	io.String(&pk.ActionJSON)
+}
+
+func (pk *NPCRequest) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Uint8(&pk.RequestType)
+	io.String(&pk.CommandString)
+	io.Uint8(&pk.ActionType)
+	io.String(&pk.SceneName)
+}
+
+func (pk *NPCRequest) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Uint8(&pk.RequestType)
+	io.String(&pk.CommandString)
+	io.Uint8(&pk.ActionType)
+	io.String(&pk.SceneName)
+}
+
+func (pk *NetworkChunkPublisherUpdate) marshalReader(io *protocol.Reader) {
+	io.BlockPos(&pk.Position)
+	io.Varuint32(&pk.Radius)
+	protocol.FuncSliceUint32Length(io, &pk.SavedChunks, io.ChunkPos)
+}
+
+func (pk *NetworkChunkPublisherUpdate) marshalWriter(io *protocol.Writer) {
+	io.BlockPos(&pk.Position)
+	io.Varuint32(&pk.Radius)
+	protocol.FuncSliceUint32Length(io, &pk.SavedChunks, io.ChunkPos)
+}
+
+func (pk *NetworkSettings) marshalReader(io *protocol.Reader) {
+	io.Uint16(&pk.CompressionThreshold)
+	io.Uint16(&pk.CompressionAlgorithm)
+	io.Bool(&pk.ClientThrottle)
+	io.Uint8(&pk.ClientThrottleThreshold)
+	io.Float32(&pk.ClientThrottleScalar)
+}
+
+func (pk *NetworkSettings) marshalWriter(io *protocol.Writer) {
+	io.Uint16(&pk.CompressionThreshold)
+	io.Uint16(&pk.CompressionAlgorithm)
+	io.Bool(&pk.ClientThrottle)
+	io.Uint8(&pk.ClientThrottleThreshold)
+	io.Float32(&pk.ClientThrottleScalar)
+}
+
+func (pk *NetworkStackLatency) marshalReader(io *protocol.Reader) {
+	io.Int64(&pk.Timestamp)
+	io.Bool(&pk.NeedsResponse)
+}
+
+func (pk *NetworkStackLatency) marshalWriter(io *protocol.Writer) {
+	io.Int64(&pk.Timestamp)
+	io.Bool(&pk.NeedsResponse)
+}
+
+func (pk *OnScreenTextureAnimation) marshalReader(io *protocol.Reader) {
+	io.Int32(&pk.AnimationType)
+}
+
+func (pk *OnScreenTextureAnimation) marshalWriter(io *protocol.Writer) {
+	io.Int32(&pk.AnimationType)
+}
+
+func (pk *OpenSign) marshalReader(io *protocol.Reader) {
+	io.UBlockPos(&pk.Position)
+	io.Bool(&pk.FrontSide)
+}
+
+func (pk *OpenSign) marshalWriter(io *protocol.Writer) {
+	io.UBlockPos(&pk.Position)
+	io.Bool(&pk.FrontSide)
+}
+
+func (pk *PacketViolationWarning) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.Type)
+	io.Varint32(&pk.Severity)
+	io.Varint32(&pk.PacketID)
+	io.String(&pk.ViolationContext)
+}
+
+func (pk *PacketViolationWarning) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.Type)
+	io.Varint32(&pk.Severity)
+	io.Varint32(&pk.PacketID)
+	io.String(&pk.ViolationContext)
+}
+
+func (pk *PassengerJump) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.JumpStrength)
+}
+
+func (pk *PassengerJump) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.JumpStrength)
+}
+
+func (pk *PhotoInfoRequest) marshalReader(io *protocol.Reader) {
+	io.Varint64(&pk.PhotoID)
+}
+
+func (pk *PhotoInfoRequest) marshalWriter(io *protocol.Writer) {
+	io.Varint64(&pk.PhotoID)
+}
+
+func (pk *PhotoTransfer) marshalReader(io *protocol.Reader) {
+	io.String(&pk.PhotoName)
+	io.ByteSlice(&pk.PhotoData)
+	io.String(&pk.BookID)
+	io.Uint8(&pk.PhotoType)
+	io.Uint8(&pk.SourceType)
+	io.Int64(&pk.OwnerEntityUniqueID)
+	io.String(&pk.NewPhotoName)
+}
+
+func (pk *PhotoTransfer) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.PhotoName)
+	io.ByteSlice(&pk.PhotoData)
+	io.String(&pk.BookID)
+	io.Uint8(&pk.PhotoType)
+	io.Uint8(&pk.SourceType)
+	io.Int64(&pk.OwnerEntityUniqueID)
+	io.String(&pk.NewPhotoName)
+}
+
+func (pk *PlaySound) marshalReader(io *protocol.Reader) {
+	io.String(&pk.SoundName)
+	io.SoundPos(&pk.Position)
+	io.Float32(&pk.Volume)
+	io.Float32(&pk.Pitch)
+}
+
+func (pk *PlaySound) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.SoundName)
+	io.SoundPos(&pk.Position)
+	io.Float32(&pk.Volume)
+	io.Float32(&pk.Pitch)
+}
+
+func (pk *PlayStatus) marshalReader(io *protocol.Reader) {
+	io.BEInt32(&pk.Status)
+}
+
+func (pk *PlayStatus) marshalWriter(io *protocol.Writer) {
+	io.BEInt32(&pk.Status)
+}
+
+func (pk *PlayerAction) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Varint32(&pk.ActionType)
+	io.UBlockPos(&pk.BlockPosition)
+	io.UBlockPos(&pk.ResultPosition)
+	io.Varint32(&pk.BlockFace)
+}
+
+func (pk *PlayerAction) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Varint32(&pk.ActionType)
+	io.UBlockPos(&pk.BlockPosition)
+	io.UBlockPos(&pk.ResultPosition)
+	io.Varint32(&pk.BlockFace)
+}
+
+func (pk *PlayerArmourDamage) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.Bitset)
+	if pk.Bitset&0b0001 != 0 {
+		io.Varint32(&pk.HelmetDamage)
+	} else {
+		pk.HelmetDamage = 0
+	}
+	if pk.Bitset&0b0010 != 0 {
+		io.Varint32(&pk.ChestplateDamage)
+	} else {
+		pk.ChestplateDamage = 0
+	}
+	if pk.Bitset&0b0100 != 0 {
+		io.Varint32(&pk.LeggingsDamage)
+	} else {
+		pk.LeggingsDamage = 0
+	}
+	if pk.Bitset&0b1000 != 0 {
+		io.Varint32(&pk.BootsDamage)
+	} else {
+		pk.BootsDamage = 0
+	}
+}
+
+func (pk *PlayerArmourDamage) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.Bitset)
+	if pk.Bitset&0b0001 != 0 {
+		io.Varint32(&pk.HelmetDamage)
+	} else {
+		pk.HelmetDamage = 0
+	}
+	if pk.Bitset&0b0010 != 0 {
+		io.Varint32(&pk.ChestplateDamage)
+	} else {
+		pk.ChestplateDamage = 0
+	}
+	if pk.Bitset&0b0100 != 0 {
+		io.Varint32(&pk.LeggingsDamage)
+	} else {
+		pk.LeggingsDamage = 0
+	}
+	if pk.Bitset&0b1000 != 0 {
+		io.Varint32(&pk.BootsDamage)
+	} else {
+		pk.BootsDamage = 0
+	}
+}
+
+func (pk *PlayerAuthInput) marshalReader(io *protocol.Reader) {
+	io.Float32(&pk.Pitch)
+	io.Float32(&pk.Yaw)
+	io.Vec3(&pk.Position)
+	io.Vec2(&pk.MoveVector)
+	io.Float32(&pk.HeadYaw)
+	io.Varuint64(&pk.InputData)
+	io.Varuint32(&pk.InputMode)
+	io.Varuint32(&pk.PlayMode)
+	io.Varuint32(&pk.InteractionModel)
+	if pk.PlayMode == PlayModeReality {
+		io.Vec3(&pk.GazeDirection)
+	}
+	io.Varuint64(&pk.Tick)
+	io.Vec3(&pk.Delta)
+
+	if pk.InputData&InputFlagPerformItemInteraction != 0 {
+		io.PlayerInventoryAction(&pk.ItemInteractionData)
+	}
+
+	if pk.InputData&InputFlagPerformItemStackRequest != 0 {
+		protocol.Single(io, &pk.ItemStackRequest)
+	}
+
+	if pk.InputData&InputFlagClientPredictedVehicle != 0 {
+		io.Vec2(&pk.VehicleRotation)
+		io.Varint64(&pk.ClientPredictedVehicle)
+	}
+
+	if pk.InputData&InputFlagPerformBlockActions != 0 {
+		protocol.SliceVarint32Length(io, &pk.BlockActions)
+	}
+
+	io.Vec2(&pk.AnalogueMoveVector)
+}
+
+func (pk *PlayerAuthInput) marshalWriter(io *protocol.Writer) {
+	io.Float32(&pk.Pitch)
+	io.Float32(&pk.Yaw)
+	io.Vec3(&pk.Position)
+	io.Vec2(&pk.MoveVector)
+	io.Float32(&pk.HeadYaw)
+	io.Varuint64(&pk.InputData)
+	io.Varuint32(&pk.InputMode)
+	io.Varuint32(&pk.PlayMode)
+	io.Varuint32(&pk.InteractionModel)
+	if pk.PlayMode == PlayModeReality {
+		io.Vec3(&pk.GazeDirection)
+	}
+	io.Varuint64(&pk.Tick)
+	io.Vec3(&pk.Delta)
+
+	if pk.InputData&InputFlagPerformItemInteraction != 0 {
+		io.PlayerInventoryAction(&pk.ItemInteractionData)
+	}
+
+	if pk.InputData&InputFlagPerformItemStackRequest != 0 {
+		protocol.Single(io, &pk.ItemStackRequest)
+	}
+
+	if pk.InputData&InputFlagClientPredictedVehicle != 0 {
+		io.Vec2(&pk.VehicleRotation)
+		io.Varint64(&pk.ClientPredictedVehicle)
+	}
+
+	if pk.InputData&InputFlagPerformBlockActions != 0 {
+		protocol.SliceVarint32Length(io, &pk.BlockActions)
+	}
+
+	io.Vec2(&pk.AnalogueMoveVector)
+}
+
+func (pk *PlayerEnchantOptions) marshalReader(io *protocol.Reader) {
+	protocol.Slice(io, &pk.Options)
+}
+
+func (pk *PlayerEnchantOptions) marshalWriter(io *protocol.Writer) {
+	protocol.Slice(io, &pk.Options)
+}
+
+func (pk *PlayerFog) marshalReader(io *protocol.Reader) {
+	protocol.FuncSlice(io, &pk.Stack, io.String)
+}
+
+func (pk *PlayerFog) marshalWriter(io *protocol.Writer) {
+	protocol.FuncSlice(io, &pk.Stack, io.String)
+}
+
+func (pk *PlayerHotBar) marshalReader(io *protocol.Reader) {
+	io.Varuint32(&pk.SelectedHotBarSlot)
+	io.Uint8(&pk.WindowID)
+	io.Bool(&pk.SelectHotBarSlot)
+}
+
+func (pk *PlayerHotBar) marshalWriter(io *protocol.Writer) {
+	io.Varuint32(&pk.SelectedHotBarSlot)
+	io.Uint8(&pk.WindowID)
+	io.Bool(&pk.SelectHotBarSlot)
+}
+
+func (pk *PlayerInput) marshalReader(io *protocol.Reader) {
+	io.Vec2(&pk.Movement)
+	io.Bool(&pk.Jumping)
+	io.Bool(&pk.Sneaking)
+}
+
+func (pk *PlayerInput) marshalWriter(io *protocol.Writer) {
+	io.Vec2(&pk.Movement)
+	io.Bool(&pk.Jumping)
+	io.Bool(&pk.Sneaking)
+}
+
+func (pk *PlayerList) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.ActionType)
+	switch pk.ActionType {
+	case PlayerListActionAdd:
+		protocol.Slice(io, &pk.Entries)
+	case PlayerListActionRemove:
+		protocol.FuncIOSlice(io, &pk.Entries, protocol.PlayerListRemoveEntry)
+	default:
+		io.UnknownEnumOption(pk.ActionType, "player list action type")
+	}
+	if pk.ActionType == PlayerListActionAdd {
+		for i := 0; i < len(pk.Entries); i++ {
+			io.Bool(&pk.Entries[i].Skin.Trusted)
+		}
+	}
+}
+
+func (pk *PlayerList) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.ActionType)
+	switch pk.ActionType {
+	case PlayerListActionAdd:
+		protocol.Slice(io, &pk.Entries)
+	case PlayerListActionRemove:
+		protocol.FuncIOSlice(io, &pk.Entries, protocol.PlayerListRemoveEntry)
+	default:
+		io.UnknownEnumOption(pk.ActionType, "player list action type")
+	}
+	if pk.ActionType == PlayerListActionAdd {
+		for i := 0; i < len(pk.Entries); i++ {
+			io.Bool(&pk.Entries[i].Skin.Trusted)
+		}
+	}
+}
+
+func (pk *PlayerSkin) marshalReader(io *protocol.Reader) {
+	io.UUID(&pk.UUID)
+	protocol.Single(io, &pk.Skin)
+	io.String(&pk.NewSkinName)
+	io.String(&pk.OldSkinName)
+	io.Bool(&pk.Skin.Trusted)
+}
+
+func (pk *PlayerSkin) marshalWriter(io *protocol.Writer) {
+	io.UUID(&pk.UUID)
+	protocol.Single(io, &pk.Skin)
+	io.String(&pk.NewSkinName)
+	io.String(&pk.OldSkinName)
+	io.Bool(&pk.Skin.Trusted)
+}
+
+func (pk *PlayerToggleCrafterSlotRequest) marshalReader(io *protocol.Reader) {
+	io.Int32(&pk.PosX)
+	io.Int32(&pk.PosY)
+	io.Int32(&pk.PosZ)
+	io.Uint8(&pk.Slot)
+	io.Bool(&pk.Disabled)
+}
+
+func (pk *PlayerToggleCrafterSlotRequest) marshalWriter(io *protocol.Writer) {
+	io.Int32(&pk.PosX)
+	io.Int32(&pk.PosY)
+	io.Int32(&pk.PosZ)
+	io.Uint8(&pk.Slot)
+	io.Bool(&pk.Disabled)
+}
+
+func (pk *PositionTrackingDBClientRequest) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.RequestAction)
+	io.Varint32(&pk.TrackingID)
+}
+
+func (pk *PositionTrackingDBClientRequest) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.RequestAction)
+	io.Varint32(&pk.TrackingID)
+}
+
+func (pk *PositionTrackingDBServerBroadcast) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.BroadcastAction)
+	io.Varint32(&pk.TrackingID)
+	io.NBT(&pk.Payload, nbt.NetworkLittleEndian)
+}
+
+func (pk *PositionTrackingDBServerBroadcast) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.BroadcastAction)
+	io.Varint32(&pk.TrackingID)
+	io.NBT(&pk.Payload, nbt.NetworkLittleEndian)
+}
+
+func (pk *PurchaseReceipt) marshalReader(io *protocol.Reader) {
+	protocol.FuncSlice(io, &pk.Receipts, io.String)
+}
+
+func (pk *PurchaseReceipt) marshalWriter(io *protocol.Writer) {
+	protocol.FuncSlice(io, &pk.Receipts, io.String)
+}
+
+func (pk *RemoveActor) marshalReader(io *protocol.Reader) {
+	io.Varint64(&pk.EntityUniqueID)
+}
+
+func (pk *RemoveActor) marshalWriter(io *protocol.Writer) {
+	io.Varint64(&pk.EntityUniqueID)
+}
+
+func (pk *RemoveObjective) marshalReader(io *protocol.Reader) {
+	io.String(&pk.ObjectiveName)
+}
+
+func (pk *RemoveObjective) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.ObjectiveName)
+}
+
+func (pk *RemoveVolumeEntity) marshalReader(io *protocol.Reader) {
+	io.Uint64(&pk.EntityRuntimeID)
+	io.Varint32(&pk.Dimension)
+}
+
+func (pk *RemoveVolumeEntity) marshalWriter(io *protocol.Writer) {
+	io.Uint64(&pk.EntityRuntimeID)
+	io.Varint32(&pk.Dimension)
+}
+
+func (pk *RequestAbility) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.Ability)
+	io.AbilityValue(&pk.Value)
+}
+
+func (pk *RequestAbility) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.Ability)
+	io.AbilityValue(&pk.Value)
+}
+
+func (pk *RequestChunkRadius) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.ChunkRadius)
+	io.Varint32(&pk.MaxChunkRadius)
+}
+
+func (pk *RequestChunkRadius) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.ChunkRadius)
+	io.Varint32(&pk.MaxChunkRadius)
+}
+
+func (pk *RequestNetworkSettings) marshalReader(io *protocol.Reader) {
+	io.BEInt32(&pk.ClientProtocol)
+}
+
+func (pk *RequestNetworkSettings) marshalWriter(io *protocol.Writer) {
+	io.BEInt32(&pk.ClientProtocol)
+}
+
+func (pk *RequestPermissions) marshalReader(io *protocol.Reader) {
+	io.Int64(&pk.EntityUniqueID)
+	io.Uint8(&pk.PermissionLevel)
+	io.Uint16(&pk.RequestedPermissions)
+}
+
+func (pk *RequestPermissions) marshalWriter(io *protocol.Writer) {
+	io.Int64(&pk.EntityUniqueID)
+	io.Uint8(&pk.PermissionLevel)
+	io.Uint16(&pk.RequestedPermissions)
+}
+
+func (pk *ResourcePackChunkData) marshalReader(io *protocol.Reader) {
+	io.String(&pk.UUID)
+	io.Uint32(&pk.ChunkIndex)
+	io.Uint64(&pk.DataOffset)
+	io.ByteSlice(&pk.Data)
+}
+
+func (pk *ResourcePackChunkData) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.UUID)
+	io.Uint32(&pk.ChunkIndex)
+	io.Uint64(&pk.DataOffset)
+	io.ByteSlice(&pk.Data)
+}
+
+func (pk *ResourcePackChunkRequest) marshalReader(io *protocol.Reader) {
+	io.String(&pk.UUID)
+	io.Uint32(&pk.ChunkIndex)
+}
+
+func (pk *ResourcePackChunkRequest) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.UUID)
+	io.Uint32(&pk.ChunkIndex)
+}
+
+func (pk *ResourcePackClientResponse) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.Response)
+	protocol.FuncSliceUint16Length(io, &pk.PacksToDownload, io.String)
+}
+
+func (pk *ResourcePackClientResponse) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.Response)
+	protocol.FuncSliceUint16Length(io, &pk.PacksToDownload, io.String)
+}
+
+func (pk *ResourcePackDataInfo) marshalReader(io *protocol.Reader) {
+	io.String(&pk.UUID)
+	io.Uint32(&pk.DataChunkSize)
+	io.Uint32(&pk.ChunkCount)
+	io.Uint64(&pk.Size)
+	io.ByteSlice(&pk.Hash)
+	io.Bool(&pk.Premium)
+	io.Uint8(&pk.PackType)
+}
+
+func (pk *ResourcePackDataInfo) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.UUID)
+	io.Uint32(&pk.DataChunkSize)
+	io.Uint32(&pk.ChunkCount)
+	io.Uint64(&pk.Size)
+	io.ByteSlice(&pk.Hash)
+	io.Bool(&pk.Premium)
+	io.Uint8(&pk.PackType)
+}
+
+func (pk *ResourcePackStack) marshalReader(io *protocol.Reader) {
+	io.Bool(&pk.TexturePackRequired)
+	protocol.Slice(io, &pk.BehaviourPacks)
+	protocol.Slice(io, &pk.TexturePacks)
+	io.String(&pk.BaseGameVersion)
+	protocol.SliceUint32Length(io, &pk.Experiments)
+	io.Bool(&pk.ExperimentsPreviouslyToggled)
+	io.Bool(&pk.IncludeEditorPacks)
+}
+
+func (pk *ResourcePackStack) marshalWriter(io *protocol.Writer) {
+	io.Bool(&pk.TexturePackRequired)
+	protocol.Slice(io, &pk.BehaviourPacks)
+	protocol.Slice(io, &pk.TexturePacks)
+	io.String(&pk.BaseGameVersion)
+	protocol.SliceUint32Length(io, &pk.Experiments)
+	io.Bool(&pk.ExperimentsPreviouslyToggled)
+	io.Bool(&pk.IncludeEditorPacks)
+}
+
+func (pk *ResourcePacksInfo) marshalReader(io *protocol.Reader) {
+	io.Bool(&pk.TexturePackRequired)
+	io.Bool(&pk.HasAddons)
+	io.Bool(&pk.HasScripts)
+	io.Bool(&pk.ForcingServerPacks)
+	protocol.SliceUint16Length(io, &pk.BehaviourPacks)
+	protocol.SliceUint16Length(io, &pk.TexturePacks)
+	protocol.Slice(io, &pk.PackURLs)
+}
+
+func (pk *ResourcePacksInfo) marshalWriter(io *protocol.Writer) {
+	io.Bool(&pk.TexturePackRequired)
+	io.Bool(&pk.HasAddons)
+	io.Bool(&pk.HasScripts)
+	io.Bool(&pk.ForcingServerPacks)
+	protocol.SliceUint16Length(io, &pk.BehaviourPacks)
+	protocol.SliceUint16Length(io, &pk.TexturePacks)
+	protocol.Slice(io, &pk.PackURLs)
+}
+
+func (pk *Respawn) marshalReader(io *protocol.Reader) {
+	io.Vec3(&pk.Position)
+	io.Uint8(&pk.State)
+	io.Varuint64(&pk.EntityRuntimeID)
+}
+
+func (pk *Respawn) marshalWriter(io *protocol.Writer) {
+	io.Vec3(&pk.Position)
+	io.Uint8(&pk.State)
+	io.Varuint64(&pk.EntityRuntimeID)
+}
+
+func (pk *ScriptCustomEvent) marshalReader(io *protocol.Reader) {
+	io.String(&pk.EventName)
+	io.ByteSlice(&pk.EventData)
+}
+
+func (pk *ScriptCustomEvent) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.EventName)
+	io.ByteSlice(&pk.EventData)
+}
+
+func (pk *ScriptMessage) marshalReader(io *protocol.Reader) {
+	io.String(&pk.Identifier)
+	io.ByteSlice(&pk.Data)
+}
+
+func (pk *ScriptMessage) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.Identifier)
+	io.ByteSlice(&pk.Data)
+}
+
+func (pk *ServerSettingsResponse) marshalReader(io *protocol.Reader) {
+	io.Varuint32(&pk.FormID)
+	io.ByteSlice(&pk.FormData)
+}
+
+func (pk *ServerSettingsResponse) marshalWriter(io *protocol.Writer) {
+	io.Varuint32(&pk.FormID)
+	io.ByteSlice(&pk.FormData)
+}
+
+func (pk *ServerStats) marshalReader(io *protocol.Reader) {
+	io.Float32(&pk.ServerTime)
+	io.Float32(&pk.NetworkTime)
+}
+
+func (pk *ServerStats) marshalWriter(io *protocol.Writer) {
+	io.Float32(&pk.ServerTime)
+	io.Float32(&pk.NetworkTime)
+}
+
+func (pk *ServerToClientHandshake) marshalReader(io *protocol.Reader) {
+	io.ByteSlice(&pk.JWT)
+}
+
+func (pk *ServerToClientHandshake) marshalWriter(io *protocol.Writer) {
+	io.ByteSlice(&pk.JWT)
+}
+
+func (pk *SetActorData) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.EntityMetadata(&pk.EntityMetadata)
+	protocol.Single(io, &pk.EntityProperties)
+	io.Varuint64(&pk.Tick)
+}
+
+func (pk *SetActorData) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.EntityMetadata(&pk.EntityMetadata)
+	protocol.Single(io, &pk.EntityProperties)
+	io.Varuint64(&pk.Tick)
+}
+
+func (pk *SetActorLink) marshalReader(io *protocol.Reader) {
+	protocol.Single(io, &pk.EntityLink)
+}
+
+func (pk *SetActorLink) marshalWriter(io *protocol.Writer) {
+	protocol.Single(io, &pk.EntityLink)
+}
+
+func (pk *SetActorMotion) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Vec3(&pk.Velocity)
+	io.Varuint64(&pk.Tick)
+}
+
+func (pk *SetActorMotion) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Vec3(&pk.Velocity)
+	io.Varuint64(&pk.Tick)
+}
+
+func (pk *SetCommandsEnabled) marshalReader(io *protocol.Reader) {
+	io.Bool(&pk.Enabled)
+}
+
+func (pk *SetCommandsEnabled) marshalWriter(io *protocol.Writer) {
+	io.Bool(&pk.Enabled)
+}
+
+func (pk *SetDefaultGameType) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.GameType)
+}
+
+func (pk *SetDefaultGameType) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.GameType)
+}
+
+func (pk *SetDifficulty) marshalReader(io *protocol.Reader) {
+	io.Varuint32(&pk.Difficulty)
+}
+
+func (pk *SetDifficulty) marshalWriter(io *protocol.Writer) {
+	io.Varuint32(&pk.Difficulty)
+}
+
+func (pk *SetDisplayObjective) marshalReader(io *protocol.Reader) {
+	io.String(&pk.DisplaySlot)
+	io.String(&pk.ObjectiveName)
+	io.String(&pk.DisplayName)
+	io.String(&pk.CriteriaName)
+	io.Varint32(&pk.SortOrder)
+}
+
+func (pk *SetDisplayObjective) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.DisplaySlot)
+	io.String(&pk.ObjectiveName)
+	io.String(&pk.DisplayName)
+	io.String(&pk.CriteriaName)
+	io.Varint32(&pk.SortOrder)
+}
+
+func (pk *SetHealth) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.Health)
+}
+
+func (pk *SetHealth) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.Health)
+}
+
+func (pk *SetHud) marshalReader(io *protocol.Reader) {
+	protocol.FuncSlice(io, &pk.Elements, io.Uint8)
+	io.Uint8(&pk.Visibility)
+}
+
+func (pk *SetHud) marshalWriter(io *protocol.Writer) {
+	protocol.FuncSlice(io, &pk.Elements, io.Uint8)
+	io.Uint8(&pk.Visibility)
+}
+
+func (pk *SetLastHurtBy) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.EntityType)
+}
+
+func (pk *SetLastHurtBy) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.EntityType)
+}
+
+func (pk *SetLocalPlayerAsInitialised) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.EntityRuntimeID)
+}
+
+func (pk *SetLocalPlayerAsInitialised) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.EntityRuntimeID)
+}
+
+func (pk *SetPlayerGameType) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.GameType)
+}
+
+func (pk *SetPlayerGameType) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.GameType)
+}
+
+func (pk *SetPlayerInventoryOptions) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.LeftInventoryTab)
+	io.Uint8(&pk.RightInventoryTab)
+	io.Bool(&pk.Filtering)
+	io.Uint8(&pk.InventoryLayout)
+	io.Uint8(&pk.CraftingLayout)
+}
+
+func (pk *SetPlayerInventoryOptions) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.LeftInventoryTab)
+	io.Uint8(&pk.RightInventoryTab)
+	io.Bool(&pk.Filtering)
+	io.Uint8(&pk.InventoryLayout)
+	io.Uint8(&pk.CraftingLayout)
+}
+
+func (pk *SetScore) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.ActionType)
+	switch pk.ActionType {
+	case ScoreboardActionRemove:
+		protocol.FuncIOSlice(io, &pk.Entries, protocol.ScoreRemoveEntry)
+	case ScoreboardActionModify:
+		protocol.Slice(io, &pk.Entries)
+	default:
+		io.UnknownEnumOption(pk.ActionType, "set score action type")
+	}
+}
+
+func (pk *SetScore) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.ActionType)
+	switch pk.ActionType {
+	case ScoreboardActionRemove:
+		protocol.FuncIOSlice(io, &pk.Entries, protocol.ScoreRemoveEntry)
+	case ScoreboardActionModify:
+		protocol.Slice(io, &pk.Entries)
+	default:
+		io.UnknownEnumOption(pk.ActionType, "set score action type")
+	}
+}
+
+func (pk *SetScoreboardIdentity) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.ActionType)
+	switch pk.ActionType {
+	case ScoreboardIdentityActionRegister:
+		protocol.Slice(io, &pk.Entries)
+	case ScoreboardIdentityActionClear:
+		protocol.FuncIOSlice(io, &pk.Entries, protocol.ScoreboardIdentityClearEntry)
+	}
+}
+
+func (pk *SetScoreboardIdentity) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.ActionType)
+	switch pk.ActionType {
+	case ScoreboardIdentityActionRegister:
+		protocol.Slice(io, &pk.Entries)
+	case ScoreboardIdentityActionClear:
+		protocol.FuncIOSlice(io, &pk.Entries, protocol.ScoreboardIdentityClearEntry)
+	}
+}
+
+func (pk *SetSpawnPosition) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.SpawnType)
+	io.UBlockPos(&pk.Position)
+	io.Varint32(&pk.Dimension)
+	io.UBlockPos(&pk.SpawnPosition)
+}
+
+func (pk *SetSpawnPosition) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.SpawnType)
+	io.UBlockPos(&pk.Position)
+	io.Varint32(&pk.Dimension)
+	io.UBlockPos(&pk.SpawnPosition)
+}
+
+func (pk *SetTime) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.Time)
+}
+
+func (pk *SetTime) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.Time)
+}
+
+func (pk *SetTitle) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.ActionType)
+	io.String(&pk.Text)
+	io.Varint32(&pk.FadeInDuration)
+	io.Varint32(&pk.RemainDuration)
+	io.Varint32(&pk.FadeOutDuration)
+	io.String(&pk.XUID)
+	io.String(&pk.PlatformOnlineID)
+}
+
+func (pk *SetTitle) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.ActionType)
+	io.String(&pk.Text)
+	io.Varint32(&pk.FadeInDuration)
+	io.Varint32(&pk.RemainDuration)
+	io.Varint32(&pk.FadeOutDuration)
+	io.String(&pk.XUID)
+	io.String(&pk.PlatformOnlineID)
+}
+
+func (pk *SettingsCommand) marshalReader(io *protocol.Reader) {
+	io.String(&pk.CommandLine)
+	io.Bool(&pk.SuppressOutput)
+}
+
+func (pk *SettingsCommand) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.CommandLine)
+	io.Bool(&pk.SuppressOutput)
+}
+
+func (pk *ShowCredits) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.PlayerRuntimeID)
+	io.Varint32(&pk.StatusType)
+}
+
+func (pk *ShowCredits) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.PlayerRuntimeID)
+	io.Varint32(&pk.StatusType)
+}
+
+func (pk *ShowProfile) marshalReader(io *protocol.Reader) {
+	io.String(&pk.XUID)
+}
+
+func (pk *ShowProfile) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.XUID)
+}
+
+func (pk *ShowStoreOffer) marshalReader(io *protocol.Reader) {
+	io.String(&pk.OfferID)
+	io.Uint8(&pk.Type)
+}
+
+func (pk *ShowStoreOffer) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.OfferID)
+	io.Uint8(&pk.Type)
+}
+
+func (pk *SimpleEvent) marshalReader(io *protocol.Reader) {
+	io.Int16(&pk.EventType)
+}
+
+func (pk *SimpleEvent) marshalWriter(io *protocol.Writer) {
+	io.Int16(&pk.EventType)
+}
+
+func (pk *SimulationType) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.SimulationType)
+}
+
+func (pk *SimulationType) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.SimulationType)
+}
+
+func (pk *SpawnExperienceOrb) marshalReader(io *protocol.Reader) {
+	io.Vec3(&pk.Position)
+	io.Varint32(&pk.ExperienceAmount)
+}
+
+func (pk *SpawnExperienceOrb) marshalWriter(io *protocol.Writer) {
+	io.Vec3(&pk.Position)
+	io.Varint32(&pk.ExperienceAmount)
+}
+
+func (pk *SpawnParticleEffect) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.Dimension)
+	io.Varint64(&pk.EntityUniqueID)
+	io.Vec3(&pk.Position)
+	io.String(&pk.ParticleName)
+	protocol.OptionalFunc(io, &pk.MoLangVariables, io.ByteSlice)
+}
+
+func (pk *SpawnParticleEffect) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.Dimension)
+	io.Varint64(&pk.EntityUniqueID)
+	io.Vec3(&pk.Position)
+	io.String(&pk.ParticleName)
+	protocol.OptionalFunc(io, &pk.MoLangVariables, io.ByteSlice)
+}
+
+func (pk *StartGame) marshalReader(io *protocol.Reader) {
+	io.Varint64(&pk.EntityUniqueID)
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Varint32(&pk.PlayerGameMode)
+	io.Vec3(&pk.PlayerPosition)
+	io.Float32(&pk.Pitch)
+	io.Float32(&pk.Yaw)
+	io.Int64(&pk.WorldSeed)
+	io.Int16(&pk.SpawnBiomeType)
+	io.String(&pk.UserDefinedBiomeName)
+	io.Varint32(&pk.Dimension)
+	io.Varint32(&pk.Generator)
+	io.Varint32(&pk.WorldGameMode)
+	io.Bool(&pk.Hardcore)
+	io.Varint32(&pk.Difficulty)
+	io.UBlockPos(&pk.WorldSpawn)
+	io.Bool(&pk.AchievementsDisabled)
+	io.Varint32(&pk.EditorWorldType)
+	io.Bool(&pk.CreatedInEditor)
+	io.Bool(&pk.ExportedFromEditor)
+	io.Varint32(&pk.DayCycleLockTime)
+	io.Varint32(&pk.EducationEditionOffer)
+	io.Bool(&pk.EducationFeaturesEnabled)
+	io.String(&pk.EducationProductID)
+	io.Float32(&pk.RainLevel)
+	io.Float32(&pk.LightningLevel)
+	io.Bool(&pk.ConfirmedPlatformLockedContent)
+	io.Bool(&pk.MultiPlayerGame)
+	io.Bool(&pk.LANBroadcastEnabled)
+	io.Varint32(&pk.XBLBroadcastMode)
+	io.Varint32(&pk.PlatformBroadcastMode)
+	io.Bool(&pk.CommandsEnabled)
+	io.Bool(&pk.TexturePackRequired)
+	protocol.FuncSlice(io, &pk.GameRules, io.GameRule)
+	protocol.SliceUint32Length(io, &pk.Experiments)
+	io.Bool(&pk.ExperimentsPreviouslyToggled)
+	io.Bool(&pk.BonusChestEnabled)
+	io.Bool(&pk.StartWithMapEnabled)
+	io.Varint32(&pk.PlayerPermissions)
+	io.Int32(&pk.ServerChunkTickRadius)
+	io.Bool(&pk.HasLockedBehaviourPack)
+	io.Bool(&pk.HasLockedTexturePack)
+	io.Bool(&pk.FromLockedWorldTemplate)
+	io.Bool(&pk.MSAGamerTagsOnly)
+	io.Bool(&pk.FromWorldTemplate)
+	io.Bool(&pk.WorldTemplateSettingsLocked)
+	io.Bool(&pk.OnlySpawnV1Villagers)
+	io.Bool(&pk.PersonaDisabled)
+	io.Bool(&pk.CustomSkinsDisabled)
+	io.Bool(&pk.EmoteChatMuted)
+	io.String(&pk.BaseGameVersion)
+	io.Int32(&pk.LimitedWorldWidth)
+	io.Int32(&pk.LimitedWorldDepth)
+	io.Bool(&pk.NewNether)
+	protocol.Single(io, &pk.EducationSharedResourceURI)
+	protocol.OptionalFunc(io, &pk.ForceExperimentalGameplay, io.Bool)
+	io.Uint8(&pk.ChatRestrictionLevel)
+	io.Bool(&pk.DisablePlayerInteractions)
+	io.String(&pk.ServerID)
+	io.String(&pk.WorldID)
+	io.String(&pk.ScenarioID)
+	io.String(&pk.LevelID)
+	io.String(&pk.WorldName)
+	io.String(&pk.TemplateContentIdentity)
+	io.Bool(&pk.Trial)
+	protocol.PlayerMoveSettings(io, &pk.PlayerMovementSettings)
+	io.Int64(&pk.Time)
+	io.Varint32(&pk.EnchantmentSeed)
+	protocol.Slice(io, &pk.Blocks)
+	protocol.Slice(io, &pk.Items)
+	io.String(&pk.MultiPlayerCorrelationID)
+	io.Bool(&pk.ServerAuthoritativeInventory)
+	io.String(&pk.GameVersion)
+	io.NBT(&pk.PropertyData, nbt.NetworkLittleEndian)
+	io.Uint64(&pk.ServerBlockStateChecksum)
+	io.UUID(&pk.WorldTemplateID)
+	io.Bool(&pk.ClientSideGeneration)
+	io.Bool(&pk.UseBlockNetworkIDHashes)
+	io.Bool(&pk.ServerAuthoritativeSound)
+}
+
+func (pk *StartGame) marshalWriter(io *protocol.Writer) {
+	io.Varint64(&pk.EntityUniqueID)
+	io.Varuint64(&pk.EntityRuntimeID)
+	io.Varint32(&pk.PlayerGameMode)
+	io.Vec3(&pk.PlayerPosition)
+	io.Float32(&pk.Pitch)
+	io.Float32(&pk.Yaw)
+	io.Int64(&pk.WorldSeed)
+	io.Int16(&pk.SpawnBiomeType)
+	io.String(&pk.UserDefinedBiomeName)
+	io.Varint32(&pk.Dimension)
+	io.Varint32(&pk.Generator)
+	io.Varint32(&pk.WorldGameMode)
+	io.Bool(&pk.Hardcore)
+	io.Varint32(&pk.Difficulty)
+	io.UBlockPos(&pk.WorldSpawn)
+	io.Bool(&pk.AchievementsDisabled)
+	io.Varint32(&pk.EditorWorldType)
+	io.Bool(&pk.CreatedInEditor)
+	io.Bool(&pk.ExportedFromEditor)
+	io.Varint32(&pk.DayCycleLockTime)
+	io.Varint32(&pk.EducationEditionOffer)
+	io.Bool(&pk.EducationFeaturesEnabled)
+	io.String(&pk.EducationProductID)
+	io.Float32(&pk.RainLevel)
+	io.Float32(&pk.LightningLevel)
+	io.Bool(&pk.ConfirmedPlatformLockedContent)
+	io.Bool(&pk.MultiPlayerGame)
+	io.Bool(&pk.LANBroadcastEnabled)
+	io.Varint32(&pk.XBLBroadcastMode)
+	io.Varint32(&pk.PlatformBroadcastMode)
+	io.Bool(&pk.CommandsEnabled)
+	io.Bool(&pk.TexturePackRequired)
+	protocol.FuncSlice(io, &pk.GameRules, io.GameRule)
+	protocol.SliceUint32Length(io, &pk.Experiments)
+	io.Bool(&pk.ExperimentsPreviouslyToggled)
+	io.Bool(&pk.BonusChestEnabled)
+	io.Bool(&pk.StartWithMapEnabled)
+	io.Varint32(&pk.PlayerPermissions)
+	io.Int32(&pk.ServerChunkTickRadius)
+	io.Bool(&pk.HasLockedBehaviourPack)
+	io.Bool(&pk.HasLockedTexturePack)
+	io.Bool(&pk.FromLockedWorldTemplate)
+	io.Bool(&pk.MSAGamerTagsOnly)
+	io.Bool(&pk.FromWorldTemplate)
+	io.Bool(&pk.WorldTemplateSettingsLocked)
+	io.Bool(&pk.OnlySpawnV1Villagers)
+	io.Bool(&pk.PersonaDisabled)
+	io.Bool(&pk.CustomSkinsDisabled)
+	io.Bool(&pk.EmoteChatMuted)
+	io.String(&pk.BaseGameVersion)
+	io.Int32(&pk.LimitedWorldWidth)
+	io.Int32(&pk.LimitedWorldDepth)
+	io.Bool(&pk.NewNether)
+	protocol.Single(io, &pk.EducationSharedResourceURI)
+	protocol.OptionalFunc(io, &pk.ForceExperimentalGameplay, io.Bool)
+	io.Uint8(&pk.ChatRestrictionLevel)
+	io.Bool(&pk.DisablePlayerInteractions)
+	io.String(&pk.ServerID)
+	io.String(&pk.WorldID)
+	io.String(&pk.ScenarioID)
+	io.String(&pk.LevelID)
+	io.String(&pk.WorldName)
+	io.String(&pk.TemplateContentIdentity)
+	io.Bool(&pk.Trial)
+	protocol.PlayerMoveSettings(io, &pk.PlayerMovementSettings)
+	io.Int64(&pk.Time)
+	io.Varint32(&pk.EnchantmentSeed)
+	protocol.Slice(io, &pk.Blocks)
+	protocol.Slice(io, &pk.Items)
+	io.String(&pk.MultiPlayerCorrelationID)
+	io.Bool(&pk.ServerAuthoritativeInventory)
+	io.String(&pk.GameVersion)
+	io.NBT(&pk.PropertyData, nbt.NetworkLittleEndian)
+	io.Uint64(&pk.ServerBlockStateChecksum)
+	io.UUID(&pk.WorldTemplateID)
+	io.Bool(&pk.ClientSideGeneration)
+	io.Bool(&pk.UseBlockNetworkIDHashes)
+	io.Bool(&pk.ServerAuthoritativeSound)
+}
+
+func (pk *StopSound) marshalReader(io *protocol.Reader) {
+	io.String(&pk.SoundName)
+	io.Bool(&pk.StopAll)
+}
+
+func (pk *StopSound) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.SoundName)
+	io.Bool(&pk.StopAll)
+}
+
+func (pk *StructureBlockUpdate) marshalReader(io *protocol.Reader) {
+	io.UBlockPos(&pk.Position)
+	io.String(&pk.StructureName)
+	io.String(&pk.DataField)
+	io.Bool(&pk.IncludePlayers)
+	io.Bool(&pk.ShowBoundingBox)
+	io.Varint32(&pk.StructureBlockType)
+	protocol.Single(io, &pk.Settings)
+	io.Varint32(&pk.RedstoneSaveMode)
+	io.Bool(&pk.ShouldTrigger)
+	io.Bool(&pk.Waterlogged)
+}
+
+func (pk *StructureBlockUpdate) marshalWriter(io *protocol.Writer) {
+	io.UBlockPos(&pk.Position)
+	io.String(&pk.StructureName)
+	io.String(&pk.DataField)
+	io.Bool(&pk.IncludePlayers)
+	io.Bool(&pk.ShowBoundingBox)
+	io.Varint32(&pk.StructureBlockType)
+	protocol.Single(io, &pk.Settings)
+	io.Varint32(&pk.RedstoneSaveMode)
+	io.Bool(&pk.ShouldTrigger)
+	io.Bool(&pk.Waterlogged)
+}
+
+func (pk *StructureTemplateDataRequest) marshalReader(io *protocol.Reader) {
+	io.String(&pk.StructureName)
+	io.UBlockPos(&pk.Position)
+	protocol.Single(io, &pk.Settings)
+	io.Uint8(&pk.RequestType)
+}
+
+func (pk *StructureTemplateDataRequest) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.StructureName)
+	io.UBlockPos(&pk.Position)
+	protocol.Single(io, &pk.Settings)
+	io.Uint8(&pk.RequestType)
+}
+
+func (pk *StructureTemplateDataResponse) marshalReader(io *protocol.Reader) {
+	io.String(&pk.StructureName)
+	io.Bool(&pk.Success)
+	if pk.Success {
+		io.NBT(&pk.StructureTemplate, nbt.NetworkLittleEndian)
+	}
+	io.Uint8(&pk.ResponseType)
+}
+
+func (pk *StructureTemplateDataResponse) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.StructureName)
+	io.Bool(&pk.Success)
+	if pk.Success {
+		io.NBT(&pk.StructureTemplate, nbt.NetworkLittleEndian)
+	}
+	io.Uint8(&pk.ResponseType)
+}
+
+func (pk *SubChunk) marshalReader(io *protocol.Reader) {
+	io.Bool(&pk.CacheEnabled)
+	io.Varint32(&pk.Dimension)
+	io.SubChunkPos(&pk.Position)
+	if pk.CacheEnabled {
+		protocol.SliceUint32Length(io, &pk.SubChunkEntries)
+	} else {
+		protocol.FuncIOSliceUint32Length(io, &pk.SubChunkEntries, protocol.SubChunkEntryNoCache)
+	}
+}
+
+func (pk *SubChunk) marshalWriter(io *protocol.Writer) {
+	io.Bool(&pk.CacheEnabled)
+	io.Varint32(&pk.Dimension)
+	io.SubChunkPos(&pk.Position)
+	if pk.CacheEnabled {
+		protocol.SliceUint32Length(io, &pk.SubChunkEntries)
+	} else {
+		protocol.FuncIOSliceUint32Length(io, &pk.SubChunkEntries, protocol.SubChunkEntryNoCache)
+	}
+}
+
+func (pk *SubChunkRequest) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.Dimension)
+	io.SubChunkPos(&pk.Position)
+	protocol.SliceUint32Length(io, &pk.Offsets)
+}
+
+func (pk *SubChunkRequest) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.Dimension)
+	io.SubChunkPos(&pk.Position)
+	protocol.SliceUint32Length(io, &pk.Offsets)
+}
+
+func (pk *SubClientLogin) marshalReader(io *protocol.Reader) {
+	io.ByteSlice(&pk.ConnectionRequest)
+}
+
+func (pk *SubClientLogin) marshalWriter(io *protocol.Writer) {
+	io.ByteSlice(&pk.ConnectionRequest)
+}
+
+func (pk *SyncActorProperty) marshalReader(io *protocol.Reader) {
+	io.NBT(&pk.PropertyData, nbt.NetworkLittleEndian)
+}
+
+func (pk *SyncActorProperty) marshalWriter(io *protocol.Writer) {
+	io.NBT(&pk.PropertyData, nbt.NetworkLittleEndian)
+}
+
+func (pk *TakeItemActor) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.ItemEntityRuntimeID)
+	io.Varuint64(&pk.TakerEntityRuntimeID)
+}
+
+func (pk *TakeItemActor) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.ItemEntityRuntimeID)
+	io.Varuint64(&pk.TakerEntityRuntimeID)
+}
+
+func (pk *Text) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.TextType)
+	io.Bool(&pk.NeedsTranslation)
+	switch pk.TextType {
+	case TextTypeChat, TextTypeWhisper, TextTypeAnnouncement:
+		io.String(&pk.SourceName)
+		io.String(&pk.Message)
+	case TextTypeRaw, TextTypeTip, TextTypeSystem, TextTypeObject, TextTypeObjectWhisper, TextTypeObjectAnnouncement:
+		io.String(&pk.Message)
+	case TextTypeTranslation, TextTypePopup, TextTypeJukeboxPopup:
+		io.String(&pk.Message)
+		protocol.FuncSlice(io, &pk.Parameters, io.String)
+	}
+	io.String(&pk.XUID)
+	io.String(&pk.PlatformChatID)
+	io.String(&pk.FilteredMessage)
+}
+
+func (pk *Text) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.TextType)
+	io.Bool(&pk.NeedsTranslation)
+	switch pk.TextType {
+	case TextTypeChat, TextTypeWhisper, TextTypeAnnouncement:
+		io.String(&pk.SourceName)
+		io.String(&pk.Message)
+	case TextTypeRaw, TextTypeTip, TextTypeSystem, TextTypeObject, TextTypeObjectWhisper, TextTypeObjectAnnouncement:
+		io.String(&pk.Message)
+	case TextTypeTranslation, TextTypePopup, TextTypeJukeboxPopup:
+		io.String(&pk.Message)
+		protocol.FuncSlice(io, &pk.Parameters, io.String)
+	}
+	io.String(&pk.XUID)
+	io.String(&pk.PlatformChatID)
+	io.String(&pk.FilteredMessage)
+}
+
+func (pk *TickSync) marshalReader(io *protocol.Reader) {
+	io.Int64(&pk.ClientRequestTimestamp)
+	io.Int64(&pk.ServerReceptionTimestamp)
+}
+
+func (pk *TickSync) marshalWriter(io *protocol.Writer) {
+	io.Int64(&pk.ClientRequestTimestamp)
+	io.Int64(&pk.ServerReceptionTimestamp)
+}
+
+func (pk *TickingAreasLoadStatus) marshalReader(io *protocol.Reader) {
+	io.Bool(&pk.Preload)
+}
+
+func (pk *TickingAreasLoadStatus) marshalWriter(io *protocol.Writer) {
+	io.Bool(&pk.Preload)
+}
+
+func (pk *ToastRequest) marshalReader(io *protocol.Reader) {
+	io.String(&pk.Title)
+	io.String(&pk.Message)
+}
+
+func (pk *ToastRequest) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.Title)
+	io.String(&pk.Message)
+}
+
+func (pk *Trailing) marshalReader(io *protocol.Reader) {
+	Marshal(pk.Packet, io)
+	io.Bytes(&pk.Data)
+}
+
+func (pk *Trailing) marshalWriter(io *protocol.Writer) {
+	Marshal(pk.Packet, io)
+	io.Bytes(&pk.Data)
+}
+
+func (pk *Transfer) marshalReader(io *protocol.Reader) {
+	io.String(&pk.Address)
+	io.Uint16(&pk.Port)
+}
+
+func (pk *Transfer) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.Address)
+	io.Uint16(&pk.Port)
+}
+
+func (pk *TrimData) marshalReader(io *protocol.Reader) {
+	protocol.Slice(io, &pk.Patterns)
+	protocol.Slice(io, &pk.Materials)
+}
+
+func (pk *TrimData) marshalWriter(io *protocol.Writer) {
+	protocol.Slice(io, &pk.Patterns)
+	protocol.Slice(io, &pk.Materials)
+}
+
+func (pk *Unknown) marshalReader(io *protocol.Reader) {
+	io.Bytes(&pk.Payload)
+}
+
+func (pk *Unknown) marshalWriter(io *protocol.Writer) {
+	io.Bytes(&pk.Payload)
+}
+
+func (pk *UnlockedRecipes) marshalReader(io *protocol.Reader) {
+	io.Uint32(&pk.UnlockType)
+	protocol.FuncSlice(io, &pk.Recipes, io.String)
+}
+
+func (pk *UnlockedRecipes) marshalWriter(io *protocol.Writer) {
+	io.Uint32(&pk.UnlockType)
+	protocol.FuncSlice(io, &pk.Recipes, io.String)
+}
+
+func (pk *UpdateAbilities) marshalReader(io *protocol.Reader) {
+	protocol.Single(io, &pk.AbilityData)
+}
+
+func (pk *UpdateAbilities) marshalWriter(io *protocol.Writer) {
+	protocol.Single(io, &pk.AbilityData)
+}
+
+func (pk *UpdateAdventureSettings) marshalReader(io *protocol.Reader) {
+	io.Bool(&pk.NoPvM)
+	io.Bool(&pk.NoMvP)
+	io.Bool(&pk.ImmutableWorld)
+	io.Bool(&pk.ShowNameTags)
+	io.Bool(&pk.AutoJump)
+}
+
+func (pk *UpdateAdventureSettings) marshalWriter(io *protocol.Writer) {
+	io.Bool(&pk.NoPvM)
+	io.Bool(&pk.NoMvP)
+	io.Bool(&pk.ImmutableWorld)
+	io.Bool(&pk.ShowNameTags)
+	io.Bool(&pk.AutoJump)
+}
+
+func (pk *UpdateAttributes) marshalReader(io *protocol.Reader) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	protocol.Slice(io, &pk.Attributes)
+	io.Varuint64(&pk.Tick)
+}
+
+func (pk *UpdateAttributes) marshalWriter(io *protocol.Writer) {
+	io.Varuint64(&pk.EntityRuntimeID)
+	protocol.Slice(io, &pk.Attributes)
+	io.Varuint64(&pk.Tick)
+}
+
+func (pk *UpdateBlock) marshalReader(io *protocol.Reader) {
+	io.UBlockPos(&pk.Position)
+	io.Varuint32(&pk.NewBlockRuntimeID)
+	io.Varuint32(&pk.Flags)
+	io.Varuint32(&pk.Layer)
+}
+
+func (pk *UpdateBlock) marshalWriter(io *protocol.Writer) {
+	io.UBlockPos(&pk.Position)
+	io.Varuint32(&pk.NewBlockRuntimeID)
+	io.Varuint32(&pk.Flags)
+	io.Varuint32(&pk.Layer)
+}
+
+func (pk *UpdateBlockSynced) marshalReader(io *protocol.Reader) {
+	io.UBlockPos(&pk.Position)
+	io.Varuint32(&pk.NewBlockRuntimeID)
+	io.Varuint32(&pk.Flags)
+	io.Varuint32(&pk.Layer)
+	io.Varuint64(&pk.EntityUniqueID)
+	io.Varuint64(&pk.TransitionType)
+}
+
+func (pk *UpdateBlockSynced) marshalWriter(io *protocol.Writer) {
+	io.UBlockPos(&pk.Position)
+	io.Varuint32(&pk.NewBlockRuntimeID)
+	io.Varuint32(&pk.Flags)
+	io.Varuint32(&pk.Layer)
+	io.Varuint64(&pk.EntityUniqueID)
+	io.Varuint64(&pk.TransitionType)
+}
+
+func (pk *UpdateClientInputLocks) marshalReader(io *protocol.Reader) {
+	io.Varuint32(&pk.Locks)
+	io.Vec3(&pk.Position)
+}
+
+func (pk *UpdateClientInputLocks) marshalWriter(io *protocol.Writer) {
+	io.Varuint32(&pk.Locks)
+	io.Vec3(&pk.Position)
+}
+
+func (pk *UpdateEquip) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.WindowID)
+	io.Uint8(&pk.WindowType)
+	io.Varint32(&pk.Size)
+	io.Varint64(&pk.EntityUniqueID)
+	io.Bytes(&pk.SerialisedInventoryData)
+}
+
+func (pk *UpdateEquip) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.WindowID)
+	io.Uint8(&pk.WindowType)
+	io.Varint32(&pk.Size)
+	io.Varint64(&pk.EntityUniqueID)
+	io.Bytes(&pk.SerialisedInventoryData)
+}
+
+func (pk *UpdatePlayerGameType) marshalReader(io *protocol.Reader) {
+	io.Varint32(&pk.GameType)
+	io.Varint64(&pk.PlayerUniqueID)
+	io.Varuint64(&pk.Tick)
+}
+
+func (pk *UpdatePlayerGameType) marshalWriter(io *protocol.Writer) {
+	io.Varint32(&pk.GameType)
+	io.Varint64(&pk.PlayerUniqueID)
+	io.Varuint64(&pk.Tick)
+}
+
+func (pk *UpdateSoftEnum) marshalReader(io *protocol.Reader) {
+	io.String(&pk.EnumType)
+	protocol.FuncSlice(io, &pk.Options, io.String)
+	io.Uint8(&pk.ActionType)
+}
+
+func (pk *UpdateSoftEnum) marshalWriter(io *protocol.Writer) {
+	io.String(&pk.EnumType)
+	protocol.FuncSlice(io, &pk.Options, io.String)
+	io.Uint8(&pk.ActionType)
+}
+
+func (pk *UpdateSubChunkBlocks) marshalReader(io *protocol.Reader) {
+	io.SubChunkPos(&pk.Position)
+	protocol.Slice(io, &pk.Blocks)
+	protocol.Slice(io, &pk.Extra)
+}
+
+func (pk *UpdateSubChunkBlocks) marshalWriter(io *protocol.Writer) {
+	io.SubChunkPos(&pk.Position)
+	protocol.Slice(io, &pk.Blocks)
+	protocol.Slice(io, &pk.Extra)
+}
+
+func (pk *UpdateTrade) marshalReader(io *protocol.Reader) {
+	io.Uint8(&pk.WindowID)
+	io.Uint8(&pk.WindowType)
+	io.Varint32(&pk.Size)
+	io.Varint32(&pk.TradeTier)
+	io.Varint64(&pk.VillagerUniqueID)
+	io.Varint64(&pk.EntityUniqueID)
+	io.String(&pk.DisplayName)
+	io.Bool(&pk.NewTradeUI)
+	io.Bool(&pk.DemandBasedPrices)
+	io.Bytes(&pk.SerialisedOffers)
+}
+
+func (pk *UpdateTrade) marshalWriter(io *protocol.Writer) {
+	io.Uint8(&pk.WindowID)
+	io.Uint8(&pk.WindowType)
+	io.Varint32(&pk.Size)
+	io.Varint32(&pk.TradeTier)
+	io.Varint64(&pk.VillagerUniqueID)
+	io.Varint64(&pk.EntityUniqueID)
+	io.String(&pk.DisplayName)
+	io.Bool(&pk.NewTradeUI)
+	io.Bool(&pk.DemandBasedPrices)
+	io.Bytes(&pk.SerialisedOffers)
+}
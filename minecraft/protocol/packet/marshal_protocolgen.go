@@ -0,0 +1,35 @@
+//go:build protocolgen
+
+package packet
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// readerMarshaler is implemented by packets with a generated method for decoding using a *protocol.Reader.
+type readerMarshaler interface {
+	marshalReader(r *protocol.Reader)
+}
+
+// writerMarshaler is implemented by packets with a generated method for encoding using a *protocol.Writer.
+type writerMarshaler interface {
+	marshalWriter(w *protocol.Writer)
+}
+
+// marshal marshals the Packet using its generated methods if the protocol.IO is a *protocol.Reader or
+// *protocol.Writer and the Packet has them, or using its Marshal method otherwise.
+func marshal(pk Packet, io protocol.IO) {
+	switch io := io.(type) {
+	case *protocol.Reader:
+		if m, ok := pk.(readerMarshaler); ok {
+			m.marshalReader(io)
+			return
+		}
+	case *protocol.Writer:
+		if m, ok := pk.(writerMarshaler); ok {
+			m.marshalWriter(io)
+			return
+		}
+	}
+	pk.Marshal(io)
+}
@@ -0,0 +1,88 @@
+package packet
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// benchmarkPackets returns packets commonly sent during gameplay, filled with data, to benchmark encoding
+// and decoding. Running the benchmarks with and without the protocolgen build tag shows the effect of the
+// methods generated by marshalgen:
+//
+//	go test -run '^$' -bench Marshal -benchmem ./minecraft/protocol/packet
+//	go test -run '^$' -bench Marshal -benchmem -tags protocolgen ./minecraft/protocol/packet
+func benchmarkPackets() []Packet {
+	attributes := make([]protocol.Attribute, 10)
+	for i := range attributes {
+		attributes[i] = protocol.Attribute{
+			AttributeValue: protocol.AttributeValue{Name: fmt.Sprintf("minecraft:attribute_%v", i), Value: 20, Max: 20},
+			Default:        20,
+			Modifiers:      []protocol.AttributeModifier{{ID: uuid.NewString(), Name: "modifier", Amount: 0.5}},
+		}
+	}
+	items := make([]protocol.ItemInstance, 36)
+	for i := range items {
+		items[i] = protocol.ItemInstance{StackNetworkID: int32(i + 1), Stack: protocol.ItemStack{
+			ItemType: protocol.ItemType{NetworkID: int32(i + 1)}, Count: 64, CanBreak: []string{"minecraft:stone"},
+		}}
+	}
+	recipes := make([]protocol.Recipe, 200)
+	for i := range recipes {
+		recipes[i] = &protocol.ShapelessRecipe{
+			RecipeID: fmt.Sprintf("minecraft:recipe_%v", i),
+			Input: []protocol.ItemDescriptorCount{
+				{Descriptor: &protocol.DefaultItemDescriptor{NetworkID: 5, MetadataValue: 1}, Count: 1},
+				{Descriptor: &protocol.DefaultItemDescriptor{NetworkID: 6}, Count: 2},
+			},
+			Output:          []protocol.ItemStack{{ItemType: protocol.ItemType{NetworkID: 7}, Count: 4}},
+			UUID:            uuid.New(),
+			Block:           "crafting_table",
+			RecipeNetworkID: uint32(i + 1),
+		}
+	}
+	return []Packet{
+		&MovePlayer{EntityRuntimeID: 1, Position: mgl32.Vec3{100, 64, -100}, Pitch: 10, Yaw: 90, HeadYaw: 90, OnGround: true, Tick: 1000},
+		&MoveActorAbsolute{EntityRuntimeID: 2, Position: mgl32.Vec3{100, 64, -100}, Rotation: mgl32.Vec3{1, 2, 3}},
+		&UpdateAttributes{EntityRuntimeID: 1, Attributes: attributes, Tick: 1000},
+		&InventoryContent{WindowID: protocol.WindowIDInventory, Content: items},
+		&CraftingData{Recipes: recipes, ClearRecipes: true},
+	}
+}
+
+func BenchmarkMarshalEncode(b *testing.B) {
+	for _, pk := range benchmarkPackets() {
+		b.Run(fmt.Sprintf("%T", pk)[len("*packet."):], func(b *testing.B) {
+			buf := bytes.NewBuffer(nil)
+			w := protocol.NewWriter(buf, 0)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				Marshal(pk, w)
+			}
+		})
+	}
+}
+
+func BenchmarkMarshalDecode(b *testing.B) {
+	pool := NewServerPool()
+	for _, pk := range benchmarkPackets() {
+		buf := bytes.NewBuffer(nil)
+		Marshal(pk, protocol.NewWriter(buf, 0))
+		data := buf.Bytes()
+
+		b.Run(fmt.Sprintf("%T", pk)[len("*packet."):], func(b *testing.B) {
+			src := bytes.NewBuffer(nil)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				src.Reset()
+				src.Write(data)
+				Marshal(pool[pk.ID()](), protocol.NewReader(src, 0, false))
+			}
+		})
+	}
+}
@@ -24,6 +24,12 @@ const (
 	EditorWorldTypeTestLevel
 )
 
+const (
+	EducationEditionOfferNone = iota
+	EducationEditionOfferRestOfWorld
+	EducationEditionOfferChina
+)
+
 // StartGame is sent by the server to send information about the world the player will be spawned in. It
 // contains information about the position the player spawns in, and information about the world in general
 // such as its game rules.
@@ -93,7 +99,7 @@ type StartGame struct {
 	// respective game rule. The client will maintain this time as long as the day cycle is disabled.
 	DayCycleLockTime int32
 	// EducationEditionOffer is some Minecraft: Education Edition field that specifies what 'region' the world
-	// was from, with 0 being None, 1 being RestOfWorld, and 2 being China.
+	// was from. It is one of the EducationEditionOffer constants above.
 	// The actual use of this field is unknown.
 	EducationEditionOffer int32
 	// EducationFeaturesEnabled specifies if the world has education edition features enabled, such as the
@@ -0,0 +1,31 @@
+package packet
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// Trailing wraps a Packet that had bytes left after it was decoded, which typically happens when a newer
+// version of the game added fields to the end of the packet that are not yet implemented. The bytes left are
+// held by Trailing and written after the Packet when it is encoded, so that the packet may be forwarded to
+// another connection unchanged. Trailing packets are only returned by connections that have trailing bytes
+// preservation enabled.
+type Trailing struct {
+	Packet
+	// Data holds the bytes that were left after decoding the Packet.
+	Data []byte
+}
+
+// Marshal encodes or decodes the Packet wrapped, followed by the trailing bytes.
+func (pk *Trailing) Marshal(io protocol.IO) {
+	Marshal(pk.Packet, io)
+	io.Bytes(&pk.Data)
+}
+
+// Unwrap returns the Packet wrapped by the Trailing packet passed, or the packet passed itself if it is not
+// a *Trailing.
+func Unwrap(pk Packet) Packet {
+	if t, ok := pk.(*Trailing); ok {
+		return t.Packet
+	}
+	return pk
+}
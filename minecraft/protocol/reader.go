@@ -22,20 +22,159 @@ type Reader struct {
 		io.Reader
 		io.ByteReader
 	}
-	shieldID      int32
-	limitsEnabled bool
-}
-
-// NewReader creates a new Reader using the io.ByteReader passed as underlying source to read bytes from.
+	// buf is the underlying bytes.Buffer of r, if any. Varints are decoded directly from its bytes rather than
+	// byte by byte.
+	buf      *bytes.Buffer
+	shieldID int32
+	limits   ReaderLimits
+	// depth is the number of slices currently being read, used to enforce ReaderLimits.MaxDepth.
+	depth int
+
+	// strict specifies if the Reader tracks the addresses of the fields it reads, so that FieldPath may
+	// resolve the field last read.
+	strict bool
+	// fields holds the addresses of the fields most recently read in strict mode. fieldsN is the total
+	// number of addresses tracked.
+	fields  [4]uintptr
+	fieldsN int
+}
+
+// ReaderLimits holds limits on the data read by a Reader, which protect against malicious length prefixes
+// causing large allocations. A limit of 0 means no limit. Regardless of these limits, a Reader reading from
+// a source that reports its remaining length, such as a bytes.Buffer, never allocates a string, byte slice
+// or slice that holds more elements than bytes remain to be read.
+type ReaderLimits struct {
+	// MaxSliceLength is the maximum number of elements of slices and maps read.
+	MaxSliceLength uint32
+	// MaxStringLength is the maximum length in bytes of strings and byte slices read.
+	MaxStringLength uint32
+	// MaxDepth is the maximum number of slices that may be nested in each other.
+	MaxDepth int
+}
+
+// DefaultReaderLimits are the ReaderLimits used by a Reader created using NewReader with limits enabled.
+var DefaultReaderLimits = ReaderLimits{MaxSliceLength: maxSliceLength, MaxStringLength: 4 << 20, MaxDepth: 16}
+
+// AliasingBuffer is a bytes.Buffer that may be passed to NewReader to decode packets without copying byte
+// slices. Byte slices read by a Reader from an AliasingBuffer, such as those read using ByteSlice and Bytes,
+// alias the memory of the buffer instead of being copied, so they are only valid as long as that memory is
+// not reused.
+type AliasingBuffer struct {
+	*bytes.Buffer
+}
+
+// next returns the next n bytes of the AliasingBuffer without copying them. The slice returned has its
+// capacity limited to n, so that appending to it never overwrites the bytes that follow it.
+func (b AliasingBuffer) next(n int) ([]byte, error) {
+	if b.Len() < n {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return b.Next(n)[:n:n], nil
+}
+
+// NewReader creates a new Reader using the io.ByteReader passed as underlying source to read bytes from. If
+// enableLimits is true, the Reader enforces the DefaultReaderLimits.
 func NewReader(r interface {
 	io.Reader
 	io.ByteReader
 }, shieldID int32, enableLimits bool) *Reader {
-	return &Reader{r: r, shieldID: shieldID, limitsEnabled: enableLimits}
+	rd := &Reader{r: r, shieldID: shieldID}
+	switch src := r.(type) {
+	case *bytes.Buffer:
+		rd.buf = src
+	case AliasingBuffer:
+		rd.buf = src.Buffer
+	}
+	if enableLimits {
+		rd.limits = DefaultReaderLimits
+	}
+	return rd
+}
+
+// SetLimits sets the ReaderLimits enforced by the Reader for all data read after the call.
+func (r *Reader) SetLimits(limits ReaderLimits) {
+	r.limits = limits
+}
+
+// SetStrict enables or disables strict mode. In strict mode, the Reader tracks the fields it reads so that
+// FieldPath may be used to find the field that was being read when decoding failed. Strict mode makes
+// reading slightly slower and should generally only be enabled for debugging.
+func (r *Reader) SetStrict(strict bool) {
+	r.strict = strict
+}
+
+// track records the address of a field about to be read. It is only called if the Reader is in strict mode,
+// so that reading fields costs nothing extra otherwise. The address is stored as a uintptr so that fields
+// read by the Reader do not escape to the heap.
+func (r *Reader) track(p unsafe.Pointer) {
+	r.fields[r.fieldsN%len(r.fields)] = uintptr(p)
+	r.fieldsN++
+}
+
+// fixed reads the next n bytes from the underlying buffer. If the Reader reads from a bytes.Buffer, the bytes
+// are returned without copying them, so they are only valid until the next read.
+func (r *Reader) fixed(n int) []byte {
+	if r.buf != nil {
+		b := r.buf.Next(n)
+		if len(b) == n {
+			return b
+		} else if len(b) == 0 {
+			r.panic(io.EOF)
+		}
+		r.panic(io.ErrUnexpectedEOF)
+	}
+	b := make([]byte, n)
+	if _, err := r.r.Read(b); err != nil {
+		r.panic(err)
+	}
+	return b
+}
+
+// checkLength panics if a slice or map holding l elements of the kind passed may not be read, either because
+// it exceeds ReaderLimits.MaxSliceLength or because fewer bytes than elements remain. Every element takes at
+// least one byte.
+func (r *Reader) checkLength(l uint32, kind string) {
+	if r.limits.MaxSliceLength != 0 && l > r.limits.MaxSliceLength {
+		r.panicf("%v length was too long: length of %v exceeds maximum of %v", kind, l, r.limits.MaxSliceLength)
+	}
+	r.checkRemaining(l, kind)
+}
+
+// checkStringLength panics if a string or byte slice of l bytes may not be read, either because it exceeds
+// ReaderLimits.MaxStringLength or because fewer bytes remain.
+func (r *Reader) checkStringLength(l uint32, kind string) {
+	if r.limits.MaxStringLength != 0 && l > r.limits.MaxStringLength {
+		r.panicf("%v length was too long: length of %v exceeds maximum of %v", kind, l, r.limits.MaxStringLength)
+	}
+	r.checkRemaining(l, kind)
+}
+
+// checkRemaining panics if the source of the Reader reports that fewer than n bytes remain.
+func (r *Reader) checkRemaining(n uint32, kind string) {
+	if lr, ok := r.r.(interface{ Len() int }); ok && uint64(n) > uint64(lr.Len()) {
+		r.panicf("%v length of %v exceeds %v remaining bytes", kind, n, lr.Len())
+	}
+}
+
+// enter is called when the Reader starts reading the elements of a slice. It panics if this exceeds
+// ReaderLimits.MaxDepth. exit must be called once the elements are read.
+func (r *Reader) enter() {
+	r.depth++
+	if r.limits.MaxDepth != 0 && r.depth > r.limits.MaxDepth {
+		r.panicf("slices nested too deeply: depth exceeds maximum of %v", r.limits.MaxDepth)
+	}
+}
+
+// exit is called when the Reader finishes reading the elements of a slice started using enter.
+func (r *Reader) exit() {
+	r.depth--
 }
 
 // Uint8 reads a uint8 from the underlying buffer.
 func (r *Reader) Uint8(x *uint8) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	var err error
 	*x, err = r.r.ReadByte()
 	if err != nil {
@@ -45,6 +184,9 @@ func (r *Reader) Uint8(x *uint8) {
 
 // Int8 reads an int8 from the underlying buffer.
 func (r *Reader) Int8(x *int8) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	var b uint8
 	r.Uint8(&b)
 	*x = int8(b)
@@ -52,6 +194,9 @@ func (r *Reader) Int8(x *int8) {
 
 // Bool reads a bool from the underlying buffer.
 func (r *Reader) Bool(x *bool) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	u, err := r.r.ReadByte()
 	if err != nil {
 		r.panic(err)
@@ -64,12 +209,19 @@ var errStringTooLong = errors.New("string length overflows a 32-bit integer")
 
 // StringUTF ...
 func (r *Reader) StringUTF(x *string) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	var length int16
 	r.Int16(&length)
 	l := int(length)
 	if l > math.MaxInt16 {
 		r.panic(errStringTooLong)
 	}
+	if l < 0 {
+		r.panicf("negative string length %v", l)
+	}
+	r.checkStringLength(uint32(l), "string")
 	data := make([]byte, l)
 	if _, err := r.r.Read(data); err != nil {
 		r.panic(err)
@@ -79,12 +231,16 @@ func (r *Reader) StringUTF(x *string) {
 
 // String reads a string from the underlying buffer.
 func (r *Reader) String(x *string) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	var length uint32
 	r.Varuint32(&length)
 	l := int(length)
 	if l > math.MaxInt32 {
 		r.panic(errStringTooLong)
 	}
+	r.checkStringLength(length, "string")
 	data := make([]byte, l)
 	if _, err := r.r.Read(data); err != nil {
 		r.panic(err)
@@ -94,12 +250,24 @@ func (r *Reader) String(x *string) {
 
 // ByteSlice reads a byte slice from the underlying buffer, similarly to String.
 func (r *Reader) ByteSlice(x *[]byte) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	var length uint32
 	r.Varuint32(&length)
 	l := int(length)
 	if l > math.MaxInt32 {
 		r.panic(errStringTooLong)
 	}
+	r.checkStringLength(length, "byte slice")
+	if b, ok := r.r.(AliasingBuffer); ok {
+		data, err := b.next(l)
+		if err != nil {
+			r.panic(err)
+		}
+		*x = data
+		return
+	}
 	data := make([]byte, l)
 	if _, err := r.r.Read(data); err != nil {
 		r.panic(err)
@@ -109,6 +277,9 @@ func (r *Reader) ByteSlice(x *[]byte) {
 
 // Vec3 reads three float32s into an mgl32.Vec3 from the underlying buffer.
 func (r *Reader) Vec3(x *mgl32.Vec3) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	r.Float32(&x[0])
 	r.Float32(&x[1])
 	r.Float32(&x[2])
@@ -116,12 +287,18 @@ func (r *Reader) Vec3(x *mgl32.Vec3) {
 
 // Vec2 reads two float32s into an mgl32.Vec2 from the underlying buffer.
 func (r *Reader) Vec2(x *mgl32.Vec2) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	r.Float32(&x[0])
 	r.Float32(&x[1])
 }
 
 // BlockPos reads three varint32s into a BlockPos from the underlying buffer.
 func (r *Reader) BlockPos(x *BlockPos) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	r.Varint32(&x[0])
 	r.Varint32(&x[1])
 	r.Varint32(&x[2])
@@ -129,6 +306,9 @@ func (r *Reader) BlockPos(x *BlockPos) {
 
 // UBlockPos reads three varint32s, one unsigned for the y, into a BlockPos from the underlying buffer.
 func (r *Reader) UBlockPos(x *BlockPos) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	r.Varint32(&x[0])
 	var y uint32
 	r.Varuint32(&y)
@@ -138,12 +318,18 @@ func (r *Reader) UBlockPos(x *BlockPos) {
 
 // ChunkPos writes a ChunkPos as 2 varint32s to the underlying buffer.
 func (r *Reader) ChunkPos(x *ChunkPos) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	r.Varint32(&x[0])
 	r.Varint32(&x[1])
 }
 
 // SubChunkPos writes a SubChunkPos as 3 varint32s to the underlying buffer.
 func (r *Reader) SubChunkPos(x *SubChunkPos) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	r.Varint32(&x[0])
 	r.Varint32(&x[1])
 	r.Varint32(&x[2])
@@ -151,6 +337,9 @@ func (r *Reader) SubChunkPos(x *SubChunkPos) {
 
 // SoundPos reads an mgl32.Vec3 that serves as a position for a sound.
 func (r *Reader) SoundPos(x *mgl32.Vec3) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	var b BlockPos
 	r.BlockPos(&b)
 	*x = mgl32.Vec3{float32(b[0]) / 8, float32(b[1]) / 8, float32(b[2]) / 8}
@@ -158,6 +347,9 @@ func (r *Reader) SoundPos(x *mgl32.Vec3) {
 
 // ByteFloat reads a rotational float32 from a single byte.
 func (r *Reader) ByteFloat(x *float32) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	var v uint8
 	r.Uint8(&v)
 	*x = float32(v) * (360.0 / 256.0)
@@ -165,6 +357,9 @@ func (r *Reader) ByteFloat(x *float32) {
 
 // RGB reads a color.RGBA x from three float32s.
 func (r *Reader) RGB(x *color.RGBA) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	var red, green, blue float32
 	r.Float32(&red)
 	r.Float32(&green)
@@ -178,6 +373,9 @@ func (r *Reader) RGB(x *color.RGBA) {
 
 // RGBA reads a color.RGBA x from a uint32.
 func (r *Reader) RGBA(x *color.RGBA) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	var v uint32
 	r.Uint32(&v)
 	*x = color.RGBA{
@@ -190,6 +388,9 @@ func (r *Reader) RGBA(x *color.RGBA) {
 
 // VarRGBA reads a color.RGBA x from a varuint32.
 func (r *Reader) VarRGBA(x *color.RGBA) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	var v uint32
 	r.Varuint32(&v)
 	*x = color.RGBA{
@@ -202,6 +403,13 @@ func (r *Reader) VarRGBA(x *color.RGBA) {
 
 // Bytes reads the leftover bytes into a byte slice.
 func (r *Reader) Bytes(p *[]byte) {
+	if r.strict {
+		r.track(unsafe.Pointer(p))
+	}
+	if b, ok := r.r.(AliasingBuffer); ok {
+		*p, _ = b.next(b.Len())
+		return
+	}
 	var err error
 	*p, err = io.ReadAll(r.r)
 	if err != nil {
@@ -211,6 +419,9 @@ func (r *Reader) Bytes(p *[]byte) {
 
 // NBT reads a compound tag into a map from the underlying buffer.
 func (r *Reader) NBT(m *map[string]any, encoding nbt.Encoding) {
+	if r.strict {
+		r.track(unsafe.Pointer(m))
+	}
 	dec := nbt.NewDecoderWithEncoding(r.r, encoding)
 	dec.AllowZero = true
 
@@ -222,6 +433,9 @@ func (r *Reader) NBT(m *map[string]any, encoding nbt.Encoding) {
 
 // NBTList reads a list of NBT tags from the underlying buffer.
 func (r *Reader) NBTList(m *[]any, encoding nbt.Encoding) {
+	if r.strict {
+		r.track(unsafe.Pointer(m))
+	}
 	if err := nbt.NewDecoderWithEncoding(r.r, encoding).Decode(m); err != nil {
 		r.panic(err)
 	}
@@ -229,6 +443,9 @@ func (r *Reader) NBTList(m *[]any, encoding nbt.Encoding) {
 
 // UUID reads a uuid.UUID from the underlying buffer.
 func (r *Reader) UUID(x *uuid.UUID) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	b := make([]byte, 16)
 	if _, err := r.r.Read(b); err != nil {
 		r.panic(err)
@@ -246,6 +463,9 @@ func (r *Reader) UUID(x *uuid.UUID) {
 
 // PlayerInventoryAction reads a PlayerInventoryAction.
 func (r *Reader) PlayerInventoryAction(x *UseItemTransactionData) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	r.Varint32(&x.LegacyRequestID)
 	if x.LegacyRequestID < -1 && (x.LegacyRequestID&1) == 0 {
 		Slice(r, &x.LegacySetItemSlots)
@@ -263,6 +483,9 @@ func (r *Reader) PlayerInventoryAction(x *UseItemTransactionData) {
 
 // GameRule reads a GameRule x from the Reader.
 func (r *Reader) GameRule(x *GameRule) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	r.String(&x.Name)
 	r.Bool(&x.CanBeModifiedByPlayer)
 	var t uint32
@@ -287,11 +510,14 @@ func (r *Reader) GameRule(x *GameRule) {
 }
 
 // EntityMetadata reads an entity metadata map from the underlying buffer into map x.
-func (r *Reader) EntityMetadata(x *map[uint32]any) {
-	*x = map[uint32]any{}
-
+func (r *Reader) EntityMetadata(x *EntityMetadata) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	var count uint32
 	r.Varuint32(&count)
+	r.checkLength(count, "entity metadata")
+	*x = make(EntityMetadata, count)
 	for i := uint32(0); i < count; i++ {
 		var key, dataType uint32
 		r.Varuint32(&key)
@@ -341,6 +567,9 @@ func (r *Reader) EntityMetadata(x *map[uint32]any) {
 
 // ItemDescriptorCount reads an ItemDescriptorCount i from the underlying buffer.
 func (r *Reader) ItemDescriptorCount(i *ItemDescriptorCount) {
+	if r.strict {
+		r.track(unsafe.Pointer(i))
+	}
 	var id uint8
 	r.Uint8(&id)
 
@@ -368,6 +597,9 @@ func (r *Reader) ItemDescriptorCount(i *ItemDescriptorCount) {
 
 // ItemInstance reads an ItemInstance i from the underlying buffer.
 func (r *Reader) ItemInstance(i *ItemInstance) {
+	if r.strict {
+		r.track(unsafe.Pointer(i))
+	}
 	x := &i.Stack
 	x.NBTData = make(map[string]any)
 	r.Varint32(&x.NetworkID)
@@ -394,7 +626,8 @@ func (r *Reader) ItemInstance(i *ItemInstance) {
 	r.ByteSlice(&extraData)
 
 	buf := bytes.NewBuffer(extraData)
-	bufReader := NewReader(buf, r.shieldID, r.limitsEnabled)
+	bufReader := NewReader(buf, r.shieldID, false)
+	bufReader.SetLimits(r.limits)
 
 	var length int16
 	bufReader.Int16(&length)
@@ -425,6 +658,9 @@ func (r *Reader) ItemInstance(i *ItemInstance) {
 
 // Item reads an ItemStack x from the underlying buffer.
 func (r *Reader) Item(x *ItemStack) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	x.NBTData = make(map[string]any)
 	r.Varint32(&x.NetworkID)
 	if x.NetworkID == 0 {
@@ -442,7 +678,8 @@ func (r *Reader) Item(x *ItemStack) {
 	r.ByteSlice(&extraData)
 
 	buf := bytes.NewBuffer(extraData)
-	bufReader := NewReader(buf, r.shieldID, r.limitsEnabled)
+	bufReader := NewReader(buf, r.shieldID, false)
+	bufReader.SetLimits(r.limits)
 
 	var length int16
 	bufReader.Int16(&length)
@@ -473,6 +710,9 @@ func (r *Reader) Item(x *ItemStack) {
 
 // StackRequestAction reads a StackRequestAction from the reader.
 func (r *Reader) StackRequestAction(x *StackRequestAction) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	var id uint8
 	r.Uint8(&id)
 	if !lookupStackRequestAction(id, x) {
@@ -484,6 +724,9 @@ func (r *Reader) StackRequestAction(x *StackRequestAction) {
 
 // MaterialReducer reads a material reducer from the reader.
 func (r *Reader) MaterialReducer(m *MaterialReducer) {
+	if r.strict {
+		r.track(unsafe.Pointer(m))
+	}
 	var mix int32
 	r.Varint32(&mix)
 	m.InputItem = ItemType{NetworkID: mix << 16, MetadataValue: uint32(mix & 0x7fff)}
@@ -492,6 +735,9 @@ func (r *Reader) MaterialReducer(m *MaterialReducer) {
 
 // Recipe reads a Recipe from the reader.
 func (r *Reader) Recipe(x *Recipe) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	var recipeType int32
 	r.Varint32(&recipeType)
 	if !lookupRecipe(recipeType, x) {
@@ -503,6 +749,9 @@ func (r *Reader) Recipe(x *Recipe) {
 
 // EventType reads an Event's type from the reader.
 func (r *Reader) EventType(x *Event) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	var t int32
 	r.Varint32(&t)
 	if !lookupEvent(t, x) {
@@ -512,6 +761,9 @@ func (r *Reader) EventType(x *Event) {
 
 // TransactionDataType reads an InventoryTransactionData type from the reader.
 func (r *Reader) TransactionDataType(x *InventoryTransactionData) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	var transactionType uint32
 	r.Varuint32(&transactionType)
 	if !lookupTransactionData(transactionType, x) {
@@ -521,6 +773,9 @@ func (r *Reader) TransactionDataType(x *InventoryTransactionData) {
 
 // AbilityValue reads an ability value from the reader.
 func (r *Reader) AbilityValue(x *any) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	valType, boolVal, floatVal := uint8(0), false, float32(0)
 	r.Uint8(&valType)
 	r.Bool(&boolVal)
@@ -541,6 +796,9 @@ func (r *Reader) AbilityValue(x *any) {
 // that the next two bytes are an int16 for the dictionary index. Otherwise, the byte is copied to the output. The dictionary
 // index is then used to look up the byte sequence to be appended to the output.
 func (r *Reader) CompressedBiomeDefinitions(x *map[string]any) {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
 	var length uint32
 	header := make([]byte, 10)
 	r.Varuint32(&length)
@@ -630,80 +888,71 @@ var errVarIntOverflow = errors.New("varint overflows integer")
 
 // Varint64 reads up to 10 bytes from the underlying buffer into an int64.
 func (r *Reader) Varint64(x *int64) {
-	var ux uint64
-	for i := 0; i < 70; i += 7 {
-		b, err := r.r.ReadByte()
-		if err != nil {
-			r.panic(err)
-		}
-
-		ux |= uint64(b&0x7f) << i
-		if b&0x80 == 0 {
-			*x = int64(ux >> 1)
-			if ux&1 != 0 {
-				*x = ^*x
-			}
-			return
-		}
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
+	ux := r.varuint64(10)
+	*x = int64(ux >> 1)
+	if ux&1 != 0 {
+		*x = ^*x
 	}
-	r.panic(errVarIntOverflow)
 }
 
 // Varuint64 reads up to 10 bytes from the underlying buffer into a uint64.
 func (r *Reader) Varuint64(x *uint64) {
-	var v uint64
-	for i := 0; i < 70; i += 7 {
-		b, err := r.r.ReadByte()
-		if err != nil {
-			r.panic(err)
-		}
-
-		v |= uint64(b&0x7f) << i
-		if b&0x80 == 0 {
-			*x = v
-			return
-		}
+	if r.strict {
+		r.track(unsafe.Pointer(x))
 	}
-	r.panic(errVarIntOverflow)
+	*x = r.varuint64(10)
 }
 
 // Varint32 reads up to 5 bytes from the underlying buffer into an int32.
 func (r *Reader) Varint32(x *int32) {
-	var ux uint32
-	for i := 0; i < 35; i += 7 {
-		b, err := r.r.ReadByte()
-		if err != nil {
-			r.panic(err)
-		}
-
-		ux |= uint32(b&0x7f) << i
-		if b&0x80 == 0 {
-			*x = int32(ux >> 1)
-			if ux&1 != 0 {
-				*x = ^*x
-			}
-			return
-		}
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
+	ux := uint32(r.varuint64(5))
+	*x = int32(ux >> 1)
+	if ux&1 != 0 {
+		*x = ^*x
 	}
-	r.panic(errVarIntOverflow)
 }
 
 // Varuint32 reads up to 5 bytes from the underlying buffer into a uint32.
 func (r *Reader) Varuint32(x *uint32) {
-	var v uint32
-	for i := 0; i < 35; i += 7 {
+	if r.strict {
+		r.track(unsafe.Pointer(x))
+	}
+	*x = uint32(r.varuint64(5))
+}
+
+// varuint64 reads a varint of up to maxBytes bytes from the underlying buffer. If the Reader reads from a
+// bytes.Buffer, the varint is decoded directly from its bytes. Otherwise, it is read byte by byte.
+func (r *Reader) varuint64(maxBytes int) uint64 {
+	if r.buf != nil {
+		v, n := decodeVaruint64(r.buf.Bytes(), maxBytes)
+		if n > 0 {
+			r.buf.Next(n)
+			return v
+		} else if n < 0 {
+			r.panic(errVarIntOverflow)
+		}
+		// The buffer ends before the varint terminates: Fall back to reading byte by byte so that the
+		// same error is returned as for other sources.
+	}
+	var v uint64
+	for i := 0; i < maxBytes; i++ {
 		b, err := r.r.ReadByte()
 		if err != nil {
 			r.panic(err)
 		}
-
-		v |= uint32(b&0x7f) << i
+		v |= uint64(b&0x7f) << (7 * i)
 		if b&0x80 == 0 {
-			*x = v
-			return
+			return v
 		}
 	}
 	r.panic(errVarIntOverflow)
+	return 0
 }
 
 // panicf panics with the format and values passed and assigns the error created to the Reader.
@@ -104,3 +104,69 @@ func WriteVaruint32(dst io.ByteWriter, x uint32) error {
 	}
 	return dst.WriteByte(byte(x))
 }
+
+// AppendVarint64 appends an int64 to the byte slice passed with a size of 1-10 bytes and returns the
+// resulting slice.
+func AppendVarint64(dst []byte, x int64) []byte {
+	ux := uint64(x) << 1
+	if x < 0 {
+		ux = ^ux
+	}
+	return AppendVaruint64(dst, ux)
+}
+
+// AppendVaruint64 appends a uint64 to the byte slice passed with a size of 1-10 bytes and returns the
+// resulting slice.
+func AppendVaruint64(dst []byte, x uint64) []byte {
+	for x >= 0x80 {
+		dst = append(dst, byte(x)|0x80)
+		x >>= 7
+	}
+	return append(dst, byte(x))
+}
+
+// AppendVarint32 appends an int32 to the byte slice passed with a size of 1-5 bytes and returns the
+// resulting slice.
+func AppendVarint32(dst []byte, x int32) []byte {
+	ux := uint32(x) << 1
+	if x < 0 {
+		ux = ^ux
+	}
+	return AppendVaruint32(dst, ux)
+}
+
+// AppendVaruint32 appends a uint32 to the byte slice passed with a size of 1-5 bytes and returns the
+// resulting slice.
+func AppendVaruint32(dst []byte, x uint32) []byte {
+	if x < 0x80 {
+		// Most varints written are small, so we handle single byte varints without a loop.
+		return append(dst, byte(x))
+	}
+	for x >= 0x80 {
+		dst = append(dst, byte(x)|0x80)
+		x >>= 7
+	}
+	return append(dst, byte(x))
+}
+
+// decodeVaruint64 decodes a varuint64 of up to maxBytes bytes from the start of the byte slice passed. It
+// returns the value decoded and the number of bytes it occupied. n is 0 if b ends before the varint
+// terminates, and -1 if the varint does not terminate within maxBytes bytes.
+func decodeVaruint64(b []byte, maxBytes int) (v uint64, n int) {
+	if len(b) > 0 && b[0] < 0x80 {
+		return uint64(b[0]), 1
+	}
+	for i, c := range b {
+		if i == maxBytes {
+			return 0, -1
+		}
+		v |= uint64(c&0x7f) << (7 * i)
+		if c < 0x80 {
+			return v, i + 1
+		}
+	}
+	if len(b) >= maxBytes {
+		return 0, -1
+	}
+	return 0, 0
+}
@@ -0,0 +1,153 @@
+package protocol
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// benchmarkVarints holds the values encoded by the varint benchmarks, ranging from single byte varints,
+// which are most common in packets, to varints of the maximum size.
+var benchmarkVarints = []uint64{0, 1, 0x7f, 0x80, 300, 1 << 14, 1 << 21, 1 << 28, 1<<32 - 1, 1 << 42, 1<<63 - 1}
+
+// legacyReadVaruint32 reads a varuint32 byte by byte, like the Reader did before decoding varints directly
+// from the bytes of a bytes.Buffer.
+func legacyReadVaruint32(r io.ByteReader) uint32 {
+	var v uint32
+	for i := 0; i < 35; i += 7 {
+		b, err := r.ReadByte()
+		if err != nil {
+			panic(err)
+		}
+		v |= uint32(b&0x7f) << i
+		if b&0x80 == 0 {
+			return v
+		}
+	}
+	panic(errVarIntOverflow)
+}
+
+// legacyReadVarint64 reads a varint64 byte by byte, like the Reader did before decoding varints directly
+// from the bytes of a bytes.Buffer.
+func legacyReadVarint64(r io.ByteReader) int64 {
+	var ux uint64
+	for i := 0; i < 70; i += 7 {
+		b, err := r.ReadByte()
+		if err != nil {
+			panic(err)
+		}
+		ux |= uint64(b&0x7f) << i
+		if b&0x80 == 0 {
+			x := int64(ux >> 1)
+			if ux&1 != 0 {
+				x = ^x
+			}
+			return x
+		}
+	}
+	panic(errVarIntOverflow)
+}
+
+// encodedVarints returns the benchmarkVarints encoded using the function passed.
+func encodedVarints(write func(w *Writer, v uint64)) []byte {
+	buf := bytes.NewBuffer(nil)
+	w := NewWriter(buf, 0)
+	for _, v := range benchmarkVarints {
+		write(w, v)
+	}
+	return buf.Bytes()
+}
+
+func BenchmarkReaderVaruint32(b *testing.B) {
+	data := encodedVarints(func(w *Writer, v uint64) { x := uint32(v); w.Varuint32(&x) })
+	buf := bytes.NewBuffer(nil)
+	b.Run("legacy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			buf.Write(data)
+			for range benchmarkVarints {
+				_ = legacyReadVaruint32(buf)
+			}
+		}
+	})
+	b.Run("buffer", func(b *testing.B) {
+		r := NewReader(buf, 0, false)
+		var x uint32
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			buf.Write(data)
+			for range benchmarkVarints {
+				r.Varuint32(&x)
+			}
+		}
+	})
+}
+
+func BenchmarkReaderVarint64(b *testing.B) {
+	data := encodedVarints(func(w *Writer, v uint64) { x := int64(v); w.Varint64(&x) })
+	buf := bytes.NewBuffer(nil)
+	b.Run("legacy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			buf.Write(data)
+			for range benchmarkVarints {
+				_ = legacyReadVarint64(buf)
+			}
+		}
+	})
+	b.Run("buffer", func(b *testing.B) {
+		r := NewReader(buf, 0, false)
+		var x int64
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			buf.Write(data)
+			for range benchmarkVarints {
+				r.Varint64(&x)
+			}
+		}
+	})
+}
+
+func BenchmarkWriterVaruint32(b *testing.B) {
+	buf := bytes.NewBuffer(make([]byte, 0, 64))
+	b.Run("legacy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			for _, v := range benchmarkVarints {
+				_ = WriteVaruint32(buf, uint32(v))
+			}
+		}
+	})
+	b.Run("buffer", func(b *testing.B) {
+		w := NewWriter(buf, 0)
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			for _, v := range benchmarkVarints {
+				x := uint32(v)
+				w.Varuint32(&x)
+			}
+		}
+	})
+}
+
+func BenchmarkWriterVarint64(b *testing.B) {
+	buf := bytes.NewBuffer(make([]byte, 0, 128))
+	b.Run("legacy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			for _, v := range benchmarkVarints {
+				_ = WriteVarint64(buf, int64(v))
+			}
+		}
+	})
+	b.Run("buffer", func(b *testing.B) {
+		w := NewWriter(buf, 0)
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			for _, v := range benchmarkVarints {
+				x := int64(v)
+				w.Varint64(&x)
+			}
+		}
+	})
+}
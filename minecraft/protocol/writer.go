@@ -8,8 +8,8 @@ import (
 	"github.com/sandertv/gophertunnel/minecraft/nbt"
 	"image/color"
 	"io"
-	"reflect"
-	"sort"
+	"slices"
+	"sync"
 	"unsafe"
 )
 
@@ -22,6 +22,9 @@ type Writer struct {
 		io.Writer
 		io.ByteWriter
 	}
+	// buf is the underlying bytes.Buffer of w, if any. Varints are appended directly to its available
+	// capacity rather than written byte by byte.
+	buf      *bytes.Buffer
 	shieldID int32
 }
 
@@ -30,7 +33,45 @@ func NewWriter(w interface {
 	io.Writer
 	io.ByteWriter
 }, shieldID int32) *Writer {
-	return &Writer{w: w, shieldID: shieldID}
+	wr := &Writer{}
+	wr.Reset(w, shieldID)
+	return wr
+}
+
+// writerPool is a sync.Pool of Writers, used to prevent allocating a new Writer for every packet encoded.
+var writerPool = sync.Pool{
+	New: func() any {
+		return new(Writer)
+	},
+}
+
+// GetWriter returns a Writer from a pool of Writers, reset to write to the io.ByteWriter passed. It behaves
+// like a Writer returned by NewWriter, but should be returned to the pool using PutWriter once it is no
+// longer used.
+func GetWriter(w interface {
+	io.Writer
+	io.ByteWriter
+}, shieldID int32) *Writer {
+	wr := writerPool.Get().(*Writer)
+	wr.Reset(w, shieldID)
+	return wr
+}
+
+// PutWriter returns a Writer obtained using GetWriter to the pool. The Writer must not be used after it is
+// returned.
+func PutWriter(w *Writer) {
+	w.Reset(nil, 0)
+	writerPool.Put(w)
+}
+
+// Reset resets the Writer so that it writes to the io.ByteWriter passed, using the shield ID passed. Reset
+// allows a single Writer to be re-used to encode multiple packets.
+func (w *Writer) Reset(dst interface {
+	io.Writer
+	io.ByteWriter
+}, shieldID int32) {
+	w.w, w.shieldID = dst, shieldID
+	w.buf, _ = dst.(*bytes.Buffer)
 }
 
 // Uint8 writes a uint8 to the underlying buffer.
@@ -198,22 +239,20 @@ func (w *Writer) GameRule(x *GameRule) {
 }
 
 // EntityMetadata writes an entity metadata map x to the underlying buffer.
-func (w *Writer) EntityMetadata(x *map[uint32]any) {
+func (w *Writer) EntityMetadata(x *EntityMetadata) {
 	l := uint32(len(*x))
 	w.Varuint32(&l)
 
 	// Entity metadata needs to be sorted for some functionality to work. NPCs, for example, need to have their fields
-	// set in increasing order, or the text or buttons won't be shown to the client. See #88.
-	// Sorting this is probably not very fast, but it'll have to do for now: We can change entity metadata to a slice
-	// later on.
-	keys := make([]int, 0, l)
+	// set in increasing order, or the text or buttons won't be shown to the client. See #88. Sorting also makes
+	// sure that the same metadata is always encoded to the same bytes.
+	keys := make([]uint32, 0, l)
 	for k := range *x {
-		keys = append(keys, int(k))
+		keys = append(keys, k)
 	}
-	sort.Ints(keys)
-	for _, k := range keys {
-		key := uint32(k)
-		value := (*x)[uint32(k)]
+	slices.Sort(keys)
+	for _, key := range keys {
+		value := (*x)[key]
 		w.Varuint32(&key)
 		switch v := value.(type) {
 		case byte:
@@ -253,7 +292,7 @@ func (w *Writer) EntityMetadata(x *map[uint32]any) {
 			w.Varuint32(&entityDataTypeVec3)
 			w.Vec3(&v)
 		default:
-			w.UnknownEnumOption(reflect.TypeOf(value), "entity metadata")
+			w.UnknownEnumOption(fmt.Sprintf("%T", value), "entity metadata")
 		}
 	}
 }
@@ -435,36 +474,35 @@ func (w *Writer) AbilityValue(x *any) {
 	}
 }
 
-// CompressedBiomeDefinitions reads a list of compressed biome definitions from the reader. Minecraft decided to make their
-// own type of compression for this, so we have to implement it ourselves. It uses a dictionary of repeated byte sequences
-// to reduce the size of the data. The compressed data is read byte-by-byte, and if the byte is 0xff then it is assumed
-// that the next two bytes are an int16 for the dictionary index. Otherwise, the byte is copied to the output. The dictionary
-// index is then used to look up the byte sequence to be appended to the output.
+// CompressedBiomeDefinitions writes a list of compressed biome definitions to the writer. The format uses a
+// dictionary of repeated byte sequences, referenced by a 0xff byte followed by an int16 dictionary index. No
+// repeated sequences are compressed: The dictionary only holds a single 0xff byte, so that 0xff bytes in the
+// NBT may be written as a reference to it.
 func (w *Writer) CompressedBiomeDefinitions(x *map[string]any) {
 	decompressed, err := nbt.Marshal(x)
 	if err != nil {
 		w.panicf("error marshaling nbt: %v", err)
 	}
 
-	var compressed []byte
-	buf := bytes.NewBuffer(compressed)
+	buf := bytes.NewBuffer(make([]byte, 0, len(decompressed)+16))
 	bufWriter := NewWriter(buf, w.shieldID)
 
 	header := []byte("COMPRESSED")
 	bufWriter.Bytes(&header)
 
-	// TODO: Dictionary compression implementation
-	var dictionaryLength uint16
+	dictionaryLength, entryLength, entry := uint16(1), uint8(1), []byte{0xff}
 	bufWriter.Uint16(&dictionaryLength)
+	bufWriter.Uint8(&entryLength)
+	bufWriter.Bytes(&entry)
 	for _, b := range decompressed {
 		bufWriter.Uint8(&b)
 		if b == 0xff {
-			dictionaryIndex := int16(1)
-			bufWriter.Int16(&dictionaryIndex)
+			entryIndex := int16(0)
+			bufWriter.Int16(&entryIndex)
 		}
 	}
 
-	compressed = buf.Bytes()
+	compressed := buf.Bytes()
 	length := uint32(len(compressed))
 	w.Varuint32(&length)
 	w.Bytes(&compressed)
@@ -472,50 +510,38 @@ func (w *Writer) CompressedBiomeDefinitions(x *map[string]any) {
 
 // Varint64 writes an int64 as 1-10 bytes to the underlying buffer.
 func (w *Writer) Varint64(x *int64) {
-	u := *x
-	ux := uint64(u) << 1
-	if u < 0 {
-		ux = ^ux
-	}
-	for ux >= 0x80 {
-		_ = w.w.WriteByte(byte(ux) | 0x80)
-		ux >>= 7
+	if w.buf != nil {
+		_, _ = w.buf.Write(AppendVarint64(w.buf.AvailableBuffer(), *x))
+		return
 	}
-	_ = w.w.WriteByte(byte(ux))
+	_ = WriteVarint64(w.w, *x)
 }
 
 // Varuint64 writes a uint64 as 1-10 bytes to the underlying buffer.
 func (w *Writer) Varuint64(x *uint64) {
-	u := *x
-	for u >= 0x80 {
-		_ = w.w.WriteByte(byte(u) | 0x80)
-		u >>= 7
+	if w.buf != nil {
+		_, _ = w.buf.Write(AppendVaruint64(w.buf.AvailableBuffer(), *x))
+		return
 	}
-	_ = w.w.WriteByte(byte(u))
+	_ = WriteVaruint64(w.w, *x)
 }
 
 // Varint32 writes an int32 as 1-5 bytes to the underlying buffer.
 func (w *Writer) Varint32(x *int32) {
-	u := *x
-	ux := uint32(u) << 1
-	if u < 0 {
-		ux = ^ux
-	}
-	for ux >= 0x80 {
-		_ = w.w.WriteByte(byte(ux) | 0x80)
-		ux >>= 7
+	if w.buf != nil {
+		_, _ = w.buf.Write(AppendVarint32(w.buf.AvailableBuffer(), *x))
+		return
 	}
-	_ = w.w.WriteByte(byte(ux))
+	_ = WriteVarint32(w.w, *x)
 }
 
 // Varuint32 writes a uint32 as 1-5 bytes to the underlying buffer.
 func (w *Writer) Varuint32(x *uint32) {
-	u := *x
-	for u >= 0x80 {
-		_ = w.w.WriteByte(byte(u) | 0x80)
-		u >>= 7
+	if w.buf != nil {
+		_, _ = w.buf.Write(AppendVaruint32(w.buf.AvailableBuffer(), *x))
+		return
 	}
-	_ = w.w.WriteByte(byte(u))
+	_ = WriteVaruint32(w.w, *x)
 }
 
 // NBT writes a map as NBT to the underlying buffer using the encoding passed.
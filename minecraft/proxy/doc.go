@@ -0,0 +1,8 @@
+// Package proxy implements a Minecraft Bedrock Edition proxy that sits between clients and a remote server.
+// Every client that connects to the Proxy is logged in to the remote server with the identity and client data
+// it logged in with, after which packets are forwarded between the two connections, optionally passing
+// through hooks that may inspect, modify or drop them.
+//
+// The package also provides helpers to remap entity runtime and unique IDs in packets, which is needed when
+// the IDs seen by the client differ from the ones used by the server, for example after switching servers.
+package proxy
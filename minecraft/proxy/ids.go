@@ -0,0 +1,102 @@
+package proxy
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Swap returns a function that maps the ID a to b and b to a, and leaves all other IDs unchanged. It may be
+// passed to RemapEntityIDs to swap the entity runtime or unique ID of the player between the ID the client
+// knows and the ID used by the server, for example after the client was moved to another server without
+// receiving a new StartGame packet.
+func Swap[T comparable](a, b T) func(id T) T {
+	return func(id T) T {
+		switch id {
+		case a:
+			return b
+		case b:
+			return a
+		}
+		return id
+	}
+}
+
+// RemapEntityIDs replaces the entity runtime IDs and entity unique IDs in the packet passed with the IDs
+// returned by the functions passed. Either function may be nil, in which case IDs of that kind are left
+// unchanged. Only packets commonly sent during gameplay are remapped, and RemapEntityIDs reports if the packet
+// passed was one of them.
+func RemapEntityIDs(pk packet.Packet, runtimeID func(id uint64) uint64, uniqueID func(id int64) int64) bool {
+	if runtimeID == nil {
+		runtimeID = func(id uint64) uint64 { return id }
+	}
+	if uniqueID == nil {
+		uniqueID = func(id int64) int64 { return id }
+	}
+	switch pk := packet.Unwrap(pk).(type) {
+	case *packet.AddActor:
+		pk.EntityUniqueID, pk.EntityRuntimeID = uniqueID(pk.EntityUniqueID), runtimeID(pk.EntityRuntimeID)
+		remapLinks(pk.EntityLinks, uniqueID)
+	case *packet.AddItemActor:
+		pk.EntityUniqueID, pk.EntityRuntimeID = uniqueID(pk.EntityUniqueID), runtimeID(pk.EntityRuntimeID)
+	case *packet.AddPainting:
+		pk.EntityUniqueID, pk.EntityRuntimeID = uniqueID(pk.EntityUniqueID), runtimeID(pk.EntityRuntimeID)
+	case *packet.AddPlayer:
+		pk.EntityRuntimeID = runtimeID(pk.EntityRuntimeID)
+		remapLinks(pk.EntityLinks, uniqueID)
+	case *packet.RemoveActor:
+		pk.EntityUniqueID = uniqueID(pk.EntityUniqueID)
+	case *packet.SetActorLink:
+		pk.EntityLink.RiddenEntityUniqueID = uniqueID(pk.EntityLink.RiddenEntityUniqueID)
+		pk.EntityLink.RiderEntityUniqueID = uniqueID(pk.EntityLink.RiderEntityUniqueID)
+	case *packet.BossEvent:
+		pk.BossEntityUniqueID = uniqueID(pk.BossEntityUniqueID)
+	case *packet.UpdateEquip:
+		pk.EntityUniqueID = uniqueID(pk.EntityUniqueID)
+	case *packet.UpdateTrade:
+		pk.VillagerUniqueID, pk.EntityUniqueID = uniqueID(pk.VillagerUniqueID), uniqueID(pk.EntityUniqueID)
+	case *packet.MoveActorAbsolute:
+		pk.EntityRuntimeID = runtimeID(pk.EntityRuntimeID)
+	case *packet.MoveActorDelta:
+		pk.EntityRuntimeID = runtimeID(pk.EntityRuntimeID)
+	case *packet.SetActorData:
+		pk.EntityRuntimeID = runtimeID(pk.EntityRuntimeID)
+	case *packet.SetActorMotion:
+		pk.EntityRuntimeID = runtimeID(pk.EntityRuntimeID)
+	case *packet.ActorEvent:
+		pk.EntityRuntimeID = runtimeID(pk.EntityRuntimeID)
+	case *packet.Animate:
+		pk.EntityRuntimeID = runtimeID(pk.EntityRuntimeID)
+	case *packet.MobEffect:
+		pk.EntityRuntimeID = runtimeID(pk.EntityRuntimeID)
+	case *packet.MobEquipment:
+		pk.EntityRuntimeID = runtimeID(pk.EntityRuntimeID)
+	case *packet.MobArmourEquipment:
+		pk.EntityRuntimeID = runtimeID(pk.EntityRuntimeID)
+	case *packet.UpdateAttributes:
+		pk.EntityRuntimeID = runtimeID(pk.EntityRuntimeID)
+	case *packet.Interact:
+		pk.TargetEntityRuntimeID = runtimeID(pk.TargetEntityRuntimeID)
+	case *packet.TakeItemActor:
+		pk.ItemEntityRuntimeID, pk.TakerEntityRuntimeID = runtimeID(pk.ItemEntityRuntimeID), runtimeID(pk.TakerEntityRuntimeID)
+	case *packet.PlayerAction:
+		pk.EntityRuntimeID = runtimeID(pk.EntityRuntimeID)
+	case *packet.Emote:
+		pk.EntityRuntimeID = runtimeID(pk.EntityRuntimeID)
+	case *packet.Respawn:
+		pk.EntityRuntimeID = runtimeID(pk.EntityRuntimeID)
+	case *packet.SetLocalPlayerAsInitialised:
+		pk.EntityRuntimeID = runtimeID(pk.EntityRuntimeID)
+	default:
+		return false
+	}
+	return true
+}
+
+// remapLinks replaces the entity unique IDs in the entity links passed with the IDs returned by the function
+// passed.
+func remapLinks(links []protocol.EntityLink, uniqueID func(id int64) int64) {
+	for i := range links {
+		links[i].RiddenEntityUniqueID = uniqueID(links[i].RiddenEntityUniqueID)
+		links[i].RiderEntityUniqueID = uniqueID(links[i].RiderEntityUniqueID)
+	}
+}
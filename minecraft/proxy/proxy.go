@@ -0,0 +1,275 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/sandertv/gophertunnel/minecraft/resource"
+)
+
+// Config holds the settings of a Proxy.
+type Config struct {
+	// Network and RemoteAddress are the network and address of the server that clients are proxied to. Network
+	// defaults to "raknet".
+	Network, RemoteAddress string
+	// ListenConfig is used to listen for clients. Its PacksFor field is overwritten if PassResourcePacks is
+	// true.
+	ListenConfig minecraft.ListenConfig
+	// Dialer is used to dial the remote server for every client. Its IdentityData and GetClientData fields
+	// are overwritten with those of the client. Note that the identity data is only used if the Dialer has no
+	// TokenSource, as the server otherwise assigns the identity of the XBOX Live account used.
+	Dialer minecraft.Dialer
+	// DialTimeout is the maximum duration of dialing the remote server and spawning in its world. It defaults
+	// to 30 seconds. If PassResourcePacks is true, it is also the maximum duration between the remote server
+	// being dialed and the client being accepted, after which the connection to the remote server is closed.
+	DialTimeout time.Duration
+
+	// PassResourcePacks specifies if the resource packs of the remote server should be sent to clients. If
+	// true, the remote server is dialed while the client is still logging in, so that the resource packs it
+	// sends may be sent to the client. The resource packs must be downloaded by the Dialer for this to work.
+	PassResourcePacks bool
+
+	// OnSession is called for every Session once both the client and the server connection are spawned,
+	// before any packets are forwarded.
+	OnSession func(s *Session)
+	// ClientPacket is called for every packet read from the client before it is forwarded to the server. The
+	// packet may be modified. If ClientPacket returns false, the packet is not forwarded.
+	ClientPacket func(s *Session, pk packet.Packet) bool
+	// ServerPacket is called for every packet read from the server before it is forwarded to the client. The
+	// packet may be modified. If ServerPacket returns false, the packet is not forwarded.
+	ServerPacket func(s *Session, pk packet.Packet) bool
+	// OnClose is called when a Session is closed, with the error that caused it to close. The error is nil
+	// if the Proxy was closed.
+	OnClose func(s *Session, err error)
+}
+
+// Proxy listens for clients and proxies each of them to a remote server. A Proxy is created using Config.Listen.
+type Proxy struct {
+	conf     Config
+	listener *minecraft.Listener
+	log      *log.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu sync.Mutex
+	// pending holds server connections dialed while their client was logging in, indexed by the connection
+	// of the client. It is only used if Config.PassResourcePacks is true.
+	pending  map[*minecraft.Conn]pendingConn
+	sessions map[*Session]struct{}
+	wg       sync.WaitGroup
+}
+
+// pendingConn is the result of dialing the remote server for a client that is still logging in.
+type pendingConn struct {
+	conn *minecraft.Conn
+	err  error
+}
+
+// Listen starts listening for clients on the address passed over the network passed, using the Config passed.
+// The Proxy returned does not accept clients until Serve is called.
+func (conf Config) Listen(network, address string) (*Proxy, error) {
+	if conf.Network == "" {
+		conf.Network = "raknet"
+	}
+	if conf.DialTimeout <= 0 {
+		conf.DialTimeout = time.Second * 30
+	}
+	if conf.ListenConfig.ErrorLog == nil {
+		conf.ListenConfig.ErrorLog = log.New(os.Stderr, "", log.LstdFlags)
+	}
+	p := &Proxy{
+		conf:     conf,
+		log:      conf.ListenConfig.ErrorLog,
+		pending:  make(map[*minecraft.Conn]pendingConn),
+		sessions: make(map[*Session]struct{}),
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+
+	lc := conf.ListenConfig
+	if conf.PassResourcePacks {
+		lc.PacksFor = p.packsFor
+	}
+	l, err := lc.Listen(network, address)
+	if err != nil {
+		p.cancel()
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+	p.listener = l
+	return p, nil
+}
+
+// Listener returns the minecraft.Listener that the Proxy accepts clients from.
+func (p *Proxy) Listener() *minecraft.Listener {
+	return p.listener
+}
+
+// Sessions returns all Sessions that are currently open.
+func (p *Proxy) Sessions() []*Session {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	sessions := make([]*Session, 0, len(p.sessions))
+	for s := range p.sessions {
+		sessions = append(sessions, s)
+	}
+	return sessions
+}
+
+// Serve accepts clients and proxies them to the remote server until the Proxy is closed. Serve always
+// returns a non-nil error. After Close, the error returned is net.ErrClosed.
+func (p *Proxy) Serve() error {
+	for {
+		c, err := p.listener.Accept()
+		if err != nil {
+			return err
+		}
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.handleConn(c.(*minecraft.Conn))
+		}()
+	}
+}
+
+// Close closes the Proxy and all of its Sessions. Clients are disconnected and the connections to the
+// remote server are closed. Close waits until all Sessions are closed.
+func (p *Proxy) Close() error {
+	p.cancel()
+	err := p.listener.Close()
+
+	p.mu.Lock()
+	for client, pending := range p.pending {
+		if pending.conn != nil {
+			_ = pending.conn.Close()
+		}
+		delete(p.pending, client)
+	}
+	p.mu.Unlock()
+
+	for _, s := range p.Sessions() {
+		s.close(nil)
+	}
+	p.wg.Wait()
+	return err
+}
+
+// packsFor dials the remote server for a client that is logging in and returns the resource packs sent by
+// the server, so that they are sent to the client. The connection to the server is kept until the client is
+// accepted, and is closed if the client fails to log in or is not accepted within the dial timeout.
+func (p *Proxy) packsFor(client *minecraft.Conn) []*resource.Pack {
+	conn, err := p.dial(client.IdentityData(), client.ClientData())
+
+	p.mu.Lock()
+	p.pending[client] = pendingConn{conn: conn, err: err}
+	p.mu.Unlock()
+	go p.expire(client)
+	if err != nil {
+		return nil
+	}
+	return conn.ResourcePacks()
+}
+
+// expire closes the pending server connection of the client passed if the client is closed or the dial
+// timeout passes before the client is accepted.
+func (p *Proxy) expire(client *minecraft.Conn) {
+	t := time.NewTimer(p.conf.DialTimeout)
+	defer t.Stop()
+	select {
+	case <-client.Closed():
+	case <-t.C:
+	case <-p.ctx.Done():
+		// Close closes all pending connections itself.
+		return
+	}
+	p.mu.Lock()
+	pending, ok := p.pending[client]
+	delete(p.pending, client)
+	p.mu.Unlock()
+	if ok && pending.conn != nil {
+		_ = pending.conn.Close()
+	}
+}
+
+// dial dials the remote server on behalf of a client with the identity data and client data passed.
+func (p *Proxy) dial(identity login.IdentityData, clientData login.ClientData) (*minecraft.Conn, error) {
+	d := p.conf.Dialer
+	d.IdentityData = identity
+	d.GetClientData = func() login.ClientData { return clientData }
+
+	ctx, cancel := context.WithTimeout(p.ctx, p.conf.DialTimeout)
+	defer cancel()
+	conn, err := d.DialContext(ctx, p.conf.Network, p.conf.RemoteAddress, p.conf.DialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("dial remote server: %w", err)
+	}
+	return conn, nil
+}
+
+// serverConn returns the connection to the remote server for the client passed. If the server was already
+// dialed while the client was logging in and the connection did not expire, that connection is returned.
+// Otherwise, the server is dialed.
+func (p *Proxy) serverConn(client *minecraft.Conn) (*minecraft.Conn, error) {
+	p.mu.Lock()
+	pending, ok := p.pending[client]
+	delete(p.pending, client)
+	p.mu.Unlock()
+	if ok {
+		return pending.conn, pending.err
+	}
+	return p.dial(client.IdentityData(), client.ClientData())
+}
+
+// handleConn proxies a client that was accepted by the Listener to the remote server until either of the
+// two connections is closed.
+func (p *Proxy) handleConn(client *minecraft.Conn) {
+	server, err := p.serverConn(client)
+	if err != nil {
+		p.log.Printf("proxy %v: %v\n", client.RemoteAddr(), err)
+		_ = p.listener.Disconnect(client, disconnectMessage(err, "Could not connect to the server."))
+		return
+	}
+	s := &Session{Client: client, Server: server, p: p, closed: make(chan struct{})}
+	if err := s.spawn(p.ctx); err != nil {
+		p.log.Printf("proxy %v: %v\n", client.RemoteAddr(), err)
+		_ = server.Close()
+		_ = p.listener.Disconnect(client, disconnectMessage(err, "Could not connect to the server."))
+		return
+	}
+
+	p.mu.Lock()
+	p.sessions[s] = struct{}{}
+	p.mu.Unlock()
+	select {
+	case <-p.ctx.Done():
+		// The Proxy was closed while the Session was spawning, so it was never closed by Close.
+		s.close(nil)
+		return
+	default:
+	}
+	if p.conf.OnSession != nil {
+		p.conf.OnSession(s)
+	}
+	s.forward()
+}
+
+// disconnectMessage returns the message that a client is disconnected with when its Session is closed with
+// the error passed. If the server disconnected the client, its message is returned. Otherwise, the fallback
+// message passed is returned.
+func disconnectMessage(err error, fallback string) string {
+	var disc minecraft.DisconnectError
+	if errors.As(err, &disc) {
+		return disc.Message
+	}
+	if err == nil {
+		return "Proxy closed."
+	}
+	return fallback
+}
@@ -0,0 +1,112 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Session is a client proxied to the remote server by a Proxy. Packets may be sent to either side directly
+// using the WritePacket methods of Client and Server.
+type Session struct {
+	// Client is the connection of the client to the Proxy.
+	Client *minecraft.Conn
+	// Server is the connection of the Proxy to the remote server.
+	Server *minecraft.Conn
+
+	p *Proxy
+
+	once   sync.Once
+	closed chan struct{}
+}
+
+// Close closes the Session. The client is disconnected and the connection to the remote server is closed.
+func (s *Session) Close() error {
+	s.close(nil)
+	return nil
+}
+
+// Closed returns a channel that is closed once the Session is closed.
+func (s *Session) Closed() <-chan struct{} {
+	return s.closed
+}
+
+// spawn spawns the client with the game data of the server and the server connection in the world of the
+// server at the same time.
+func (s *Session) spawn(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.p.conf.DialTimeout)
+	defer cancel()
+
+	errs := make(chan error, 2)
+	go func() {
+		if err := s.Client.StartGameContext(ctx, s.Server.GameData()); err != nil {
+			errs <- fmt.Errorf("start game of client: %w", err)
+			return
+		}
+		errs <- nil
+	}()
+	go func() {
+		if err := s.Server.DoSpawnContext(ctx); err != nil {
+			errs <- fmt.Errorf("spawn on server: %w", err)
+			return
+		}
+		errs <- nil
+	}()
+	return errors.Join(<-errs, <-errs)
+}
+
+// forward forwards packets between the client and the server until either of them is closed, after which
+// the Session is closed.
+func (s *Session) forward() {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		s.close(s.pipe(s.Client, s.Server, s.p.conf.ClientPacket))
+	}()
+	go func() {
+		defer wg.Done()
+		s.close(s.pipe(s.Server, s.Client, s.p.conf.ServerPacket))
+	}()
+	wg.Wait()
+}
+
+// pipe reads packets from src and writes them to dst until either of them is closed. Each packet is first
+// passed to the hook passed, if not nil, which may prevent the packet from being forwarded.
+func (s *Session) pipe(src, dst *minecraft.Conn, hook func(s *Session, pk packet.Packet) bool) error {
+	for {
+		pk, err := src.ReadPacket()
+		if err != nil {
+			return err
+		}
+		// Hooks are passed the packet decoded rather than a *packet.Trailing wrapping it. Changes made to the
+		// packet are still written, as the wrapper refers to the same packet.
+		if hook != nil && !hook(s, packet.Unwrap(pk)) {
+			continue
+		}
+		if err := dst.WritePacket(pk); err != nil {
+			return err
+		}
+	}
+}
+
+// close closes the Session with the error passed. If the server disconnected the client, the client is
+// disconnected with the same message.
+func (s *Session) close(err error) {
+	s.once.Do(func() {
+		close(s.closed)
+		s.p.mu.Lock()
+		delete(s.p.sessions, s)
+		s.p.mu.Unlock()
+
+		_ = s.Server.Close()
+		_ = s.p.listener.Disconnect(s.Client, disconnectMessage(err, "Connection lost."))
+		if s.p.conf.OnClose != nil {
+			s.p.conf.OnClose(s, err)
+		}
+	})
+}
@@ -2,26 +2,67 @@ package minecraft
 
 import (
 	"context"
-	"github.com/sandertv/go-raknet"
+	"log/slog"
 	"net"
+	"time"
+
+	"github.com/sandertv/go-raknet"
 )
 
-// RakNet is an implementation of a RakNet v10 Network.
-type RakNet struct{}
+// RakNet is an implementation of a RakNet v10 Network. The zero value uses the default settings of
+// go-raknet and is registered under the network ID "raknet". A RakNet with other settings may be set as
+// Dialer.RakNet or ListenConfig.RakNet to tune the RakNet layer without constructing a listener manually.
+type RakNet struct {
+	// ErrorLog is the logger that errors in the RakNet layer are logged to. If nil, go-raknet logs to its
+	// default logger.
+	ErrorLog *slog.Logger
+
+	// UpstreamDialer, if non-nil, is used to dial the UDP connections that RakNet runs on, for example to
+	// dial through a proxy.
+	UpstreamDialer raknet.UpstreamDialer
+	// UpstreamPacketListener, if non-nil, is used to listen for the UDP datagrams that RakNet runs on.
+	UpstreamPacketListener raknet.UpstreamPacketListener
+
+	// DisableCookies specifies if listeners should not send cookies in the open connection handshake.
+	// Cookies protect against spoofed connection requests, but are not supported by some older clients.
+	DisableCookies bool
+	// BlockDuration is the duration that listeners block addresses that send invalid packets for. If zero,
+	// the go-raknet default is used. If negative, addresses are never blocked.
+	BlockDuration time.Duration
+}
 
 // DialContext ...
 func (r RakNet) DialContext(ctx context.Context, address string) (net.Conn, error) {
-	return raknet.DialContext(ctx, address)
+	return r.dialer().DialContext(ctx, address)
 }
 
 // PingContext ...
 func (r RakNet) PingContext(ctx context.Context, address string) (response []byte, err error) {
-	return raknet.PingContext(ctx, address)
+	return r.dialer().PingContext(ctx, address)
 }
 
 // Listen ...
 func (r RakNet) Listen(address string) (NetworkListener, error) {
-	return raknet.Listen(address)
+	return raknet.ListenConfig{
+		ErrorLog:               r.ErrorLog,
+		UpstreamPacketListener: r.UpstreamPacketListener,
+		DisableCookies:         r.DisableCookies,
+		BlockDuration:          r.BlockDuration,
+	}.Listen(address)
+}
+
+// dialer returns a raknet.Dialer with the settings of the RakNet.
+func (r RakNet) dialer() raknet.Dialer {
+	return raknet.Dialer{ErrorLog: r.ErrorLog, UpstreamDialer: r.UpstreamDialer}
+}
+
+// networkFor returns the Network with the ID passed, or the RakNet passed if it is non-nil and the ID is
+// "raknet".
+func networkFor(id string, r *RakNet) (Network, bool) {
+	if r != nil && id == "raknet" {
+		return *r, true
+	}
+	return networkByID(id)
 }
 
 // init registers the RakNet network.
@@ -0,0 +1,135 @@
+package minecraft
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket that limits the amount of bytes that may be sent per second. A nil
+// *rateLimiter imposes no limit at all.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a rateLimiter that allows bytesPerSecond bytes to be sent every second, with bursts
+// of up to burst bytes. If bytesPerSecond is 0 or lower, newRateLimiter returns nil.
+func newRateLimiter(bytesPerSecond, burst int) *rateLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	if burst < bytesPerSecond {
+		burst = bytesPerSecond
+	}
+	return &rateLimiter{rate: float64(bytesPerSecond), burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// wait blocks until n bytes may be sent according to the limits of the rateLimiter. It returns false if the
+// closed channel passed was closed before that was the case.
+func (l *rateLimiter) wait(n int, closed <-chan struct{}) bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	// The tokens are taken right away, even if it means the bucket ends up in debt. Subsequent calls will
+	// then have to wait for that debt to be paid off first, which keeps the order of callers intact.
+	l.tokens -= float64(n)
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return true
+	}
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-closed:
+		return false
+	}
+}
+
+// throttledConn is a net.Conn that limits the rate at which data is written to and read from the net.Conn
+// it wraps. Because a Conn writes every batch of packets in a single call to Write, limits are applied per
+// batch.
+type throttledConn struct {
+	net.Conn
+	up, down *rateLimiter
+
+	once   sync.Once
+	closed chan struct{}
+}
+
+// throttle wraps the net.Conn passed in a throttledConn that limits its upload and download rate to the
+// amount of bytes per second passed, with bursts of up to burst bytes. If both rates are 0 or lower, the
+// net.Conn is returned as is.
+func throttle(conn net.Conn, upload, download, burst int) net.Conn {
+	if upload <= 0 && download <= 0 {
+		return conn
+	}
+	c := &throttledConn{Conn: conn, up: newRateLimiter(upload, burst), down: newRateLimiter(download, burst), closed: make(chan struct{})}
+	if pr, ok := conn.(interface{ ReadPacket() ([]byte, error) }); ok {
+		// The decoder of a Conn reads full packets if the net.Conn supports it, so we need to make sure we
+		// keep supporting it.
+		return &throttledPacketConn{throttledConn: c, pr: pr}
+	}
+	return c
+}
+
+// Write waits until the upload limit allows len(b) bytes to be written and writes b to the net.Conn.
+func (c *throttledConn) Write(b []byte) (int, error) {
+	if !c.up.wait(len(b), c.closed) {
+		return 0, net.ErrClosed
+	}
+	return c.Conn.Write(b)
+}
+
+// Read reads data from the net.Conn into b and waits until the download limit allows the data read.
+func (c *throttledConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 && !c.down.wait(n, c.closed) {
+		return n, net.ErrClosed
+	}
+	return n, err
+}
+
+// Close closes the net.Conn and stops any calls waiting for the limits of the throttledConn.
+func (c *throttledConn) Close() error {
+	c.once.Do(func() {
+		close(c.closed)
+	})
+	return c.Conn.Close()
+}
+
+// Latency returns the latency of the net.Conn wrapped, so that Conn.Latency keeps working for throttled
+// connections. If the net.Conn has no Latency method, 0 is returned.
+func (c *throttledConn) Latency() time.Duration {
+	if l, ok := c.Conn.(interface{ Latency() time.Duration }); ok {
+		return l.Latency()
+	}
+	return 0
+}
+
+// throttledPacketConn is a throttledConn for net.Conns that support reading full packets at once, such as
+// RakNet connections.
+type throttledPacketConn struct {
+	*throttledConn
+	pr interface{ ReadPacket() ([]byte, error) }
+}
+
+// ReadPacket reads a full packet from the net.Conn and waits until the download limit allows the packet.
+func (c *throttledPacketConn) ReadPacket() ([]byte, error) {
+	b, err := c.pr.ReadPacket()
+	if len(b) > 0 && !c.down.wait(len(b), c.closed) {
+		return nil, net.ErrClosed
+	}
+	return b, err
+}
@@ -0,0 +1,91 @@
+package minecraft
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"slices"
+)
+
+// WriteRawBatch writes a decompressed batch of packets, each prefixed with their length as a varuint32, to the
+// Conn without decoding it. It is typically a batch returned by ReadRawBatch on another Conn, so that proxies
+// may forward batches untouched. Packets buffered before are sent first, so that ordering is preserved.
+// Because the packets are not decoded, they are not converted to the Protocol of the Conn and are not passed
+// through middleware, logged or captured. The batch must therefore hold packets of the Protocol of the Conn.
+func (conn *Conn) WriteRawBatch(batch []byte) error {
+	select {
+	case <-conn.close:
+		return conn.closeErr("write raw batch")
+	default:
+	}
+	if conn.parent != nil {
+		// Sub-clients share the connection of their parent, so their packets are sent in its batches.
+		return conn.parent.WriteRawBatch(batch)
+	}
+	conn.sendMu.Lock()
+	defer conn.sendMu.Unlock()
+
+	conn.dequeue()
+	if err := conn.sendBuffered("write raw batch"); err != nil {
+		return err
+	}
+	conn.applyWriteTimeout()
+	if err := conn.enc.EncodeRaw(batch); err != nil && !errors.Is(err, net.ErrClosed) {
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			// Close flushes too, so it must be called without holding the lock.
+			go conn.closeWithErr(err)
+		}
+		return conn.wrap(err, "write raw batch")
+	}
+	return nil
+}
+
+// ReadRawBatch reads the next batch received by the Conn without decoding it. The batch returned is
+// decompressed and decrypted and holds the packets, each prefixed with their length as a varuint32. It may be
+// split into packets using packet.SplitBatch or forwarded using WriteRawBatch. ReadRawBatch may only be used
+// if the Conn was created with the ReadRawBatches field of a Dialer or ListenConfig set to true, and only
+// returns batches received after the Conn spawned. Until then, and for Conns that do not read raw batches,
+// ReadRawBatch blocks until the Conn is closed or a read deadline expires.
+func (conn *Conn) ReadRawBatch() ([]byte, error) {
+	timeout, stop := conn.readTimer()
+	defer stop()
+	select {
+	case <-conn.close:
+		return nil, conn.closeErr("read raw batch")
+	case <-conn.readDeadline:
+		return nil, conn.wrap(context.DeadlineExceeded, "read raw batch")
+	case <-timeout:
+		return nil, conn.wrap(context.DeadlineExceeded, "read raw batch")
+	case batch := <-conn.rawBatches:
+		return batch, nil
+	}
+}
+
+// decode decodes the next batch received over the connection and returns the packets it holds. If the Conn
+// reads raw batches and has spawned, the batch is passed to ReadRawBatch undecoded and no packets are
+// returned.
+func (conn *Conn) decode() ([][]byte, error) {
+	if conn.rawBatches == nil || !conn.spawned() {
+		return conn.dec.Decode()
+	}
+	batch, err := conn.dec.DecodeRaw()
+	if err != nil || len(batch) == 0 {
+		return nil, err
+	}
+	select {
+	case <-conn.close:
+	case conn.rawBatches <- slices.Clone(batch):
+	}
+	return nil, nil
+}
+
+// spawned checks if the spawn sequence of the Conn was completed.
+func (conn *Conn) spawned() bool {
+	select {
+	case <-conn.spawn:
+		return true
+	default:
+		return false
+	}
+}
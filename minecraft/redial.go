@@ -0,0 +1,226 @@
+package minecraft
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// RedialOptions holds the settings of a RedialingConn, which determine when and how often it dials the server
+// again after being disconnected.
+type RedialOptions struct {
+	// MinBackoff and MaxBackoff are the minimum and maximum duration to wait between two attempts to dial the
+	// server. The duration starts at MinBackoff and is doubled after every failed attempt, up to MaxBackoff.
+	// MinBackoff defaults to 1 second and MaxBackoff to 30 seconds.
+	MinBackoff, MaxBackoff time.Duration
+	// Timeout is the maximum duration of a single attempt to dial the server and spawn in its world. It
+	// defaults to 30 seconds.
+	Timeout time.Duration
+	// MaxAttempts is the maximum amount of consecutive failed attempts to dial the server, after which the
+	// RedialingConn gives up and returns the last error. If 0, the RedialingConn never gives up.
+	MaxAttempts int
+	// FollowTransfers specifies if the RedialingConn follows Transfer packets sent by the server. If true, the
+	// address in a Transfer packet is dialed immediately and used for all future attempts, and the Transfer
+	// packet is not returned by ReadPacket. The Dialer used should not have a TransferFunc set.
+	FollowTransfers bool
+
+	// ShouldRedial is called with the error that the connection was closed with. If it returns false, the
+	// server is not dialed again and the error is returned instead. If nil, the server is always dialed
+	// again.
+	ShouldRedial func(err error) bool
+	// OnDisconnect is called when the connection was closed and the server is about to be dialed again.
+	OnDisconnect func(err error)
+	// OnRedial is called with the new Conn after the server was dialed again and the Conn spawned in its
+	// world, before any packets are read from it. It may be used to restore the state of the session, such as
+	// by sending commands. If OnRedial returns an error, the Conn is closed and the attempt is considered
+	// failed.
+	OnRedial func(conn *Conn) error
+}
+
+// RedialingConn is a connection to a server that is automatically dialed again, with backoff, when it is
+// disconnected. Every new connection goes through the full login and spawn sequence, after which
+// RedialOptions.OnRedial is called so that the state of the session may be restored. RedialingConn is
+// useful for long-running clients such as bots.
+// RedialingConn is safe for concurrent use, but ReadPacket must not be called on multiple goroutines
+// simultaneously.
+type RedialingConn struct {
+	d                Dialer
+	network, address string
+	opts             RedialOptions
+
+	mu   sync.RWMutex
+	conn *Conn
+
+	once  sync.Once
+	close chan struct{}
+}
+
+// DialRedialing dials a Minecraft connection to the address passed over the network passed and spawns it in
+// the world of the server, like DialContext followed by Conn.DoSpawnContext. The RedialingConn returned dials
+// the server again using the same Dialer whenever the connection is closed, according to the RedialOptions
+// passed.
+func (d Dialer) DialRedialing(ctx context.Context, network, address string, opts RedialOptions) (*RedialingConn, error) {
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = time.Second
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = max(opts.MinBackoff, time.Second*30)
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = time.Second * 30
+	}
+	c := &RedialingConn{d: d, network: network, address: address, opts: opts, close: make(chan struct{})}
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	return c, nil
+}
+
+// Conn returns the Conn currently used by the RedialingConn. The Conn changes every time the server is dialed
+// again.
+func (c *RedialingConn) Conn() *Conn {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.conn
+}
+
+// ReadPacket reads a packet from the current Conn. If the Conn is closed, ReadPacket dials the server again
+// and continues reading from the new Conn once it is spawned. ReadPacket only returns an error if the
+// RedialingConn was closed or if it gave up dialing the server again.
+func (c *RedialingConn) ReadPacket() (packet.Packet, error) {
+	for {
+		conn := c.Conn()
+		pk, err := conn.ReadPacket()
+		if err == nil {
+			t, ok := packet.Unwrap(pk).(*packet.Transfer)
+			if !ok || !c.opts.FollowTransfers {
+				return pk, nil
+			}
+			c.mu.Lock()
+			c.address = transferAddress(t)
+			c.mu.Unlock()
+			_ = conn.Close()
+			if err := c.redial(conn, nil); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := c.redial(conn, err); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// WritePacket writes a packet to the current Conn. Packets written while the server is being dialed again
+// are written to the closed Conn and thus return an error.
+func (c *RedialingConn) WritePacket(pk packet.Packet) error {
+	return c.Conn().WritePacket(pk)
+}
+
+// Close closes the RedialingConn and its current Conn. The server is not dialed again afterwards.
+func (c *RedialingConn) Close() error {
+	var err error
+	c.once.Do(func() {
+		close(c.close)
+		err = c.Conn().Close()
+	})
+	return err
+}
+
+// redial dials the server again after the Conn passed was closed with the error passed. It returns an error
+// if the RedialingConn was closed or if the server should not or could not be dialed again. If cause is nil,
+// the Conn was closed because the server transferred the client, in which case the first attempt is made
+// immediately.
+func (c *RedialingConn) redial(old *Conn, cause error) error {
+	select {
+	case <-c.close:
+		if cause == nil {
+			return old.closeErr("read packet")
+		}
+		return cause
+	default:
+	}
+	backoff := c.opts.MinBackoff
+	if cause == nil {
+		backoff = 0
+	} else {
+		if c.opts.ShouldRedial != nil && !c.opts.ShouldRedial(cause) {
+			return cause
+		}
+		if c.opts.OnDisconnect != nil {
+			c.opts.OnDisconnect(cause)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.close:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for attempt := 1; ; attempt++ {
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return &net.OpError{Op: "redial", Net: "minecraft", Addr: old.RemoteAddr(), Err: net.ErrClosed}
+		case <-timer.C:
+		}
+		conn, err := c.dial(ctx)
+		if err == nil {
+			c.mu.Lock()
+			c.conn = conn
+			c.mu.Unlock()
+			select {
+			case <-c.close:
+				// The RedialingConn was closed while dialing, so make sure the new Conn doesn't stay open.
+				_ = conn.Close()
+				return conn.closeErr("redial")
+			default:
+				return nil
+			}
+		}
+		if c.opts.MaxAttempts > 0 && attempt >= c.opts.MaxAttempts {
+			return fmt.Errorf("redial: gave up after %v attempts: %w", attempt, err)
+		}
+		backoff = min(max(backoff*2, c.opts.MinBackoff), c.opts.MaxBackoff)
+	}
+}
+
+// dial dials the server, spawns the Conn in its world and calls the OnRedial function of the RedialOptions if
+// the server was dialed before.
+func (c *RedialingConn) dial(ctx context.Context) (*Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+
+	c.mu.RLock()
+	address := c.address
+	c.mu.RUnlock()
+	conn, err := c.d.DialContext(ctx, c.network, address, c.opts.Timeout)
+	if err != nil {
+		if conn != nil {
+			_ = conn.Close()
+		}
+		return nil, err
+	}
+	if err := conn.DoSpawnContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if c.Conn() != nil && c.opts.OnRedial != nil {
+		if err := c.opts.OnRedial(conn); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("restore session: %w", err)
+		}
+	}
+	return conn, nil
+}
@@ -0,0 +1,59 @@
+package minecraft
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/sandertv/gophertunnel/minecraft/resource"
+)
+
+// RenegotiateResourcePacks starts a new resource pack negotiation with a client that already spawned, by
+// sending a ResourcePacksInfo packet again. The client may download packs it does not yet have, after which
+// the new resource pack stack is sent. If packs is non-nil and the Conn uses the default ResourcePackHandler,
+// the packs passed replace the resource packs of the Conn. Otherwise, the packs of the ResourcePackHandler are
+// sent again. RenegotiateResourcePacks must only be called on a Conn obtained using a Listener, and returns
+// once the ResourcePacksInfo packet is sent, while the negotiation completes in the background.
+func (conn *Conn) RenegotiateResourcePacks(packs []*resource.Pack) error {
+	if !conn.spawned() {
+		return conn.wrap(errors.New("client has not spawned"), "renegotiate resource packs")
+	}
+	if !conn.renegotiating.CompareAndSwap(false, true) {
+		return conn.wrap(errors.New("resource packs are already being negotiated"), "renegotiate resource packs")
+	}
+	if h, ok := conn.ResourcePackHandler.(*defaultResourcepackHandler); ok && packs != nil {
+		h.packMu.Lock()
+		h.resourcePacks, h.packsFor = packs, nil
+		h.packMu.Unlock()
+	}
+	conn.expect(packet.IDResourcePackClientResponse)
+	conn.enterPhase("resource packs", conn.loginTimeouts.ResourcePacks)
+	if err := conn.WritePacket(conn.ResourcePackHandler.GetResourcePacksInfo(conn.texturePacksRequired)); err != nil {
+		conn.renegotiating.Store(false)
+		return conn.wrap(fmt.Errorf("send ResourcePacksInfo: %w", err), "renegotiate resource packs")
+	}
+	return nil
+}
+
+// renegotiatingPacks checks if the packetData passed, received after the Conn logged in, is part of a resource
+// pack negotiation that takes place after spawning, and must therefore be handled by the Conn. A negotiation
+// is started if a client Conn receives a ResourcePacksInfo packet after spawning.
+func (conn *Conn) renegotiatingPacks(pkData *packetData) bool {
+	if pkData.h.PacketID == packet.IDResourcePacksInfo && conn.spawned() && conn.renegotiating.CompareAndSwap(false, true) {
+		conn.expect(packet.IDResourcePacksInfo)
+		return true
+	}
+	return conn.renegotiating.Load() && slices.Contains(*conn.expectedIDs.Load(), pkData.h.PacketID)
+}
+
+// finishRenegotiation ends a resource pack negotiation that took place after spawning. It returns false if the
+// Conn was not renegotiating resource packs, meaning the negotiation was part of the login sequence.
+func (conn *Conn) finishRenegotiation() bool {
+	if !conn.renegotiating.CompareAndSwap(true, false) {
+		return false
+	}
+	conn.expect()
+	conn.enterPhase("", 0)
+	return true
+}
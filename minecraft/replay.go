@@ -0,0 +1,125 @@
+package minecraft
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// ReplayConn replays the packets read by a Conn obtained using Dial from a capture written by a
+// CaptureWriter, such as one recorded using Dialer.Capture. It implements the read methods of Conn, so that
+// tools that consume the packets of a Conn may be tested offline, without connecting to a live server.
+// Packets written by the captured Conn are skipped.
+type ReplayConn struct {
+	r        *CaptureReader
+	proto    Protocol
+	pool     packet.Pool
+	speed    float64
+	shieldID int32
+
+	start, first time.Time
+	pending      []packet.Packet
+	pendingTime  time.Time
+
+	once  sync.Once
+	close chan struct{}
+}
+
+// NewReplayConn returns a ReplayConn that replays the capture read from the io.Reader passed. Packets are
+// returned by ReadPacket at the same pace as they were originally read, multiplied by speed: A speed of 2
+// replays a capture twice as fast. If speed is 0 or lower, packets are returned as fast as possible.
+func NewReplayConn(r io.Reader, speed float64) (*ReplayConn, error) {
+	cr, err := NewCaptureReader(r)
+	if err != nil {
+		return nil, err
+	}
+	p := proto{}
+	return &ReplayConn{r: cr, proto: p, pool: p.Packets(false), speed: speed, close: make(chan struct{})}, nil
+}
+
+// ReadPacket reads the next packet from the capture, waiting until it is due according to the timing of the
+// capture and the speed of the ReplayConn. ReadPacket returns io.EOF once all packets were replayed.
+func (c *ReplayConn) ReadPacket() (pk packet.Packet, err error) {
+	pk, _, err = c.ReadPacketWithTime()
+	return pk, err
+}
+
+// ReadPacketWithTime reads the next packet from the capture like ReadPacket. It also returns the time at
+// which the packet was originally read.
+func (c *ReplayConn) ReadPacketWithTime() (pk packet.Packet, receivedAt time.Time, err error) {
+	for len(c.pending) == 0 {
+		captured, err := c.r.ReadPacket()
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		if captured.Direction != DirectionRead {
+			continue
+		}
+		if !c.wait(captured.Time) {
+			return nil, time.Time{}, &net.OpError{Op: "read packet", Net: "minecraft", Err: net.ErrClosed}
+		}
+		data, err := ParseData(captured.Data, nil, nil, nil)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		pks, err := data.Decode(c.pool, c.proto, c.Close, false, false, c.shieldID, protocol.ReaderLimits{}, false, false)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("replay packet: %w", err)
+		}
+		for _, pk := range pks {
+			if start, ok := pk.(*packet.StartGame); ok {
+				// Like a Conn, we need the shield ID from the StartGame packet to decode items properly.
+				for _, item := range start.Items {
+					if item.Name == "minecraft:shield" {
+						c.shieldID = int32(item.RuntimeID)
+					}
+				}
+			}
+		}
+		c.pending, c.pendingTime = pks, captured.Time
+	}
+	pk, c.pending = c.pending[0], c.pending[1:]
+	return pk, c.pendingTime, nil
+}
+
+// wait waits until a packet captured at the time passed is due. It returns false if the ReplayConn was
+// closed while waiting.
+func (c *ReplayConn) wait(t time.Time) bool {
+	if c.first.IsZero() {
+		c.start, c.first = time.Now(), t
+	}
+	select {
+	case <-c.close:
+		return false
+	default:
+	}
+	if c.speed <= 0 {
+		return true
+	}
+	delay := time.Until(c.start.Add(time.Duration(float64(t.Sub(c.first)) / c.speed)))
+	if delay <= 0 {
+		return true
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-c.close:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// Close closes the ReplayConn. Calls to ReadPacket that are waiting for a packet to be due return
+// immediately with an error.
+func (c *ReplayConn) Close() error {
+	c.once.Do(func() {
+		close(c.close)
+	})
+	return nil
+}
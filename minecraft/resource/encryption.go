@@ -0,0 +1,179 @@
+package resource
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// contentsMagic is the magic number found in the header of an encrypted contents.json file.
+const contentsMagic = 0x9bcfb9fc
+
+// contentsHeaderSize is the size of the header of an encrypted contents.json file. The encrypted data starts
+// right after it.
+const contentsHeaderSize = 0x100
+
+// contents is the decrypted form of the contents.json file of an encrypted pack. It holds the keys used to
+// encrypt each of the files in the pack.
+type contents struct {
+	Content []struct {
+		Path string `json:"path"`
+		Key  string `json:"key"`
+	} `json:"content"`
+}
+
+// Decrypt decrypts the files of an encrypted resource pack using its content key and returns a new Pack that
+// holds the decrypted files, so that they may be read directly. If the pack is not encrypted, Decrypt returns
+// the pack itself.
+func (pack *Pack) Decrypt() (*Pack, error) {
+	if !pack.Encrypted() {
+		return pack, nil
+	}
+	zr, err := zip.NewReader(pack.content, pack.content.Size())
+	if err != nil {
+		return nil, fmt.Errorf("decrypt resource pack: open zip reader: %w", err)
+	}
+	baseDir := filepath.ToSlash(pack.baseDir)
+	if baseDir == "." {
+		baseDir = ""
+	}
+	contentsName := path.Join(baseDir, "contents.json")
+
+	var c contents
+	var decryptedContents []byte
+	for _, f := range zr.File {
+		if f.Name != contentsName {
+			continue
+		}
+		data, err := readZipFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt resource pack: %w", err)
+		}
+		if decryptedContents, err = decryptContents(data, []byte(pack.contentKey)); err != nil {
+			return nil, fmt.Errorf("decrypt resource pack: %w", err)
+		}
+		if err := parseJson(decryptedContents, &c); err != nil {
+			return nil, fmt.Errorf("decrypt resource pack: parse contents.json: %w", err)
+		}
+		break
+	}
+	if decryptedContents == nil {
+		return nil, fmt.Errorf("decrypt resource pack: contents.json not found")
+	}
+	keys := make(map[string]string, len(c.Content))
+	for _, entry := range c.Content {
+		if entry.Key != "" {
+			keys[path.Join(baseDir, entry.Path)] = entry.Key
+		}
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, pack.content.Size()))
+	w := zip.NewWriter(buf)
+	for _, f := range zr.File {
+		var data []byte
+		switch key, ok := keys[f.Name]; {
+		case f.Name == contentsName:
+			data = decryptedContents
+		case strings.HasSuffix(f.Name, "/"):
+			if _, err := w.CreateHeader(&f.FileHeader); err != nil {
+				return nil, fmt.Errorf("decrypt resource pack: create zip directory: %w", err)
+			}
+			continue
+		default:
+			if data, err = readZipFile(f); err != nil {
+				return nil, fmt.Errorf("decrypt resource pack: %w", err)
+			}
+			if ok {
+				if data, err = decryptCFB8(data, []byte(key)); err != nil {
+					return nil, fmt.Errorf("decrypt resource pack: decrypt %v: %w", f.Name, err)
+				}
+			}
+		}
+		fw, err := w.CreateHeader(&zip.FileHeader{Name: f.Name, Method: zip.Deflate, Modified: f.Modified})
+		if err != nil {
+			return nil, fmt.Errorf("decrypt resource pack: create zip file: %w", err)
+		}
+		if _, err := fw.Write(data); err != nil {
+			return nil, fmt.Errorf("decrypt resource pack: write zip file: %w", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("decrypt resource pack: close zip writer: %w", err)
+	}
+	decrypted, err := Read(buf)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt resource pack: %w", err)
+	}
+	decrypted.downloadURL = pack.downloadURL
+	return decrypted, nil
+}
+
+// decryptContents decrypts the data of an encrypted contents.json file using the content key of the pack.
+func decryptContents(data, key []byte) ([]byte, error) {
+	if len(data) < contentsHeaderSize {
+		return nil, fmt.Errorf("contents.json too short: %v bytes", len(data))
+	}
+	if magic := binary.LittleEndian.Uint32(data[4:]); magic != contentsMagic {
+		return nil, fmt.Errorf("contents.json has invalid magic %#x", magic)
+	}
+	return decryptCFB8(data[contentsHeaderSize:], key)
+}
+
+// decryptCFB8 decrypts data that was encrypted using AES-256 in CFB8 mode, with the first 16 bytes of the key
+// used as IV.
+func decryptCFB8(data, key []byte) ([]byte, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("invalid key length %v: expected 32", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data))
+	newCFB8Decrypter(block, key[:aes.BlockSize]).XORKeyStream(out, data)
+	return out, nil
+}
+
+// cfb8 implements cipher.Stream for CFB8 decryption, which is not implemented by the crypto/cipher package.
+type cfb8 struct {
+	b        cipher.Block
+	register []byte
+	tmp      []byte
+}
+
+// newCFB8Decrypter returns a cipher.Stream that decrypts data using the cipher.Block and IV passed in CFB8
+// mode.
+func newCFB8Decrypter(b cipher.Block, iv []byte) cipher.Stream {
+	return &cfb8{b: b, register: bytes.Clone(iv), tmp: make([]byte, b.BlockSize())}
+}
+
+// XORKeyStream decrypts src into dst, one byte at a time.
+func (x *cfb8) XORKeyStream(dst, src []byte) {
+	for i, c := range src {
+		x.b.Encrypt(x.tmp, x.register)
+		copy(x.register, x.register[1:])
+		x.register[len(x.register)-1] = c
+		dst[i] = c ^ x.tmp[0]
+	}
+}
+
+// readZipFile reads the full content of the zip.File passed.
+func readZipFile(f *zip.File) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open zip file %v: %w", f.Name, err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read zip file %v: %w", f.Name, err)
+	}
+	return data, nil
+}
@@ -1,7 +1,6 @@
 package minecraft
 
 import (
-	"bytes"
 	"fmt"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 	"github.com/sandertv/gophertunnel/minecraft/resource"
@@ -14,6 +13,7 @@ type resourcePackQueue struct {
 	packsToDownload map[string]*resource.Pack
 	currentPack     *resource.Pack
 	currentOffset   uint64
+	chunkSize       uint64
 
 	packAmount       int
 	downloadingPacks map[string]downloadingPack
@@ -22,12 +22,14 @@ type resourcePackQueue struct {
 
 // downloadingPack is a resource pack that is being downloaded by a client connection.
 type downloadingPack struct {
-	buf           *bytes.Buffer
+	buf           *spillBuffer
 	chunkSize     uint32
 	size          uint64
 	expectedIndex uint32
 	newFrag       chan []byte
 	contentKey    string
+	version       string
+	hash          []byte
 }
 
 // Request 'requests' all resource packs passed, provided they all exist in the resourcePackQueue. If not,
@@ -77,8 +79,8 @@ func (queue *resourcePackQueue) NextPack() (pk *packet.ResourcePackDataInfo, ok
 		}
 		return &packet.ResourcePackDataInfo{
 			UUID:          pack.UUID(),
-			DataChunkSize: packChunkSize,
-			ChunkCount:    uint32(pack.DataChunkCount(packChunkSize)),
+			DataChunkSize: uint32(queue.chunkSize),
+			ChunkCount:    uint32(pack.DataChunkCount(int(queue.chunkSize))),
 			Size:          uint64(pack.Len()),
 			Hash:          checksum[:],
 			PackType:      packType,
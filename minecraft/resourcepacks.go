@@ -2,15 +2,22 @@ package minecraft
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
 	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 	"github.com/sandertv/gophertunnel/minecraft/resource"
+	"github.com/sandertv/gophertunnel/minecraft/text"
 )
 
 type ResourcePackHandler interface {
@@ -36,15 +43,44 @@ type defaultResourcepackHandler struct {
 	// ignoredResourcePacks is a slice of resource packs that are not being downloaded due to the downloadResourcePack
 	// func returning false for the specific pack.
 	ignoredResourcePacks []exemptedResourcePack
+	// acknowledgedPacks holds the metadata of resource packs that were acknowledged without being downloaded.
+	acknowledgedPacks []AcknowledgedPack
+
+	// packsFor, if non-nil, is called to select the resource packs sent to the client once it has logged in.
+	packsFor func(conn *Conn) []*resource.Pack
+	// missingPacksMessage, if non-nil, returns the message that a client is disconnected with if it did not
+	// download required resource packs.
+	missingPacksMessage func(conn *Conn, missing []*resource.Pack) string
+	// stack holds the base game version, experiments and editor pack flag sent in the ResourcePackStack packet.
+	stack packet.ResourcePackStack
+	// stackFunc, if non-nil, is called with the ResourcePackStack packet before it is sent to the client.
+	stackFunc func(conn *Conn, stack *packet.ResourcePackStack)
+	// chunkSize is the size in bytes of the chunks that resource packs are split into when sent to a client.
+	chunkSize uint64
+	// limiters holds the rate limiters that resource pack chunk data sent to the client is subject to. Nil
+	// limiters in the slice impose no limit.
+	limiters []*rateLimiter
+	// lastChunk is the resource pack chunk data most recently sent to the client. It is sent again if the
+	// client requests the same chunk again, which it does if the chunk did not arrive in time.
+	lastChunk *packet.ResourcePackChunkData
 }
 
 func (r *defaultResourcepackHandler) ResourcePacks() []*resource.Pack {
 	return r.resourcePacks
 }
 
+// AcknowledgedPacks returns the metadata of all resource packs that were acknowledged without downloading them.
+func (r *defaultResourcepackHandler) AcknowledgedPacks() []AcknowledgedPack {
+	r.packMu.Lock()
+	defer r.packMu.Unlock()
+	return slices.Clone(r.acknowledgedPacks)
+}
+
 // OnResourcePacksInfo handles a ResourcePacksInfo packet sent by the server. The client responds by
 // sending the packs it needs downloaded.
 func (r *defaultResourcepackHandler) OnResourcePacksInfo(pk *packet.ResourcePacksInfo) error {
+	r.c.packHooks.info(r.c, pk)
+
 	// First create a new resource pack queue with the information in the packet so we can download them
 	// properly later.
 	totalPacks := len(pk.TexturePacks) + len(pk.BehaviourPacks)
@@ -55,59 +91,53 @@ func (r *defaultResourcepackHandler) OnResourcePacksInfo(pk *packet.ResourcePack
 	}
 	packsToDownload := make([]string, 0, totalPacks)
 
+	urls := make(map[string]string, len(pk.PackURLs))
+	for _, packURL := range pk.PackURLs {
+		urls[packURL.UUIDVersion] = packURL.URL
+	}
 	for index, pack := range pk.TexturePacks {
-		if _, ok := r.packQueue.downloadingPacks[pack.UUID]; ok {
-			r.c.log.Printf("duplicate texture pack entry %v in resource pack info\n", pack.UUID)
-			r.packQueue.packAmount--
-			continue
-		}
-		if r.c.downloadResourcePack != nil && !r.c.downloadResourcePack(uuid.MustParse(pack.UUID), pack.Version, index, totalPacks) {
-			r.ignoredResourcePacks = append(r.ignoredResourcePacks, exemptedResourcePack{
-				uuid:    pack.UUID,
-				version: pack.Version,
-			})
-			r.packQueue.packAmount--
-			continue
-		}
-		// This UUID_Version is a hack Mojang put in place.
-		packsToDownload = append(packsToDownload, pack.UUID+"_"+pack.Version)
-		r.packQueue.downloadingPacks[pack.UUID] = downloadingPack{
-			size:       pack.Size,
-			buf:        bytes.NewBuffer(make([]byte, 0, pack.Size)),
-			newFrag:    make(chan []byte),
-			contentKey: pack.ContentKey,
+		info := AcknowledgedPack{UUID: pack.UUID, Version: pack.Version, Size: pack.Size, ContentKey: pack.ContentKey}
+		if r.queueDownload(info, index, totalPacks) {
+			// This UUID_Version is a hack Mojang put in place.
+			packsToDownload = append(packsToDownload, pack.UUID+"_"+pack.Version)
 		}
 	}
 	for index, pack := range pk.BehaviourPacks {
-		if _, ok := r.packQueue.downloadingPacks[pack.UUID]; ok {
-			r.c.log.Printf("duplicate behaviour pack entry %v in resource pack info\n", pack.UUID)
-			r.packQueue.packAmount--
-			continue
-		}
-		if r.c.downloadResourcePack != nil && !r.c.downloadResourcePack(uuid.MustParse(pack.UUID), pack.Version, index, totalPacks) {
-			r.ignoredResourcePacks = append(r.ignoredResourcePacks, exemptedResourcePack{
-				uuid:    pack.UUID,
-				version: pack.Version,
-			})
-			r.packQueue.packAmount--
-			continue
-		}
-		// This UUID_Version is a hack Mojang put in place.
-		packsToDownload = append(packsToDownload, pack.UUID+"_"+pack.Version)
-		r.packQueue.downloadingPacks[pack.UUID] = downloadingPack{
-			size:       pack.Size,
-			buf:        bytes.NewBuffer(make([]byte, 0, pack.Size)),
-			newFrag:    make(chan []byte),
-			contentKey: pack.ContentKey,
+		// Behaviour packs are numbered after the texture packs, so that the index passed to the
+		// downloadResourcePack function is unique for every pack.
+		info := AcknowledgedPack{UUID: pack.UUID, Version: pack.Version, Size: pack.Size, ContentKey: pack.ContentKey, Behaviours: true}
+		if r.queueDownload(info, len(pk.TexturePacks)+index, totalPacks) {
+			packsToDownload = append(packsToDownload, pack.UUID+"_"+pack.Version)
 		}
 	}
 
+	var packsFromURL []string
+	packsToDownload = slices.DeleteFunc(packsToDownload, func(uuidVersion string) bool {
+		if _, ok := urls[uuidVersion]; ok {
+			packsFromURL = append(packsFromURL, uuidVersion)
+			return true
+		}
+		return false
+	})
+	if len(packsFromURL) != 0 {
+		// Like the vanilla client, we download packs that the server serves over HTTP from their URL. This
+		// happens before any other packs are requested, so that packs that fail to download from their URL
+		// may still be requested over the connection.
+		go func() {
+			for _, uuidVersion := range packsFromURL {
+				if err := r.downloadURL(uuidVersion, urls[uuidVersion]); err != nil {
+					r.c.log.Printf("download resource pack %v from URL, falling back to connection: %v\n", uuidVersion, err)
+					packsToDownload = append(packsToDownload, uuidVersion)
+				}
+			}
+			if len(packsToDownload) != 0 {
+				r.requestPacks(packsToDownload)
+			}
+		}()
+		return nil
+	}
 	if len(packsToDownload) != 0 {
-		r.c.expect(packet.IDResourcePackDataInfo, packet.IDResourcePackChunkData)
-		_ = r.c.WritePacket(&packet.ResourcePackClientResponse{
-			Response:        packet.PackResponseSendPacks,
-			PacksToDownload: packsToDownload,
-		})
+		r.requestPacks(packsToDownload)
 		return nil
 	}
 	r.c.expect(packet.IDResourcePackStack)
@@ -116,6 +146,163 @@ func (r *defaultResourcepackHandler) OnResourcePacksInfo(pk *packet.ResourcePack
 	return nil
 }
 
+// requestPacks requests the server to send the packs passed, in the format UUID_Version, over the connection.
+func (r *defaultResourcepackHandler) requestPacks(packsToDownload []string) {
+	r.c.expect(packet.IDResourcePackDataInfo, packet.IDResourcePackChunkData)
+	_ = r.c.WritePacket(&packet.ResourcePackClientResponse{
+		Response:        packet.PackResponseSendPacks,
+		PacksToDownload: packsToDownload,
+	})
+}
+
+// downloadURL downloads the pack with the UUID_Version passed from the URL that the server sent for it. If
+// successful, the pack is marked as downloaded. If not, an error is returned and the pack remains queued, so
+// that it may still be downloaded over the connection.
+func (r *defaultResourcepackHandler) downloadURL(uuidVersion, url string) error {
+	id := strings.Split(uuidVersion, "_")[0]
+	pack := r.packQueue.downloadingPacks[id]
+	r.transferStarted(id, pack.version, pack.size)
+
+	err := r.fetchURL(url, &pack)
+
+	r.packMu.Lock()
+	defer r.packMu.Unlock()
+	var newPack *resource.Pack
+	if err == nil {
+		newPack, err = r.readDownload(id, &pack)
+	}
+	if err == nil && newPack != nil && (newPack.UUID() != id || newPack.Version() != pack.version) {
+		err = fmt.Errorf("URL served pack %v_%v", newPack.UUID(), newPack.Version())
+	}
+	if err != nil {
+		r.transferFinished(id, pack.version, err)
+		// The pack is downloaded over the connection instead, so its data has to be written to an empty
+		// buffer.
+		_ = pack.buf.Close()
+		pack.buf = newSpillBuffer(r.c.packMemory, r.c.checksumPolicy != ChecksumPolicyIgnore)
+		r.packQueue.downloadingPacks[id] = pack
+		return err
+	}
+	_ = pack.buf.Close()
+	delete(r.packQueue.downloadingPacks, id)
+	if newPack != nil {
+		r.addPack(id, newPack.WithDownloadURL(url), pack.contentKey)
+		r.transferFinished(id, pack.version, nil)
+		r.packDownloaded()
+	}
+	return nil
+}
+
+// fetchURL downloads the data of the pack passed from the URL passed into the buffer of the pack. The
+// download is cancelled if it takes longer than the URL timeout of the connection or if the connection is
+// closed. No more than one byte more than the size announced for the pack is read, so that a URL serving
+// more data than that fails the size check without being downloaded completely.
+func (r *defaultResourcepackHandler) fetchURL(url string, pack *downloadingPack) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if r.c.packURLTimeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, r.c.packURLTimeout)
+		defer cancelTimeout()
+	}
+	go func() {
+		select {
+		case <-r.c.close:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%v (%d)", resp.Status, resp.StatusCode)
+	}
+	_, err = io.Copy(pack.buf, io.LimitReader(resp.Body, int64(pack.size)+1))
+	return err
+}
+
+// queueDownload adds a texture or behaviour pack sent in the ResourcePacksInfo packet to the pack queue, so
+// that it may be downloaded. False is returned if the pack should not be downloaded, either because it was a
+// duplicate entry or because the user chose not to download it.
+func (r *defaultResourcepackHandler) queueDownload(info AcknowledgedPack, index, totalPacks int) bool {
+	if _, ok := r.packQueue.downloadingPacks[info.UUID]; ok {
+		kind := "texture"
+		if info.Behaviours {
+			kind = "behaviour"
+		}
+		r.c.log.Printf("duplicate %v pack entry %v in resource pack info\n", kind, info.UUID)
+		r.packQueue.packAmount--
+		return false
+	}
+	decision := PackDecisionDownload
+	if r.c.skipPacks {
+		decision = PackDecisionIgnore
+	} else if r.c.packDecision != nil {
+		decision = r.c.packDecision(uuid.MustParse(info.UUID), info.Version, index, totalPacks)
+	} else if r.c.downloadResourcePack != nil && !r.c.downloadResourcePack(uuid.MustParse(info.UUID), info.Version, index, totalPacks) {
+		decision = PackDecisionIgnore
+	}
+	if decision != PackDecisionDownload {
+		r.ignoredResourcePacks = append(r.ignoredResourcePacks, exemptedResourcePack{
+			uuid:    info.UUID,
+			version: info.Version,
+		})
+		if decision == PackDecisionAcknowledge {
+			r.packMu.Lock()
+			r.acknowledgedPacks = append(r.acknowledgedPacks, info)
+			r.packMu.Unlock()
+		}
+		r.packQueue.packAmount--
+		return false
+	}
+	r.packQueue.downloadingPacks[info.UUID] = downloadingPack{
+		size:       info.Size,
+		buf:        newSpillBuffer(r.c.packMemory, r.c.checksumPolicy != ChecksumPolicyIgnore),
+		newFrag:    make(chan []byte),
+		contentKey: info.ContentKey,
+		version:    info.Version,
+	}
+	return true
+}
+
+// PackDecision is a decision made by a Conn obtained using Dial on what to do with a resource pack that the
+// server announced in its ResourcePacksInfo packet.
+type PackDecision int
+
+const (
+	// PackDecisionDownload downloads the resource pack fully. Once downloaded, it is available through
+	// Conn.ResourcePacks.
+	PackDecisionDownload PackDecision = iota
+	// PackDecisionIgnore does not download the resource pack. The pack is still accepted if the server puts
+	// it on the resource pack stack.
+	PackDecisionIgnore
+	// PackDecisionAcknowledge does not download the data of the resource pack, but acknowledges the pack so
+	// that it is accepted on the resource pack stack. Its metadata, as sent by the server, is available
+	// through Conn.AcknowledgedPacks.
+	PackDecisionAcknowledge
+)
+
+// AcknowledgedPack holds the metadata of a resource pack as announced by the server in the ResourcePacksInfo
+// packet. It is used for packs that were acknowledged without downloading their data.
+type AcknowledgedPack struct {
+	// UUID and Version are the UUID and version of the resource pack.
+	UUID, Version string
+	// Size is the size in bytes of the archive of the resource pack.
+	Size uint64
+	// ContentKey is the key used to decrypt the resource pack if it is encrypted.
+	ContentKey string
+	// Behaviours is true if the pack was sent as a behaviour pack rather than a texture pack.
+	Behaviours bool
+}
+
 // OnResourcePackDataInfo handles a resource pack data info packet, which initiates the downloading of the
 // pack by the client.
 func (r *defaultResourcepackHandler) OnResourcePackDataInfo(pk *packet.ResourcePackDataInfo) error {
@@ -138,6 +325,10 @@ func (r *defaultResourcepackHandler) OnResourcePackDataInfo(pk *packet.ResourceP
 	delete(r.packQueue.downloadingPacks, id)
 	r.packQueue.awaitingPacks[id] = &pack
 
+	if pk.DataChunkSize == 0 {
+		return fmt.Errorf("resource pack %v has a data chunk size of 0", pk.UUID)
+	}
+	// We always respect the chunk size that the server advertises, regardless of what it is.
 	pack.chunkSize = pk.DataChunkSize
 
 	// The client calculates the chunk count by itself: You could in theory send a chunk count of 0 even
@@ -147,62 +338,272 @@ func (r *defaultResourcepackHandler) OnResourcePackDataInfo(pk *packet.ResourceP
 		chunkCount++
 	}
 
+	pack.hash = pk.Hash
+	r.transferStarted(id, pack.version, pack.size)
+
 	idCopy := pk.UUID
 	go func() {
+		defer pack.buf.Close()
 		for i := uint32(0); i < chunkCount; i++ {
-			_ = r.c.WritePacket(&packet.ResourcePackChunkRequest{
-				UUID:       idCopy,
-				ChunkIndex: i,
-			})
-			select {
-			case <-r.c.close:
+			frag, err := r.requestChunk(idCopy, i, &pack)
+			if errors.Is(err, net.ErrClosed) {
+				r.transferFinished(id, pack.version, err)
+				return
+			} else if err != nil {
+				r.c.log.Printf("download resource pack %v: %v\n", id, err)
+				r.transferFinished(id, pack.version, err)
+				r.c.closeWithErr(fmt.Errorf("download resource pack %v: %w", id, err))
+				return
+			}
+			// Write the fragment to the full buffer of the downloading resource pack.
+			if _, err := pack.buf.Write(frag); err != nil {
+				r.c.log.Printf("download resource pack %v: %v\n", id, err)
+				r.transferFinished(id, pack.version, err)
+				r.c.closeWithErr(fmt.Errorf("download resource pack %v: %w", id, err))
 				return
-			case frag := <-pack.newFrag:
-				// Write the fragment to the full buffer of the downloading resource pack.
-				_, _ = pack.buf.Write(frag)
 			}
 		}
 		r.packMu.Lock()
 		defer r.packMu.Unlock()
 
-		if pack.buf.Len() != int(pack.size) {
-			r.c.log.Printf("incorrect resource pack size: expected %v, but got %v\n", pack.size, pack.buf.Len())
-			return
-		}
-		// First parse the resource pack from the total byte buffer we obtained.
-		newPack, err := resource.Read(pack.buf)
+		newPack, err := r.readDownload(id, &pack)
 		if err != nil {
-			r.c.log.Printf("invalid full resource pack data for UUID %v: %v\n", id, err)
+			r.c.log.Printf("download resource pack %v: %v\n", id, err)
+			r.transferFinished(id, pack.version, err)
 			return
 		}
-		r.packQueue.packAmount--
-		// Finally we add the resource to the resource packs slice.
-		r.resourcePacks = append(r.resourcePacks, newPack.WithContentKey(pack.contentKey))
-		if r.packQueue.packAmount == 0 {
-			r.c.expect(packet.IDResourcePackStack)
-			_ = r.c.WritePacket(&packet.ResourcePackClientResponse{Response: packet.PackResponseAllPacksDownloaded})
+		if newPack != nil {
+			r.addPack(id, newPack, pack.contentKey)
+			r.transferFinished(id, pack.version, nil)
+			r.packDownloaded()
 		}
 	}()
 	return nil
 }
 
+// readDownload verifies the data downloaded into the buffer of the pack passed against the size and checksum
+// sent by the server and parses it. If the checksum does not match, the ChecksumPolicy of the connection is
+// applied: If the pack is rejected or the connection closed as a result, a nil pack and error are returned.
+// Packs downloaded from a URL have no checksum, as the server only sends it in the ResourcePackDataInfo
+// packet, so only their size is verified. readDownload must be called while holding r.packMu.
+func (r *defaultResourcepackHandler) readDownload(id string, pack *downloadingPack) (*resource.Pack, error) {
+	if pack.buf.Len() != int(pack.size) {
+		return nil, fmt.Errorf("incorrect resource pack size: expected %v, but got %v", pack.size, pack.buf.Len())
+	}
+	// With ChecksumPolicyIgnore, the checksum is not computed while downloading, so it is not verified either.
+	if r.c.checksumPolicy != ChecksumPolicyIgnore {
+		if err := verifyChecksum(pack.buf.Sum(), pack.hash); err != nil {
+			switch r.c.checksumPolicy {
+			case ChecksumPolicyWarn:
+				r.c.log.Printf("resource pack %v: %v\n", id, err)
+			case ChecksumPolicyRejectPack:
+				r.c.log.Printf("rejecting resource pack %v: %v\n", id, err)
+				// The pack is treated as if it was never downloaded in the first place, so that the
+				// connection may continue without it.
+				r.ignoredResourcePacks = append(r.ignoredResourcePacks, exemptedResourcePack{uuid: id, version: pack.version})
+				r.transferFinished(id, pack.version, err)
+				r.packDownloaded()
+				return nil, nil
+			case ChecksumPolicyDisconnect:
+				r.c.log.Printf("closing connection: resource pack %v: %v\n", id, err)
+				r.transferFinished(id, pack.version, err)
+				r.c.closeWithErr(fmt.Errorf("download resource pack %v: %w", id, err))
+				return nil, nil
+			}
+		}
+	}
+	data, err := pack.buf.Reader()
+	if err != nil {
+		return nil, err
+	}
+	newPack, err := resource.Read(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid full resource pack data: %w", err)
+	}
+	return newPack, nil
+}
+
+// transferStarted records the start of the transfer of a resource pack and calls the DownloadStarted hook of
+// the connection.
+func (r *defaultResourcepackHandler) transferStarted(uuid, version string, size uint64) {
+	r.c.packStats.started(uuid, version, size)
+	r.c.packHooks.downloadStarted(r.c, uuid, version, size)
+}
+
+// transferFinished records the end of the transfer of a resource pack and calls the DownloadFinished hook of
+// the connection.
+func (r *defaultResourcepackHandler) transferFinished(uuid, version string, err error) {
+	r.c.packStats.finished(uuid, version, err)
+	r.c.packHooks.downloadFinished(r.c, uuid, version, err)
+}
+
+// addPack adds a pack downloaded from the server to the resource packs of the handler. If the server sent a
+// content key for the pack, it is added to the pack, which is then decrypted if the connection is configured
+// to do so. addPack must be called while holding r.packMu.
+func (r *defaultResourcepackHandler) addPack(id string, pack *resource.Pack, contentKey string) {
+	pack = pack.WithContentKey(contentKey)
+	if r.c.decryptPacks && pack.Encrypted() {
+		if decrypted, err := pack.Decrypt(); err != nil {
+			r.c.log.Printf("decrypt resource pack %v: %v\n", id, err)
+		} else {
+			pack = decrypted
+		}
+	}
+	r.resourcePacks = append(r.resourcePacks, pack)
+}
+
+// requestChunk requests the chunk with the index passed of a resource pack and waits for it to arrive. If the
+// chunk does not arrive within the chunk timeout of the connection, it is requested again, up to the maximum
+// amount of retries, after which an error is returned.
+func (r *defaultResourcepackHandler) requestChunk(id string, index uint32, pack *downloadingPack) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		_ = r.c.WritePacket(&packet.ResourcePackChunkRequest{UUID: id, ChunkIndex: index})
+		frag, err := r.awaitChunk(pack)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			return frag, err
+		}
+		if attempt >= r.c.chunkRetries {
+			return nil, fmt.Errorf("chunk %v not received after %v attempt(s)", index, attempt+1)
+		}
+		r.c.log.Printf("resource pack %v: chunk %v timed out, requesting it again\n", id, index)
+		r.c.packStats.rerequested()
+	}
+}
+
+// awaitChunk waits for the next chunk of the pack passed to arrive. context.DeadlineExceeded is returned if
+// it does not arrive within the chunk timeout of the connection.
+func (r *defaultResourcepackHandler) awaitChunk(pack *downloadingPack) ([]byte, error) {
+	var timeout <-chan time.Time
+	if r.c.chunkTimeout > 0 {
+		t := time.NewTimer(r.c.chunkTimeout)
+		defer t.Stop()
+		timeout = t.C
+	}
+	select {
+	case <-r.c.close:
+		return nil, net.ErrClosed
+	case frag := <-pack.newFrag:
+		return frag, nil
+	case <-timeout:
+		return nil, context.DeadlineExceeded
+	}
+}
+
+// packDownloaded marks a single pack of the pack queue as downloaded. If all packs were downloaded, the server
+// is notified that it may send the resource pack stack.
+func (r *defaultResourcepackHandler) packDownloaded() {
+	r.packQueue.packAmount--
+	if r.packQueue.packAmount == 0 {
+		r.c.expect(packet.IDResourcePackStack)
+		_ = r.c.WritePacket(&packet.ResourcePackClientResponse{Response: packet.PackResponseAllPacksDownloaded})
+	}
+}
+
+// ResourcePackHooks holds functions that are called during the phases of resource pack negotiation, so that
+// the negotiation may be timed or logged without implementing a ResourcePackHandler. Any of the functions may
+// be nil. For a Conn obtained using Dial, the hooks are called as packs are received from the server. For a
+// Conn accepted by a Listener, they are called as packs are sent to the client.
+type ResourcePackHooks struct {
+	// Info is called when the ResourcePacksInfo packet is received from the server or sent to the client.
+	Info func(conn *Conn, pk *packet.ResourcePacksInfo)
+	// DownloadStarted is called when the transfer of a resource pack over the Minecraft connection starts.
+	DownloadStarted func(conn *Conn, uuid, version string, size uint64)
+	// DownloadFinished is called when the transfer of a resource pack started earlier has finished. err is
+	// non-nil if the transfer failed.
+	DownloadFinished func(conn *Conn, uuid, version string, err error)
+	// Stack is called when the ResourcePackStack packet is received from the server or sent to the client.
+	Stack func(conn *Conn, pk *packet.ResourcePackStack)
+	// Completed is called once the resource pack negotiation is completed.
+	Completed func(conn *Conn)
+}
+
+// info calls h.Info if it is non-nil.
+func (h ResourcePackHooks) info(conn *Conn, pk *packet.ResourcePacksInfo) {
+	if h.Info != nil {
+		h.Info(conn, pk)
+	}
+}
+
+// downloadStarted calls h.DownloadStarted if it is non-nil.
+func (h ResourcePackHooks) downloadStarted(conn *Conn, uuid, version string, size uint64) {
+	if h.DownloadStarted != nil {
+		h.DownloadStarted(conn, uuid, version, size)
+	}
+}
+
+// downloadFinished calls h.DownloadFinished if it is non-nil.
+func (h ResourcePackHooks) downloadFinished(conn *Conn, uuid, version string, err error) {
+	if h.DownloadFinished != nil {
+		h.DownloadFinished(conn, uuid, version, err)
+	}
+}
+
+// stack calls h.Stack if it is non-nil.
+func (h ResourcePackHooks) stack(conn *Conn, pk *packet.ResourcePackStack) {
+	if h.Stack != nil {
+		h.Stack(conn, pk)
+	}
+}
+
+// completed calls h.Completed if it is non-nil.
+func (h ResourcePackHooks) completed(conn *Conn) {
+	if h.Completed != nil {
+		h.Completed(conn)
+	}
+}
+
+// ChecksumPolicy specifies what a Conn obtained using Dial does when a downloaded resource pack does not
+// match the SHA256 checksum that the server sent for it.
+type ChecksumPolicy int
+
+const (
+	// ChecksumPolicyWarn logs a checksum mismatch to the error log of the connection, but continues using
+	// the pack as usual. It is the default ChecksumPolicy.
+	ChecksumPolicyWarn ChecksumPolicy = iota
+	// ChecksumPolicyRejectPack discards a pack with a mismatching checksum. The connection continues to log
+	// in as if the pack was never downloaded.
+	ChecksumPolicyRejectPack
+	// ChecksumPolicyDisconnect closes the connection as soon as a pack with a mismatching checksum is
+	// downloaded.
+	ChecksumPolicyDisconnect
+	// ChecksumPolicyIgnore does not verify checksums of downloaded packs at all.
+	ChecksumPolicyIgnore
+)
+
+// verifyChecksum verifies if the SHA256 checksum sum of downloaded data matches the checksum passed. If
+// checksum is empty, as some servers do not send it, no error is returned.
+func verifyChecksum(sum, checksum []byte) error {
+	if len(checksum) == 0 {
+		return nil
+	}
+	if !bytes.Equal(sum, checksum) {
+		return fmt.Errorf("checksum mismatch: expected %x, got %x", checksum, sum)
+	}
+	return nil
+}
+
 // OnChunkRequest handles a resource pack chunk request, which requests a part of the resource
 // pack to be downloaded.
 func (r *defaultResourcepackHandler) OnResourcePackChunkRequest(pk *packet.ResourcePackChunkRequest) error {
+	if last := r.lastChunk; last != nil && last.UUID == pk.UUID && last.ChunkIndex == pk.ChunkIndex {
+		// The client requested the chunk it was sent last again, because it did not arrive in time. This may
+		// also be the last chunk of the previous pack, so we check this before checking the current pack.
+		return r.writeChunk(last)
+	}
 	current := r.packQueue.currentPack
 	if current.UUID() != pk.UUID {
 		return fmt.Errorf("resource pack chunk request had unexpected UUID: expected %v, but got %v", current.UUID(), pk.UUID)
 	}
-	if r.packQueue.currentOffset != uint64(pk.ChunkIndex)*packChunkSize {
-		return fmt.Errorf("resource pack chunk request had unexpected chunk index: expected %v, but got %v", r.packQueue.currentOffset/packChunkSize, pk.ChunkIndex)
+	if r.packQueue.currentOffset != uint64(pk.ChunkIndex)*r.chunkSize {
+		return fmt.Errorf("resource pack chunk request had unexpected chunk index: expected %v, but got %v", r.packQueue.currentOffset/r.chunkSize, pk.ChunkIndex)
 	}
 	response := &packet.ResourcePackChunkData{
 		UUID:       pk.UUID,
 		ChunkIndex: pk.ChunkIndex,
 		DataOffset: r.packQueue.currentOffset,
-		Data:       make([]byte, packChunkSize),
+		Data:       make([]byte, r.chunkSize),
 	}
-	r.packQueue.currentOffset += packChunkSize
+	r.packQueue.currentOffset += r.chunkSize
 	// We read the data directly into the response's data.
 	if n, err := current.ReadAt(response.Data, int64(response.DataOffset)); err != nil {
 		// If we hit an EOF, we don't need to return an error, as we've simply reached the end of the content
@@ -213,17 +614,31 @@ func (r *defaultResourcepackHandler) OnResourcePackChunkRequest(pk *packet.Resou
 		response.Data = response.Data[:n]
 
 		defer func() {
+			r.transferFinished(current.UUID(), current.Version(), nil)
 			if !r.packQueue.AllDownloaded() {
 				_ = r.nextResourcePackDownload()
 			} else {
-				r.c.expect(packet.IDResourcePackClientResponse)
+				// The client may still request the last chunk again if it did not arrive in time.
+				r.c.expect(packet.IDResourcePackClientResponse, packet.IDResourcePackChunkRequest)
 			}
 		}()
 	}
-	if err := r.c.WritePacket(response); err != nil {
+	r.lastChunk = response
+	return r.writeChunk(response)
+}
+
+// writeChunk writes the resource pack chunk data passed to the client, waiting for the rate limiters of the
+// handler first.
+func (r *defaultResourcepackHandler) writeChunk(pk *packet.ResourcePackChunkData) error {
+	for _, limiter := range r.limiters {
+		if !limiter.wait(len(pk.Data), r.c.close) {
+			return r.c.closeErr("write resource pack chunk data")
+		}
+	}
+	if err := r.c.WritePacket(pk); err != nil {
 		return fmt.Errorf("error writing resource pack chunk data packet: %v", err)
 	}
-
+	r.c.packStats.sent(len(pk.Data))
 	return nil
 }
 
@@ -237,17 +652,26 @@ func (r *defaultResourcepackHandler) OnResourcePackChunkData(pk *packet.Resource
 		// download a resource pack.
 		return fmt.Errorf("resource pack chunk data for resource pack that was not being downloaded")
 	}
-	lastData := pack.buf.Len()+int(pack.chunkSize) >= int(pack.size)
+	if pk.ChunkIndex < pack.expectedIndex {
+		// The chunk was requested again after timing out, but the original chunk ended up arriving after all.
+		// We already have the data, so we can safely drop it.
+		return nil
+	}
+	if pk.ChunkIndex != pack.expectedIndex {
+		return fmt.Errorf("resource pack chunk data had chunk index %v, but expected %v", pk.ChunkIndex, pack.expectedIndex)
+	}
+	lastData := uint64(pk.ChunkIndex+1)*uint64(pack.chunkSize) >= pack.size
 	if !lastData && uint32(len(pk.Data)) != pack.chunkSize {
 		// The chunk data didn't have the full size and wasn't the last data to be sent for the resource pack,
 		// meaning we got too little data.
 		return fmt.Errorf("resource pack chunk data had a length of %v, but expected %v", len(pk.Data), pack.chunkSize)
 	}
-	if pk.ChunkIndex != pack.expectedIndex {
-		return fmt.Errorf("resource pack chunk data had chunk index %v, but expected %v", pk.ChunkIndex, pack.expectedIndex)
-	}
 	pack.expectedIndex++
-	pack.newFrag <- pk.Data
+	r.c.packStats.received(len(pk.Data))
+	select {
+	case <-r.c.close:
+	case pack.newFrag <- pk.Data:
+	}
 	return nil
 }
 
@@ -261,6 +685,7 @@ func (r *defaultResourcepackHandler) nextResourcePackDownload() error {
 	if err := r.c.WritePacket(pk); err != nil {
 		return fmt.Errorf("error sending resource pack data info packet: %v", err)
 	}
+	r.transferStarted(pk.UUID, r.packQueue.currentPack.Version(), pk.Size)
 	// Set the next expected packet to ResourcePackChunkRequest packets.
 	r.c.expect(packet.IDResourcePackChunkRequest)
 	return nil
@@ -269,17 +694,27 @@ func (r *defaultResourcepackHandler) nextResourcePackDownload() error {
 // OnResourcePackStack handles a ResourcePackStack packet sent by the server. The stack defines the order
 // that resource packs are applied in.
 func (r *defaultResourcepackHandler) OnResourcePackStack(pk *packet.ResourcePackStack) error {
+	r.c.packHooks.stack(r.c, pk)
+	if r.c.skipPacks {
+		// None of the packs were downloaded, so there is no point in checking the stack.
+		return r.completeStack()
+	}
+
 	// We currently don't apply resource packs in any way, so instead we just check if all resource packs in
 	// the stacks are also downloaded.
-	for _, pack := range pk.TexturePacks {
-		for i, behaviourPack := range pk.BehaviourPacks {
-			if pack.UUID == behaviourPack.UUID {
-				// We had a behaviour pack with the same UUID as the texture pack, so we drop the texture
-				// pack and log it.
-				r.c.log.Printf("dropping behaviour pack with UUID %v due to a texture pack with the same UUID\n", pack.UUID)
-				pk.BehaviourPacks = append(pk.BehaviourPacks[:i], pk.BehaviourPacks[i+1:]...)
-			}
+	behaviourPacks := make([]protocol.StackResourcePack, 0, len(pk.BehaviourPacks))
+	for _, behaviourPack := range pk.BehaviourPacks {
+		if slices.ContainsFunc(pk.TexturePacks, func(pack protocol.StackResourcePack) bool { return pack.UUID == behaviourPack.UUID }) {
+			// We had a behaviour pack with the same UUID as a texture pack, so we drop the behaviour pack and
+			// log it.
+			r.c.log.Printf("dropping behaviour pack with UUID %v due to a texture pack with the same UUID\n", behaviourPack.UUID)
+			continue
 		}
+		behaviourPacks = append(behaviourPacks, behaviourPack)
+	}
+	pk.BehaviourPacks = behaviourPacks
+
+	for _, pack := range pk.TexturePacks {
 		if !r.hasPack(pack.UUID, pack.Version, false) {
 			return fmt.Errorf("texture pack {uuid=%v, version=%v} not downloaded", pack.UUID, pack.Version)
 		}
@@ -289,20 +724,28 @@ func (r *defaultResourcepackHandler) OnResourcePackStack(pk *packet.ResourcePack
 			return fmt.Errorf("behaviour pack {uuid=%v, version=%v} not downloaded", pack.UUID, pack.Version)
 		}
 	}
-	r.c.expect(packet.IDStartGame)
+	return r.completeStack()
+}
+
+// completeStack informs the server that the resource pack stack was applied, completing the resource pack
+// sequence. If the sequence took place after spawning, the Conn continues as before rather than waiting for
+// the StartGame packet.
+func (r *defaultResourcepackHandler) completeStack() error {
+	if !r.c.finishRenegotiation() {
+		r.c.expect(packet.IDStartGame)
+	}
 	_ = r.c.WritePacket(&packet.ResourcePackClientResponse{Response: packet.PackResponseCompleted})
+	r.c.packHooks.completed(r.c)
 	return nil
 }
 
 // hasPack checks if the connection has a resource pack downloaded with the UUID and version passed, provided
 // the pack either has or does not have behaviours in it.
 func (r *defaultResourcepackHandler) hasPack(uuid string, version string, hasBehaviours bool) bool {
-	for _, exempted := range exemptedPacks {
-		if exempted.uuid == uuid && exempted.version == version {
-			// The server may send this resource pack on the stack without sending it in the info, as the client
-			// always has it downloaded.
-			return true
-		}
+	if r.c.exemptedPacks.Contains(uuid, version) {
+		// The server may send this resource pack on the stack without sending it in the info, as the client
+		// always has it downloaded.
+		return true
 	}
 	r.packMu.Lock()
 	defer r.packMu.Unlock()
@@ -320,7 +763,7 @@ func (r *defaultResourcepackHandler) hasPack(uuid string, version string, hasBeh
 	return false
 }
 
-// packChunkSize is the size of a single chunk of data from a resource pack: 512 kB or 0.5 MB
+// packChunkSize is the default size of a single chunk of data from a resource pack: 128 kB.
 const packChunkSize = 1024 * 128
 
 // OnResourcePackClientResponse handles an incoming resource pack client response packet. The packet is
@@ -330,10 +773,16 @@ func (r *defaultResourcepackHandler) OnResourcePackClientResponse(pk *packet.Res
 	case packet.PackResponseRefused:
 		// Even though this response is never sent, we handle it appropriately in case it is changed to work
 		// correctly again.
+		if r.c.texturePacksRequired {
+			return r.disconnectMissing(r.ResourcePacks())
+		}
 		return r.c.Close()
 	case packet.PackResponseSendPacks:
 		packs := pk.PacksToDownload
-		r.packQueue = &resourcePackQueue{packs: r.resourcePacks}
+		if r.chunkSize == 0 {
+			r.chunkSize = packChunkSize
+		}
+		r.packQueue = &resourcePackQueue{packs: r.resourcePacks, chunkSize: r.chunkSize}
 		if err := r.packQueue.Request(packs); err != nil {
 			return fmt.Errorf("error looking up resource packs to download: %v", err)
 		}
@@ -343,7 +792,14 @@ func (r *defaultResourcepackHandler) OnResourcePackClientResponse(pk *packet.Res
 			return err
 		}
 	case packet.PackResponseAllPacksDownloaded:
-		pk := &packet.ResourcePackStack{BaseGameVersion: protocol.CurrentVersion, Experiments: []protocol.ExperimentData{{Name: "cameras", Enabled: true}}}
+		if missing := r.missingPacks(); r.c.texturePacksRequired && len(missing) != 0 {
+			return r.disconnectMissing(missing)
+		}
+		pk := &packet.ResourcePackStack{
+			BaseGameVersion:    r.stack.BaseGameVersion,
+			Experiments:        slices.Clone(r.stack.Experiments),
+			IncludeEditorPacks: r.stack.IncludeEditorPacks,
+		}
 		for _, pack := range r.resourcePacks {
 			resourcePack := protocol.StackResourcePack{UUID: pack.UUID(), Version: pack.Version()}
 			// If it has behaviours, add it to the behaviour pack list. If not, we add it to the texture packs
@@ -354,24 +810,69 @@ func (r *defaultResourcepackHandler) OnResourcePackClientResponse(pk *packet.Res
 			}
 			pk.TexturePacks = append(pk.TexturePacks, resourcePack)
 		}
-		for _, exempted := range exemptedPacks {
-			pk.TexturePacks = append(pk.TexturePacks, protocol.StackResourcePack{
-				UUID:    exempted.uuid,
-				Version: exempted.version,
-			})
+		pk.TexturePacks = append(pk.TexturePacks, r.c.exemptedPacks.Packs()...)
+		if r.stackFunc != nil {
+			r.stackFunc(r.c, pk)
 		}
 		if err := r.c.WritePacket(pk); err != nil {
 			return fmt.Errorf("error writing resource pack stack packet: %v", err)
 		}
+		r.c.packHooks.stack(r.c, pk)
 	case packet.PackResponseCompleted:
-		r.c.loggedIn = true
+		if !r.c.finishRenegotiation() {
+			r.c.loggedIn = true
+			r.c.enterPhase("", 0)
+		}
+		r.c.packHooks.completed(r.c)
 	default:
 		return fmt.Errorf("unknown resource pack client response: %v", pk.Response)
 	}
 	return nil
 }
 
+// missingPacks returns the resource packs that the client requested, but that were not fully sent to it
+// before it reported that all packs were downloaded.
+func (r *defaultResourcepackHandler) missingPacks() []*resource.Pack {
+	if r.packQueue == nil {
+		// The client did not request any packs, as it already had all of them.
+		return nil
+	}
+	missing := make([]*resource.Pack, 0, len(r.packQueue.packsToDownload)+1)
+	if current := r.packQueue.currentPack; current != nil && r.packQueue.currentOffset < uint64(current.Len()) {
+		missing = append(missing, current)
+	}
+	for _, pack := range r.packQueue.packsToDownload {
+		missing = append(missing, pack)
+	}
+	return missing
+}
+
+// disconnectMissing disconnects the client because it did not download the required resource packs passed.
+// The message shown to the client is produced by the missing packs message function, if set.
+func (r *defaultResourcepackHandler) disconnectMissing(missing []*resource.Pack) error {
+	var message string
+	if r.missingPacksMessage != nil {
+		message = r.missingPacksMessage(r.c, missing)
+	} else {
+		names := make([]string, 0, len(missing))
+		for _, pack := range missing {
+			names = append(names, pack.Name())
+		}
+		message = text.Colourf("<red>You must download the resource packs of this server to join.</red>\nMissing: %v", strings.Join(names, ", "))
+	}
+	_ = r.c.Disconnect(message, false)
+	return fmt.Errorf("client did not download %v required resource pack(s)", len(missing))
+}
+
 func (r *defaultResourcepackHandler) GetResourcePacksInfo(texturePacksRequired bool) *packet.ResourcePacksInfo {
+	if r.packsFor != nil {
+		// The resource packs are selected specifically for this connection right before they are first sent,
+		// as that is when the identity and client data of the connection are known.
+		packs := r.packsFor(r.c)
+		r.packMu.Lock()
+		r.resourcePacks = packs
+		r.packMu.Unlock()
+	}
 	pk := &packet.ResourcePacksInfo{TexturePackRequired: texturePacksRequired}
 	for _, pack := range r.ResourcePacks() {
 		if pack.DownloadURL() != "" {
@@ -390,6 +891,14 @@ func (r *defaultResourcepackHandler) GetResourcePacksInfo(texturePacksRequired b
 				pk.HasScripts = true
 				behaviourPack.HasScripts = true
 			}
+			if pack.HasTextures() {
+				// The pack holds both behaviours and textures, meaning it is an add-on.
+				pk.HasAddons = true
+			}
+			if pack.Encrypted() {
+				behaviourPack.ContentKey = pack.ContentKey()
+				behaviourPack.ContentIdentity = pack.Manifest().Header.UUID
+			}
 			pk.BehaviourPacks = append(pk.BehaviourPacks, behaviourPack)
 			continue
 		}
@@ -400,5 +909,6 @@ func (r *defaultResourcepackHandler) GetResourcePacksInfo(texturePacksRequired b
 		}
 		pk.TexturePacks = append(pk.TexturePacks, texturePack)
 	}
+	r.c.packHooks.info(r.c, pk)
 	return pk
 }
@@ -0,0 +1,833 @@
+package minecraft
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Resumable is a Network that adds session resumption to the Network it wraps, so that connections survive
+// a change of the address of the client, such as when a mobile device switches between Wi-Fi and a cellular
+// network. A Resumable listener hands out a session token to every Resumable dialer that connects. If the
+// underlying connection of the dialer is lost, it dials the listener again and presents the token, after
+// which the listener re-associates the new connection with the existing Conn instead of timing it out. Both
+// sides then resend the batches that the other side did not receive.
+// Session resumption is only used if both the dialer and the listener use a Resumable network. Clients that
+// do not, such as the vanilla client, are still accepted by a Resumable listener, but cannot be resumed. A
+// Resumable may be registered under a network ID of its own using RegisterNetwork:
+//
+//	minecraft.RegisterNetwork("raknet-resumable", minecraft.Resumable{Network: minecraft.RakNet{}})
+type Resumable struct {
+	// Network is the Network that the connections are made over. If nil, RakNet is used.
+	Network Network
+	// Timeout is the maximum duration that a connection waits to be resumed after its underlying connection
+	// is lost. If zero, a timeout of 30 seconds is used.
+	Timeout time.Duration
+	// MaxUnacknowledged is the maximum amount of bytes of batches sent that are kept until the other side
+	// acknowledges them, so that they can be resent after resuming. A connection that exceeds it can no
+	// longer be resumed. If zero, a maximum of 8 MiB is used.
+	MaxUnacknowledged int
+}
+
+const (
+	// resumeMagic is the first byte of the handshake frames of a Resumable connection. It differs from the
+	// header of a batch, so that a Resumable listener can tell apart dialers that do not use session
+	// resumption.
+	resumeMagic byte = 0xfd
+
+	// resumeAckInterval is the amount of batches received without sending a batch after which an ack frame
+	// is sent, so that the other side can release the batches it kept.
+	resumeAckInterval = 16
+	// resumeHandshakeTimeout is the maximum duration of the handshake of a Resumable connection.
+	resumeHandshakeTimeout = time.Second * 5
+	// resumeRetryInterval is the interval at which a Resumable dialer attempts to dial the listener again
+	// while resuming.
+	resumeRetryInterval = time.Second
+	// resumeReadBufferSize is the size of the buffer used to read frames from a net.Conn that cannot read
+	// full packets at once.
+	resumeReadBufferSize = 1024 * 1024 * 3
+)
+
+const (
+	// resumeFrameData, resumeFrameAck and resumeFrameClose are the first bytes of the frames sent over a
+	// Resumable connection after the handshake. Data frames carry a batch, ack frames acknowledge the
+	// batches received and close frames indicate that the connection was closed on purpose. Like the
+	// header of a batch, they are outside the range of the IDs of RakNet messages, which RakNet handles
+	// itself.
+	resumeFrameData byte = 0xf0 + iota
+	resumeFrameAck
+	resumeFrameClose
+)
+
+// errSessionRejected is returned when a Resumable listener does not know the session that a dialer tried to
+// resume, for example because it already expired.
+var errSessionRejected = errors.New("session rejected by listener")
+
+// resumeToken is a random token identifying the session of a Resumable connection.
+type resumeToken [16]byte
+
+// DialContext ...
+func (r Resumable) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	n := r.network()
+	dial := func(ctx context.Context) (net.Conn, error) {
+		return n.DialContext(ctx, address)
+	}
+	c, err := dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, _, err := resumeHandshake(ctx, c, resumeToken{}, 0)
+	if err != nil {
+		_ = c.Close()
+		return nil, fmt.Errorf("resumable handshake: %w", err)
+	}
+	conn := r.newConn(token)
+	conn.redial = dial
+	if err := conn.attach(c, newFrameReader(c), 0, false); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// PingContext ...
+func (r Resumable) PingContext(ctx context.Context, address string) (response []byte, err error) {
+	return r.network().PingContext(ctx, address)
+}
+
+// Listen ...
+func (r Resumable) Listen(address string) (NetworkListener, error) {
+	l, err := r.network().Listen(address)
+	if err != nil {
+		return nil, err
+	}
+	listener := &resumableListener{
+		NetworkListener: l,
+		r:               r,
+		conns:           make(chan net.Conn),
+		closed:          make(chan struct{}),
+		sessions:        make(map[resumeToken]*resumableConn),
+	}
+	go listener.accept()
+	return listener, nil
+}
+
+// network returns the Network wrapped by the Resumable.
+func (r Resumable) network() Network {
+	if r.Network == nil {
+		return RakNet{}
+	}
+	return r.Network
+}
+
+// newConn returns a resumableConn without an underlying connection for the session token passed.
+func (r Resumable) newConn(token resumeToken) *resumableConn {
+	c := &resumableConn{token: token, timeout: r.Timeout, max: r.MaxUnacknowledged, notify: make(chan struct{}, 1)}
+	if c.timeout <= 0 {
+		c.timeout = time.Second * 30
+	}
+	if c.max <= 0 {
+		c.max = 8 * 1024 * 1024
+	}
+	return c
+}
+
+// resumableListener is a NetworkListener that accepts connections with session resumption.
+type resumableListener struct {
+	NetworkListener
+	r Resumable
+
+	conns  chan net.Conn
+	once   sync.Once
+	closed chan struct{}
+
+	mu       sync.Mutex
+	sessions map[resumeToken]*resumableConn
+}
+
+// accept accepts new connections until the resumableListener is closed. Every connection is handshaken
+// separately, so that a slow handshake does not hold up others.
+func (l *resumableListener) accept() {
+	defer l.Close()
+	for {
+		c, err := l.NetworkListener.Accept()
+		if err != nil {
+			return
+		}
+		go l.handshake(c)
+	}
+}
+
+// handshake reads the first frame of a new connection. If it is not the handshake of a Resumable dialer, the
+// connection is passed on as is. Otherwise, a new session is started or an existing one is resumed.
+func (l *resumableListener) handshake(c net.Conn) {
+	r := newFrameReader(c)
+	// Not every net.Conn supports read deadlines, such as RakNet connections, so we close the connection if
+	// the first frame does not arrive in time.
+	t := time.AfterFunc(resumeHandshakeTimeout, func() { _ = c.Close() })
+	frame, err := r.read()
+	if !t.Stop() || err != nil {
+		_ = c.Close()
+		return
+	}
+	if len(frame) == 0 || frame[0] != resumeMagic {
+		l.deliver(&prefixedConn{Conn: c, r: r, first: frame})
+		return
+	}
+	token, received, err := parseResumeHandshake(frame)
+	if err != nil {
+		_ = c.Close()
+		return
+	}
+	if token == (resumeToken{}) {
+		_, _ = rand.Read(token[:])
+		conn := l.r.newConn(token)
+		conn.onClose = func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			delete(l.sessions, token)
+		}
+		l.mu.Lock()
+		l.sessions[token] = conn
+		l.mu.Unlock()
+		if err := conn.attach(c, r, 0, true); err != nil {
+			_ = c.Close()
+			return
+		}
+		l.deliver(conn)
+		return
+	}
+	l.mu.Lock()
+	conn, ok := l.sessions[token]
+	l.mu.Unlock()
+	if !ok || conn.attach(c, r, received, true) != nil {
+		_, _ = c.Write([]byte{resumeMagic})
+		_ = c.Close()
+	}
+}
+
+// deliver passes the net.Conn to Accept, or closes it if the resumableListener is closed first.
+func (l *resumableListener) deliver(c net.Conn) {
+	select {
+	case l.conns <- c:
+	case <-l.closed:
+		_ = c.Close()
+	}
+}
+
+// Accept ...
+func (l *resumableListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.closed:
+		return nil, &net.OpError{Op: "accept", Net: "resumable", Addr: l.Addr(), Err: net.ErrClosed}
+	}
+}
+
+// Close ...
+func (l *resumableListener) Close() error {
+	var err error
+	l.once.Do(func() {
+		close(l.closed)
+		err = l.NetworkListener.Close()
+	})
+	return err
+}
+
+// PongDataFunc sets a function that generates the pong data for every ping received, if the
+// NetworkListener wrapped supports it.
+func (l *resumableListener) PongDataFunc(f func() []byte) {
+	if pl, ok := l.NetworkListener.(interface{ PongDataFunc(f func() []byte) }); ok {
+		pl.PongDataFunc(f)
+	}
+}
+
+// resumableConn is a net.Conn with session resumption. Every batch written is sent in a data frame with a
+// sequence number and kept until the other side acknowledges it, so that it can be resent over a new
+// underlying connection after resuming.
+type resumableConn struct {
+	token   resumeToken
+	timeout time.Duration
+	max     int
+	// redial dials a new underlying connection. It is nil for connections accepted by a listener, which wait
+	// for the dialer to resume them instead.
+	redial func(ctx context.Context) (net.Conn, error)
+	// onClose is called when the resumableConn is closed or can no longer be resumed.
+	onClose func()
+
+	// writeMu is held while writing to the underlying connection, so that frames are written in the order
+	// of their sequence numbers.
+	writeMu sync.Mutex
+
+	mu sync.Mutex
+	// conn is the current underlying connection. It is nil while the resumableConn waits to be resumed.
+	conn          net.Conn
+	local, remote net.Addr
+	measures      bool
+	// gen is incremented every time a new underlying connection is attached.
+	gen uint64
+
+	// sent is the sequence number of the last batch sent, acked that of the last batch acknowledged by the
+	// other side and received that of the last batch received.
+	sent, acked, received uint64
+	unacked               [][]byte
+	unackedSize           int
+	exceeded              bool
+	pendingAcks           int
+
+	queue         [][]byte
+	readDeadline  time.Time
+	writeDeadline time.Time
+	notify        chan struct{}
+	err           error
+}
+
+// Read ...
+func (c *resumableConn) Read(b []byte) (int, error) {
+	pk, err := c.ReadPacket()
+	if err != nil {
+		return 0, err
+	}
+	if len(pk) > len(b) {
+		return 0, errBufferTooSmall
+	}
+	return copy(b, pk), nil
+}
+
+// ReadPacket reads the next batch received. Batches received before the resumableConn was closed by the
+// other side are returned before the error.
+func (c *resumableConn) ReadPacket() ([]byte, error) {
+	for {
+		c.mu.Lock()
+		if len(c.queue) > 0 {
+			b := c.queue[0]
+			c.queue[0] = nil
+			c.queue = c.queue[1:]
+			c.mu.Unlock()
+			return b, nil
+		}
+		err, deadline := c.err, c.readDeadline
+		c.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+
+		if deadline.IsZero() {
+			<-c.notify
+			continue
+		}
+		d := time.Until(deadline)
+		if d <= 0 {
+			return nil, os.ErrDeadlineExceeded
+		}
+		t := time.NewTimer(d)
+		select {
+		case <-c.notify:
+		case <-t.C:
+		}
+		t.Stop()
+	}
+}
+
+// Write sends b in a data frame. If the underlying connection is lost, the frame is sent after the
+// resumableConn is resumed.
+func (c *resumableConn) Write(b []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.mu.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		return 0, err
+	}
+	c.sent++
+	frame := make([]byte, 1, 1+binary.MaxVarintLen64*2+len(b))
+	frame[0] = resumeFrameData
+	frame = binary.AppendUvarint(frame, c.sent)
+	frame = binary.AppendUvarint(frame, c.received)
+	frame = append(frame, b...)
+	c.pendingAcks = 0
+	if !c.exceeded {
+		c.unacked = append(c.unacked, frame)
+		c.unackedSize += len(frame)
+		if c.unackedSize > c.max {
+			c.exceeded, c.unacked, c.unackedSize = true, nil, 0
+		}
+	}
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn != nil {
+		if _, err := conn.Write(frame); err != nil {
+			c.lose(conn, err)
+		}
+	}
+	return len(b), nil
+}
+
+// Close closes the resumableConn and lets the other side know that it should not wait for it to be resumed.
+func (c *resumableConn) Close() error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil && c.err == nil {
+		_, _ = c.conn.Write([]byte{resumeFrameClose})
+	}
+	c.fail(net.ErrClosed)
+	return nil
+}
+
+// LocalAddr returns the local address of the current underlying connection, or of the last one if the
+// resumableConn is waiting to be resumed.
+func (c *resumableConn) LocalAddr() net.Addr {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.local
+}
+
+// RemoteAddr returns the remote address of the current underlying connection, or of the last one if the
+// resumableConn is waiting to be resumed.
+func (c *resumableConn) RemoteAddr() net.Addr {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.remote
+}
+
+// SetDeadline ...
+func (c *resumableConn) SetDeadline(t time.Time) error {
+	_ = c.SetReadDeadline(t)
+	return c.SetWriteDeadline(t)
+}
+
+// SetReadDeadline ...
+func (c *resumableConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline = t
+	c.signal()
+	return nil
+}
+
+// SetWriteDeadline sets the write deadline of the underlying connection. It is also applied to underlying
+// connections attached after resuming.
+func (c *resumableConn) SetWriteDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writeDeadline = t
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.SetWriteDeadline(t)
+}
+
+// Latency returns the latency of the current underlying connection, or 0 if it has no Latency method or if
+// the resumableConn is waiting to be resumed.
+func (c *resumableConn) Latency() time.Duration {
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+	if l, ok := conn.(interface{ Latency() time.Duration }); ok {
+		return l.Latency()
+	}
+	return 0
+}
+
+// attach makes the net.Conn passed the underlying connection of the resumableConn, closing the previous one
+// if it is still open. If the resumableConn can no longer be resumed, an error is returned and the net.Conn
+// is left open. received is the sequence number of the last batch received by the other side: All
+// batches sent after it are resent over the new connection. If welcome is true, the handshake of the dialer
+// is answered first.
+func (c *resumableConn) attach(conn net.Conn, r *frameReader, received uint64, welcome bool) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.mu.Lock()
+	if c.exceeded {
+		c.fail(errors.New("resume session: too many unacknowledged batches"))
+	}
+	if c.err != nil {
+		c.mu.Unlock()
+		return net.ErrClosed
+	}
+	if c.conn != nil {
+		_ = c.conn.Close()
+	}
+	if c.gen == 0 {
+		c.measures = measuresLatency(conn)
+	}
+	c.conn, c.local, c.remote = conn, conn.LocalAddr(), conn.RemoteAddr()
+	c.gen++
+	c.acknowledge(received)
+	resend, own := slices.Clone(c.unacked), c.received
+	c.pendingAcks = 0
+	if !c.writeDeadline.IsZero() {
+		_ = conn.SetWriteDeadline(c.writeDeadline)
+	}
+	c.mu.Unlock()
+
+	go c.read(conn, r)
+	if welcome {
+		if _, err := conn.Write(appendResumeHandshake(nil, c.token, own)); err != nil {
+			c.lose(conn, err)
+			return nil
+		}
+	}
+	for _, frame := range resend {
+		if _, err := conn.Write(frame); err != nil {
+			c.lose(conn, err)
+			return nil
+		}
+	}
+	return nil
+}
+
+// read reads frames from the underlying connection passed until it is lost or no longer the underlying
+// connection of the resumableConn.
+func (c *resumableConn) read(conn net.Conn, r *frameReader) {
+	for {
+		frame, err := r.read()
+		if err != nil {
+			c.lose(conn, err)
+			return
+		}
+		if ok, err := c.handle(conn, frame); err != nil {
+			c.mu.Lock()
+			if c.conn == conn {
+				c.fail(fmt.Errorf("read resumable frame: %w", err))
+			}
+			c.mu.Unlock()
+			return
+		} else if !ok {
+			return
+		}
+	}
+}
+
+// handle handles a frame read from the underlying connection passed. False is returned if the connection
+// should no longer be read from.
+func (c *resumableConn) handle(conn net.Conn, frame []byte) (bool, error) {
+	if len(frame) == 0 {
+		return false, fmt.Errorf("empty frame")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != conn {
+		// The connection was replaced after resuming, so anything still received over it is stale.
+		return false, nil
+	}
+	switch frame[0] {
+	case resumeFrameData:
+		seq, n := binary.Uvarint(frame[1:])
+		if n <= 0 {
+			return false, fmt.Errorf("invalid sequence number")
+		}
+		ack, m := binary.Uvarint(frame[1+n:])
+		if m <= 0 {
+			return false, fmt.Errorf("invalid acknowledgement")
+		}
+		c.acknowledge(ack)
+		switch {
+		case seq <= c.received:
+			// The batch was already received before the connection was resumed.
+		case seq == c.received+1:
+			c.received = seq
+			c.queue = append(c.queue, frame[1+n+m:])
+			c.pendingAcks++
+			c.signal()
+		default:
+			return false, fmt.Errorf("unexpected sequence number %v, expected %v", seq, c.received+1)
+		}
+		if c.pendingAcks >= resumeAckInterval {
+			c.pendingAcks = 0
+			ackFrame := binary.AppendUvarint([]byte{resumeFrameAck}, c.received)
+			go c.writeFrame(conn, ackFrame)
+		}
+	case resumeFrameAck:
+		ack, n := binary.Uvarint(frame[1:])
+		if n <= 0 {
+			return false, fmt.Errorf("invalid acknowledgement")
+		}
+		c.acknowledge(ack)
+	case resumeFrameClose:
+		// Like RakNet connections closed by the other side, reads return net.ErrClosed.
+		c.fail(net.ErrClosed)
+		return false, nil
+	default:
+		return false, fmt.Errorf("unknown frame %#x", frame[0])
+	}
+	return true, nil
+}
+
+// writeFrame writes a frame to the underlying connection passed if it is still the underlying connection of
+// the resumableConn.
+func (c *resumableConn) writeFrame(conn net.Conn, frame []byte) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.mu.Lock()
+	current := c.conn == conn
+	c.mu.Unlock()
+	if !current {
+		return
+	}
+	if _, err := conn.Write(frame); err != nil {
+		c.lose(conn, err)
+	}
+}
+
+// acknowledge releases all batches sent up to the sequence number passed. c.mu must be held.
+func (c *resumableConn) acknowledge(ack uint64) {
+	ack = min(ack, c.sent)
+	if ack <= c.acked {
+		return
+	}
+	if !c.exceeded {
+		n := int(ack - c.acked)
+		for _, frame := range c.unacked[:n] {
+			c.unackedSize -= len(frame)
+		}
+		clear(c.unacked[:n])
+		c.unacked = c.unacked[n:]
+	}
+	c.acked = ack
+}
+
+// lose handles the loss of the underlying connection passed. If it is still the underlying connection of
+// the resumableConn, the resumableConn is resumed, or fails if it cannot be.
+func (c *resumableConn) lose(conn net.Conn, cause error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != conn || c.err != nil {
+		return
+	}
+	_ = conn.Close()
+	c.conn = nil
+	if c.exceeded {
+		c.fail(fmt.Errorf("resume session: too many unacknowledged batches: %w", cause))
+		return
+	}
+	if c.redial != nil {
+		go c.resume(cause)
+		return
+	}
+	gen := c.gen
+	time.AfterFunc(c.timeout, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.gen == gen && c.conn == nil {
+			c.fail(fmt.Errorf("resume session: not resumed within %v: %w", c.timeout, cause))
+		}
+	})
+}
+
+// resume dials the listener again until the session of the resumableConn is resumed, the listener rejects
+// it or the timeout of the resumableConn expires.
+func (c *resumableConn) resume(cause error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	for {
+		err := c.redialOnce(ctx)
+		if err == nil {
+			return
+		}
+		if errors.Is(err, errSessionRejected) || errors.Is(err, net.ErrClosed) {
+			cause = err
+			break
+		}
+		t := time.NewTimer(resumeRetryInterval)
+		select {
+		case <-ctx.Done():
+		case <-t.C:
+		}
+		t.Stop()
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fail(fmt.Errorf("resume session: %w", cause))
+}
+
+// redialOnce dials a new underlying connection and attempts to resume the session over it.
+func (c *resumableConn) redialOnce(ctx context.Context) error {
+	c.mu.Lock()
+	closed, received := c.err != nil, c.received
+	c.mu.Unlock()
+	if closed {
+		return net.ErrClosed
+	}
+	conn, err := c.redial(ctx)
+	if err != nil {
+		return err
+	}
+	token, peerReceived, err := resumeHandshake(ctx, conn, c.token, received)
+	if err == nil && token != c.token {
+		err = fmt.Errorf("listener resumed session %x, expected %x", token, c.token)
+	}
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	if err := c.attach(conn, newFrameReader(conn), peerReceived, false); err != nil {
+		_ = conn.Close()
+		return err
+	}
+	return nil
+}
+
+// signal wakes up a call to ReadPacket waiting for a batch. c.mu must be held.
+func (c *resumableConn) signal() {
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+// fail closes the resumableConn with the error passed, which is returned by reads and writes after all
+// batches received were read. c.mu must be held.
+func (c *resumableConn) fail(err error) {
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	if c.conn != nil {
+		_ = c.conn.Close()
+		c.conn = nil
+	}
+	c.unacked, c.unackedSize = nil, 0
+	c.signal()
+	if c.onClose != nil {
+		c.onClose()
+	}
+}
+
+// resumeHandshake writes the handshake of a Resumable dialer to the net.Conn passed and reads the answer of
+// the listener. The session token and the sequence number of the last batch received by the listener are
+// returned.
+func resumeHandshake(ctx context.Context, c net.Conn, token resumeToken, received uint64) (resumeToken, uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, resumeHandshakeTimeout)
+	defer cancel()
+	// Not every net.Conn supports read deadlines, such as RakNet connections, so we close the connection if
+	// the context expires before the answer arrives.
+	stop := context.AfterFunc(ctx, func() { _ = c.Close() })
+
+	if _, err := c.Write(appendResumeHandshake(nil, token, received)); err != nil {
+		stop()
+		return token, 0, err
+	}
+	frame, err := newFrameReader(c).read()
+	if !stop() {
+		return token, 0, ctx.Err()
+	}
+	if err != nil {
+		return token, 0, err
+	}
+	return parseResumeHandshake(frame)
+}
+
+// appendResumeHandshake appends a handshake frame holding the token and sequence number passed to b.
+func appendResumeHandshake(b []byte, token resumeToken, received uint64) []byte {
+	b = append(b, resumeMagic)
+	b = append(b, token[:]...)
+	return binary.AppendUvarint(b, received)
+}
+
+// parseResumeHandshake parses a handshake frame and returns the token and sequence number it holds. A
+// handshake frame without a token indicates that the listener rejected the session.
+func parseResumeHandshake(b []byte) (resumeToken, uint64, error) {
+	var token resumeToken
+	if len(b) == 1 && b[0] == resumeMagic {
+		return token, 0, errSessionRejected
+	}
+	if len(b) < 1+len(token) || b[0] != resumeMagic {
+		return token, 0, fmt.Errorf("invalid resumable handshake")
+	}
+	copy(token[:], b[1:])
+	received, n := binary.Uvarint(b[1+len(token):])
+	if n <= 0 {
+		return token, 0, fmt.Errorf("invalid resumable handshake: invalid sequence number")
+	}
+	return token, received, nil
+}
+
+// frameReader reads full frames from a net.Conn. It uses ReadPacket if the net.Conn supports it, and reads
+// into a buffer otherwise.
+type frameReader struct {
+	c   net.Conn
+	pr  interface{ ReadPacket() ([]byte, error) }
+	buf []byte
+}
+
+// newFrameReader returns a frameReader reading from the net.Conn passed.
+func newFrameReader(c net.Conn) *frameReader {
+	r := &frameReader{c: c}
+	r.pr, _ = c.(interface{ ReadPacket() ([]byte, error) })
+	return r
+}
+
+// read reads the next frame. The frame returned is not reused by later calls.
+func (r *frameReader) read() ([]byte, error) {
+	if r.pr != nil {
+		return r.pr.ReadPacket()
+	}
+	if r.buf == nil {
+		r.buf = make([]byte, resumeReadBufferSize)
+	}
+	n, err := r.c.Read(r.buf)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(r.buf[:n]), nil
+}
+
+// prefixedConn is a net.Conn accepted by a resumableListener from a dialer that does not use session
+// resumption. The first frame, which was read to find out, is returned by the first read.
+type prefixedConn struct {
+	net.Conn
+	r *frameReader
+
+	mu    sync.Mutex
+	first []byte
+}
+
+// Read ...
+func (c *prefixedConn) Read(b []byte) (int, error) {
+	pk, err := c.ReadPacket()
+	if err != nil {
+		return 0, err
+	}
+	if len(pk) > len(b) {
+		return 0, errBufferTooSmall
+	}
+	return copy(b, pk), nil
+}
+
+// ReadPacket ...
+func (c *prefixedConn) ReadPacket() ([]byte, error) {
+	c.mu.Lock()
+	first := c.first
+	c.first = nil
+	c.mu.Unlock()
+	if first != nil {
+		return first, nil
+	}
+	return c.r.read()
+}
+
+// Latency returns the latency of the net.Conn wrapped, or 0 if it has no Latency method.
+func (c *prefixedConn) Latency() time.Duration {
+	if l, ok := c.Conn.(interface{ Latency() time.Duration }); ok {
+		return l.Latency()
+	}
+	return 0
+}
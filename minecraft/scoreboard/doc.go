@@ -0,0 +1,5 @@
+// Package scoreboard implements helpers for showing scoreboards to players using the SetDisplayObjective,
+// SetScore and RemoveObjective packets. An Objective describes a scoreboard, an Identity describes who or
+// what an entry of the scoreboard represents, and a Tracker computes the SetScore packets needed to move
+// the scoreboard shown to a player to a desired state.
+package scoreboard
@@ -0,0 +1,90 @@
+package scoreboard
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Objective is a scoreboard objective, which is shown in one of the display slots of a player.
+type Objective struct {
+	// Name is the name of the objective, which is not shown but must be unique for the player.
+	Name string
+	// DisplayName is the name shown at the top of the scoreboard.
+	DisplayName string
+	// Criteria is the name of the criteria of the objective. It is not shown and may be left empty, in which
+	// case 'dummy' is used.
+	Criteria string
+	// Slot is the display slot in which the objective is shown, one of the packet.ScoreboardSlot constants. If
+	// empty, packet.ScoreboardSlotSidebar is used.
+	Slot string
+	// SortOrder is the order in which entries are sorted by their score, one of the
+	// packet.ScoreboardSortOrder constants.
+	SortOrder int32
+}
+
+// Display returns a SetDisplayObjective packet showing the Objective.
+func (o Objective) Display() *packet.SetDisplayObjective {
+	criteria, slot := o.Criteria, o.Slot
+	if criteria == "" {
+		criteria = "dummy"
+	}
+	if slot == "" {
+		slot = packet.ScoreboardSlotSidebar
+	}
+	return &packet.SetDisplayObjective{
+		DisplaySlot:   slot,
+		ObjectiveName: o.Name,
+		DisplayName:   o.DisplayName,
+		CriteriaName:  criteria,
+		SortOrder:     o.SortOrder,
+	}
+}
+
+// Remove returns a RemoveObjective packet removing the Objective, and with it all its entries.
+func (o Objective) Remove() *packet.RemoveObjective {
+	return &packet.RemoveObjective{ObjectiveName: o.Name}
+}
+
+// Identity is what an entry of a scoreboard represents: A player, an entity or a fake player, which is a line
+// of plain text. Identity is comparable, so that it may be used as map key.
+type Identity struct {
+	// Type is the identity type, one of the protocol.ScoreboardIdentity constants.
+	Type byte
+	// EntityUniqueID is the unique ID of the player or entity, if Type is protocol.ScoreboardIdentityPlayer
+	// or protocol.ScoreboardIdentityEntity.
+	EntityUniqueID int64
+	// Name is the text shown, if Type is protocol.ScoreboardIdentityFakePlayer.
+	Name string
+}
+
+// FakePlayer returns an Identity of a fake player, which shows the name passed as plain text.
+func FakePlayer(name string) Identity {
+	return Identity{Type: protocol.ScoreboardIdentityFakePlayer, Name: name}
+}
+
+// Player returns an Identity of the player with the entity unique ID passed. The name of the player is shown.
+func Player(entityUniqueID int64) Identity {
+	return Identity{Type: protocol.ScoreboardIdentityPlayer, EntityUniqueID: entityUniqueID}
+}
+
+// Entity returns an Identity of the entity with the entity unique ID passed. The name of the entity is shown.
+func Entity(entityUniqueID int64) Identity {
+	return Identity{Type: protocol.ScoreboardIdentityEntity, EntityUniqueID: entityUniqueID}
+}
+
+// Entry returns a protocol.ScoreboardEntry with the entry ID, objective name and score passed, representing
+// the Identity.
+func (id Identity) Entry(entryID int64, objective string, score int32) protocol.ScoreboardEntry {
+	e := protocol.ScoreboardEntry{
+		EntryID:       entryID,
+		ObjectiveName: objective,
+		Score:         score,
+		IdentityType:  id.Type,
+	}
+	if id.Type == protocol.ScoreboardIdentityFakePlayer {
+		e.DisplayName = id.Name
+	} else {
+		e.EntityUniqueID = id.EntityUniqueID
+	}
+	return e
+}
@@ -0,0 +1,127 @@
+package scoreboard
+
+import (
+	"slices"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Tracker tracks the entries of an Objective shown to a player. Entries are changed using Set, Remove and
+// SetLines, after which Packets returns the SetScore packets that move the scoreboard of the player from
+// the state last sent to the desired state. Only entries that changed are sent. A Tracker is not safe for
+// concurrent use.
+type Tracker struct {
+	objective Objective
+	nextID    int64
+	desired   map[Identity]int32
+	sent      map[Identity]sentEntry
+}
+
+// sentEntry is an entry of a Tracker as last sent to the player.
+type sentEntry struct {
+	id    int64
+	score int32
+}
+
+// NewTracker returns a Tracker for the Objective passed. The Tracker starts out empty and assumes the player
+// has no entries for the Objective.
+func NewTracker(objective Objective) *Tracker {
+	return &Tracker{objective: objective, desired: make(map[Identity]int32), sent: make(map[Identity]sentEntry)}
+}
+
+// Objective returns the Objective of the Tracker.
+func (t *Tracker) Objective() Objective {
+	return t.objective
+}
+
+// Set sets the score of the entry representing the Identity passed, adding the entry if not yet present.
+func (t *Tracker) Set(id Identity, score int32) {
+	t.desired[id] = score
+}
+
+// Remove removes the entry representing the Identity passed.
+func (t *Tracker) Remove(id Identity) {
+	delete(t.desired, id)
+}
+
+// Score returns the desired score of the entry representing the Identity passed. False is returned if no
+// such entry is present.
+func (t *Tracker) Score(id Identity) (int32, bool) {
+	score, ok := t.desired[id]
+	return score, ok
+}
+
+// SetLines replaces all entries with fake player entries showing the lines passed, in the order passed.
+// Scores are assigned so that the lines are shown in order for the SortOrder of the Objective. Because a
+// fake player entry is identified by its text, duplicate lines are shown once: Lines may be made unique by
+// appending colour codes such as '§r'.
+func (t *Tracker) SetLines(lines ...string) {
+	clear(t.desired)
+	for i, line := range lines {
+		score := int32(i)
+		if t.objective.SortOrder == packet.ScoreboardSortOrderDescending {
+			score = int32(len(lines) - 1 - i)
+		}
+		t.desired[FakePlayer(line)] = score
+	}
+}
+
+// Clear removes all entries.
+func (t *Tracker) Clear() {
+	clear(t.desired)
+}
+
+// Packets returns the SetScore packets needed to move the scoreboard of the player from the state last
+// returned by Packets to the current state of the Tracker. Removals are sent first, followed by the entries
+// added or changed. Nil is returned if nothing changed.
+func (t *Tracker) Packets() []packet.Packet {
+	var removed, modified []protocol.ScoreboardEntry
+	for id, e := range t.sent {
+		if _, ok := t.desired[id]; !ok {
+			removed = append(removed, id.Entry(e.id, t.objective.Name, e.score))
+			delete(t.sent, id)
+		}
+	}
+	for id, score := range t.desired {
+		e, ok := t.sent[id]
+		if ok && e.score == score {
+			continue
+		}
+		if !ok {
+			t.nextID++
+			e.id = t.nextID
+		}
+		e.score = score
+		t.sent[id] = e
+		modified = append(modified, id.Entry(e.id, t.objective.Name, score))
+	}
+
+	var pks []packet.Packet
+	if len(removed) > 0 {
+		slices.SortFunc(removed, compareEntries)
+		pks = append(pks, &packet.SetScore{ActionType: packet.ScoreboardActionRemove, Entries: removed})
+	}
+	if len(modified) > 0 {
+		slices.SortFunc(modified, compareEntries)
+		pks = append(pks, &packet.SetScore{ActionType: packet.ScoreboardActionModify, Entries: modified})
+	}
+	return pks
+}
+
+// Reset makes the Tracker assume that the player has no entries for the Objective, for example after the
+// Objective was removed and displayed again, so that the next call to Packets sends all entries.
+func (t *Tracker) Reset() {
+	clear(t.sent)
+}
+
+// compareEntries orders scoreboard entries by their entry ID.
+func compareEntries(a, b protocol.ScoreboardEntry) int {
+	switch {
+	case a.EntryID < b.EntryID:
+		return -1
+	case a.EntryID > b.EntryID:
+		return 1
+	}
+	return 0
+}
@@ -35,6 +35,22 @@ type ServerStatus struct {
 	// MaxPlayers is the maximum amount of players in the server. If set to 0, MaxPlayers is set to
 	// PlayerCount + 1.
 	MaxPlayers int
+	// GameMode is the name of the game mode shown in the server list, such as "Survival". If empty,
+	// "Creative" is used.
+	GameMode string
+	// ProtocolVersion and Version are the protocol version and game version that the server advertises.
+	// If left empty, protocol.CurrentProtocol and protocol.CurrentVersion are used.
+	ProtocolVersion int32
+	Version         string
+}
+
+// ServerStatusFunc is a function that implements ServerStatusProvider. It may be used to generate the status
+// of a Listener dynamically, for example to show live player counts or a rotating MOTD.
+type ServerStatusFunc func(playerCount, maxPlayers int) ServerStatus
+
+// ServerStatus calls f.
+func (f ServerStatusFunc) ServerStatus(playerCount, maxPlayers int) ServerStatus {
+	return f(playerCount, maxPlayers)
 }
 
 // ListenerStatusProvider is the default ServerStatusProvider of a Listener. It displays a static server name/
@@ -136,10 +152,14 @@ func parsePongData(pong []byte) ServerStatus {
 	if err != nil {
 		return ServerStatus{ServerName: "Invalid max player count"}
 	}
-	return ServerStatus{
+	status := ServerStatus{
 		ServerName:    serverName,
 		ServerSubName: serverSubName,
 		PlayerCount:   online,
 		MaxPlayers:    max,
 	}
+	if len(frag) > 8 {
+		status.GameMode = frag[8]
+	}
+	return status
 }
@@ -0,0 +1,110 @@
+package minecraft
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// serverTickDuration is the duration of a single tick of a vanilla server.
+const serverTickDuration = time.Second / 20
+
+// ServerTick returns the current tick of the server that a Conn obtained using Dial is connected to. The tick
+// is estimated from the most recent tick sent by the server, in a TickSync packet or in packets such as
+// MovePlayer and SetActorMotion, assuming the server runs at 20 ticks per second since then. False is
+// returned if the server has not yet sent its tick, or if the Conn was obtained using a Listener. Sending a
+// TickSync packet, for example by setting Dialer.KeepAliveInterval, makes the server send its tick.
+func (conn *Conn) ServerTick() (uint64, bool) {
+	if conn.ticks == nil {
+		return 0, false
+	}
+	return conn.ticks.current()
+}
+
+// OnServerTick adds a function that is called with the tick of the server whenever a packet read from a Conn
+// obtained using Dial holds a tick newer than the ones before. The function is called on the goroutine that
+// reads packets, so it must not block. The function returned removes the function again.
+func (conn *Conn) OnServerTick(f func(tick uint64)) (remove func()) {
+	if conn.ticks == nil {
+		return func() {}
+	}
+	return conn.ticks.subscribe(f)
+}
+
+// tickTracker keeps track of the tick of the server that a client Conn is connected to. It is safe for
+// concurrent use.
+type tickTracker struct {
+	mu     sync.Mutex
+	tick   uint64
+	at     time.Time
+	subs   map[int]func(tick uint64)
+	nextID int
+}
+
+// observe updates the tickTracker with the server tick held by the packet passed, if it holds one.
+func (t *tickTracker) observe(pk packet.Packet) {
+	var tick uint64
+	switch pk := packet.Unwrap(pk).(type) {
+	case *packet.TickSync:
+		tick = uint64(pk.ServerReceptionTimestamp)
+	case *packet.MovePlayer:
+		tick = pk.Tick
+	case *packet.SetActorData:
+		tick = pk.Tick
+	case *packet.SetActorMotion:
+		tick = pk.Tick
+	case *packet.UpdateAttributes:
+		tick = pk.Tick
+	case *packet.MobEffect:
+		tick = pk.Tick
+	case *packet.UpdatePlayerGameType:
+		tick = pk.Tick
+	}
+	if tick == 0 {
+		return
+	}
+
+	t.mu.Lock()
+	if tick <= t.tick {
+		t.mu.Unlock()
+		return
+	}
+	t.tick, t.at = tick, time.Now()
+	subs := make([]func(tick uint64), 0, len(t.subs))
+	for _, f := range t.subs {
+		subs = append(subs, f)
+	}
+	t.mu.Unlock()
+
+	for _, f := range subs {
+		f(tick)
+	}
+}
+
+// current returns the estimated current tick of the server.
+func (t *tickTracker) current() (uint64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.tick == 0 {
+		return 0, false
+	}
+	return t.tick + uint64(time.Since(t.at)/serverTickDuration), true
+}
+
+// subscribe adds a function called for every new tick and returns a function that removes it.
+func (t *tickTracker) subscribe(f func(tick uint64)) func() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.subs == nil {
+		t.subs = make(map[int]func(tick uint64))
+	}
+	id := t.nextID
+	t.nextID++
+	t.subs[id] = f
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		delete(t.subs, id)
+	}
+}
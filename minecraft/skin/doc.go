@@ -0,0 +1,5 @@
+// Package skin implements the validation and construction of skins as sent over network in a protocol.Skin.
+// Malformed skins, such as those with image data that does not match their dimensions or with geometry that
+// the client cannot parse, are a common cause of players being invisible to others, so Validate may be used
+// to check skins before they are sent. New constructs a skin from a PNG image and, optionally, geometry.
+package skin
@@ -0,0 +1,208 @@
+package skin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+const (
+	PieceSkeleton        = "persona_skeleton"
+	PieceBody            = "persona_body"
+	PieceSkin            = "persona_skin"
+	PieceBottom          = "persona_bottom"
+	PieceFeet            = "persona_feet"
+	PieceTop             = "persona_top"
+	PieceMouth           = "persona_mouth"
+	PieceHair            = "persona_hair"
+	PieceEyes            = "persona_eyes"
+	PieceFacialHair      = "persona_facial_hair"
+	PieceHighPants       = "persona_high_pants"
+	PieceDress           = "persona_dress"
+	PieceBack            = "persona_back"
+	PieceFaceAccessory   = "persona_face_accessory"
+	PieceHood            = "persona_hood"
+	PieceCapes           = "persona_capes"
+	PieceEmote           = "persona_emote"
+	PieceLeftArm         = "persona_left_arm"
+	PieceRightArm        = "persona_right_arm"
+	PieceLeftLeg         = "persona_left_leg"
+	PieceRightLeg        = "persona_right_leg"
+	PieceHands           = "persona_hands"
+	PieceHeadAccessory   = "persona_head_accessory"
+	PieceOutfitAccessory = "persona_outfit_accessory"
+)
+
+// requiredPieces holds the piece types that every persona skin must have.
+var requiredPieces = []string{PieceSkeleton, PieceBody, PieceSkin}
+
+// Persona holds the persona pieces and tint colours that a persona skin is composed of. Its pieces and tint
+// colours may be set as PersonaPieces and PieceTintColours of a Config to construct the skin.
+type Persona struct {
+	// Pieces holds the persona pieces of the skin.
+	Pieces []protocol.PersonaPiece
+	// TintColours holds the tint colours of (some of) the pieces in Pieces.
+	TintColours []protocol.PersonaPieceTintColour
+}
+
+// Piece adds a piece of the piece type passed, such as PieceHair, to the Persona. The product ID should be
+// empty for default pieces, which are those that a Steve or Alex skin have. A piece of the same type that
+// was added before is replaced.
+func (p *Persona) Piece(pieceType, pieceID, packID, productID string) {
+	piece := protocol.PersonaPiece{
+		PieceID:   pieceID,
+		PieceType: pieceType,
+		PackID:    packID,
+		Default:   productID == "",
+		ProductID: productID,
+	}
+	for i, existing := range p.Pieces {
+		if existing.PieceType == pieceType {
+			p.Pieces[i] = piece
+			return
+		}
+	}
+	p.Pieces = append(p.Pieces, piece)
+}
+
+// Tint sets the tint colours of the pieces of the type passed. Colours are ARGB hex colours such as
+// '#ffa12722'. Up to four colours may be passed: Colours not passed are set to '#0'.
+func (p *Persona) Tint(pieceType string, colours ...string) {
+	tint := protocol.PersonaPieceTintColour{PieceType: pieceType, Colours: make([]string, 4)}
+	for i := range tint.Colours {
+		tint.Colours[i] = "#0"
+		if i < len(colours) {
+			tint.Colours[i] = colours[i]
+		}
+	}
+	for i, existing := range p.TintColours {
+		if existing.PieceType == pieceType {
+			p.TintColours[i] = tint
+			return
+		}
+	}
+	p.TintColours = append(p.TintColours, tint)
+}
+
+// Validate checks if the Persona has all pieces required for a persona skin, if no piece type is present
+// more than once, and if the tint colours are valid ARGB hex colours of pieces present in the Persona.
+func (p *Persona) Validate() error {
+	var errs []error
+	pieceTypes := make(map[string]struct{}, len(p.Pieces))
+	for _, piece := range p.Pieces {
+		if _, ok := pieceTypes[piece.PieceType]; ok {
+			errs = append(errs, fmt.Errorf("duplicate persona piece type %q", piece.PieceType))
+		}
+		pieceTypes[piece.PieceType] = struct{}{}
+		if piece.PieceID == "" {
+			errs = append(errs, fmt.Errorf("persona piece of type %q has no piece ID", piece.PieceType))
+		}
+	}
+	for _, pieceType := range requiredPieces {
+		if _, ok := pieceTypes[pieceType]; !ok {
+			errs = append(errs, fmt.Errorf("missing required persona piece type %q", pieceType))
+		}
+	}
+	for _, tint := range p.TintColours {
+		if _, ok := pieceTypes[tint.PieceType]; !ok {
+			errs = append(errs, fmt.Errorf("tint colours for persona piece type %q without a piece of that type", tint.PieceType))
+		}
+		if len(tint.Colours) != 4 {
+			errs = append(errs, fmt.Errorf("tint colours for persona piece type %q must hold 4 colours, got %v", tint.PieceType, len(tint.Colours)))
+		}
+		for _, c := range tint.Colours {
+			if !validColour(c) {
+				errs = append(errs, fmt.Errorf("tint colour %q for persona piece type %q is not a valid hex colour", c, tint.PieceType))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validColour checks if the string passed is a hex colour such as '#ffa12722' or '#0'.
+func validColour(c string) bool {
+	hex, ok := strings.CutPrefix(c, "#")
+	if !ok || len(hex) == 0 || len(hex) > 8 {
+		return false
+	}
+	_, err := strconv.ParseUint(hex, 16, 32)
+	return err == nil
+}
+
+// Classic converts the persona protocol.Skin passed to a classic skin, approximating its appearance. The
+// skin image is scaled down to the 64x64 layout of classic skins and the built-in humanoid geometry matching
+// the arm size of the skin is used. Persona pieces, tint colours, animations and custom geometry are
+// dropped. The skin returned is meant for rendering tools that do not support persona skins, and should not
+// be expected to look exactly like the persona skin in game.
+func Classic(s protocol.Skin) (protocol.Skin, error) {
+	if err := validImage(s.SkinImageWidth, s.SkinImageHeight, s.SkinData); err != nil {
+		return s, fmt.Errorf("classic skin: skin image: %w", err)
+	}
+	img := ClassicImage(&image.NRGBA{
+		Pix:    s.SkinData,
+		Stride: int(s.SkinImageWidth) * 4,
+		Rect:   image.Rect(0, 0, int(s.SkinImageWidth), int(s.SkinImageHeight)),
+	})
+	identifier := "geometry.humanoid.custom"
+	if s.ArmSize == "slim" {
+		identifier = "geometry.humanoid.customSlim"
+	}
+
+	s.SkinData, s.SkinImageWidth, s.SkinImageHeight = img.Pix, 64, 64
+	s.SkinResourcePatch, _ = json.Marshal(map[string]any{"geometry": map[string]string{"default": identifier}})
+	s.SkinGeometry, s.Animations, s.AnimationData = nil, nil, nil
+	s.PersonaSkin, s.PersonaPieces, s.PieceTintColours = false, nil, nil
+	return s, nil
+}
+
+// ClassicImage returns a 64x64 approximation of the skin image passed in the layout of classic skins. Images
+// with a 64x32 legacy layout are extended with the left arm and leg mirrored from the right ones. Larger
+// square images are scaled down by averaging the colours of the pixels that make up each pixel.
+func ClassicImage(img *image.NRGBA) *image.NRGBA {
+	out := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	b := img.Bounds()
+	if b.Dx() == 64 && b.Dy() == 32 {
+		for y := range 32 {
+			copy(out.Pix[y*out.Stride:y*out.Stride+64*4], img.Pix[y*img.Stride:y*img.Stride+64*4])
+		}
+		// The legacy layout has no left arm and leg: They are mirrored from the right ones.
+		mirrorLimb(out, 0, 16, 16, 48)
+		mirrorLimb(out, 40, 16, 32, 48)
+		return out
+	}
+
+	scaleX, scaleY := max(b.Dx()/64, 1), max(b.Dy()/64, 1)
+	for y := range 64 {
+		for x := range 64 {
+			var r, g, bl, a, n int
+			for sy := y * scaleY; sy < (y+1)*scaleY && sy < b.Dy(); sy++ {
+				for sx := x * scaleX; sx < (x+1)*scaleX && sx < b.Dx(); sx++ {
+					i := img.PixOffset(b.Min.X+sx, b.Min.Y+sy)
+					r, g, bl, a = r+int(img.Pix[i]), g+int(img.Pix[i+1]), bl+int(img.Pix[i+2]), a+int(img.Pix[i+3])
+					n++
+				}
+			}
+			if n == 0 {
+				continue
+			}
+			i := out.PixOffset(x, y)
+			out.Pix[i], out.Pix[i+1], out.Pix[i+2], out.Pix[i+3] = uint8(r/n), uint8(g/n), uint8(bl/n), uint8(a/n)
+		}
+	}
+	return out
+}
+
+// mirrorLimb copies the 16x16 limb texture at (srcX, srcY) of the image passed to (dstX, dstY), mirrored
+// horizontally.
+func mirrorLimb(img *image.NRGBA, srcX, srcY, dstX, dstY int) {
+	for y := range 16 {
+		for x := range 16 {
+			img.SetNRGBA(dstX+15-x, dstY+y, img.NRGBAAt(srcX+x, srcY+y))
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package skin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// Config holds the settings used by New to construct a protocol.Skin.
+type Config struct {
+	// Skin is a reader holding the PNG image of the skin. The image must be 64x32, 64x64, 128x128, 256x256 or
+	// 512x512 pixels.
+	Skin io.Reader
+	// Geometry holds the JSON encoded geometry of the skin. If empty, the built-in geometry of the client is
+	// used, which is 'geometry.humanoid.customSlim' if ArmSize is 'slim' and 'geometry.humanoid.custom'
+	// otherwise.
+	Geometry []byte
+	// GeometryIdentifier is the identifier of the geometry in Geometry used as default geometry of the skin.
+	// If empty, the first geometry found in Geometry is used.
+	GeometryIdentifier string
+	// ArmSize is the size of the arms of the skin, either 'wide' or 'slim'. If empty, 'wide' is used.
+	ArmSize string
+	// Cape is a reader holding the PNG image of the cape of the skin. If nil, the skin has no cape.
+	Cape io.Reader
+	// CapeID is the ID of the cape. If empty and Cape is non-nil, a random ID is used.
+	CapeID string
+	// Animations holds the animations of the skin, which may be created using Animation.
+	Animations []protocol.SkinAnimation
+	// Persona specifies if the skin was created using the in-game skin creator.
+	Persona bool
+	// PersonaCapeOnClassicSkin specifies if the skin is a classic skin with a persona cape equipped.
+	PersonaCapeOnClassicSkin bool
+	// PersonaPieces and PieceTintColours hold the pieces that a persona skin is composed of and their tint
+	// colours.
+	PersonaPieces    []protocol.PersonaPiece
+	PieceTintColours []protocol.PersonaPieceTintColour
+	// Premium specifies if the skin was bought from the marketplace.
+	Premium bool
+}
+
+// New constructs a protocol.Skin using the Config passed and validates it using Validate. The skin returned
+// has a random skin ID and overrides the appearance of the player client-side.
+func New(conf Config) (protocol.Skin, error) {
+	s := protocol.Skin{
+		SkinID:                    uuid.New().String(),
+		Animations:                conf.Animations,
+		SkinGeometry:              conf.Geometry,
+		GeometryDataEngineVersion: []byte("0.0.0"),
+		PremiumSkin:               conf.Premium,
+		PersonaSkin:               conf.Persona,
+		PersonaCapeOnClassicSkin:  conf.PersonaCapeOnClassicSkin,
+		ArmSize:                   conf.ArmSize,
+		SkinColour:                "#0",
+		PersonaPieces:             conf.PersonaPieces,
+		PieceTintColours:          conf.PieceTintColours,
+		OverrideAppearance:        true,
+	}
+	if s.ArmSize == "" {
+		s.ArmSize = "wide"
+	}
+	if conf.Skin == nil {
+		return s, errors.New("new skin: no skin image")
+	}
+	var err error
+	if s.SkinData, s.SkinImageWidth, s.SkinImageHeight, err = Image(conf.Skin); err != nil {
+		return s, fmt.Errorf("new skin: skin image: %w", err)
+	}
+	if conf.Cape != nil {
+		if s.CapeData, s.CapeImageWidth, s.CapeImageHeight, err = Image(conf.Cape); err != nil {
+			return s, fmt.Errorf("new skin: cape image: %w", err)
+		}
+		if s.CapeID = conf.CapeID; s.CapeID == "" {
+			s.CapeID = uuid.New().String()
+		}
+	}
+
+	identifier := conf.GeometryIdentifier
+	switch {
+	case identifier != "":
+	case len(conf.Geometry) != 0:
+		identifiers, err := GeometryIdentifiers(conf.Geometry)
+		if err != nil {
+			return s, fmt.Errorf("new skin: %w", err)
+		}
+		if len(identifiers) == 0 {
+			return s, errors.New("new skin: skin geometry defines no geometries")
+		}
+		identifier = identifiers[0]
+	case s.ArmSize == "slim":
+		identifier = "geometry.humanoid.customSlim"
+	default:
+		identifier = "geometry.humanoid.custom"
+	}
+	s.SkinResourcePatch, _ = json.Marshal(map[string]any{"geometry": map[string]string{"default": identifier}})
+	s.FullID = s.SkinID + s.CapeID
+
+	if err := Validate(s); err != nil {
+		return s, fmt.Errorf("new skin: %w", err)
+	}
+	return s, nil
+}
+
+// Animation constructs a protocol.SkinAnimation from a PNG image holding the frames passed of the
+// animation, stacked vertically. The animation type is one of the protocol.SkinAnimation constants and the
+// expression type one of the protocol.ExpressionType constants.
+func Animation(r io.Reader, animationType uint32, frames int, expressionType uint32) (protocol.SkinAnimation, error) {
+	data, width, height, err := Image(r)
+	if err != nil {
+		return protocol.SkinAnimation{}, fmt.Errorf("animation image: %w", err)
+	}
+	a := protocol.SkinAnimation{
+		ImageWidth:     width,
+		ImageHeight:    height,
+		ImageData:      data,
+		AnimationType:  animationType,
+		FrameCount:     float32(frames),
+		ExpressionType: expressionType,
+	}
+	if err := validAnimation(a); err != nil {
+		return a, err
+	}
+	return a, nil
+}
+
+// Image decodes the PNG image from the reader passed and returns its RGBA pixel data with its width and
+// height, as used for the image data of a protocol.Skin.
+func Image(r io.Reader) (data []byte, width, height uint32, err error) {
+	img, err := png.Decode(r)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("decode png: %w", err)
+	}
+	bounds := img.Bounds()
+	rgba, ok := img.(*image.NRGBA)
+	if !ok || rgba.Stride != bounds.Dx()*4 {
+		// Skins hold non-premultiplied RGBA data, so images of other colour models are converted.
+		rgba = image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	}
+	return rgba.Pix, uint32(bounds.Dx()), uint32(bounds.Dy()), nil
+}
@@ -0,0 +1,175 @@
+package skin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// Validate checks if the protocol.Skin passed is valid, so that clients are able to display it. It checks
+// the dimensions of the skin, cape and animation images against the length of their data, the frames of
+// animations, if the geometry and resource patch are valid JSON and if the geometry referenced by the
+// resource patch exists, and if the persona pieces and tint colours are consistent. All problems found are
+// returned, joined using errors.Join.
+func Validate(s protocol.Skin) error {
+	var errs []error
+	if s.SkinID == "" {
+		errs = append(errs, errors.New("skin ID must not be empty"))
+	}
+	if err := validSkinSize(s.SkinImageWidth, s.SkinImageHeight); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validImage(s.SkinImageWidth, s.SkinImageHeight, s.SkinData); err != nil {
+		errs = append(errs, fmt.Errorf("skin image: %w", err))
+	}
+	if err := validImage(s.CapeImageWidth, s.CapeImageHeight, s.CapeData); err != nil {
+		errs = append(errs, fmt.Errorf("cape image: %w", err))
+	}
+	for i, a := range s.Animations {
+		if err := validAnimation(a); err != nil {
+			errs = append(errs, fmt.Errorf("animation %v: %w", i, err))
+		}
+	}
+	if err := validGeometry(s.SkinGeometry, s.SkinResourcePatch); err != nil {
+		errs = append(errs, err)
+	}
+	switch s.ArmSize {
+	case "", "wide", "slim":
+	default:
+		errs = append(errs, fmt.Errorf("arm size must be 'wide' or 'slim', got %q", s.ArmSize))
+	}
+	if err := validPersona(s); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// validSkinSize checks if the dimensions passed are those of a skin image the client accepts.
+func validSkinSize(width, height uint32) error {
+	switch {
+	case width == 64 && height == 32:
+		return nil
+	case width == height && (width == 64 || width == 128 || width == 256 || width == 512):
+		return nil
+	}
+	return fmt.Errorf("skin image dimensions must be 64x32, 64x64, 128x128, 256x256 or 512x512, got %vx%v", width, height)
+}
+
+// validImage checks if the length of the RGBA image data passed matches the dimensions passed.
+func validImage(width, height uint32, data []byte) error {
+	if expected := uint64(width) * uint64(height) * 4; uint64(len(data)) != expected {
+		return fmt.Errorf("expected %v bytes for %vx%v image, got %v bytes", expected, width, height, len(data))
+	}
+	return nil
+}
+
+// validAnimation checks if the protocol.SkinAnimation passed is valid.
+func validAnimation(a protocol.SkinAnimation) error {
+	if a.AnimationType < protocol.SkinAnimationHead || a.AnimationType > protocol.SkinAnimationBody128x128 {
+		return fmt.Errorf("unknown animation type %v", a.AnimationType)
+	}
+	if a.ExpressionType != protocol.ExpressionTypeLinear && a.ExpressionType != protocol.ExpressionTypeBlinking {
+		return fmt.Errorf("unknown expression type %v", a.ExpressionType)
+	}
+	if err := validImage(a.ImageWidth, a.ImageHeight, a.ImageData); err != nil {
+		return err
+	}
+	frames := a.FrameCount
+	if frames < 1 || frames != float32(math.Trunc(float64(frames))) {
+		return fmt.Errorf("frame count must be a positive whole number, got %v", frames)
+	}
+	if a.ImageHeight%uint32(frames) != 0 {
+		return fmt.Errorf("image height %v is not divisible by frame count %v", a.ImageHeight, frames)
+	}
+	return nil
+}
+
+// validGeometry checks if the geometry and resource patch passed are valid JSON, and if the default geometry
+// referenced by the resource patch is either present in the geometry or one of the built-in geometries.
+func validGeometry(geometry, resourcePatch []byte) error {
+	var patch struct {
+		Geometry struct {
+			Default string `json:"default"`
+		} `json:"geometry"`
+	}
+	if err := json.Unmarshal(resourcePatch, &patch); err != nil {
+		return fmt.Errorf("resource patch is not valid JSON: %w", err)
+	}
+	if patch.Geometry.Default == "" {
+		return errors.New("resource patch has no default geometry")
+	}
+	if len(geometry) == 0 {
+		return nil
+	}
+	identifiers, err := GeometryIdentifiers(geometry)
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(patch.Geometry.Default, "geometry.humanoid") {
+		// Built-in geometry of the client, which need not be present in the skin geometry.
+		return nil
+	}
+	for _, identifier := range identifiers {
+		if identifier == patch.Geometry.Default {
+			return nil
+		}
+	}
+	return fmt.Errorf("default geometry %q of resource patch not found in skin geometry (found %v)", patch.Geometry.Default, identifiers)
+}
+
+// validPersona checks if the persona pieces and tint colours of the protocol.Skin passed are consistent.
+func validPersona(s protocol.Skin) error {
+	if !s.PersonaSkin {
+		if len(s.PersonaPieces) != 0 {
+			return errors.New("skin has persona pieces but is not a persona skin")
+		}
+		return nil
+	}
+	pieceTypes := make(map[string]struct{}, len(s.PersonaPieces))
+	for _, piece := range s.PersonaPieces {
+		pieceTypes[piece.PieceType] = struct{}{}
+	}
+	for _, tint := range s.PieceTintColours {
+		if _, ok := pieceTypes[tint.PieceType]; !ok {
+			return fmt.Errorf("tint colours for persona piece type %q without a piece of that type", tint.PieceType)
+		}
+	}
+	return nil
+}
+
+// GeometryIdentifiers returns the identifiers of the geometries defined in the skin geometry JSON passed,
+// such as 'geometry.humanoid.custom'. Both the legacy format, in which each geometry is a top-level key, and
+// the format used since format version 1.12.0, in which geometries are listed under 'minecraft:geometry', are
+// supported. An error is returned if the geometry is not valid JSON.
+func GeometryIdentifiers(geometry []byte) ([]string, error) {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(geometry, &m); err != nil {
+		return nil, fmt.Errorf("skin geometry is not valid JSON: %w", err)
+	}
+	var identifiers []string
+	if data, ok := m["minecraft:geometry"]; ok {
+		var geometries []struct {
+			Description struct {
+				Identifier string `json:"identifier"`
+			} `json:"description"`
+		}
+		if err := json.Unmarshal(data, &geometries); err != nil {
+			return nil, fmt.Errorf("skin geometry has invalid minecraft:geometry: %w", err)
+		}
+		for _, g := range geometries {
+			identifiers = append(identifiers, g.Description.Identifier)
+		}
+	}
+	for k := range m {
+		if strings.HasPrefix(k, "geometry.") {
+			// Legacy geometry keys may be suffixed with ':parent', such as 'geometry.a:geometry.humanoid'.
+			identifier, _, _ := strings.Cut(k, ":")
+			identifiers = append(identifiers, identifier)
+		}
+	}
+	return identifiers, nil
+}
@@ -0,0 +1,111 @@
+package minecraft
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Sniffer decodes packets from batches captured outside of a Conn, for example by an external capture tool,
+// using the same packet pools and readers as a Conn. It allows analysis tools to decode a stream of packets
+// without a live connection. A Sniffer decodes the packets of one direction of a single connection and is
+// not safe for concurrent use.
+type Sniffer struct {
+	// Protocol is the Protocol that packets are decoded with. If nil, DefaultProtocol is used. Protocol and
+	// FromServer must not be changed after the first packet was decoded.
+	Protocol Protocol
+	// FromServer specifies if the packets decoded are sent by the server. If false, the packets are assumed
+	// to be sent by the client.
+	FromServer bool
+	// ShieldID is the runtime ID of the shield item, which affects how items are decoded. It is updated
+	// automatically when a StartGame packet is decoded.
+	ShieldID int32
+	// Compressed specifies if the batches passed to DecodeBatch are compressed and thus start with the ID of
+	// the compression algorithm. It is set automatically when a NetworkSettings packet is decoded.
+	Compressed bool
+	// StrictDecoding specifies if errors returned for packets that could not be decoded should include the
+	// path of the field being read when decoding failed, such as 'AvailableCommands.Enums[3].Options'.
+	StrictDecoding bool
+
+	pool packet.Pool
+}
+
+// DecodeBatch decodes all packets in the batch passed. The batch must already be decrypted, and must not
+// hold the batch header (0xfe) or the checksum that follows the packets in an encrypted batch. Packets that
+// could not be decoded are returned as far as they were decoded, together with an error.
+func (s *Sniffer) DecodeBatch(batch []byte) ([]packet.Packet, error) {
+	if len(batch) == 0 {
+		return nil, nil
+	}
+	data := batch
+	if s.Compressed {
+		if data[0] == 0xff {
+			data = data[1:]
+		} else {
+			compression, ok := packet.CompressionByID(uint16(data[0]))
+			if !ok {
+				return nil, fmt.Errorf("decompress batch: unknown compression algorithm %v", data[0])
+			}
+			var err error
+			if data, err = compression.Decompress(data[1:]); err != nil {
+				return nil, fmt.Errorf("decompress batch: %w", err)
+			}
+		}
+	}
+
+	var (
+		pks  []packet.Packet
+		errs []error
+	)
+	buf := bytes.NewBuffer(data)
+	for buf.Len() != 0 {
+		var length uint32
+		if err := protocol.Varuint32(buf, &length); err != nil {
+			return pks, errors.Join(append(errs, fmt.Errorf("decode batch: read packet length: %w", err))...)
+		}
+		decoded, err := s.DecodePacket(buf.Next(int(length)))
+		if err != nil {
+			errs = append(errs, err)
+		}
+		pks = append(pks, decoded...)
+	}
+	return pks, errors.Join(errs...)
+}
+
+// DecodePacket decodes a single packet, including its header, as found in a batch. More than one packet may
+// be returned if the Protocol of the Sniffer converts the packet to multiple packets.
+func (s *Sniffer) DecodePacket(data []byte) ([]packet.Packet, error) {
+	if s.Protocol == nil {
+		s.Protocol = DefaultProtocol
+	}
+	if s.pool == nil {
+		s.pool = s.Protocol.Packets(!s.FromServer)
+	}
+	pkData, err := ParseData(data, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	pks, err := pkData.Decode(s.pool, s.Protocol, func() error { return nil }, false, false, s.ShieldID, protocol.ReaderLimits{}, s.StrictDecoding, false)
+	for _, pk := range pks {
+		s.observe(pk)
+	}
+	return pks, err
+}
+
+// observe updates the state of the Sniffer using the packet passed, so that packets that follow it are
+// decoded correctly.
+func (s *Sniffer) observe(pk packet.Packet) {
+	switch pk := pk.(type) {
+	case *packet.NetworkSettings:
+		s.Compressed = true
+	case *packet.StartGame:
+		for _, item := range pk.Items {
+			if item.Name == "minecraft:shield" {
+				s.ShieldID = int32(item.RuntimeID)
+			}
+		}
+	}
+}
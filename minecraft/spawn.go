@@ -0,0 +1,87 @@
+package minecraft
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// SpawnConfig holds settings for the spawn sequence of a Conn obtained using Dial, which starts once the
+// server sends the StartGame packet. It may be set as Dialer.Spawn to deal with servers that deviate from
+// the spawn sequence of vanilla servers.
+type SpawnConfig struct {
+	// ChunkRadius is the chunk radius requested from the server in the RequestChunkRadius packet. If 0, a
+	// chunk radius of 16 is requested.
+	ChunkRadius int32
+	// SkipChunkRadius specifies if the client should be considered spawned without the server responding to
+	// the RequestChunkRadius packet with a ChunkRadiusUpdated packet.
+	SkipChunkRadius bool
+	// SkipPlayStatus specifies if the client should be considered spawned without the server sending a
+	// PlayStatus packet with the PlayerSpawn status.
+	SkipPlayStatus bool
+
+	// StartGameTimeout is the maximum duration between DoSpawn being called and the StartGame packet arriving.
+	// SpawnTimeout is the maximum duration between the StartGame packet arriving and the client being
+	// spawned. If zero, only the timeout or context passed to DoSpawn applies to the stage.
+	StartGameTimeout, SpawnTimeout time.Duration
+}
+
+// chunkRadius returns the chunk radius to request from the server.
+func (cfg SpawnConfig) chunkRadius() int32 {
+	if cfg.ChunkRadius <= 0 {
+		return 16
+	}
+	return cfg.ChunkRadius
+}
+
+// startSpawn starts the spawn sequence of a client Conn after the StartGame packet was received, according
+// to the SpawnConfig of the Conn.
+func (conn *Conn) startSpawn() {
+	close(conn.startGameReceived)
+	conn.enterPhase("spawn", conn.loginTimeouts.Spawn)
+
+	_ = conn.WritePacket(&packet.RequestChunkRadius{ChunkRadius: conn.spawnCfg.chunkRadius()})
+	conn.expect(packet.IDChunkRadiusUpdated, packet.IDPlayStatus)
+	if conn.spawnCfg.SkipChunkRadius {
+		conn.gameDataReceived.Store(true)
+	}
+	if conn.spawnCfg.SkipPlayStatus {
+		conn.waitingForSpawn.Store(true)
+	}
+	conn.tryFinaliseClientConn()
+}
+
+// awaitSpawnStage waits until the channel passed is closed, returning an error if the Conn is closed, the
+// context passed is cancelled or if the timeout passed, if not zero, expires first.
+func (conn *Conn) awaitSpawnStage(ctx context.Context, timeout time.Duration, done <-chan struct{}, stage string) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	select {
+	case <-conn.close:
+		return conn.closeErr("do spawn")
+	case <-ctx.Done():
+		return conn.wrap(LoginTimeoutError{Stage: stage, Err: ctx.Err()}, "do spawn")
+	case <-done:
+		return nil
+	}
+}
+
+// spawnStage returns a description of the packets that the spawn sequence of the Conn is still waiting for.
+func (conn *Conn) spawnStage() string {
+	var missing []string
+	if !conn.gameDataReceived.Load() {
+		missing = append(missing, "ChunkRadiusUpdated")
+	}
+	if !conn.waitingForSpawn.Load() {
+		missing = append(missing, "PlayStatus (Status=PlayerSpawn)")
+	}
+	if len(missing) == 0 {
+		return "spawn"
+	}
+	return "spawn: waiting for " + strings.Join(missing, " and ")
+}
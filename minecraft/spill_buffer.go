@@ -0,0 +1,114 @@
+package minecraft
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"sync/atomic"
+)
+
+// spillBuffer is a buffer that holds the data of a resource pack being downloaded. It keeps the data in
+// memory as long as the memory budget it shares with other spillBuffers allows it, after which all of its
+// data is moved to a temporary file. The SHA256 checksum of the data is computed while it is written, unless
+// it is not needed.
+type spillBuffer struct {
+	mem  bytes.Buffer
+	file *os.File
+	n    int
+	sum  hash.Hash
+
+	// budget is the amount of bytes that may still be held in memory by all spillBuffers sharing it. If
+	// nil, the spillBuffer never spills to disk.
+	budget *atomic.Int64
+}
+
+// newSpillBuffer returns a new spillBuffer that takes memory from the budget passed. budget may be nil, in
+// which case all data is kept in memory. If checksum is false, the SHA256 checksum of the data is not
+// computed and Sum returns nil.
+func newSpillBuffer(budget *atomic.Int64, checksum bool) *spillBuffer {
+	b := &spillBuffer{budget: budget}
+	if checksum {
+		b.sum = sha256.New()
+	}
+	return b
+}
+
+// Write writes p to the buffer. If writing p to memory would exceed the memory budget, all data is moved to
+// a temporary file first.
+func (b *spillBuffer) Write(p []byte) (int, error) {
+	if b.file == nil && b.budget != nil && b.budget.Add(-int64(len(p))) < 0 {
+		b.budget.Add(int64(len(p)))
+		if err := b.spill(); err != nil {
+			return 0, err
+		}
+	}
+	var n int
+	var err error
+	if b.file != nil {
+		n, err = b.file.Write(p)
+	} else {
+		n, err = b.mem.Write(p)
+	}
+	b.n += n
+	if b.sum != nil {
+		b.sum.Write(p[:n])
+	}
+	return n, err
+}
+
+// spill moves the data held in memory to a temporary file and releases the memory back to the budget.
+func (b *spillBuffer) spill() error {
+	f, err := os.CreateTemp("", "resource_pack_download-*")
+	if err != nil {
+		return fmt.Errorf("spill resource pack to disk: %w", err)
+	}
+	if _, err := f.Write(b.mem.Bytes()); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("spill resource pack to disk: %w", err)
+	}
+	b.file = f
+	b.budget.Add(int64(b.mem.Len()))
+	b.mem = bytes.Buffer{}
+	return nil
+}
+
+// Len returns the total amount of bytes written to the buffer.
+func (b *spillBuffer) Len() int {
+	return b.n
+}
+
+// Sum returns the SHA256 checksum of all data written to the buffer, or nil if the buffer does not compute it.
+func (b *spillBuffer) Sum() []byte {
+	if b.sum == nil {
+		return nil
+	}
+	return b.sum.Sum(nil)
+}
+
+// Reader returns an io.Reader that reads all data written to the buffer from the start.
+func (b *spillBuffer) Reader() (io.Reader, error) {
+	if b.file == nil {
+		return bytes.NewReader(b.mem.Bytes()), nil
+	}
+	if _, err := b.file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("seek resource pack file: %w", err)
+	}
+	return b.file, nil
+}
+
+// Close releases the memory held by the buffer back to the budget and removes its temporary file, if any.
+func (b *spillBuffer) Close() error {
+	if b.file == nil {
+		if b.budget != nil {
+			b.budget.Add(int64(b.mem.Len()))
+		}
+		b.mem = bytes.Buffer{}
+		return nil
+	}
+	_ = b.file.Close()
+	return os.Remove(b.file.Name())
+}
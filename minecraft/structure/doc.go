@@ -0,0 +1,5 @@
+// Package structure implements the structure template format, as used in .mcstructure files and in the
+// StructureTemplate field of the StructureTemplateDataResponse packet. A Template holds a palette of block
+// states, the palette indices of the blocks in the structure, the data of block entities and the entities in
+// the structure.
+package structure
@@ -0,0 +1,317 @@
+package structure
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/sandertv/gophertunnel/minecraft/block"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// formatVersion is the version of the structure template format implemented.
+const formatVersion = 1
+
+// Template is a structure template: A cuboid of blocks, optionally with block entities and entities. Blocks
+// are stored in two layers, the second of which typically holds water in waterlogged blocks.
+type Template struct {
+	// Size is the size of the structure on the X, Y and Z axes.
+	Size [3]int32
+	// Origin is the position in the world that the structure was saved from.
+	Origin [3]int32
+	// Palette holds the block states used in the structure. Blocks refer to states by their index in the
+	// Palette.
+	Palette []block.State
+	// Layers holds the two layers of blocks of the structure. Each layer holds a Palette index for every
+	// block, ordered as returned by Index, or -1 if the structure holds no block at that position, leaving
+	// the block in the world as it is when the structure is placed.
+	Layers [2][]int32
+	// PositionData holds additional data of blocks by their index, as returned by Index. The data of a block
+	// entity is held under the 'block_entity_data' key.
+	PositionData map[int32]map[string]any
+	// Entities holds the NBT data of the entities in the structure.
+	Entities []map[string]any
+}
+
+// New returns an empty Template of the size passed, holding no blocks.
+func New(size [3]int32) *Template {
+	t := &Template{Size: size, PositionData: make(map[int32]map[string]any)}
+	n := int(size[0]) * int(size[1]) * int(size[2])
+	for i := range t.Layers {
+		t.Layers[i] = make([]int32, n)
+		for j := range t.Layers[i] {
+			t.Layers[i][j] = -1
+		}
+	}
+	return t
+}
+
+// Index returns the index of the block at the position passed, relative to the structure, in the Layers of
+// the Template. Blocks are ordered by X, then Y, then Z, so that the Z coordinate changes fastest. False is
+// returned if the position is outside the Template.
+func (t *Template) Index(x, y, z int32) (int32, bool) {
+	if x < 0 || y < 0 || z < 0 || x >= t.Size[0] || y >= t.Size[1] || z >= t.Size[2] {
+		return 0, false
+	}
+	return (x*t.Size[1]+y)*t.Size[2] + z, true
+}
+
+// Block returns the block state at the position and layer passed. False is returned if the position is
+// outside the Template or if the Template holds no block there.
+func (t *Template) Block(x, y, z int32, layer int) (block.State, bool) {
+	i, ok := t.Index(x, y, z)
+	if !ok || layer < 0 || layer >= len(t.Layers) || int(i) >= len(t.Layers[layer]) {
+		return block.State{}, false
+	}
+	p := t.Layers[layer][i]
+	if p < 0 || int(p) >= len(t.Palette) {
+		return block.State{}, false
+	}
+	return t.Palette[p], true
+}
+
+// SetBlock sets the block state at the position and layer passed, adding the state to the Palette if it is
+// not yet present. SetBlock panics if the position is outside the Template or the layer is not 0 or 1.
+func (t *Template) SetBlock(x, y, z int32, layer int, s block.State) {
+	i, ok := t.Index(x, y, z)
+	if !ok {
+		panic(fmt.Sprintf("structure: position (%v, %v, %v) outside structure of size %v", x, y, z, t.Size))
+	}
+	t.Layers[layer][i] = t.paletteIndex(s)
+}
+
+// paletteIndex returns the index of the block state passed in the Palette, adding it if not yet present.
+func (t *Template) paletteIndex(s block.State) int32 {
+	h := block.Hash(s)
+	for i, existing := range t.Palette {
+		if existing.Name == s.Name && block.Hash(existing) == h {
+			return int32(i)
+		}
+	}
+	t.Palette = append(t.Palette, s)
+	return int32(len(t.Palette) - 1)
+}
+
+// BlockEntity returns the block entity data of the block at the position passed. False is returned if the
+// block has no block entity data.
+func (t *Template) BlockEntity(x, y, z int32) (map[string]any, bool) {
+	i, ok := t.Index(x, y, z)
+	if !ok {
+		return nil, false
+	}
+	data, ok := t.PositionData[i]["block_entity_data"].(map[string]any)
+	return data, ok
+}
+
+// SetBlockEntity sets the block entity data of the block at the position passed. SetBlockEntity panics if
+// the position is outside the Template.
+func (t *Template) SetBlockEntity(x, y, z int32, data map[string]any) {
+	i, ok := t.Index(x, y, z)
+	if !ok {
+		panic(fmt.Sprintf("structure: position (%v, %v, %v) outside structure of size %v", x, y, z, t.Size))
+	}
+	if t.PositionData == nil {
+		t.PositionData = make(map[int32]map[string]any)
+	}
+	if t.PositionData[i] == nil {
+		t.PositionData[i] = make(map[string]any)
+	}
+	t.PositionData[i]["block_entity_data"] = data
+}
+
+// Read reads a Template from a .mcstructure file, which holds the structure template NBT in the little
+// endian NBT encoding.
+func Read(r io.Reader) (*Template, error) {
+	var m map[string]any
+	if err := nbt.NewDecoderWithEncoding(r, nbt.LittleEndian).Decode(&m); err != nil {
+		return nil, fmt.Errorf("read structure: %w", err)
+	}
+	return Decode(m)
+}
+
+// Write writes the Template to a .mcstructure file, encoding its NBT in the little endian NBT encoding.
+func (t *Template) Write(w io.Writer) error {
+	if err := nbt.NewEncoderWithEncoding(w, nbt.LittleEndian).Encode(t.Encode()); err != nil {
+		return fmt.Errorf("write structure: %w", err)
+	}
+	return nil
+}
+
+// FromResponse decodes the Template held by the StructureTemplateDataResponse packet passed. An error is
+// returned if the response was not successful.
+func FromResponse(pk *packet.StructureTemplateDataResponse) (*Template, error) {
+	if !pk.Success {
+		return nil, fmt.Errorf("decode structure %q: response was not successful", pk.StructureName)
+	}
+	return Decode(pk.StructureTemplate)
+}
+
+// Response returns a StructureTemplateDataResponse packet holding the Template, with the structure name and
+// response type passed. The response type is one of the packet.StructureTemplateResponse constants.
+func (t *Template) Response(name string, responseType byte) *packet.StructureTemplateDataResponse {
+	return &packet.StructureTemplateDataResponse{
+		StructureName:     name,
+		Success:           true,
+		ResponseType:      responseType,
+		StructureTemplate: t.Encode(),
+	}
+}
+
+// Encode encodes the Template to its structure template NBT.
+func (t *Template) Encode() map[string]any {
+	palette := make([]any, len(t.Palette))
+	for i, s := range t.Palette {
+		properties := s.Properties
+		if properties == nil {
+			properties = map[string]any{}
+		}
+		palette[i] = map[string]any{"name": s.Name, "states": properties, "version": s.Version}
+	}
+	positionData := make(map[string]any, len(t.PositionData))
+	for i, data := range t.PositionData {
+		positionData[strconv.Itoa(int(i))] = data
+	}
+	entities := make([]any, len(t.Entities))
+	for i, e := range t.Entities {
+		entities[i] = e
+	}
+	layers := make([]any, len(t.Layers))
+	for i, l := range t.Layers {
+		if l == nil {
+			l = []int32{}
+		}
+		layers[i] = l
+	}
+	return map[string]any{
+		"format_version": int32(formatVersion),
+		"size":           t.Size[:],
+		"structure": map[string]any{
+			"block_indices": layers,
+			"entities":      entities,
+			"palette": map[string]any{
+				"default": map[string]any{
+					"block_palette":       palette,
+					"block_position_data": positionData,
+				},
+			},
+		},
+		"structure_world_origin": t.Origin[:],
+	}
+}
+
+// Decode decodes a Template from the structure template NBT passed. An error is returned if the NBT is not
+// a valid structure template.
+func Decode(m map[string]any) (*Template, error) {
+	t := &Template{PositionData: make(map[int32]map[string]any)}
+	if v, ok := m["format_version"].(int32); !ok || v != formatVersion {
+		return nil, fmt.Errorf("decode structure: unsupported format version %v", m["format_version"])
+	}
+	var err error
+	if t.Size, err = vec(m["size"]); err != nil {
+		return nil, fmt.Errorf("decode structure: size: %w", err)
+	}
+	if _, ok := m["structure_world_origin"]; ok {
+		if t.Origin, err = vec(m["structure_world_origin"]); err != nil {
+			return nil, fmt.Errorf("decode structure: origin: %w", err)
+		}
+	}
+	structure, ok := m["structure"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("decode structure: expected compound structure, got %T", m["structure"])
+	}
+
+	n := int(t.Size[0]) * int(t.Size[1]) * int(t.Size[2])
+	indices, _ := structure["block_indices"].([]any)
+	if len(indices) != len(t.Layers) {
+		return nil, fmt.Errorf("decode structure: expected %v block index layers, got %v", len(t.Layers), len(indices))
+	}
+	for i, l := range indices {
+		layer, ok := l.([]int32)
+		if !ok && !isEmptyList(l) {
+			return nil, fmt.Errorf("decode structure: block index layer %v: expected list of ints, got %T", i, l)
+		}
+		if len(layer) != n && (i == 0 || len(layer) != 0) {
+			return nil, fmt.Errorf("decode structure: block index layer %v: expected %v indices, got %v", i, n, len(layer))
+		}
+		t.Layers[i] = layer
+	}
+
+	palettes, _ := structure["palette"].(map[string]any)
+	palette, _ := palettes["default"].(map[string]any)
+	states, _ := palette["block_palette"].([]any)
+	for i, v := range states {
+		s, err := decodeState(v)
+		if err != nil {
+			return nil, fmt.Errorf("decode structure: palette entry %v: %w", i, err)
+		}
+		t.Palette = append(t.Palette, s)
+	}
+	for _, layer := range t.Layers {
+		for j, p := range layer {
+			if p < -1 || int(p) >= len(t.Palette) {
+				return nil, fmt.Errorf("decode structure: block %v refers to palette index %v out of range for %v states", j, p, len(t.Palette))
+			}
+		}
+	}
+	positionData, _ := palette["block_position_data"].(map[string]any)
+	for k, v := range positionData {
+		i, err := strconv.ParseInt(k, 10, 32)
+		if err != nil || i < 0 || int(i) >= n {
+			return nil, fmt.Errorf("decode structure: invalid block position data index %q", k)
+		}
+		data, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("decode structure: block position data %v: expected compound, got %T", k, v)
+		}
+		t.PositionData[int32(i)] = data
+	}
+
+	entities, _ := structure["entities"].([]any)
+	for i, v := range entities {
+		e, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("decode structure: entity %v: expected compound, got %T", i, v)
+		}
+		t.Entities = append(t.Entities, e)
+	}
+	return t, nil
+}
+
+// decodeState decodes a block state from a compound of the block palette.
+func decodeState(v any) (block.State, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return block.State{}, fmt.Errorf("expected compound, got %T", v)
+	}
+	name, ok := m["name"].(string)
+	if !ok {
+		return block.State{}, fmt.Errorf("expected string name, got %T", m["name"])
+	}
+	properties, _ := m["states"].(map[string]any)
+	if properties == nil {
+		properties = map[string]any{}
+	}
+	version, _ := m["version"].(int32)
+	return block.State{Name: name, Properties: properties, Version: version}, nil
+}
+
+// vec decodes a list of three ints.
+func vec(v any) ([3]int32, error) {
+	l, ok := v.([]int32)
+	if !ok || len(l) != 3 {
+		return [3]int32{}, fmt.Errorf("expected list of 3 ints, got %v", v)
+	}
+	return [3]int32{l[0], l[1], l[2]}, nil
+}
+
+// isEmptyList checks if the value passed is an empty NBT list, which may be decoded with any element type.
+func isEmptyList(v any) bool {
+	switch l := v.(type) {
+	case []any:
+		return len(l) == 0
+	case []byte:
+		return len(l) == 0
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package minecraft
+
+import (
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/sandertv/gophertunnel/minecraft/text"
+)
+
+// subClientID returns the ID of the sub-client that the packet with the header passed was sent by or is
+// destined for. It returns 0 for packets of the main client of a connection.
+func subClientID(h *packet.Header) byte {
+	if h.SenderSubClient != 0 {
+		return h.SenderSubClient
+	}
+	return h.TargetSubClient
+}
+
+// SubClientID returns the ID of the sub-client that the Conn represents. Split-screen players share the
+// connection of the main client, and each get their own Conn with an ID from 1 to 3. SubClientID returns 0
+// for the main client.
+func (conn *Conn) SubClientID() byte {
+	return conn.subClientID
+}
+
+// SubClients returns the sub-clients that are currently logged in over the Conn, such as the second player
+// of a console playing split-screen. Each sub-client is a Conn of its own, which shares the underlying
+// connection of the Conn.
+func (conn *Conn) SubClients() []*Conn {
+	conn.subClientsMu.Lock()
+	defer conn.subClientsMu.Unlock()
+	subClients := make([]*Conn, 0, len(conn.subClients))
+	for _, sub := range conn.subClients {
+		subClients = append(subClients, sub)
+	}
+	return subClients
+}
+
+// receiveSubClient handles the packetData of a packet sent by or destined for the sub-client with the ID
+// passed. It returns false if there is no such sub-client and the packet was not a SubClientLogin, in which
+// case the packet should be handled by the Conn itself.
+func (conn *Conn) receiveSubClient(id byte, pkData *packetData) (bool, error) {
+	conn.subClientsMu.Lock()
+	sub, ok := conn.subClients[id]
+	conn.subClientsMu.Unlock()
+	if ok {
+		return true, sub.receivePacketData(pkData)
+	}
+	if pkData.h.PacketID != packet.IDSubClientLogin || conn.onSubClient == nil || !conn.loggedIn {
+		return false, nil
+	}
+	pks, err := pkData.decode(conn)
+	if err != nil || len(pks) == 0 {
+		return true, err
+	}
+	pk, ok := packet.Unwrap(pks[0]).(*packet.SubClientLogin)
+	if !ok {
+		return true, nil
+	}
+	return true, conn.handleSubClientLogin(id, pk)
+}
+
+// handleSubClientLogin handles a SubClientLogin packet sent by the sub-client with the ID passed. It
+// verifies the login request of the sub-client and adds it to the Conn once it is valid.
+func (conn *Conn) handleSubClientLogin(id byte, pk *packet.SubClientLogin) error {
+	sub := conn.newSubClient(id)
+	var (
+		err        error
+		authResult login.AuthResult
+	)
+	sub.identityData, sub.clientData, authResult, err = sub.parseLogin(pk.ConnectionRequest)
+	if err != nil {
+		return fmt.Errorf("parse sub-client login request: %w", err)
+	}
+	if sub.onClientData != nil {
+		sub.onClientData(sub)
+	}
+	if !authResult.XBOXLiveAuthenticated && sub.authEnabled {
+		_ = sub.WritePacket(&packet.Disconnect{Message: text.Colourf("<red>You must be logged in with XBOX Live to join.</red>")})
+		return nil
+	}
+	if sub.allowLogin != nil {
+		if message, ok := sub.allowLogin(sub); !ok {
+			_ = sub.WritePacket(&packet.Disconnect{Message: message})
+			return nil
+		}
+	}
+
+	conn.subClientsMu.Lock()
+	if conn.subClients == nil {
+		conn.subClients = make(map[byte]*Conn)
+	}
+	conn.subClients[id] = sub
+	conn.subClientsMu.Unlock()
+
+	// Sub-clients share the encryption and resource packs of the main client, so they are logged in right
+	// away and only need to be spawned using StartGame.
+	if err := sub.WritePacket(&packet.PlayStatus{Status: packet.PlayStatusLoginSuccess}); err != nil {
+		return fmt.Errorf("send PlayStatus (Status=LoginSuccess) to sub-client: %w", err)
+	}
+	sub.loggedIn = true
+	conn.onSubClient(sub)
+	return nil
+}
+
+// newSubClient creates a Conn for the sub-client with the ID passed, which shares the underlying connection
+// and settings of the Conn.
+func (conn *Conn) newSubClient(id byte) *Conn {
+	sub := &Conn{
+		parent:                    conn,
+		subClientID:               id,
+		conn:                      conn.conn,
+		log:                       conn.log,
+		authEnabled:               conn.authEnabled,
+		serverSide:                conn.serverSide,
+		proto:                     conn.proto,
+		pool:                      conn.pool,
+		packets:                   make(chan *packetData, 8),
+		additional:                make(chan packet.Packet, 16),
+		close:                     make(chan struct{}),
+		spawn:                     make(chan struct{}),
+		hdr:                       &packet.Header{TargetSubClient: id},
+		disconnectOnUnknownPacket: conn.disconnectOnUnknownPacket,
+		disconnectOnInvalidPacket: conn.disconnectOnInvalidPacket,
+		onClientData:              conn.onClientData,
+		allowLogin:                conn.allowLogin,
+		chainCache:                conn.chainCache,
+		onLoginVerified:           conn.onLoginVerified,
+		biomes:                    conn.biomes,
+		cacheEnabled:              conn.cacheEnabled,
+		packetFunc:                conn.packetFunc,
+		captureWriter:             conn.captureWriter,
+		packetLogger:              conn.packetLogger,
+		exemptedPacks:             conn.exemptedPacks,
+		flushIDs:                  conn.flushIDs,
+		readLimits:                conn.readLimits,
+		strictDecoding:            conn.strictDecoding,
+		preserveTrailing:          conn.preserveTrailing,
+		pooledPackets:             conn.pooledPackets,
+		decodePool:                conn.decodePool,
+		gameData:                  GameData{WorldName: conn.gameData.WorldName},
+	}
+	sub.shieldID.Store(conn.shieldID.Load())
+	sub.ResourcePackHandler = &defaultResourcepackHandler{c: sub}
+	sub.expect()
+	return sub
+}
+
+// closeSubClient closes a sub-client Conn and removes it from its parent. The underlying connection is not
+// closed, as it is still used by the main client.
+func (conn *Conn) closeSubClient() {
+	conn.once.Do(func() {
+		close(conn.close)
+		conn.parent.subClientsMu.Lock()
+		delete(conn.parent.subClients, conn.subClientID)
+		conn.parent.subClientsMu.Unlock()
+	})
+}
+
+// closeSubClients closes all sub-clients of the Conn.
+func (conn *Conn) closeSubClients() {
+	for _, sub := range conn.SubClients() {
+		sub.closeSubClient()
+	}
+}
@@ -0,0 +1,238 @@
+package minecraft
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TCP is an implementation of a Network that runs Minecraft connections over a plain TCP stream rather than
+// RakNet. Every batch of packets is sent in a frame prefixed with its length. TCP is not supported by the
+// vanilla client: It is meant for connections between trusted backends, such as proxies and the servers
+// behind them, which do not need the reliability layer of RakNet.
+// TCP is registered under the network ID "tcp".
+type TCP struct{}
+
+const (
+	// tcpConnect and tcpPing are the first byte sent over a TCP connection, indicating if the connection
+	// is used as a Minecraft connection or only to obtain the pong data of the server.
+	tcpConnect byte = iota
+	tcpPing
+
+	// maxTCPFrameSize is the maximum size of a single frame sent over a TCP connection.
+	maxTCPFrameSize = 1 << 25
+	// tcpHandshakeTimeout is the maximum duration a listener waits for the first byte of a new connection.
+	tcpHandshakeTimeout = time.Second * 5
+)
+
+// DialContext ...
+func (TCP) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	var d net.Dialer
+	c, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.Write([]byte{tcpConnect}); err != nil {
+		_ = c.Close()
+		return nil, fmt.Errorf("write tcp handshake: %w", err)
+	}
+	return newTCPConn(c), nil
+}
+
+// PingContext ...
+func (TCP) PingContext(ctx context.Context, address string) (response []byte, err error) {
+	var d net.Dialer
+	c, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = c.SetDeadline(deadline)
+	}
+	if _, err := c.Write([]byte{tcpPing}); err != nil {
+		return nil, fmt.Errorf("write tcp ping: %w", err)
+	}
+	return newTCPConn(c).ReadPacket()
+}
+
+// Listen ...
+func (TCP) Listen(address string) (NetworkListener, error) {
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	listener := &tcpListener{Listener: l, id: rand.Int63(), conns: make(chan net.Conn), closed: make(chan struct{})}
+	listener.pong.Store(&[]byte{})
+	go listener.accept()
+	return listener, nil
+}
+
+// tcpListener is a NetworkListener that accepts Minecraft connections over TCP.
+type tcpListener struct {
+	net.Listener
+	id       int64
+	pong     atomic.Pointer[[]byte]
+	pongFunc atomic.Pointer[func() []byte]
+
+	conns  chan net.Conn
+	once   sync.Once
+	closed chan struct{}
+}
+
+// accept accepts new TCP connections until the tcpListener is closed. Pings are answered directly, whereas
+// other connections are passed on to Accept.
+func (l *tcpListener) accept() {
+	defer l.Close()
+	for {
+		c, err := l.Listener.Accept()
+		if err != nil {
+			return
+		}
+		go l.handshake(c)
+	}
+}
+
+// handshake reads the first byte of a new TCP connection to find out what it is used for and handles it
+// accordingly.
+func (l *tcpListener) handshake(c net.Conn) {
+	_ = c.SetReadDeadline(time.Now().Add(tcpHandshakeTimeout))
+	var b [1]byte
+	if _, err := io.ReadFull(c, b[:]); err != nil {
+		_ = c.Close()
+		return
+	}
+	_ = c.SetReadDeadline(time.Time{})
+
+	switch b[0] {
+	case tcpConnect:
+		select {
+		case l.conns <- newTCPConn(c):
+		case <-l.closed:
+			_ = c.Close()
+		}
+	case tcpPing:
+		pong := *l.pong.Load()
+		if f := l.pongFunc.Load(); f != nil {
+			pong = (*f)()
+		}
+		_, _ = newTCPConn(c).Write(pong)
+		_ = c.Close()
+	default:
+		_ = c.Close()
+	}
+}
+
+// Accept ...
+func (l *tcpListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.closed:
+		return nil, &net.OpError{Op: "accept", Net: "tcp", Addr: l.Addr(), Err: net.ErrClosed}
+	}
+}
+
+// Close ...
+func (l *tcpListener) Close() error {
+	var err error
+	l.once.Do(func() {
+		close(l.closed)
+		err = l.Listener.Close()
+	})
+	return err
+}
+
+// ID ...
+func (l *tcpListener) ID() int64 {
+	return l.id
+}
+
+// PongData ...
+func (l *tcpListener) PongData(data []byte) {
+	l.pong.Store(&data)
+}
+
+// PongDataFunc sets a function that generates the pong data for every ping received, overriding the data set
+// using PongData.
+func (l *tcpListener) PongDataFunc(f func() []byte) {
+	l.pongFunc.Store(&f)
+}
+
+// tcpConn is a net.Conn that sends and receives length prefixed frames over a TCP connection, so that the
+// boundaries of batches are kept intact.
+type tcpConn struct {
+	net.Conn
+	r *bufio.Reader
+
+	mu sync.Mutex
+}
+
+// newTCPConn wraps the TCP connection passed in a tcpConn.
+func newTCPConn(c net.Conn) *tcpConn {
+	return &tcpConn{Conn: c, r: bufio.NewReader(c)}
+}
+
+// Write writes b as a single frame to the connection.
+func (c *tcpConn) Write(b []byte) (int, error) {
+	if len(b) > maxTCPFrameSize {
+		return 0, fmt.Errorf("write tcp frame: size %v exceeds maximum of %v", len(b), maxTCPFrameSize)
+	}
+	frame := make([]byte, 4+len(b))
+	binary.BigEndian.PutUint32(frame, uint32(len(b)))
+	copy(frame[4:], b)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := c.Conn.Write(frame); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// ReadPacket reads a single frame from the connection and returns its data.
+func (c *tcpConn) ReadPacket() ([]byte, error) {
+	var l [4]byte
+	if _, err := io.ReadFull(c.r, l[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(l[:])
+	if size > maxTCPFrameSize {
+		return nil, fmt.Errorf("read tcp frame: size %v exceeds maximum of %v", size, maxTCPFrameSize)
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(c.r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Read reads a single frame from the connection into b. If b is too small to hold the frame, an error is
+// returned.
+func (c *tcpConn) Read(b []byte) (int, error) {
+	frame, err := c.ReadPacket()
+	if err != nil {
+		return 0, err
+	}
+	if len(frame) > len(b) {
+		return 0, errBufferTooSmall
+	}
+	return copy(b, frame), nil
+}
+
+// Latency always returns 0, as TCP connections do not measure their latency.
+func (c *tcpConn) Latency() time.Duration {
+	return 0
+}
+
+// init registers the TCP network.
+func init() {
+	RegisterNetwork("tcp", TCP{})
+}
@@ -0,0 +1,124 @@
+package minecraft
+
+import (
+	"context"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// LoginTimeouts holds the maximum durations of the phases of the login sequence of a Conn. A Conn that does
+// not complete a phase in time is closed with a LoginTimeoutError holding the name of the phase. A phase with
+// a timeout of 0 or lower may take as long as it needs, which is useful for clients on slow connections that
+// download many resource packs.
+type LoginTimeouts struct {
+	// Total is the maximum duration of the login sequence as a whole, from the connection being established
+	// until the Conn is logged in: Until it is accepted by a Listener, or returned by Dial. It bounds all
+	// phases below combined, except for the Spawn phase of a Listener Conn, which starts after it is accepted.
+	Total time.Duration
+	// NetworkSettings is the maximum duration between the connection being established and the network
+	// settings, such as compression, being negotiated.
+	NetworkSettings time.Duration
+	// Login is the maximum duration between the network settings being negotiated and the login request
+	// being verified and encryption being enabled.
+	Login time.Duration
+	// ResourcePacks is the maximum duration of the resource pack negotiation, including the download of all
+	// resource packs.
+	ResourcePacks time.Duration
+	// Spawn is the maximum duration between the StartGame packet being sent and the client spawning.
+	Spawn time.Duration
+}
+
+// startLogin starts the login sequence of the Conn, starting the Total timeout of its LoginTimeouts and
+// entering the first phase.
+func (conn *Conn) startLogin() {
+	conn.phaseMu.Lock()
+	if conn.loginTimeouts.Total > 0 {
+		conn.loginDeadline = time.Now().Add(conn.loginTimeouts.Total)
+	}
+	conn.phaseMu.Unlock()
+	conn.enterPhase("network settings", conn.loginTimeouts.NetworkSettings)
+}
+
+// enterPhase moves the Conn to the phase of the login sequence with the name passed, replacing the timer of
+// the previous phase with one that closes the Conn if the phase does not finish within the timeout passed, or
+// before the Total timeout of the login sequence expires, whichever comes first. An empty name ends the login
+// sequence, so that no further timeouts apply.
+func (conn *Conn) enterPhase(name string, timeout time.Duration) {
+	conn.phaseMu.Lock()
+	defer conn.phaseMu.Unlock()
+	if conn.phaseTimer != nil {
+		conn.phaseTimer.Stop()
+		conn.phaseTimer = nil
+	}
+	if name == "" {
+		conn.loginDeadline = time.Time{}
+		return
+	}
+	if !conn.loginDeadline.IsZero() {
+		if remaining := time.Until(conn.loginDeadline); timeout <= 0 || remaining < timeout {
+			timeout = max(remaining, time.Nanosecond)
+		}
+	}
+	if timeout <= 0 {
+		return
+	}
+	conn.phaseTimer = time.AfterFunc(timeout, func() {
+		conn.closeWithErr(LoginTimeoutError{Stage: name, Err: context.DeadlineExceeded})
+	})
+}
+
+// keepAlive sends a packet over the Conn every keep alive interval of the Conn until it is closed, so that
+// the connection is not considered idle by the other end or by middleboxes. Clients send a TickSync packet,
+// while servers send a NetworkStackLatency packet that does not need a response.
+func (conn *Conn) keepAlive(server bool) {
+	if conn.keepAliveInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(conn.keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-conn.close:
+			return
+		case <-ticker.C:
+			var pk packet.Packet = &packet.TickSync{}
+			if server {
+				pk = &packet.NetworkStackLatency{Timestamp: time.Now().UnixMilli()}
+			}
+			_ = conn.WritePacket(pk)
+		}
+	}
+}
+
+// SetReadTimeout sets the maximum duration that a single call to ReadPacket or Read waits for a packet to
+// arrive. Unlike SetReadDeadline, the timeout applies to every call separately, so it does not have to be
+// renewed before every call. If d is 0 or lower, reads do not time out.
+func (conn *Conn) SetReadTimeout(d time.Duration) {
+	conn.readTimeout.Store(int64(d))
+}
+
+// SetWriteTimeout sets the maximum duration that a single write of a batch of packets to the underlying
+// connection, as done by Flush, may take. If d is 0 or lower, writes do not time out.
+func (conn *Conn) SetWriteTimeout(d time.Duration) {
+	conn.writeTimeout.Store(int64(d))
+}
+
+// readTimer returns a channel that receives a value once the read timeout of the Conn expires, and a function
+// that must be called to release the timer. If the Conn has no read timeout, the channel returned is nil.
+func (conn *Conn) readTimer() (<-chan time.Time, func()) {
+	d := time.Duration(conn.readTimeout.Load())
+	if d <= 0 {
+		return nil, func() {}
+	}
+	t := time.NewTimer(d)
+	return t.C, func() { t.Stop() }
+}
+
+// applyWriteTimeout sets the write deadline of the underlying connection according to the write timeout of
+// the Conn, if it has one.
+func (conn *Conn) applyWriteTimeout() {
+	if d := time.Duration(conn.writeTimeout.Load()); d > 0 {
+		_ = conn.conn.SetWriteDeadline(time.Now().Add(d))
+	}
+}
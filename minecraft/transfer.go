@@ -0,0 +1,38 @@
+package minecraft
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// transferAddress returns the address that a Transfer packet transfers the client to.
+func transferAddress(pk *packet.Transfer) string {
+	return joinHostPort(pk.Address, int(pk.Port))
+}
+
+// followTransfer follows a Transfer packet received over the Conn passed, which was dialed over the network
+// passed. It closes the Conn, dials the address in the packet and calls the TransferFunc of the Dialer with
+// the result.
+func (d Dialer) followTransfer(conn *Conn, network string, pk *packet.Transfer) {
+	address := transferAddress(pk)
+	conn.closeWithErr(fmt.Errorf("transferred to %v", address))
+
+	timeout := d.TransferTimeout
+	if timeout <= 0 {
+		timeout = time.Second * 30
+	}
+	// The capture of the old Conn was finished when it was closed, so the new Conn is not recorded.
+	d.Capture = nil
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	newConn, err := d.DialContext(ctx, network, address, timeout)
+	if err != nil && newConn != nil {
+		_ = newConn.Close()
+		newConn = nil
+	}
+	d.TransferFunc(conn, newConn, err)
+}
@@ -0,0 +1,237 @@
+package minecraft
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Translation describes the differences between a protocol version and the protocol version directly after it.
+// Translations are registered to a TranslationRegistry, which chains them to produce a Protocol for every
+// version registered, so that only the differences between two adjacent versions have to be maintained.
+type Translation struct {
+	// Protocol and Version are the protocol version and game version that the Translation translates to,
+	// such as 712 and "1.21.20".
+	Protocol int32
+	Version  string
+
+	// IDs maps the IDs of packets in the next version to their ID in this version, for packets of which the
+	// ID changed between the two versions.
+	IDs map[uint32]uint32
+	// Removed holds the IDs of packets in the next version that do not exist in this version. Packets with
+	// these IDs are passed to Fallback when written, or dropped if Fallback is nil.
+	Removed []uint32
+	// Fallback converts a packet that does not exist in this version to packets that do, for example by
+	// replacing it with an older packet that has a similar effect. It is called for packets with an ID in
+	// Removed.
+	Fallback func(pk packet.Packet, conn IConn) []packet.Packet
+
+	// Packets holds the packets of which the encoding changed in this version, indexed by their ID in this
+	// version. These packets are read instead of the ones of the next version and should be converted by
+	// Upgrade and Downgrade.
+	Packets map[uint32]func() packet.Packet
+	// Upgrade converts a packet read in this version to packets of the next version. It is typically only
+	// needed for packets found in Packets. If nil, packets are not converted.
+	Upgrade func(pk packet.Packet, conn IConn) []packet.Packet
+	// Downgrade converts a packet of the next version to packets of this version. Packets returned that are
+	// not the packet passed are written with the ID returned by their ID method. If nil, packets are not
+	// converted.
+	Downgrade func(pk packet.Packet, conn IConn) []packet.Packet
+}
+
+// TranslationRegistry holds Translations registered for protocol versions older than the current one. It
+// produces a Protocol for each of those versions that chains the Translations of all versions in between.
+// The Protocols returned may be passed to ListenConfig.AcceptedProtocols or Dialer.Protocol. A
+// TranslationRegistry is safe for concurrent use.
+type TranslationRegistry struct {
+	mu sync.RWMutex
+	// translations holds the Translations registered, sorted from newest to oldest protocol version.
+	translations []Translation
+}
+
+// NewTranslationRegistry returns an empty TranslationRegistry.
+func NewTranslationRegistry() *TranslationRegistry {
+	return &TranslationRegistry{}
+}
+
+// Register registers the Translations passed. A Translation with the same protocol version as one already
+// registered replaces it.
+func (r *TranslationRegistry) Register(translations ...Translation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range translations {
+		r.translations = slices.DeleteFunc(r.translations, func(other Translation) bool {
+			return other.Protocol == t.Protocol
+		})
+		r.translations = append(r.translations, t)
+	}
+	slices.SortFunc(r.translations, func(a, b Translation) int {
+		return cmp.Compare(b.Protocol, a.Protocol)
+	})
+}
+
+// Protocol returns the Protocol for the protocol version passed, which translates packets through the
+// Translations of all versions between the current version and the one passed. False is returned if no
+// Translation was registered for the version.
+func (r *TranslationRegistry) Protocol(id int32) (Protocol, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for i, t := range r.translations {
+		if t.Protocol == id {
+			return translatedProtocol{chain: slices.Clone(r.translations[:i+1])}, true
+		}
+	}
+	return nil, false
+}
+
+// Protocols returns a Protocol for every protocol version registered, from newest to oldest.
+func (r *TranslationRegistry) Protocols() []Protocol {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	protocols := make([]Protocol, 0, len(r.translations))
+	for i := range r.translations {
+		protocols = append(protocols, translatedProtocol{chain: slices.Clone(r.translations[:i+1])})
+	}
+	return protocols
+}
+
+// translatedProtocol is a Protocol that translates packets of the current version through a chain of
+// Translations, ordered from newest to oldest. The last Translation in the chain is the version of the
+// Protocol.
+type translatedProtocol struct {
+	chain []Translation
+}
+
+// ID ...
+func (p translatedProtocol) ID() int32 { return p.chain[len(p.chain)-1].Protocol }
+
+// Ver ...
+func (p translatedProtocol) Ver() string { return p.chain[len(p.chain)-1].Version }
+
+// Packets returns the packet.Pool of the current version, with the IDs remapped and packets replaced by
+// every Translation in the chain.
+func (p translatedProtocol) Packets(listener bool) packet.Pool {
+	pool := proto{}.Packets(listener)
+	for _, t := range p.chain {
+		next := make(packet.Pool, len(pool))
+		for id, f := range pool {
+			if slices.Contains(t.Removed, id) {
+				continue
+			}
+			if old, ok := t.IDs[id]; ok {
+				next[old] = remapConstructor(f, old)
+				continue
+			}
+			next[id] = f
+		}
+		for id, f := range t.Packets {
+			next[id] = f
+		}
+		pool = next
+	}
+	return pool
+}
+
+// NewReader ...
+func (p translatedProtocol) NewReader(r ByteReader, shieldID int32, enableLimits bool) protocol.IO {
+	return protocol.NewReader(r, shieldID, enableLimits)
+}
+
+// NewWriter ...
+func (p translatedProtocol) NewWriter(w ByteWriter, shieldID int32) protocol.IO {
+	return protocol.NewWriter(w, shieldID)
+}
+
+// ConvertToLatest upgrades the packet passed through every Translation in the chain, from oldest to newest.
+func (p translatedProtocol) ConvertToLatest(pk packet.Packet, conn IConn) []packet.Packet {
+	if r, ok := pk.(*remappedPacket); ok {
+		pk = r.Packet
+	}
+	pks := []packet.Packet{pk}
+	for i := len(p.chain) - 1; i >= 0; i-- {
+		t := p.chain[i]
+		if t.Upgrade == nil {
+			continue
+		}
+		upgraded := make([]packet.Packet, 0, len(pks))
+		for _, pk := range pks {
+			upgraded = append(upgraded, t.Upgrade(pk, conn)...)
+		}
+		pks = upgraded
+	}
+	return pks
+}
+
+// ConvertFromLatest downgrades the packet passed through every Translation in the chain, from newest to
+// oldest, remapping the IDs of packets that are not converted along the way.
+func (p translatedProtocol) ConvertFromLatest(pk packet.Packet, conn IConn) []packet.Packet {
+	pks := []packet.Packet{pk}
+	for _, t := range p.chain {
+		downgraded := make([]packet.Packet, 0, len(pks))
+		for _, pk := range pks {
+			if slices.Contains(t.Removed, pk.ID()) {
+				if t.Fallback != nil {
+					downgraded = append(downgraded, t.Fallback(unwrapRemapped(pk), conn)...)
+				}
+				continue
+			}
+			if t.Downgrade == nil {
+				downgraded = append(downgraded, remap(pk, t.IDs))
+				continue
+			}
+			inner := unwrapRemapped(pk)
+			for _, converted := range t.Downgrade(inner, conn) {
+				if converted == inner {
+					// The packet was not converted, so it keeps the ID it had in the next version, which may
+					// have changed in this version.
+					converted = remap(pk, t.IDs)
+				}
+				downgraded = append(downgraded, converted)
+			}
+		}
+		pks = downgraded
+	}
+	return pks
+}
+
+// remappedPacket is a packet.Packet of which the ID differs from the ID returned by the packet.Packet it
+// wraps, because the ID of the packet changed in an older protocol version.
+type remappedPacket struct {
+	packet.Packet
+	id uint32
+}
+
+// ID returns the remapped ID of the packet.
+func (pk *remappedPacket) ID() uint32 {
+	return pk.id
+}
+
+// remap returns the packet passed with its ID remapped using the ID mapping passed. The packet is returned
+// unchanged if its ID is not found in the mapping.
+func remap(pk packet.Packet, ids map[uint32]uint32) packet.Packet {
+	id, ok := ids[pk.ID()]
+	if !ok {
+		return pk
+	}
+	return &remappedPacket{Packet: unwrapRemapped(pk), id: id}
+}
+
+// remapConstructor returns a function that creates packets using the function passed, with their ID remapped
+// to the ID passed.
+func remapConstructor(f func() packet.Packet, id uint32) func() packet.Packet {
+	return func() packet.Packet {
+		return &remappedPacket{Packet: unwrapRemapped(f()), id: id}
+	}
+}
+
+// unwrapRemapped returns the packet.Packet wrapped by a remappedPacket, or the packet passed if it is not a
+// remappedPacket.
+func unwrapRemapped(pk packet.Packet) packet.Packet {
+	if r, ok := pk.(*remappedPacket); ok {
+		return r.Packet
+	}
+	return pk
+}